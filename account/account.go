@@ -76,7 +76,15 @@ func (acc *Account) String() string {
 }
 
 func AccountEncoder(o interface{}, w io.Writer, n *int, err *error) {
-	wire.WriteBinary(o.(*Account), w, n, err)
+	acc := o.(*Account)
+	// so that accounts using none of the newer permissions keep the bytes, and
+	// so the state hash, older versions give them
+	if len(acc.Permissions.BaseExpiries) == 0 && len(acc.Permissions.RoleExpiries) == 0 &&
+		acc.Permissions.CallAllowList == nil {
+		wire.WriteBinary(newLegacyAccount(acc), w, n, err)
+		return
+	}
+	wire.WriteBinary(acc, w, n, err)
 }
 
 func AccountDecoder(r io.Reader, n *int, err *error) interface{} {
@@ -100,5 +108,68 @@ func DecodeAccount(accBytes []byte) *Account {
 	var n int
 	var err error
 	acc := AccountDecoder(bytes.NewBuffer(accBytes), &n, &err)
+	if err != nil {
+		// the account may have been saved before it had expiries or a call
+		// allow list
+		if legacyAcc := decodeLegacyAccount(accBytes); legacyAcc != nil {
+			return legacyAcc
+		}
+	}
 	return acc.(*Account)
 }
+
+// Account as encoded before AccountPermissions had BaseExpiries, RoleExpiries
+// and CallAllowList, and still while it has none of them. go-wire encodes
+// structs as their fields in order, so these accounts end where the newer
+// fields would begin.
+type legacyAccount struct {
+	Address     []byte
+	PubKey      crypto.PubKey
+	Sequence    int
+	Balance     int64
+	Code        []byte
+	StorageRoot []byte
+	Permissions struct {
+		Base  ptypes.BasePermissions
+		Roles []string
+	}
+}
+
+func newLegacyAccount(acc *Account) *legacyAccount {
+	legacyAcc := &legacyAccount{
+		Address:     acc.Address,
+		PubKey:      acc.PubKey,
+		Sequence:    acc.Sequence,
+		Balance:     acc.Balance,
+		Code:        acc.Code,
+		StorageRoot: acc.StorageRoot,
+	}
+	legacyAcc.Permissions.Base = acc.Permissions.Base
+	legacyAcc.Permissions.Roles = acc.Permissions.Roles
+	return legacyAcc
+}
+
+// Decode an account saved in the legacy layout, returning nil if accBytes is
+// not one
+func decodeLegacyAccount(accBytes []byte) *Account {
+	var n int
+	var err error
+	legacyAcc := new(legacyAccount)
+	r := bytes.NewReader(accBytes)
+	wire.ReadBinaryPtr(legacyAcc, r, 0, &n, &err)
+	if err != nil || r.Len() > 0 {
+		return nil
+	}
+	return &Account{
+		Address:     legacyAcc.Address,
+		PubKey:      legacyAcc.PubKey,
+		Sequence:    legacyAcc.Sequence,
+		Balance:     legacyAcc.Balance,
+		Code:        legacyAcc.Code,
+		StorageRoot: legacyAcc.StorageRoot,
+		Permissions: ptypes.AccountPermissions{
+			Base:  legacyAcc.Permissions.Base,
+			Roles: legacyAcc.Permissions.Roles,
+		},
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright 2017 Monax Industries Limited
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package account
+
+import (
+	"testing"
+
+	ptypes "github.com/hyperledger/burrow/permission/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tendermint/go-wire"
+)
+
+func TestEncodeAccountDecodeAccount(t *testing.T) {
+	privAccount := GenPrivAccountFromSecret("encodeAccount")
+	acc := &Account{
+		Address:     privAccount.Address,
+		PubKey:      privAccount.PubKey,
+		Sequence:    3,
+		Balance:     100,
+		Code:        []byte{1, 2, 3},
+		StorageRoot: []byte{4, 5, 6},
+		Permissions: ptypes.DefaultAccountPermissions.Clone(),
+	}
+	acc.Permissions.SetBaseUntil(ptypes.Call, true, 20)
+	acc.Permissions.AddRoleUntil("contractors", 30)
+	assert.Equal(t, acc, DecodeAccount(EncodeAccount(acc)))
+}
+
+func TestDecodeLegacyAccount(t *testing.T) {
+	privAccount := GenPrivAccountFromSecret("decodeLegacyAccount")
+	legacyAcc := &legacyAccount{
+		Address:  privAccount.Address,
+		PubKey:   privAccount.PubKey,
+		Sequence: 3,
+		Balance:  100,
+		Code:     []byte{1, 2, 3},
+	}
+	legacyAcc.Permissions.Base = ptypes.BasePermissions{Perms: ptypes.Send,
+		SetBit: ptypes.Send | ptypes.Call}
+	legacyAcc.Permissions.Roles = []string{ptypes.RoleName("auditors")}
+
+	// Accounts saved before expiries and call allow lists still load
+	acc := DecodeAccount(wire.BinaryBytes(legacyAcc))
+	assert.Equal(t, privAccount.Address, acc.Address)
+	assert.Equal(t, privAccount.PubKey, acc.PubKey)
+	assert.Equal(t, 3, acc.Sequence)
+	assert.Equal(t, int64(100), acc.Balance)
+	assert.Equal(t, []byte{1, 2, 3}, acc.Code)
+	assert.Equal(t, legacyAcc.Permissions.Base, acc.Permissions.Base)
+	assert.True(t, acc.Permissions.HasRole("auditors"))
+	assert.Empty(t, acc.Permissions.BaseExpiries)
+	assert.Empty(t, acc.Permissions.RoleExpiries)
+	assert.Nil(t, acc.Permissions.CallAllowList)
+
+	// and accounts using none of the newer permissions are still saved so
+	acc.Permissions.AddRole("contractors")
+	assert.Equal(t, wire.BinaryBytes(newLegacyAccount(acc)), EncodeAccount(acc))
+	assert.Equal(t, acc, DecodeAccount(EncodeAccount(acc)))
+	acc.Permissions.AllowCall([]byte{1})
+	assert.NotEqual(t, wire.BinaryBytes(newLegacyAccount(acc)), EncodeAccount(acc))
+	assert.Equal(t, acc.Permissions.CallAllowList,
+		DecodeAccount(EncodeAccount(acc)).Permissions.CallAllowList)
+
+	// but bytes that are neither layout are not taken for a legacy account
+	assert.Nil(t, decodeLegacyAccount(append(wire.BinaryBytes(legacyAcc), 0, 0)))
+}
@@ -0,0 +1,218 @@
+// Copyright 2017 Monax Industries Limited
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package account
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/hyperledger/burrow/manager/burrow-mint/evm/sha3"
+
+	"github.com/tendermint/go-crypto"
+)
+
+// Account keys are either ed25519 keys, the default, or secp256k1 keys as
+// held by Ethereum wallets and most HSMs. Key servers hand keys around as raw
+// bytes so the type of a public key is told by its length.
+
+type KeyType string
+
+const (
+	KeyTypeEd25519   KeyType = "ed25519"
+	KeyTypeSecp256k1 KeyType = "secp256k1"
+)
+
+const (
+	PubKeyEd25519Length    = 32
+	PubKeySecp256k1Length  = 64 // uncompressed, without the 0x04 prefix
+	PrivKeyEd25519Length   = 64
+	PrivKeySecp256k1Length = 32
+	SignatureEd25519Length = 64
+)
+
+// Addresses are derived from public keys by one of these schemes, chosen for
+// the chain in its genesis params
+const (
+	// RIPEMD160 of the go-wire encoding of the public key
+	AddressSchemeTendermint = "tendermint"
+	// The last 20 bytes of the Keccak256 hash of an uncompressed secp256k1
+	// public key as Ethereum does, and as for tendermint for ed25519 keys
+	AddressSchemeEthereum = "ethereum"
+)
+
+// KeyTypeFromString returns the key type named by keyType, defaulting to ed25519
+func KeyTypeFromString(keyType string) (KeyType, error) {
+	switch KeyType(keyType) {
+	case "", KeyTypeEd25519:
+		return KeyTypeEd25519, nil
+	case KeyTypeSecp256k1:
+		return KeyTypeSecp256k1, nil
+	}
+	return "", fmt.Errorf("Unknown key type '%s' (use %s or %s)", keyType,
+		KeyTypeEd25519, KeyTypeSecp256k1)
+}
+
+// KeyTypeOf returns the type of pubKey
+func KeyTypeOf(pubKey crypto.PubKey) (KeyType, error) {
+	switch pubKey.(type) {
+	case crypto.PubKeyEd25519:
+		return KeyTypeEd25519, nil
+	case crypto.PubKeySecp256k1:
+		return KeyTypeSecp256k1, nil
+	}
+	return "", fmt.Errorf("Unsupported public key type %T", pubKey)
+}
+
+// PubKeyFromBytes returns the ed25519 or secp256k1 public key with raw bytes
+// pubKeyBytes
+func PubKeyFromBytes(pubKeyBytes []byte) (crypto.PubKey, error) {
+	switch len(pubKeyBytes) {
+	case PubKeyEd25519Length:
+		var pubKey crypto.PubKeyEd25519
+		copy(pubKey[:], pubKeyBytes)
+		return pubKey, nil
+	case PubKeySecp256k1Length:
+		var pubKey crypto.PubKeySecp256k1
+		copy(pubKey[:], pubKeyBytes)
+		return pubKey, nil
+	}
+	return nil, fmt.Errorf("Public key %X has length %v but ed25519 public keys "+
+		"have length %v and secp256k1 public keys have length %v", pubKeyBytes,
+		len(pubKeyBytes), PubKeyEd25519Length, PubKeySecp256k1Length)
+}
+
+// SignatureFromBytes returns the signature with raw bytes sigBytes made by the
+// private key of pubKey. Secp256k1 signatures are DER encoded so vary in length.
+func SignatureFromBytes(pubKey crypto.PubKey, sigBytes []byte) (crypto.Signature, error) {
+	switch pubKey.(type) {
+	case crypto.PubKeyEd25519:
+		if len(sigBytes) != SignatureEd25519Length {
+			return nil, fmt.Errorf("Signature %X has length %v but ed25519 "+
+				"signatures have length %v", sigBytes, len(sigBytes),
+				SignatureEd25519Length)
+		}
+		var sig crypto.SignatureEd25519
+		copy(sig[:], sigBytes)
+		return sig, nil
+	case crypto.PubKeySecp256k1:
+		if len(sigBytes) == 0 {
+			return nil, fmt.Errorf("Secp256k1 signature is empty")
+		}
+		return crypto.SignatureSecp256k1(sigBytes), nil
+	}
+	return nil, fmt.Errorf("Unsupported public key type %T", pubKey)
+}
+
+// SignatureMatchesPubKey is true when sig is of the type made by the private
+// key of pubKey
+func SignatureMatchesPubKey(pubKey crypto.PubKey, sig crypto.Signature) bool {
+	switch pubKey.(type) {
+	case crypto.PubKeyEd25519:
+		_, ok := sig.(crypto.SignatureEd25519)
+		return ok
+	case crypto.PubKeySecp256k1:
+		_, ok := sig.(crypto.SignatureSecp256k1)
+		return ok
+	}
+	return false
+}
+
+// ValidateAddressScheme checks that scheme is a known address scheme, the
+// empty string meaning tendermint
+func ValidateAddressScheme(scheme string) error {
+	switch scheme {
+	case "", AddressSchemeTendermint, AddressSchemeEthereum:
+		return nil
+	}
+	return fmt.Errorf("Unknown address scheme '%s' (use %s or %s)", scheme,
+		AddressSchemeTendermint, AddressSchemeEthereum)
+}
+
+// AddressFromPubKey derives the address of pubKey under scheme
+func AddressFromPubKey(scheme string, pubKey crypto.PubKey) ([]byte, error) {
+	if err := ValidateAddressScheme(scheme); err != nil {
+		return nil, err
+	}
+	if secp256k1PubKey, ok := pubKey.(crypto.PubKeySecp256k1); ok &&
+		scheme == AddressSchemeEthereum {
+		return sha3.Sha3(secp256k1PubKey[:])[12:], nil
+	}
+	return pubKey.Address(), nil
+}
+
+// AddressMatchesPubKey is true when address is derived from pubKey under any
+// scheme, for clients that do not know the scheme of the chain
+func AddressMatchesPubKey(address []byte, pubKey crypto.PubKey) bool {
+	for _, scheme := range []string{AddressSchemeTendermint, AddressSchemeEthereum} {
+		derived, _ := AddressFromPubKey(scheme, pubKey)
+		if bytes.Equal(derived, address) {
+			return true
+		}
+	}
+	return false
+}
+
+// GenPrivKey generates a new private key of keyType
+func GenPrivKey(keyType KeyType) (crypto.PrivKey, error) {
+	switch keyType {
+	case KeyTypeEd25519:
+		return crypto.GenPrivKeyEd25519(), nil
+	case KeyTypeSecp256k1:
+		return crypto.GenPrivKeySecp256k1(), nil
+	}
+	return nil, fmt.Errorf("Unknown key type '%s'", keyType)
+}
+
+// PrivKeyFromBytes returns the private key with raw bytes privKeyBytes: 64
+// bytes for ed25519 or 32 bytes for secp256k1
+func PrivKeyFromBytes(privKeyBytes []byte) (crypto.PrivKey, error) {
+	switch len(privKeyBytes) {
+	case PrivKeyEd25519Length:
+		var privKey crypto.PrivKeyEd25519
+		copy(privKey[:], privKeyBytes)
+		return privKey, nil
+	case PrivKeySecp256k1Length:
+		var privKey crypto.PrivKeySecp256k1
+		copy(privKey[:], privKeyBytes)
+		return privKey, nil
+	}
+	return nil, fmt.Errorf("Private key has length %v but ed25519 private keys "+
+		"have length %v and secp256k1 private keys have length %v",
+		len(privKeyBytes), PrivKeyEd25519Length, PrivKeySecp256k1Length)
+}
+
+// PubKeyBytes returns the raw bytes of pubKey, without a type byte, as
+// handled by key servers
+func PubKeyBytes(pubKey crypto.PubKey) []byte {
+	switch pub := pubKey.(type) {
+	case crypto.PubKeyEd25519:
+		return pub[:]
+	case crypto.PubKeySecp256k1:
+		return pub[:]
+	}
+	return nil
+}
+
+// SignatureBytes returns the raw bytes of sig, without a type byte, as
+// handled by key servers
+func SignatureBytes(sig crypto.Signature) []byte {
+	switch s := sig.(type) {
+	case crypto.SignatureEd25519:
+		return s[:]
+	case crypto.SignatureSecp256k1:
+		return s
+	}
+	return nil
+}
@@ -69,6 +69,23 @@ func GenPrivAccount() *PrivAccount {
 	}
 }
 
+// Generates a new account with a private key of keyType
+func GenPrivAccountWithKeyType(keyType KeyType) (*PrivAccount, error) {
+	if keyType == KeyTypeEd25519 {
+		return GenPrivAccount(), nil
+	}
+	privKey, err := GenPrivKey(keyType)
+	if err != nil {
+		return nil, err
+	}
+	pubKey := privKey.PubKey()
+	return &PrivAccount{
+		Address: pubKey.Address(),
+		PubKey:  pubKey,
+		PrivKey: privKey,
+	}, nil
+}
+
 // Generates 32 priv key bytes from secret
 func GenPrivKeyBytesFromSecret(secret string) []byte {
 	return wire.BinarySha256(secret) // Not Ripemd160 because we want 32 bytes.
@@ -89,7 +106,19 @@ func GenPrivAccountFromSecret(secret string) *PrivAccount {
 	}
 }
 
+// Returns the account of a 64 byte ed25519 private key or a 32 byte secp256k1
+// private key
 func GenPrivAccountFromPrivKeyBytes(privKeyBytes []byte) *PrivAccount {
+	if len(privKeyBytes) == PrivKeySecp256k1Length {
+		var privKey crypto.PrivKeySecp256k1
+		copy(privKey[:], privKeyBytes)
+		pubKey := privKey.PubKey()
+		return &PrivAccount{
+			Address: pubKey.Address(),
+			PubKey:  pubKey,
+			PrivKey: privKey,
+		}
+	}
 	if len(privKeyBytes) != 64 {
 		sanity.PanicSanity(fmt.Sprintf("Expected 64 bytes but got %v", len(privKeyBytes)))
 	}
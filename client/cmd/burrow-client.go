@@ -59,9 +59,23 @@ func AddGlobalFlags() {
 func AddClientCommands() {
 	BurrowClientCmd.AddCommand(buildTransactionCommand())
 	BurrowClientCmd.AddCommand(buildStatusCommand())
+	BurrowClientCmd.AddCommand(buildQueryCommand())
+	BurrowClientCmd.AddCommand(buildDeployCommand())
+	BurrowClientCmd.AddCommand(buildRunCommand())
+	BurrowClientCmd.AddCommand(buildSignCommand())
+	BurrowClientCmd.AddCommand(buildBroadcastCommand())
+	BurrowClientCmd.AddCommand(buildKeysCommand())
+	BurrowClientCmd.AddCommand(buildAccountCommand())
+	BurrowClientCmd.AddCommand(buildStorageCommand())
+	BurrowClientCmd.AddCommand(buildNameCommand())
+	BurrowClientCmd.AddCommand(buildBlockCommand())
+	BurrowClientCmd.AddCommand(buildValidatorsCommand())
+	BurrowClientCmd.AddCommand(buildUnconfirmedCommand())
+	BurrowClientCmd.AddCommand(buildEventsCommand())
 
 	buildGenesisGenCommand()
 	BurrowClientCmd.AddCommand(GenesisGenCmd)
+	BurrowClientCmd.AddCommand(buildGenesisCommand())
 
 }
 
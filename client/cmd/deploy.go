@@ -0,0 +1,62 @@
+// Copyright 2017 Monax Industries Limited
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/hyperledger/burrow/client/methods"
+	"github.com/hyperledger/burrow/util"
+)
+
+func buildDeployCommand() *cobra.Command {
+	deployCmd := &cobra.Command{
+		Use:   "deploy",
+		Short: "burrow-client deploy --bin <bin file> --abi <abi file> [constructor args...]",
+		Long: `burrow-client deploy creates a contract from its bytecode, as output by
+solc --bin, and prints the address of the new contract on stdout:
+
+  address=$(burrow-client deploy --bin Token.bin --abi Token.abi "My Token" 1000)
+
+Constructor arguments are encoded using the contract's JSON ABI (as output by
+solc --abi). Array and tuple arguments are given as JSON arrays.
+
+Library link placeholders in the bytecode are replaced with the addresses given
+with --libraries as comma separated Name:Address pairs, where Name is the
+library name solc used, for example Math:<addr>,lib/Strings.sol:Strings:<addr>.
+
+The command waits for the contract creation to be committed and fails if there
+is no code at the new address.
+`,
+		Run: func(cmd *cobra.Command, args []string) {
+			err := methods.Deploy(clientDo, args)
+			if err != nil {
+				util.Fatalf("Could not deploy contract: %s", err)
+			}
+		},
+		PreRun: assertParameters,
+	}
+	addSigningPersistentFlags(deployCmd)
+	deployCmd.Flags().StringVarP(&clientDo.BinFlag, "bin", "", "", "specify the file of hex encoded contract bytecode")
+	deployCmd.Flags().StringVarP(&clientDo.AbiFlag, "abi", "", "", "specify the JSON ABI file of the contract to encode constructor arguments")
+	deployCmd.Flags().StringVarP(&clientDo.LibrariesFlag, "libraries", "", "", "specify library addresses to link as comma separated Name:Address pairs")
+	// NOTE: these flags share their ClientDo fields with tx call so must have
+	// the same defaults; Deploy fills in its own defaults for empty values
+	deployCmd.Flags().StringVarP(&clientDo.AmtFlag, "amt", "a", "", "specify an amount to endow the contract with (default 0)")
+	deployCmd.Flags().StringVarP(&clientDo.FeeFlag, "fee", "f", "", "specify the fee to send (default 0)")
+	deployCmd.Flags().StringVarP(&clientDo.GasFlag, "gas", "g", "", "specify the gas limit for contract creation (default 1000000)")
+
+	return deployCmd
+}
@@ -0,0 +1,58 @@
+// Copyright 2017 Monax Industries Limited
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/hyperledger/burrow/client/methods"
+	"github.com/hyperledger/burrow/util"
+)
+
+func buildEventsCommand() *cobra.Command {
+	eventsCmd := &cobra.Command{
+		Use:   "events",
+		Short: "burrow-client events --type <log|call|input|output|newblock> --address <addr>",
+		Long: `burrow-client events streams events from the node to stdout as JSON lines
+until interrupted, reconnecting when the connection is lost:
+
+  log       logs emitted by the contract at --address
+  call      calls to the contract at --address
+  input     transactions sent from the account at --address
+  output    transactions sent to the account at --address
+  newblock  new blocks
+
+Logs are decoded when the JSON ABI of the contract is given with --abi.
+
+With --from-height the input, output and newblock events of the blocks from
+that height are backfilled from the node before streaming new events, as are
+those of blocks committed while reconnecting. Backfilled events are derived
+from the transactions in blocks so are marked "backfilled" and carry no return
+value or exception. Log and call events cannot be backfilled.
+`,
+		Run: func(cmd *cobra.Command, args []string) {
+			err := methods.Events(clientDo)
+			if err != nil {
+				util.Fatalf("Could not stream events: %s", err)
+			}
+		},
+	}
+	eventsCmd.Flags().StringVarP(&clientDo.NodeAddrFlag, "node-addr", "", defaultNodeRpcAddress(), "set the burrow node rpc server address (default respects $BURROW_CLIENT_NODE_ADDRESS)")
+	eventsCmd.Flags().StringVarP(&clientDo.EventTypeFlag, "type", "", methods.EventTypeLog, "set the type of events to stream: log, call, input, output or newblock")
+	eventsCmd.Flags().StringVarP(&clientDo.EventAddressFlag, "address", "", "", "specify the address of the account or contract to stream events of")
+	eventsCmd.Flags().StringVarP(&clientDo.FromHeightFlag, "from-height", "", "", "backfill events from the block at this height")
+	eventsCmd.Flags().StringVarP(&clientDo.AbiFlag, "abi", "", "", "specify the JSON ABI file of the contract to decode logs")
+	return eventsCmd
+}
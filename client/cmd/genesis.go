@@ -17,8 +17,10 @@ package commands
 import (
 	"fmt"
 
+	"github.com/hyperledger/burrow/client/methods"
 	"github.com/hyperledger/burrow/common/sanity"
 	"github.com/hyperledger/burrow/genesis"
+	"github.com/hyperledger/burrow/util"
 
 	"github.com/spf13/cobra"
 )
@@ -27,14 +29,52 @@ import (
 var (
 	AccountsPathFlag   string
 	ValidatorsPathFlag string
+	SpecPathFlag       string
+	OutputDirFlag      string
+	GenesisJSONFlag    bool
 )
 
 var GenesisGenCmd = &cobra.Command{
 	Use:   "make-genesis",
 	Short: "burrow-client make-genesis creates a genesis.json with known inputs",
-	Long:  "burrow-client make-genesis creates a genesis.json with known inputs",
+	Long: `burrow-client make-genesis <chain id> --accounts accounts.csv --validators validators.csv
+creates a genesis.json with known inputs.
+
+burrow-client make-genesis --spec chain.toml [--output dir] instead generates
+keys for the groups of accounts and validators declared in chain.toml, writing
+genesis.json, a priv_validator.json for each validator and the keys of the
+other accounts to the output directory. An example spec:
+
+  chain_id = "consortium"
+
+  [permission_sets.developer]
+  grant = ["root"]
+
+  [permission_sets.validator]
+  grant = ["bond"]
+
+  [[validators]]
+  name = "validator"
+  count = 3
+  bond = 1000000
+  permissions = "validator"
+
+  [[accounts]]
+  name = "developer"
+  count = 5
+  amount = 1000000
+  permissions = "developer"
+
+Roles are declared as [[roles]] with a name, permissions (a permission set),
+inherits and admin, and held by listing them in the roles of a group.`,
 
 	Run: func(cmd *cobra.Command, args []string) {
+		if SpecPathFlag != "" {
+			if err := methods.MakeGenesisFromSpec(SpecPathFlag, OutputDirFlag); err != nil {
+				util.Fatalf("Could not make genesis: %s", err)
+			}
+			return
+		}
 		// TODO refactor to not panic
 		genesisFile, err := genesis.GenerateKnown(args[0], AccountsPathFlag, ValidatorsPathFlag)
 		if err != nil {
@@ -44,6 +84,41 @@ var GenesisGenCmd = &cobra.Command{
 	},
 }
 
+func buildGenesisCommand() *cobra.Command {
+	genesisCmd := &cobra.Command{
+		Use:   "genesis",
+		Short: "burrow-client genesis checks genesis files.",
+		Long:  "burrow-client genesis checks genesis files.",
+		Run:   func(cmd *cobra.Command, args []string) { cmd.Help() },
+	}
+
+	verifyCmd := &cobra.Command{
+		Use:   "verify",
+		Short: "burrow-client genesis verify <genesis.json> [--json]",
+		Long: `burrow-client genesis verify checks a genesis file could start a chain and
+lists each problem found with it by the JSON path of the offending value, such
+as accounts[2].amount, exiting with an error if there are any.
+
+A valid genesis file has its genesis hash printed, which is the genesis_hash
+nodes started from it report, so operators can compare their genesis files
+out-of-band before starting a chain. With --json the outcome is printed as
+{"valid": ..., "genesis_hash": ..., "problems": [{"path": ..., "message": ...}]}.
+`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(args) != 1 {
+				util.Fatalf("Expected the path of a genesis file")
+			}
+			if err := methods.VerifyGenesis(args[0], GenesisJSONFlag); err != nil {
+				util.Fatalf("Invalid genesis: %s", err)
+			}
+		},
+	}
+	verifyCmd.Flags().BoolVarP(&GenesisJSONFlag, "json", "", false, "print the outcome as JSON")
+
+	genesisCmd.AddCommand(verifyCmd)
+	return genesisCmd
+}
+
 func buildGenesisGenCommand() {
 	addGenesisPersistentFlags()
 }
@@ -51,4 +126,6 @@ func buildGenesisGenCommand() {
 func addGenesisPersistentFlags() {
 	GenesisGenCmd.Flags().StringVarP(&AccountsPathFlag, "accounts", "", "", "path to accounts.csv with the following params: (pubkey, starting balance, name, permissions, setbit")
 	GenesisGenCmd.Flags().StringVarP(&ValidatorsPathFlag, "validators", "", "", "path to validators.csv with the following params: (pubkey, starting balance, name, permissions, setbit")
+	GenesisGenCmd.Flags().StringVarP(&SpecPathFlag, "spec", "", "", "path to a TOML genesis spec declaring groups of accounts and validators to generate keys for")
+	GenesisGenCmd.Flags().StringVarP(&OutputDirFlag, "output", "", ".", "directory to write genesis.json and the generated keys to when using --spec")
 }
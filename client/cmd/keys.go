@@ -0,0 +1,66 @@
+// Copyright 2017 Monax Industries Limited
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/hyperledger/burrow/client/methods"
+	"github.com/hyperledger/burrow/util"
+)
+
+func buildKeysCommand() *cobra.Command {
+	keysCmd := &cobra.Command{
+		Use:   "keys",
+		Short: "burrow-client keys manages the keys of a keystore directory.",
+		Long:  "burrow-client keys manages the keys of a keystore directory.",
+		Run:   func(cmd *cobra.Command, args []string) { cmd.Help() },
+	}
+
+	genCmd := &cobra.Command{
+		Use:   "gen",
+		Short: "burrow-client keys gen --key-type <ed25519|secp256k1>",
+		Long: `burrow-client keys gen generates a new key in the --keystore-dir directory,
+encrypted with --keystore-password, and prints its address.
+
+Account keys are ed25519 keys by default. Secp256k1 keys, as used by Ethereum
+wallets, are written like Ethereum keystore files. Their address depends on the
+address scheme of the chain they are used on, so --address-scheme must be
+given: ethereum for their Ethereum address or tendermint for the ripemd160
+address of the key. Generate keys held by monax-keys with monax-keys itself.
+`,
+		Run: func(cmd *cobra.Command, args []string) {
+			err := methods.GenerateKey(clientDo)
+			if err != nil {
+				util.Fatalf("Could not generate key: %s", err)
+			}
+		},
+	}
+	genCmd.Flags().StringVarP(&clientDo.KeyTypeFlag, "key-type", "", defaultKeyType(), "set the type of key to generate: ed25519 or secp256k1 (default respects $BURROW_CLIENT_KEY_TYPE)")
+	genCmd.Flags().StringVarP(&clientDo.AddressSchemeFlag, "address-scheme", "", defaultAddressScheme(), "set the address scheme of the chain the key is used on: tendermint or ethereum (default respects $BURROW_CLIENT_ADDRESS_SCHEME)")
+	genCmd.Flags().StringVarP(&clientDo.KeyStoreDirFlag, "keystore-dir", "", defaultKeyStoreDir(), "set the directory of encrypted JSON keystore files (default respects $BURROW_CLIENT_KEYSTORE_DIR)")
+	genCmd.Flags().StringVarP(&clientDo.KeyStorePasswordFlag, "keystore-password", "", defaultKeyStorePassword(), "set the password to encrypt the key with; prefer $BURROW_CLIENT_KEYSTORE_PASSWORD to keep it out of shell history")
+
+	keysCmd.AddCommand(genCmd)
+	return keysCmd
+}
+
+func defaultKeyType() string {
+	return setDefaultString("BURROW_CLIENT_KEY_TYPE", "ed25519")
+}
+
+func defaultAddressScheme() string {
+	return setDefaultString("BURROW_CLIENT_ADDRESS_SCHEME", "")
+}
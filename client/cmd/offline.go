@@ -0,0 +1,97 @@
+// Copyright 2017 Monax Industries Limited
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/hyperledger/burrow/client/methods"
+	"github.com/hyperledger/burrow/util"
+)
+
+// Signing offline happens in three steps, each of which logs the transaction
+// hash for cross-checking:
+//
+//	burrow-client tx send --unsigned -o tx.json ...  (online, to get the nonce)
+//	burrow-client sign tx.json                       (offline, with the key)
+//	burrow-client broadcast tx.json                  (online)
+//
+// A transaction spending from several accounts is signed by each key holder in
+// turn with burrow-client sign --addr <address> tx.json, and cannot be
+// broadcast until every input is signed.
+
+func buildSignCommand() *cobra.Command {
+	signCmd := &cobra.Command{
+		Use:   "sign <tx.json>",
+		Short: "burrow-client sign signs a transaction written by burrow-client tx --unsigned.",
+		Long: `burrow-client sign signs a transaction written by burrow-client tx --unsigned,
+without connecting to a node, so that it can be run on an offline machine.
+
+The transaction is read from the file given, or from stdin for '-', and the
+signed transaction is written back to that file or to --output. Broadcast it
+with burrow-client broadcast. The transaction hash is logged to check that the
+same transaction is handled at every step.
+`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(args) != 1 {
+				util.Fatalf("Please provide a single transaction file to sign")
+			}
+			err := methods.Sign(clientDo, args[0])
+			if err != nil {
+				util.Fatalf("Could not sign transaction: %s", err)
+			}
+		},
+		PreRun: func(cmd *cobra.Command, args []string) {
+			assertChainId()
+			assertKeysBackend()
+		},
+	}
+	addKeysPersistentFlags(signCmd)
+	signCmd.Flags().StringVarP(&clientDo.ChainidFlag, "chain-id", "", defaultChainId(), "specify the chainID (default respects $CHAIN_ID)")
+	signCmd.Flags().StringVarP(&clientDo.AddrFlag, "addr", "", defaultAddress(), "specify the address of the input to sign in a transaction with several inputs (default respects $BURROW_CLIENT_ADDRESS, otherwise every input whose key is available)")
+	signCmd.Flags().StringVarP(&clientDo.OutputFlag, "output", "o", "", "specify the file to write the signed transaction to, or - for stdout (default the input file)")
+
+	return signCmd
+}
+
+func buildBroadcastCommand() *cobra.Command {
+	broadcastCmd := &cobra.Command{
+		Use:   "broadcast <tx.json>",
+		Short: "burrow-client broadcast broadcasts a transaction signed with burrow-client sign.",
+		Long: `burrow-client broadcast checks the signatures of a transaction signed with
+burrow-client sign and broadcasts it to a node.
+
+The transaction is read from the file given, or from stdin for '-'.
+`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(args) != 1 {
+				util.Fatalf("Please provide a single transaction file to broadcast")
+			}
+			err := methods.Broadcast(clientDo, args[0])
+			if err != nil {
+				util.Fatalf("Could not broadcast transaction: %s", err)
+			}
+		},
+		PreRun: func(cmd *cobra.Command, args []string) {
+			assertChainId()
+			assertNodeAddress()
+		},
+	}
+	broadcastCmd.Flags().StringVarP(&clientDo.NodeAddrFlag, "node-addr", "", defaultNodeRpcAddress(), "set the burrow node rpc server address (default respects $BURROW_CLIENT_NODE_ADDRESS)")
+	broadcastCmd.Flags().StringVarP(&clientDo.ChainidFlag, "chain-id", "", defaultChainId(), "specify the chainID (default respects $CHAIN_ID)")
+	broadcastCmd.Flags().BoolVarP(&clientDo.WaitFlag, "wait", "w", true, "wait for the transaction to be committed in a block")
+
+	return broadcastCmd
+}
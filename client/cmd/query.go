@@ -0,0 +1,55 @@
+// Copyright 2017 Monax Industries Limited
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/hyperledger/burrow/client/methods"
+	"github.com/hyperledger/burrow/util"
+)
+
+func buildQueryCommand() *cobra.Command {
+	queryCmd := &cobra.Command{
+		Use:   "query",
+		Short: "burrow-client query --to <contract addr> --abi <abi file> --function <function> [args...]",
+		Long: `burrow-client query runs a call against a contract on the node without
+sending a transaction and prints the return value.
+
+The call data is either given directly with --data or encoded from a function
+in the contract's JSON ABI (as output by solc --abi) and its arguments:
+
+  burrow-client query --to <contract addr> --abi Token.abi --function balanceOf <addr>
+
+The function may be given by name or, when overloaded, by signature such as
+'transfer(address,uint256)'. Array and tuple arguments are given as JSON arrays.
+When an ABI is used the return values are decoded.
+`,
+		Run: func(cmd *cobra.Command, args []string) {
+			err := methods.Query(clientDo, args)
+			if err != nil {
+				util.Fatalf("Could not complete query: %s", err)
+			}
+		},
+	}
+	queryCmd.Flags().StringVarP(&clientDo.NodeAddrFlag, "node-addr", "", defaultNodeRpcAddress(), "set the burrow node rpc server address (default respects $BURROW_CLIENT_NODE_ADDRESS)")
+	queryCmd.Flags().StringVarP(&clientDo.AddrFlag, "addr", "", defaultAddress(), "specify the caller address (default respects $BURROW_CLIENT_ADDRESS)")
+	queryCmd.Flags().StringVarP(&clientDo.ToFlag, "to", "t", "", "specify the address of the contract to query")
+	queryCmd.Flags().StringVarP(&clientDo.DataFlag, "data", "", "", "specify the hex call data")
+	queryCmd.Flags().StringVarP(&clientDo.AbiFlag, "abi", "", "", "specify the JSON ABI file of the contract to encode the call and decode the return value")
+	queryCmd.Flags().StringVarP(&clientDo.FunctionFlag, "function", "", "", "specify the function to call by name or signature (requires --abi)")
+
+	return queryCmd
+}
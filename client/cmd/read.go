@@ -0,0 +1,144 @@
+// Copyright 2017 Monax Industries Limited
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/hyperledger/burrow/client/methods"
+	"github.com/hyperledger/burrow/util"
+)
+
+// The read commands print chain state from the node in a table, or as JSON
+// with --format json, without sending transactions
+
+func buildAccountCommand() *cobra.Command {
+	accountCmd := &cobra.Command{
+		Use:   "account [address]",
+		Short: "burrow-client account prints an account.",
+		Long: `burrow-client account prints the balance, sequence, code and permissions of
+the account at address, or at --addr when no address is given.
+`,
+		Run: func(cmd *cobra.Command, args []string) {
+			err := methods.GetAccount(clientDo, args)
+			if err != nil {
+				util.Fatalf("Could not get account: %s", err)
+			}
+		},
+	}
+	addReadFlags(accountCmd)
+	accountCmd.Flags().StringVarP(&clientDo.AddrFlag, "addr", "", defaultAddress(), "specify the account address (default respects $BURROW_CLIENT_ADDRESS)")
+	return accountCmd
+}
+
+func buildStorageCommand() *cobra.Command {
+	storageCmd := &cobra.Command{
+		Use:   "storage [address]",
+		Short: "burrow-client storage prints the storage of a contract.",
+		Long: `burrow-client storage prints the storage keys and values of the contract at
+address, or at --addr when no address is given.
+`,
+		Run: func(cmd *cobra.Command, args []string) {
+			err := methods.GetStorage(clientDo, args)
+			if err != nil {
+				util.Fatalf("Could not get storage: %s", err)
+			}
+		},
+	}
+	addReadFlags(storageCmd)
+	storageCmd.Flags().StringVarP(&clientDo.AddrFlag, "addr", "", defaultAddress(), "specify the contract address (default respects $BURROW_CLIENT_ADDRESS)")
+	return storageCmd
+}
+
+func buildNameCommand() *cobra.Command {
+	nameCmd := &cobra.Command{
+		Use:   "name [name]",
+		Short: "burrow-client name prints name registry entries.",
+		Long: `burrow-client name prints the owner, expiry and data of the name registry
+entry for name, or of all entries when no name is given.
+`,
+		Run: func(cmd *cobra.Command, args []string) {
+			err := methods.GetName(clientDo, args)
+			if err != nil {
+				util.Fatalf("Could not get name registry entries: %s", err)
+			}
+		},
+	}
+	addReadFlags(nameCmd)
+	return nameCmd
+}
+
+func buildBlockCommand() *cobra.Command {
+	blockCmd := &cobra.Command{
+		Use:   "block [height]",
+		Short: "burrow-client block prints a block.",
+		Long: `burrow-client block prints the header and transactions of the block at
+height, or of the latest block when no height is given.
+`,
+		Run: func(cmd *cobra.Command, args []string) {
+			err := methods.GetBlock(clientDo, args)
+			if err != nil {
+				util.Fatalf("Could not get block: %s", err)
+			}
+		},
+	}
+	addReadFlags(blockCmd)
+	return blockCmd
+}
+
+func buildValidatorsCommand() *cobra.Command {
+	validatorsCmd := &cobra.Command{
+		Use:   "validators",
+		Short: "burrow-client validators prints the validators.",
+		Long: `burrow-client validators prints the bonded and unbonding validators with
+their voting power.
+`,
+		Run: func(cmd *cobra.Command, args []string) {
+			err := methods.ListValidators(clientDo)
+			if err != nil {
+				util.Fatalf("Could not list validators: %s", err)
+			}
+		},
+	}
+	addReadFlags(validatorsCmd)
+	return validatorsCmd
+}
+
+func buildUnconfirmedCommand() *cobra.Command {
+	unconfirmedCmd := &cobra.Command{
+		Use:   "unconfirmed",
+		Short: "burrow-client unconfirmed prints the unconfirmed transactions.",
+		Long: `burrow-client unconfirmed prints the transactions waiting in the mempool of
+the node to be included in a block.
+`,
+		Run: func(cmd *cobra.Command, args []string) {
+			err := methods.ListUnconfirmed(clientDo)
+			if err != nil {
+				util.Fatalf("Could not list unconfirmed transactions: %s", err)
+			}
+		},
+	}
+	addReadFlags(unconfirmedCmd)
+	return unconfirmedCmd
+}
+
+func addReadFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&clientDo.NodeAddrFlag, "node-addr", "", defaultNodeRpcAddress(), "set the burrow node rpc server address (default respects $BURROW_CLIENT_NODE_ADDRESS)")
+	cmd.Flags().StringVarP(&clientDo.FormatFlag, "format", "", defaultFormat(), "set the output format: table or json (default respects $BURROW_CLIENT_FORMAT)")
+}
+
+func defaultFormat() string {
+	return setDefaultString("BURROW_CLIENT_FORMAT", methods.FormatTable)
+}
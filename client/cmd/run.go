@@ -0,0 +1,83 @@
+// Copyright 2017 Monax Industries Limited
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/hyperledger/burrow/client/methods"
+	"github.com/hyperledger/burrow/util"
+)
+
+func buildRunCommand() *cobra.Command {
+	runCmd := &cobra.Command{
+		Use:   "run <jobs.yaml>",
+		Short: "burrow-client run executes the jobs in a YAML jobs file.",
+		Long: `burrow-client run executes the jobs in a YAML jobs file and writes their
+results to a JSON results file.
+
+Each job has a name and one of the actions deploy, call, query, send,
+register (a name registry entry), permission, or assert:
+
+  account: <address of the account that signs transactions>
+  variables:
+    bob: <address>
+  jobs:
+  - name: token
+    deploy:
+      bin: Token.bin
+      abi: Token.abi
+      libraries: {Math: <address>}
+      args: ["My Token", 1000]
+  - name: transfer
+    call: {to: $token, abi: Token.abi, function: transfer, args: [$bob, 100]}
+  - name: balance
+    query: {to: $token, abi: Token.abi, function: balanceOf, args: [$bob]}
+  - name: checkBalance
+    assert: {value: $balance, equals: 100}
+  - name: pay
+    send: {to: $bob, amount: 10}
+  - name: registerToken
+    register: {name: token, data: $token, amount: 100}
+  - name: allowBob
+    permission: {function: setBase, args: [$bob, call, true]}
+
+Jobs refer to variables, the results of earlier jobs, and $account with $name
+or ${name}. The result of a deploy is the contract address, of a call or query
+its first return value, and of other transactions the tx hash. Decoded return
+values are also available by output name or index as $name.output. Relative
+bin and abi paths are resolved against the directory of the jobs file.
+
+Jobs run as soon as the jobs they refer to have succeeded. Nonces are assigned
+in file order from the account's current sequence and tracked locally, so
+deployed contract addresses are reproducible. The run stops at the first
+failure.
+`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(args) != 1 {
+				util.Fatalf("Please provide a single jobs file to run")
+			}
+			err := methods.Run(clientDo, args[0])
+			if err != nil {
+				util.Fatalf("Could not complete jobs: %s", err)
+			}
+		},
+		PreRun: assertParameters,
+	}
+	addSigningPersistentFlags(runCmd)
+	runCmd.Flags().StringVarP(&clientDo.ResultsFileFlag, "results", "", "", "specify the file to write job results to (default <jobs file>.results.json)")
+
+	return runCmd
+}
@@ -20,11 +20,12 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/hyperledger/burrow/client/methods"
+	"github.com/hyperledger/burrow/keys"
 	"github.com/hyperledger/burrow/util"
 )
 
 func buildTransactionCommand() *cobra.Command {
-	// Transaction command has subcommands send, name, call, bond,
+	// Transaction command has subcommands send, multisend, name, transfer-name, call, bond,
 	// unbond, rebond, permissions. Dupeout transaction is not accessible through the command line.
 	transactionCmd := &cobra.Command{
 		Use:   "tx",
@@ -51,13 +52,57 @@ func buildTransactionCommand() *cobra.Command {
 	sendCmd.Flags().StringVarP(&clientDo.AmtFlag, "amt", "a", "", "specify an amount")
 	sendCmd.Flags().StringVarP(&clientDo.ToFlag, "to", "t", "", "specify an address to send to")
 
+	// SendTx with several inputs and outputs
+	multisendCmd := &cobra.Command{
+		Use:   "multisend",
+		Short: "burrow-client tx multisend --from <addr>:<amt>[:<nonce>],... --to <addr>:<amt>,...",
+		Long: `burrow-client tx multisend --from <addr>:<amt>[:<nonce>],... --to <addr>:<amt>,...
+
+Sends a single transaction spending from each of the --from accounts to each
+of the --to accounts. Any difference between the totals is paid as a fee.
+Nonces that are not given are fetched from the node.
+
+When the keys of the inputs are held by different people write the transaction
+with --unsigned and pass the file around for each key holder to sign their
+input with burrow-client sign --addr <addr> before broadcasting it with
+burrow-client broadcast.
+`,
+		Run: func(cmd *cobra.Command, args []string) {
+			err := methods.SendMulti(clientDo)
+			if err != nil {
+				util.Fatalf("Could not complete send: %s", err)
+			}
+		},
+		PreRun: func(cmd *cobra.Command, args []string) {
+			assertChainId()
+			assertNodeAddress()
+			if !clientDo.UnsignedFlag {
+				assertKeysBackend()
+			}
+		},
+	}
+	multisendCmd.Flags().StringVarP(&clientDo.InputsFlag, "from", "", "", "specify the inputs as comma separated <address>:<amount>[:<nonce>]")
+	multisendCmd.Flags().StringVarP(&clientDo.OutputsFlag, "to", "t", "", "specify the outputs as comma separated <address>:<amount>")
+
 	// NameTx
 	nameCmd := &cobra.Command{
 		Use:   "name",
-		Short: "burrow-client tx name --amt <amt> --name <name> --data <data>",
-		Long:  "burrow-client tx name --amt <amt> --name <name> --data <data>",
+		Short: "burrow-client tx name --amt <amt> --fee <fee> --name <name> --data <data>",
+		Long: `burrow-client tx name --amt <amt> --fee <fee> --name <name> --data <data>
+
+Registers, updates or extends the name registry entry for --name. The amount
+beyond the fee pays for the number of blocks the entry lasts. Sending no data
+and an amount covering only the fee removes the entry.
+
+Names are file system like: once a name such as 'a' is registered only an
+account controlling it may register names nested beneath it, such as 'a/b' or
+'a/b/c', and the owner of 'a' may remove or transfer them.
+`,
 		Run: func(cmd *cobra.Command, args []string) {
-			// transaction.Name(clientDo)
+			err := methods.Name(clientDo)
+			if err != nil {
+				util.Fatalf("Could not complete name: %s", err)
+			}
 		},
 		PreRun: assertParameters,
 	}
@@ -67,13 +112,45 @@ func buildTransactionCommand() *cobra.Command {
 	nameCmd.Flags().StringVarP(&clientDo.DataFileFlag, "data-file", "", "", "specify a file with some data")
 	nameCmd.Flags().StringVarP(&clientDo.FeeFlag, "fee", "f", "", "specify the fee to send")
 
+	// NameTransferTx
+	nameTransferCmd := &cobra.Command{
+		Use:   "transfer-name",
+		Short: "burrow-client tx transfer-name --amt <amt> --name <name> --to <addr>",
+		Long: `burrow-client tx transfer-name --amt <amt> --name <name> --to <addr>
+
+Hands the name registry entry for --name to the account at --to. Can be sent by
+the owner of the name or of any name it is nested beneath. The amount is taken
+as a fee.
+`,
+		Run: func(cmd *cobra.Command, args []string) {
+			err := methods.TransferName(clientDo)
+			if err != nil {
+				util.Fatalf("Could not complete name transfer: %s", err)
+			}
+		},
+		PreRun: assertParameters,
+	}
+	nameTransferCmd.Flags().StringVarP(&clientDo.AmtFlag, "amt", "a", "", "specify an amount")
+	nameTransferCmd.Flags().StringVarP(&clientDo.NameFlag, "name", "n", "", "specify a name")
+	nameTransferCmd.Flags().StringVarP(&clientDo.ToFlag, "to", "t", "", "specify the address of the new owner")
+
 	// CallTx
 	callCmd := &cobra.Command{
 		Use:   "call",
 		Short: "burrow-client tx call --amt <amt> --fee <fee> --gas <gas> --to <contract addr> --data <data>",
-		Long:  "burrow-client tx call --amt <amt> --fee <fee> --gas <gas> --to <contract addr> --data <data>",
+		Long: `burrow-client tx call --amt <amt> --fee <fee> --gas <gas> --to <contract addr> --data <data>
+
+Instead of raw --data the call can be encoded from a function in the contract's
+JSON ABI (as output by solc --abi) and its arguments, in which case the return
+value and any events the contract emits are decoded:
+
+  burrow-client tx call --amt 0 --fee 0 --gas 100000 --to <contract addr> --abi Token.abi --function transfer <addr> 100
+
+The function may be given by name or, when overloaded, by signature such as
+'transfer(address,uint256)'. Array and tuple arguments are given as JSON arrays.
+`,
 		Run: func(cmd *cobra.Command, args []string) {
-			err := methods.Call(clientDo)
+			err := methods.Call(clientDo, args)
 			if err != nil {
 				util.Fatalf("Could not complete call: %s", err)
 			}
@@ -85,6 +162,8 @@ func buildTransactionCommand() *cobra.Command {
 	callCmd.Flags().StringVarP(&clientDo.DataFlag, "data", "", "", "specify some data")
 	callCmd.Flags().StringVarP(&clientDo.FeeFlag, "fee", "f", "", "specify the fee to send")
 	callCmd.Flags().StringVarP(&clientDo.GasFlag, "gas", "g", "", "specify the gas limit for a CallTx")
+	callCmd.Flags().StringVarP(&clientDo.AbiFlag, "abi", "", "", "specify the JSON ABI file of the contract to encode the call and decode the result")
+	callCmd.Flags().StringVarP(&clientDo.FunctionFlag, "function", "", "", "specify the function to call by name or signature (requires --abi)")
 
 	// BondTx
 	bondCmd := &cobra.Command{
@@ -128,29 +207,90 @@ func buildTransactionCommand() *cobra.Command {
 	// PermissionsTx
 	permissionsCmd := &cobra.Command{
 		Use:   "permission",
-		Short: "burrow-client tx perm <function name> <args ...>",
-		Long:  "burrow-client tx perm <function name> <args ...>",
+		Short: "burrow-client tx permission <function name> <args ...>",
+		Long: `burrow-client tx permission <function name> <args ...>
+
+Functions and their arguments are:
+  setBase <address> <permission> <true|false> [expires]
+  unsetBase <address> <permission>
+  setGlobal <permission> <true|false>
+  addRole <address> <role> [expires]
+  rmRole <address> <role>
+  setRole <role> <permissions> <inherits> <admin>
+  allowCall <address> <contract>
+  disallowCall <address> <contract>
+  unrestrictCalls <address>
+  allowRoleCall <role> <contract>
+  disallowRoleCall <role> <contract>
+  unrestrictRoleCalls <role>
+  approve <proposal>
+
+where permission is one of root, send, call, create_contract, create_account,
+bond, name, has_base, set_base, unset_base, set_global, has_role, add_role,
+rm_role, set_role, set_call_allow_list, or govern.
+
+setBase and addRole take an optional block height at which the permission or
+role lapses, holding for blocks up to and including it.
+
+setRole defines a role: permissions is a comma separated list of the
+permissions its members get (prefix a permission with ! to deny it instead),
+inherits a comma separated list of roles its members also have, and admin the
+role whose members may add and remove members of the role without add_role or
+rm_role. Pass "" for no permissions, inherited roles, or admin.
+
+allowCall restricts an account (allowRoleCall the members of a role) to calling
+only the contracts it is allowed, disallowCall removes a contract from those
+allowed, and unrestrictCalls lifts the restriction.
+
+On chains with governance enabled in their genesis params, setGlobal, granting
+root or govern with setBase, and changing the admin or granting root or govern
+with setRole only propose the change. It is made once enough accounts with the
+govern permission approve it with approve, passing the hash of the proposing
+transaction, before the proposal expires.`,
 		Run: func(cmd *cobra.Command, args []string) {
-			// transaction.Permsissions(clientDo)
+			if len(args) == 0 {
+				cmd.Help()
+				util.Fatalf("Please provide a permission function.")
+			}
+			err := methods.Permissions(clientDo, args[0], args[1:])
+			if err != nil {
+				util.Fatalf("Could not complete permissions: %s", err)
+			}
 		},
 		PreRun: assertParameters,
 	}
 
-	transactionCmd.AddCommand(sendCmd, nameCmd, callCmd, bondCmd, unbondCmd, rebondCmd, permissionsCmd)
+	transactionCmd.AddCommand(sendCmd, multisendCmd, nameCmd, nameTransferCmd, callCmd, bondCmd, unbondCmd, rebondCmd, permissionsCmd)
 	return transactionCmd
 }
 
 func addTransactionPersistentFlags(transactionCmd *cobra.Command) {
-	transactionCmd.PersistentFlags().StringVarP(&clientDo.SignAddrFlag, "sign-addr", "", defaultKeyDaemonAddress(), "set monax-keys daemon address (default respects $BURROW_CLIENT_SIGN_ADDRESS)")
+	addSigningPersistentFlags(transactionCmd)
+
+	// transactionCmd.PersistentFlags().BoolVarP(&clientDo.SignFlag, "sign", "s", false, "sign the transaction using the monax-keys daemon")
+	transactionCmd.PersistentFlags().BoolVarP(&clientDo.BroadcastFlag, "broadcast", "b", true, "broadcast the transaction to the blockchain")
+	transactionCmd.PersistentFlags().BoolVarP(&clientDo.WaitFlag, "wait", "w", true, "wait for the transaction to be committed in a block")
+	transactionCmd.PersistentFlags().BoolVarP(&clientDo.UnsignedFlag, "unsigned", "", false, "write the transaction to --output to sign offline with burrow-client sign instead of signing and broadcasting it (give --pubkey if the key is not available)")
+	transactionCmd.PersistentFlags().StringVarP(&clientDo.OutputFlag, "output", "o", "", "specify the file to write an --unsigned transaction to (default stdout)")
+}
+
+// addSigningPersistentFlags adds the flags needed to form and sign a
+// transaction and send it to a node
+func addSigningPersistentFlags(transactionCmd *cobra.Command) {
+	addKeysPersistentFlags(transactionCmd)
 	transactionCmd.PersistentFlags().StringVarP(&clientDo.NodeAddrFlag, "node-addr", "", defaultNodeRpcAddress(), "set the burrow node rpc server address (default respects $BURROW_CLIENT_NODE_ADDRESS)")
 	transactionCmd.PersistentFlags().StringVarP(&clientDo.PubkeyFlag, "pubkey", "", defaultPublicKey(), "specify the public key to sign with (defaults to $BURROW_CLIENT_PUBLIC_KEY)")
 	transactionCmd.PersistentFlags().StringVarP(&clientDo.AddrFlag, "addr", "", defaultAddress(), "specify the account address (for which the public key can be found at monax-keys) (default respects $BURROW_CLIENT_ADDRESS)")
 	transactionCmd.PersistentFlags().StringVarP(&clientDo.ChainidFlag, "chain-id", "", defaultChainId(), "specify the chainID (default respects $CHAIN_ID)")
 	transactionCmd.PersistentFlags().StringVarP(&clientDo.NonceFlag, "nonce", "", "", "specify the nonce to use for the transaction (should equal the sender account's nonce + 1)")
+}
 
-	// transactionCmd.PersistentFlags().BoolVarP(&clientDo.SignFlag, "sign", "s", false, "sign the transaction using the monax-keys daemon")
-	transactionCmd.PersistentFlags().BoolVarP(&clientDo.BroadcastFlag, "broadcast", "b", true, "broadcast the transaction to the blockchain")
-	transactionCmd.PersistentFlags().BoolVarP(&clientDo.WaitFlag, "wait", "w", true, "wait for the transaction to be committed in a block")
+// addKeysPersistentFlags adds the flags that select the signing backend
+func addKeysPersistentFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringVarP(&clientDo.KeysBackendFlag, "keys-backend", "", defaultKeysBackend(), "set the signing backend: monax-keys, keystore, or remote (default respects $BURROW_CLIENT_KEYS_BACKEND)")
+	cmd.PersistentFlags().StringVarP(&clientDo.SignAddrFlag, "sign-addr", "", defaultKeyDaemonAddress(), "set monax-keys daemon address, or the unix:// or tcp:// address of a remote signer (default respects $BURROW_CLIENT_SIGN_ADDRESS)")
+	cmd.PersistentFlags().StringVarP(&clientDo.KeyStoreDirFlag, "keystore-dir", "", defaultKeyStoreDir(), "set the directory of encrypted JSON keystore files for the keystore backend (default respects $BURROW_CLIENT_KEYSTORE_DIR)")
+	cmd.PersistentFlags().StringVarP(&clientDo.KeyStorePasswordFlag, "keystore-password", "", defaultKeyStorePassword(), "set the password of the keystore files; prefer $BURROW_CLIENT_KEYSTORE_PASSWORD to keep it out of shell history")
 }
 
 //------------------------------------------------------------------------------
@@ -160,6 +300,18 @@ func defaultChainId() string {
 	return setDefaultString("CHAIN_ID", "")
 }
 
+func defaultKeysBackend() string {
+	return setDefaultString("BURROW_CLIENT_KEYS_BACKEND", keys.MonaxKeysBackend)
+}
+
+func defaultKeyStoreDir() string {
+	return setDefaultString("BURROW_CLIENT_KEYSTORE_DIR", "")
+}
+
+func defaultKeyStorePassword() string {
+	return setDefaultString("BURROW_CLIENT_KEYSTORE_PASSWORD", "")
+}
+
 func defaultKeyDaemonAddress() string {
 	return setDefaultString("BURROW_CLIENT_SIGN_ADDRESS", "http://127.0.0.1:4767")
 }
@@ -180,10 +332,18 @@ func defaultAddress() string {
 // Helper functions
 
 func assertParameters(cmd *cobra.Command, args []string) {
+	assertChainId()
+	assertNodeAddress()
+	assertKeysBackend()
+}
+
+func assertChainId() {
 	if clientDo.ChainidFlag == "" {
 		util.Fatalf(`Please provide a chain id either through the flag --chain-id or environment variable $CHAIN_ID.`)
 	}
+}
 
+func assertNodeAddress() {
 	if !strings.HasPrefix(clientDo.NodeAddrFlag, "tcp://") &&
 		!strings.HasPrefix(clientDo.NodeAddrFlag, "unix://") {
 		// TODO: [ben] go-rpc will deprecate reformatting; also it is bad practice to auto-correct for this;
@@ -191,6 +351,27 @@ func assertParameters(cmd *cobra.Command, args []string) {
 		// below
 		util.Fatalf(`Please use fully formed listening address for the node, including the tcp:// or unix:// prefix.`)
 	}
+}
+
+func assertKeysBackend() {
+	switch clientDo.KeysBackendFlag {
+	case keys.MonaxKeysBackend:
+	case keys.KeyStoreBackend:
+		if clientDo.KeyStoreDirFlag == "" {
+			util.Fatalf(`Please provide the keystore directory either through the flag --keystore-dir or environment variable $BURROW_CLIENT_KEYSTORE_DIR.`)
+		}
+		return
+	case keys.RemoteSignerBackend:
+		if !strings.HasPrefix(clientDo.SignAddrFlag, "tcp://") &&
+			!strings.HasPrefix(clientDo.SignAddrFlag, "unix://") {
+			util.Fatalf(`Please use fully formed address for the remote signer, including the tcp:// or unix:// prefix.`)
+		}
+		return
+	default:
+		util.Fatalf("Unknown keys backend '%s', expected one of %s, %s, or %s.",
+			clientDo.KeysBackendFlag, keys.MonaxKeysBackend, keys.KeyStoreBackend,
+			keys.RemoteSignerBackend)
+	}
 
 	if !strings.HasPrefix(clientDo.SignAddrFlag, "http://") {
 		// NOTE: [ben] we preserve the auto-correction here as it is a simple http request-response to the key server.
@@ -0,0 +1,298 @@
+// Copyright 2017 Monax Industries Limited
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hyperledger/burrow/logging"
+	"github.com/hyperledger/burrow/txs"
+	tm_types "github.com/tendermint/tendermint/types"
+)
+
+// An Event received from the node or, when backfilled, derived from the
+// transactions of a block
+type Event struct {
+	Event string        `json:"event"`
+	Data  txs.EventData `json:"data"`
+	// Height of the block of the event when known, that is for NewBlock and
+	// backfilled events
+	Height int `json:"height,omitempty"`
+	// Backfilled events are derived from the transactions of a block rather
+	// than from their execution, so carry neither return values nor
+	// exceptions, and are derived even for transactions that failed
+	Backfilled bool `json:"backfilled,omitempty"`
+}
+
+// How long to wait before reconnecting the websocket of an event stream
+var reconnectWait = 2 * time.Second
+
+// StreamEvents sends the events with eventId on the returned channel until
+// stop is closed, after which the channel is closed.
+//
+// When fromHeight is positive the events of the blocks from fromHeight up to
+// the latest block are backfilled from the node before live events are sent.
+// When the websocket connection is lost it is reconnected, and the events of
+// the blocks committed meanwhile are backfilled. Only the NewBlock, input and
+// output events of accounts can be derived from blocks; the events of calls
+// and logs are only known when the transactions are executed so cannot be
+// backfilled, and events of these types are missed while disconnected. The
+// events of a block being committed while connecting may be sent twice.
+func StreamEvents(nodeClient NodeClient, eventId string, fromHeight int,
+	stop <-chan struct{}) (<-chan Event, error) {
+	if fromHeight > 0 && !canBackfill(eventId) {
+		return nil, fmt.Errorf("Cannot backfill %s events from height %v since "+
+			"they are not recorded in blocks", eventId, fromHeight)
+	}
+	stream := &eventStream{
+		nodeClient:  nodeClient,
+		eventId:     eventId,
+		lastHeight:  fromHeight - 1,
+		heightKnown: fromHeight > 0,
+		events:      make(chan Event),
+		stop:        stop,
+	}
+	go stream.run()
+	return stream.events, nil
+}
+
+type eventStream struct {
+	nodeClient NodeClient
+	eventId    string
+	// Height of the last block whose events have been sent, once known
+	lastHeight  int
+	heightKnown bool
+	events      chan Event
+	stop        <-chan struct{}
+}
+
+func (stream *eventStream) run() {
+	defer close(stream.events)
+	logger := stream.nodeClient.Logger()
+	for {
+		err := stream.connect()
+		if stream.stopped() {
+			return
+		}
+		logging.InfoMsg(logger, "Event stream disconnected, reconnecting",
+			"event", stream.eventId,
+			"last_height", stream.lastHeight,
+			"error", err)
+		select {
+		case <-time.After(reconnectWait):
+		case <-stream.stop:
+			return
+		}
+	}
+}
+
+// connect streams events over one websocket connection, returning when the
+// connection is lost or the stream is stopped
+func (stream *eventStream) connect() error {
+	wsClient, err := stream.nodeClient.DeriveWebsocketClient()
+	if err != nil {
+		return err
+	}
+	if wsClient == nil {
+		return fmt.Errorf("Node client offers no websocket")
+	}
+	defer wsClient.Close()
+	liveEvents, err := wsClient.Events()
+	if err != nil {
+		return err
+	}
+	// Let the websocket finish delivering events after we stop reading them
+	defer func() {
+		go func() {
+			for range liveEvents {
+			}
+		}()
+	}()
+	// NewBlock events give the height from which to backfill on reconnection
+	if err = wsClient.Subscribe(txs.EventStringNewBlock()); err != nil {
+		return err
+	}
+	if stream.eventId != txs.EventStringNewBlock() {
+		if err = wsClient.Subscribe(stream.eventId); err != nil {
+			return err
+		}
+	}
+	if stream.heightKnown {
+		if err = stream.backfill(); err != nil {
+			return err
+		}
+	}
+	for {
+		select {
+		case event, ok := <-liveEvents:
+			if !ok {
+				return fmt.Errorf("Websocket connection lost")
+			}
+			if newBlock, ok := event.Data.(txs.EventDataNewBlock); ok && newBlock.Block != nil {
+				height := newBlock.Block.Height
+				if stream.heightKnown && height <= stream.lastHeight {
+					// Already backfilled
+					continue
+				}
+				stream.lastHeight, stream.heightKnown = height, true
+				event.Height = height
+			}
+			if event.Event != stream.eventId {
+				continue
+			}
+			if !stream.send(event) {
+				return nil
+			}
+		case <-stream.stop:
+			return nil
+		}
+	}
+}
+
+// backfill sends the events of the blocks after lastHeight up to the latest
+func (stream *eventStream) backfill() error {
+	_, _, _, latestHeight, _, err := stream.nodeClient.Status()
+	if err != nil {
+		return err
+	}
+	if !canBackfill(stream.eventId) {
+		if latestHeight > stream.lastHeight {
+			logging.InfoMsg(stream.nodeClient.Logger(), "Cannot backfill events "+
+				"so events may have been missed",
+				"event", stream.eventId,
+				"from_height", stream.lastHeight+1,
+				"to_height", latestHeight)
+			stream.lastHeight = latestHeight
+		}
+		return nil
+	}
+	for height := stream.lastHeight + 1; height <= latestHeight; height++ {
+		_, block, err := stream.nodeClient.GetBlock(height)
+		if err != nil {
+			return err
+		}
+		if block == nil {
+			return fmt.Errorf("Node returned no block at height %v", height)
+		}
+		events, err := BlockEvents(block, stream.eventId)
+		if err != nil {
+			return err
+		}
+		for _, event := range events {
+			if !stream.send(event) {
+				return nil
+			}
+		}
+		stream.lastHeight = height
+	}
+	return nil
+}
+
+func (stream *eventStream) send(event Event) bool {
+	select {
+	case stream.events <- event:
+		return true
+	case <-stream.stop:
+		return false
+	}
+}
+
+func (stream *eventStream) stopped() bool {
+	select {
+	case <-stream.stop:
+		return true
+	default:
+		return false
+	}
+}
+
+// BlockEvents derives the events with eventId that the transactions of block
+// fired when they were executed, as far as they can be told from the
+// transactions alone. See StreamEvents.
+func BlockEvents(block *tm_types.Block, eventId string) ([]Event, error) {
+	height := block.Height
+	if eventId == txs.EventStringNewBlock() {
+		return []Event{{
+			Event:      eventId,
+			Data:       txs.EventDataNewBlock{Block: block},
+			Height:     height,
+			Backfilled: true,
+		}}, nil
+	}
+	var events []Event
+	for _, txBytes := range block.Data.Txs {
+		tx, err := txs.DecodeTx(txBytes)
+		if err != nil {
+			return nil, fmt.Errorf("Could not decode transaction in block %v: %s",
+				height, err)
+		}
+		inputs, outputs := txAccounts(tx)
+		for _, address := range inputs {
+			if txs.EventStringAccInput(address) == eventId {
+				events = append(events, backfilledTxEvent(eventId, tx, height))
+			}
+		}
+		for _, address := range outputs {
+			if txs.EventStringAccOutput(address) == eventId {
+				events = append(events, backfilledTxEvent(eventId, tx, height))
+			}
+		}
+	}
+	return events, nil
+}
+
+func backfilledTxEvent(eventId string, tx txs.Tx, height int) Event {
+	return Event{
+		Event:      eventId,
+		Data:       txs.EventDataTx{Tx: tx},
+		Height:     height,
+		Backfilled: true,
+	}
+}
+
+// The accounts for which tx fires input and output events
+func txAccounts(tx txs.Tx) (inputs, outputs [][]byte) {
+	switch tx := tx.(type) {
+	case *txs.SendTx:
+		for _, input := range tx.Inputs {
+			inputs = append(inputs, input.Address)
+		}
+		for _, output := range tx.Outputs {
+			outputs = append(outputs, output.Address)
+		}
+	case *txs.CallTx:
+		inputs = [][]byte{tx.Input.Address}
+		if len(tx.Address) > 0 {
+			outputs = [][]byte{tx.Address}
+		}
+	case *txs.NameTx:
+		inputs = [][]byte{tx.Input.Address}
+	case *txs.NameTransferTx:
+		inputs = [][]byte{tx.Input.Address}
+	case *txs.PermissionsTx:
+		inputs = [][]byte{tx.Input.Address}
+	}
+	return inputs, outputs
+}
+
+// Only NewBlock events and the input and output events of accounts can be
+// derived from blocks
+func canBackfill(eventId string) bool {
+	return eventId == txs.EventStringNewBlock() ||
+		(strings.HasPrefix(eventId, "Acc/") &&
+			(strings.HasSuffix(eventId, "/Input") || strings.HasSuffix(eventId, "/Output")))
+}
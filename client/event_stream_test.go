@@ -0,0 +1,209 @@
+// Copyright 2017 Monax Industries Limited
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/burrow/txs"
+
+	"github.com/stretchr/testify/assert"
+	tm_types "github.com/tendermint/tendermint/types"
+)
+
+var otherAddress = []byte("98765432109876543210")
+
+func TestStreamEventsBackfillAndReconnect(t *testing.T) {
+	defer func(wait time.Duration) { reconnectWait = wait }(reconnectWait)
+	reconnectWait = time.Millisecond
+
+	nodeClient := newStreamNodeClient()
+	nodeClient.addBlock(t)
+	nodeClient.addBlock(t, sendTx(address, otherAddress))
+	nodeClient.addBlock(t, sendTx(otherAddress, address))
+
+	stop := make(chan struct{})
+	eventId := txs.EventStringAccInput(address)
+	events, err := StreamEvents(nodeClient, eventId, 1, stop)
+	assert.NoError(t, err)
+
+	wsClient := <-nodeClient.connections
+	event := <-events
+	assert.Equal(t, eventId, event.Event)
+	assert.Equal(t, 2, event.Height)
+	assert.True(t, event.Backfilled)
+	assert.Equal(t, []string{txs.EventStringNewBlock(), eventId},
+		wsClient.subscribed())
+
+	// Blocks that have been backfilled are skipped
+	wsClient.events <- newBlockEvent(3)
+	wsClient.events <- newBlockEvent(4)
+	wsClient.events <- Event{Event: eventId, Data: txs.EventDataTx{}}
+	event = <-events
+	assert.Equal(t, eventId, event.Event)
+	assert.False(t, event.Backfilled)
+
+	// Blocks committed while disconnected are backfilled on reconnection
+	nodeClient.addBlock(t)
+	nodeClient.addBlock(t, sendTx(address, otherAddress))
+	close(wsClient.events)
+	wsClient = <-nodeClient.connections
+	event = <-events
+	assert.Equal(t, 5, event.Height)
+	assert.True(t, event.Backfilled)
+
+	close(stop)
+	for range events {
+	}
+}
+
+func TestStreamEventsNewBlock(t *testing.T) {
+	nodeClient := newStreamNodeClient()
+	nodeClient.addBlock(t)
+
+	stop := make(chan struct{})
+	events, err := StreamEvents(nodeClient, txs.EventStringNewBlock(), 0, stop)
+	assert.NoError(t, err)
+	wsClient := <-nodeClient.connections
+	wsClient.events <- newBlockEvent(2)
+	event := <-events
+	assert.Equal(t, 2, event.Height)
+	assert.False(t, event.Backfilled)
+	assert.Equal(t, []string{txs.EventStringNewBlock()}, wsClient.subscribed())
+	close(stop)
+	for range events {
+	}
+}
+
+func TestStreamEventsCannotBackfill(t *testing.T) {
+	_, err := StreamEvents(newStreamNodeClient(),
+		txs.EventStringLogEvent(address), 1, make(chan struct{}))
+	assert.Error(t, err)
+}
+
+func TestBlockEvents(t *testing.T) {
+	callTx := &txs.CallTx{
+		Input:   &txs.TxInput{Address: otherAddress},
+		Address: address,
+	}
+	block := makeBlock(t, 7, sendTx(address, otherAddress), callTx)
+
+	events, err := BlockEvents(block, txs.EventStringAccInput(address))
+	assert.NoError(t, err)
+	assert.Len(t, events, 1)
+
+	events, err = BlockEvents(block, txs.EventStringAccOutput(address))
+	assert.NoError(t, err)
+	if assert.Len(t, events, 1) {
+		assert.Equal(t, callTx, events[0].Data.(txs.EventDataTx).Tx)
+		assert.Equal(t, 7, events[0].Height)
+	}
+
+	events, err = BlockEvents(block, txs.EventStringNewBlock())
+	assert.NoError(t, err)
+	assert.Len(t, events, 1)
+}
+
+func sendTx(from, to []byte) *txs.SendTx {
+	return &txs.SendTx{
+		Inputs:  []*txs.TxInput{{Address: from, Amount: 1, Sequence: 1}},
+		Outputs: []*txs.TxOutput{{Address: to, Amount: 1}},
+	}
+}
+
+func makeBlock(t *testing.T, height int, transactions ...txs.Tx) *tm_types.Block {
+	data := &tm_types.Data{}
+	for _, tx := range transactions {
+		txBytes, err := txs.EncodeTx(tx)
+		assert.NoError(t, err)
+		data.Txs = append(data.Txs, txBytes)
+	}
+	return &tm_types.Block{
+		Header: &tm_types.Header{Height: height, NumTxs: len(transactions)},
+		Data:   data,
+	}
+}
+
+func newBlockEvent(height int) Event {
+	return Event{
+		Event: txs.EventStringNewBlock(),
+		Data: txs.EventDataNewBlock{
+			Block: &tm_types.Block{Header: &tm_types.Header{Height: height}},
+		},
+	}
+}
+
+// Serves blocks and hands each new websocket connection to the test
+type streamNodeClient struct {
+	*fakeNodeClient
+	blocks      []*tm_types.Block
+	connections chan *fakeEventWebsocketClient
+}
+
+func newStreamNodeClient() *streamNodeClient {
+	return &streamNodeClient{
+		fakeNodeClient: &fakeNodeClient{},
+		connections:    make(chan *fakeEventWebsocketClient),
+	}
+}
+
+func (client *streamNodeClient) addBlock(t *testing.T, transactions ...txs.Tx) {
+	client.blocks = append(client.blocks,
+		makeBlock(t, len(client.blocks)+1, transactions...))
+}
+
+func (client *streamNodeClient) Status() ([]byte, []byte, []byte, int, int64, error) {
+	return nil, nil, nil, len(client.blocks), 0, nil
+}
+
+func (client *streamNodeClient) GetBlock(height int) (*tm_types.BlockMeta,
+	*tm_types.Block, error) {
+	return nil, client.blocks[height-1], nil
+}
+
+func (client *streamNodeClient) DeriveWebsocketClient() (NodeWebsocketClient, error) {
+	wsClient := &fakeEventWebsocketClient{events: make(chan Event)}
+	go func() { client.connections <- wsClient }()
+	return wsClient, nil
+}
+
+type fakeEventWebsocketClient struct {
+	NodeWebsocketClient
+	sync.Mutex
+	events        chan Event
+	subscriptions []string
+}
+
+func (client *fakeEventWebsocketClient) Subscribe(eventId string) error {
+	client.Lock()
+	defer client.Unlock()
+	client.subscriptions = append(client.subscriptions, eventId)
+	return nil
+}
+
+func (client *fakeEventWebsocketClient) subscribed() []string {
+	client.Lock()
+	defer client.Unlock()
+	return client.subscriptions
+}
+
+func (client *fakeEventWebsocketClient) Events() (<-chan Event, error) {
+	return client.events, nil
+}
+
+func (client *fakeEventWebsocketClient) Close() {
+}
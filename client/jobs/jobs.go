@@ -0,0 +1,367 @@
+// Copyright 2017 Monax Industries Limited
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package jobs runs a declarative list of jobs - contract deployments, calls,
+// queries, sends, name registrations, permission changes, and assertions -
+// against a chain. Jobs refer to the results of earlier jobs through
+// variables such as $token or ${balance.amount}.
+package jobs
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Jobs is the YAML document read by ReadJobsFile:
+//
+//	account: <address of the account that signs transactions>
+//	variables:
+//	  bob: 0A1B...
+//	jobs:
+//	- name: token
+//	  deploy:
+//	    bin: Token.bin
+//	    abi: Token.abi
+//	    args: ["My Token", 1000]
+//	- name: transfer
+//	  call:
+//	    to: $token
+//	    abi: Token.abi
+//	    function: transfer
+//	    args: [$bob, 100]
+//	- name: balance
+//	  query:
+//	    to: $token
+//	    abi: Token.abi
+//	    function: balanceOf
+//	    args: [$bob]
+//	- name: checkBalance
+//	  assert:
+//	    value: $balance
+//	    equals: 100
+type Jobs struct {
+	Account   string            `yaml:"account"`
+	Variables map[string]string `yaml:"variables"`
+	Jobs      []*Job            `yaml:"jobs"`
+	// Directory that relative bin and abi paths are resolved against
+	BaseDir string `yaml:"-"`
+}
+
+// Job has a name and exactly one action
+type Job struct {
+	Name       string      `yaml:"name"`
+	Deploy     *Deploy     `yaml:"deploy"`
+	Call       *Call       `yaml:"call"`
+	Query      *Query      `yaml:"query"`
+	Send       *Send       `yaml:"send"`
+	Register   *Register   `yaml:"register"`
+	Permission *Permission `yaml:"permission"`
+	Assert     *Assert     `yaml:"assert"`
+}
+
+// Deploy creates a contract. Its result is the contract address.
+type Deploy struct {
+	Bin       string            `yaml:"bin"`
+	Abi       string            `yaml:"abi"`
+	Libraries map[string]string `yaml:"libraries"`
+	Args      []interface{}     `yaml:"args"`
+	Amount    string            `yaml:"amount"`
+	Fee       string            `yaml:"fee"`
+	Gas       string            `yaml:"gas"`
+}
+
+// Call sends a CallTx to a contract. Its result is the first return value,
+// or the hex return data when there is no ABI.
+type Call struct {
+	To       string        `yaml:"to"`
+	Abi      string        `yaml:"abi"`
+	Function string        `yaml:"function"`
+	Args     []interface{} `yaml:"args"`
+	Data     string        `yaml:"data"`
+	Amount   string        `yaml:"amount"`
+	Fee      string        `yaml:"fee"`
+	Gas      string        `yaml:"gas"`
+}
+
+// Query calls a contract without sending a transaction. Its result is as for
+// Call.
+type Query struct {
+	To       string        `yaml:"to"`
+	Abi      string        `yaml:"abi"`
+	Function string        `yaml:"function"`
+	Args     []interface{} `yaml:"args"`
+	Data     string        `yaml:"data"`
+}
+
+// Send transfers an amount to an account. Its result is the tx hash.
+type Send struct {
+	To     string `yaml:"to"`
+	Amount string `yaml:"amount"`
+}
+
+// Register sets a name registry entry. Its result is the tx hash.
+type Register struct {
+	Name   string `yaml:"name"`
+	Data   string `yaml:"data"`
+	Amount string `yaml:"amount"`
+	Fee    string `yaml:"fee"`
+}
+
+// Permission sends a PermissionsTx with the same function and arguments as
+// burrow-client tx permission. Its result is the tx hash.
+type Permission struct {
+	Function string   `yaml:"function"`
+	Args     []string `yaml:"args"`
+}
+
+// Assert fails the run unless value equals equals. Its result is the value.
+type Assert struct {
+	Value  string `yaml:"value"`
+	Equals string `yaml:"equals"`
+}
+
+// Variables available to all jobs besides job results and Jobs.Variables
+const AccountVariable = "account"
+
+// $name, $name.output, ${name}, ${name.output}, or $$ for a literal $
+var variableRegexp = regexp.MustCompile(`\$(\$|\{[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z0-9_]+)?\}|[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z0-9_]+)?)`)
+
+var nameRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// ReadJobsFile reads and validates a YAML jobs file
+func ReadJobsFile(fileName string) (*Jobs, error) {
+	jobsYAML, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+	jobs, err := ReadJobs(jobsYAML)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid jobs file %s: %s", fileName, err)
+	}
+	jobs.BaseDir = filepath.Dir(fileName)
+	return jobs, nil
+}
+
+// ReadJobs reads and validates a YAML jobs document
+func ReadJobs(jobsYAML []byte) (*Jobs, error) {
+	jobs := new(Jobs)
+	err := yaml.Unmarshal(jobsYAML, jobs)
+	if err != nil {
+		return nil, err
+	}
+	return jobs, jobs.Validate()
+}
+
+// Validate checks that each job has a unique name and a single action, and
+// that variables only refer to earlier jobs so that the jobs form a
+// dependency graph that can be run in file order.
+func (jobs *Jobs) Validate() error {
+	if len(jobs.Jobs) == 0 {
+		return fmt.Errorf("No jobs to run")
+	}
+	defined := map[string]bool{AccountVariable: true}
+	for name := range jobs.Variables {
+		if !nameRegexp.MatchString(name) {
+			return fmt.Errorf("Invalid variable name '%s'", name)
+		}
+		if defined[name] {
+			return fmt.Errorf("Variable name '%s' is reserved", name)
+		}
+		defined[name] = true
+	}
+	for i, job := range jobs.Jobs {
+		if !nameRegexp.MatchString(job.Name) {
+			return fmt.Errorf("Job %v has invalid name '%s'; names must be "+
+				"letters, digits, and underscores", i, job.Name)
+		}
+		if defined[job.Name] {
+			return fmt.Errorf("Job name '%s' is already used by a variable or "+
+				"an earlier job", job.Name)
+		}
+		if _, err := job.Type(); err != nil {
+			return err
+		}
+		for _, reference := range job.References() {
+			if !defined[reference] {
+				return fmt.Errorf("Job '%s' refers to $%s, which is neither a "+
+					"variable nor an earlier job", job.Name, reference)
+			}
+		}
+		defined[job.Name] = true
+	}
+	return nil
+}
+
+// Type returns the name of the job's action
+func (job *Job) Type() (string, error) {
+	var types []string
+	if job.Deploy != nil {
+		types = append(types, "deploy")
+	}
+	if job.Call != nil {
+		types = append(types, "call")
+	}
+	if job.Query != nil {
+		types = append(types, "query")
+	}
+	if job.Send != nil {
+		types = append(types, "send")
+	}
+	if job.Register != nil {
+		types = append(types, "register")
+	}
+	if job.Permission != nil {
+		types = append(types, "permission")
+	}
+	if job.Assert != nil {
+		types = append(types, "assert")
+	}
+	if len(types) != 1 {
+		return "", fmt.Errorf("Job '%s' should have exactly one of deploy, "+
+			"call, query, send, register, permission, or assert but has %v",
+			job.Name, len(types))
+	}
+	return types[0], nil
+}
+
+// SendsTransaction is true for jobs that need a nonce
+func (job *Job) SendsTransaction() bool {
+	return job.Deploy != nil || job.Call != nil || job.Send != nil ||
+		job.Register != nil || job.Permission != nil
+}
+
+// References returns the names of the variables and jobs the job refers to
+func (job *Job) References() []string {
+	var references []string
+	seen := make(map[string]bool)
+	for _, s := range job.strings() {
+		for _, match := range variableRegexp.FindAllStringSubmatch(s, -1) {
+			name := referenceName(match[1])
+			if name != "" && !seen[name] {
+				seen[name] = true
+				references = append(references, name)
+			}
+		}
+	}
+	return references
+}
+
+// All the strings of the job that may contain variables
+func (job *Job) strings() []string {
+	var ss []string
+	switch {
+	case job.Deploy != nil:
+		d := job.Deploy
+		ss = append(ss, d.Bin, d.Abi, d.Amount, d.Fee, d.Gas)
+		for _, address := range d.Libraries {
+			ss = append(ss, address)
+		}
+		ss = append(ss, argStrings(d.Args)...)
+	case job.Call != nil:
+		c := job.Call
+		ss = append(ss, c.To, c.Abi, c.Function, c.Data, c.Amount, c.Fee, c.Gas)
+		ss = append(ss, argStrings(c.Args)...)
+	case job.Query != nil:
+		q := job.Query
+		ss = append(ss, q.To, q.Abi, q.Function, q.Data)
+		ss = append(ss, argStrings(q.Args)...)
+	case job.Send != nil:
+		ss = append(ss, job.Send.To, job.Send.Amount)
+	case job.Register != nil:
+		r := job.Register
+		ss = append(ss, r.Name, r.Data, r.Amount, r.Fee)
+	case job.Permission != nil:
+		ss = append(ss, job.Permission.Function)
+		ss = append(ss, job.Permission.Args...)
+	case job.Assert != nil:
+		ss = append(ss, job.Assert.Value, job.Assert.Equals)
+	}
+	return ss
+}
+
+func argStrings(args []interface{}) []string {
+	var ss []string
+	for _, arg := range args {
+		switch a := arg.(type) {
+		case string:
+			ss = append(ss, a)
+		case []interface{}:
+			ss = append(ss, argStrings(a)...)
+		}
+	}
+	return ss
+}
+
+// The job or variable name of the body of a variable match, or "" for $$
+func referenceName(body string) string {
+	if body == "$" {
+		return ""
+	}
+	body = strings.TrimSuffix(strings.TrimPrefix(body, "{"), "}")
+	return strings.SplitN(body, ".", 2)[0]
+}
+
+// substitute replaces the variables in s with their values
+func substitute(s string, lookup func(name string) (string, bool)) (string, error) {
+	var err error
+	substituted := variableRegexp.ReplaceAllStringFunc(s, func(match string) string {
+		body := match[1:]
+		if body == "$" {
+			return "$"
+		}
+		name := strings.TrimSuffix(strings.TrimPrefix(body, "{"), "}")
+		value, ok := lookup(name)
+		if !ok && err == nil {
+			err = fmt.Errorf("No value for $%s", name)
+		}
+		return value
+	})
+	if err != nil {
+		return "", err
+	}
+	return substituted, nil
+}
+
+// substituteArgs substitutes variables in ABI arguments, rendering other
+// scalars as strings for ABI encoding and keeping lists as lists
+func substituteArgs(args []interface{},
+	lookup func(name string) (string, bool)) ([]interface{}, error) {
+	substituted := make([]interface{}, len(args))
+	for i, arg := range args {
+		switch a := arg.(type) {
+		case string:
+			s, err := substitute(a, lookup)
+			if err != nil {
+				return nil, err
+			}
+			substituted[i] = s
+		case []interface{}:
+			elements, err := substituteArgs(a, lookup)
+			if err != nil {
+				return nil, err
+			}
+			substituted[i] = elements
+		case nil:
+			return nil, fmt.Errorf("Argument %v is empty", i)
+		default:
+			substituted[i] = fmt.Sprintf("%v", a)
+		}
+	}
+	return substituted, nil
+}
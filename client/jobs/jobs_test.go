@@ -0,0 +1,179 @@
+// Copyright 2017 Monax Industries Limited
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jobs
+
+import (
+	"testing"
+
+	"github.com/hyperledger/burrow/client/mock"
+	"github.com/hyperledger/burrow/keys"
+	"github.com/hyperledger/burrow/logging/loggers"
+	"github.com/stretchr/testify/assert"
+)
+
+const exampleJobs = `
+account: 0000000000000000000000000000000000000001
+variables:
+  bob: 00000000000000000000000000000000000000B0
+jobs:
+- name: token
+  deploy:
+    bin: Token.bin
+    abi: Token.abi
+    args: ["My Token", 1000]
+- name: transfer
+  call:
+    to: $token
+    abi: Token.abi
+    function: transfer
+    args: [$bob, 100]
+    gas: 50000
+- name: balance
+  query:
+    to: ${token}
+    abi: Token.abi
+    function: balanceOf
+    args: [["$bob"]]
+- name: check
+  assert:
+    value: ${balance.amount}
+    equals: 100
+`
+
+func TestReadJobs(t *testing.T) {
+	jobs, err := ReadJobs([]byte(exampleJobs))
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Len(t, jobs.Jobs, 4)
+	assert.Equal(t, "50000", jobs.Jobs[1].Call.Gas, "numbers are read as strings")
+	assert.Equal(t, "100", jobs.Jobs[3].Assert.Equals)
+	assert.Equal(t, []string{"token", "bob"}, jobs.Jobs[1].References())
+	assert.Equal(t, []string{"token", "bob"}, jobs.Jobs[2].References())
+	assert.Equal(t, []string{"balance"}, jobs.Jobs[3].References())
+	assert.True(t, jobs.Jobs[1].SendsTransaction())
+	assert.False(t, jobs.Jobs[2].SendsTransaction())
+
+	for _, invalid := range []string{
+		// later job
+		"jobs: [{name: a, assert: {value: $b, equals: 1}}, {name: b, assert: {value: 1, equals: 1}}]",
+		// duplicate name
+		"jobs: [{name: a, assert: {value: 1, equals: 1}}, {name: a, assert: {value: 1, equals: 1}}]",
+		// two actions
+		"jobs: [{name: a, assert: {value: 1, equals: 1}, send: {to: $account, amount: 1}}]",
+		// reserved name
+		"jobs: [{name: account, assert: {value: 1, equals: 1}}]",
+		"jobs: [{name: a-b, assert: {value: 1, equals: 1}}]",
+		"jobs: []",
+	} {
+		_, err := ReadJobs([]byte(invalid))
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestSubstitute(t *testing.T) {
+	values := map[string]string{"a": "1", "b.out": "2"}
+	lookup := func(name string) (string, bool) {
+		value, ok := values[name]
+		return value, ok
+	}
+	s, err := substitute("$a-${b.out}-$$a", lookup)
+	assert.NoError(t, err)
+	assert.Equal(t, "1-2-$a", s)
+	_, err = substitute("$c", lookup)
+	assert.Error(t, err)
+
+	args, err := substituteArgs([]interface{}{"$a", 3, true, []interface{}{"${b.out}", 4}},
+		lookup)
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{"1", "3", "true", []interface{}{"2", "4"}}, args)
+}
+
+func TestRun(t *testing.T) {
+	jobs, err := ReadJobs([]byte(`
+variables:
+  expected: ""
+jobs:
+- name: q
+  query:
+    to: 00000000000000000000000000000000000000C0
+    data: 1234
+- name: first
+  assert:
+    value: $q
+    equals: $expected
+- name: second
+  assert:
+    value: $first
+    equals: wrong
+- name: afterFailure
+  assert:
+    value: $second
+    equals: ""
+`))
+	if !assert.NoError(t, err) {
+		return
+	}
+	runner := NewRunner("test-chain", mock.NewMockNodeClient(), nil,
+		loggers.NewNoopInfoTraceLogger())
+	results, err := runner.Run(jobs)
+	assert.Error(t, err)
+	if !assert.Len(t, results, 4) {
+		return
+	}
+	assert.Equal(t, StatusSucceeded, results[0].Status)
+	assert.Equal(t, StatusSucceeded, results[1].Status)
+	assert.Equal(t, StatusFailed, results[2].Status)
+	assert.Contains(t, results[2].Error, "wrong")
+	assert.Equal(t, StatusSkipped, results[3].Status)
+}
+
+func TestRunReleasesNonces(t *testing.T) {
+	jobs, err := ReadJobs([]byte(`
+account: 0000000000000000000000000000000000000001
+jobs:
+- name: first
+  send:
+    to: 00000000000000000000000000000000000000B0
+    amount: 10
+- name: second
+  send:
+    to: 00000000000000000000000000000000000000B0
+    amount: $first
+`))
+	if !assert.NoError(t, err) {
+		return
+	}
+	nodeClient := mock.NewMockNodeClient()
+	// The key client does not hold the account's key so forming fails
+	runner := NewRunner("test-chain", nodeClient, keys.NewMemoryKeyClient(),
+		loggers.NewNoopInfoTraceLogger())
+	results, err := runner.Run(jobs)
+	assert.Error(t, err)
+	if !assert.Len(t, results, 2) {
+		return
+	}
+	assert.Equal(t, int64(1), results[0].Nonce)
+	assert.Equal(t, int64(2), results[1].Nonce)
+	assert.Equal(t, StatusFailed, results[0].Status)
+	assert.Equal(t, StatusSkipped, results[1].Status)
+
+	// The nonces of the failed and skipped jobs are handed out again
+	address := make([]byte, 20)
+	address[19] = 1
+	nonce, err := nodeClient.NonceManager().Reserve(address)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, nonce)
+}
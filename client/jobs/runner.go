@@ -0,0 +1,594 @@
+// Copyright 2017 Monax Industries Limited
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jobs
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/hyperledger/burrow/client"
+	"github.com/hyperledger/burrow/client/rpc"
+	"github.com/hyperledger/burrow/keys"
+	"github.com/hyperledger/burrow/logging"
+	logging_types "github.com/hyperledger/burrow/logging/types"
+	"github.com/hyperledger/burrow/manager/burrow-mint/evm/abi"
+	"github.com/hyperledger/burrow/txs"
+)
+
+const (
+	defaultGas = "1000000"
+	defaultFee = "0"
+)
+
+// Result is the outcome of a job as written to the results file
+type Result struct {
+	Name  string `json:"name"`
+	Type  string `json:"type"`
+	Value string `json:"value,omitempty"`
+	// Decoded return values of calls and queries by output name or index
+	Outputs map[string]string `json:"outputs,omitempty"`
+	TxHash  string            `json:"tx_hash,omitempty"`
+	Nonce   int64             `json:"nonce,omitempty"`
+	// One of succeeded, failed, or skipped
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+const (
+	StatusSucceeded = "succeeded"
+	StatusFailed    = "failed"
+	StatusSkipped   = "skipped"
+)
+
+type Runner struct {
+	chainID    string
+	nodeClient client.NodeClient
+	keyClient  keys.KeyClient
+	logger     logging_types.InfoTraceLogger
+
+	mtx    sync.RWMutex
+	values map[string]string
+}
+
+// NewRunner returns a Runner that signs transactions with keyClient and sends
+// them to the chain chainID through nodeClient
+func NewRunner(chainID string, nodeClient client.NodeClient, keyClient keys.KeyClient,
+	logger logging_types.InfoTraceLogger) *Runner {
+	return &Runner{
+		chainID:    chainID,
+		nodeClient: nodeClient,
+		keyClient:  keyClient,
+		logger:     logging.WithScope(logger, "JobRunner"),
+		values:     make(map[string]string),
+	}
+}
+
+// Run runs jobs, returning a result for every job in file order and an error
+// if any job failed.
+//
+// Each job runs as soon as the jobs it refers to have succeeded, so
+// independent jobs run concurrently. Nonces are reserved for the jobs that
+// send transactions in file order from the NonceManager of the node client,
+// which broadcasts the transactions in nonce order. Contract addresses are
+// therefore the same whenever the jobs are run from the same account state.
+// The first failure stops jobs that have not yet started from running, and
+// since their nonces are released jobs waiting to broadcast after them fail.
+func (runner *Runner) Run(jobs *Jobs) ([]*Result, error) {
+	if err := jobs.Validate(); err != nil {
+		return nil, err
+	}
+	for name, value := range jobs.Variables {
+		runner.setValue(name, value)
+	}
+
+	account := jobs.Account
+	var address []byte
+	nonces := make(map[string]int64)
+	if hasTransactions(jobs) {
+		if account == "" {
+			return nil, fmt.Errorf("Please provide the account to send " +
+				"transactions from")
+		}
+		var err error
+		address, err = hex.DecodeString(account)
+		if err != nil {
+			return nil, fmt.Errorf("Account address (%s) is not valid hex: %s",
+				account, err)
+		}
+		acc, err := runner.nodeClient.GetAccount(address)
+		if err != nil {
+			return nil, fmt.Errorf("Could not get account %s: %s", account, err)
+		}
+		if acc == nil {
+			return nil, fmt.Errorf("Account %s does not exist on the chain", account)
+		}
+		for _, job := range jobs.Jobs {
+			if job.SendsTransaction() {
+				nonce, err := runner.nodeClient.NonceManager().Reserve(address)
+				if err != nil {
+					runner.releaseNonces(address, nonces)
+					return nil, err
+				}
+				nonces[job.Name] = int64(nonce)
+			}
+		}
+	}
+	runner.setValue(AccountVariable, account)
+
+	// Closed by the first job to fail
+	failed := make(chan struct{})
+	var failOnce sync.Once
+
+	results := make([]*Result, len(jobs.Jobs))
+	done := make(map[string]chan struct{}, len(jobs.Jobs))
+	for _, job := range jobs.Jobs {
+		done[job.Name] = make(chan struct{})
+	}
+	var wg sync.WaitGroup
+	for i, job := range jobs.Jobs {
+		jobType, _ := job.Type()
+		result := &Result{
+			Name:  job.Name,
+			Type:  jobType,
+			Nonce: nonces[job.Name],
+		}
+		results[i] = result
+		wg.Add(1)
+		go func(job *Job) {
+			defer wg.Done()
+			defer close(done[job.Name])
+			if job.SendsTransaction() {
+				// Does nothing once the transaction has been broadcast
+				defer runner.nodeClient.NonceManager().Release(address,
+					int(result.Nonce))
+			}
+			for _, reference := range job.References() {
+				if ch, ok := done[reference]; ok {
+					<-ch
+				}
+			}
+			select {
+			case <-failed:
+				result.Status = StatusSkipped
+				return
+			default:
+			}
+			jr := &jobRunner{
+				Runner:  runner,
+				baseDir: jobs.BaseDir,
+				account: account,
+				job:     job,
+				result:  result,
+			}
+			err := jr.run()
+			if err != nil {
+				result.Status = StatusFailed
+				result.Error = err.Error()
+				failOnce.Do(func() { close(failed) })
+				logging.InfoMsg(runner.logger, "Job failed",
+					"job", job.Name,
+					"error", err)
+				return
+			}
+			result.Status = StatusSucceeded
+			runner.setValue(job.Name, result.Value)
+			for output, value := range result.Outputs {
+				runner.setValue(job.Name+"."+output, value)
+			}
+			logging.InfoMsg(runner.logger, "Job succeeded",
+				"job", job.Name,
+				"type", result.Type,
+				"value", result.Value)
+		}(job)
+	}
+	wg.Wait()
+
+	var failed []string
+	for _, result := range results {
+		if result.Status == StatusFailed {
+			failed = append(failed, fmt.Sprintf("%s (%s)", result.Name, result.Error))
+		}
+	}
+	if len(failed) > 0 {
+		return results, fmt.Errorf("Jobs failed: %s", strings.Join(failed, ", "))
+	}
+	return results, nil
+}
+
+// WriteResults writes results as JSON to fileName
+func WriteResults(fileName string, results []*Result) error {
+	resultsJSON, err := json.MarshalIndent(results, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(fileName, append(resultsJSON, '\n'), 0644)
+}
+
+func (runner *Runner) releaseNonces(address []byte, nonces map[string]int64) {
+	for _, nonce := range nonces {
+		runner.nodeClient.NonceManager().Release(address, int(nonce))
+	}
+}
+
+func hasTransactions(jobs *Jobs) bool {
+	for _, job := range jobs.Jobs {
+		if job.SendsTransaction() {
+			return true
+		}
+	}
+	return false
+}
+
+func (runner *Runner) setValue(name, value string) {
+	runner.mtx.Lock()
+	defer runner.mtx.Unlock()
+	runner.values[name] = value
+}
+
+func (runner *Runner) lookup(name string) (string, bool) {
+	runner.mtx.RLock()
+	defer runner.mtx.RUnlock()
+	value, ok := runner.values[name]
+	return value, ok
+}
+
+//------------------------------------------------------------------------------
+// Running a single job
+
+type jobRunner struct {
+	*Runner
+	baseDir string
+	account string
+	job     *Job
+	result  *Result
+}
+
+func (jr *jobRunner) run() error {
+	job := jr.job
+	switch {
+	case job.Deploy != nil:
+		return jr.deploy(job.Deploy)
+	case job.Call != nil:
+		return jr.call(job.Call)
+	case job.Query != nil:
+		return jr.query(job.Query)
+	case job.Send != nil:
+		return jr.send(job.Send)
+	case job.Register != nil:
+		return jr.register(job.Register)
+	case job.Permission != nil:
+		return jr.permission(job.Permission)
+	case job.Assert != nil:
+		return jr.assert(job.Assert)
+	}
+	return fmt.Errorf("Job '%s' has no action", job.Name)
+}
+
+func (jr *jobRunner) deploy(deploy *Deploy) error {
+	ss, err := jr.substituteAll(deploy.Bin, deploy.Abi, deploy.Amount, deploy.Fee,
+		deploy.Gas)
+	if err != nil {
+		return err
+	}
+	binFile, abiFile, amount, fee, gas := ss[0], ss[1], ss[2], ss[3], ss[4]
+	bin, err := ioutil.ReadFile(jr.path(binFile))
+	if err != nil {
+		return fmt.Errorf("Could not read bytecode: %s", err)
+	}
+	libraries := make(map[string]abi.Address)
+	for name, addressString := range deploy.Libraries {
+		addressString, err = substitute(addressString, jr.lookup)
+		if err != nil {
+			return err
+		}
+		address, err := toAddress(addressString)
+		if err != nil {
+			return fmt.Errorf("Invalid address for library %s: %s", name, err)
+		}
+		libraries[name] = address
+	}
+	bytecode, err := abi.Link(strings.TrimPrefix(strings.TrimSpace(string(bin)), "0x"),
+		libraries)
+	if err != nil {
+		return err
+	}
+	constructor := &abi.Function{}
+	if abiFile != "" {
+		spec, err := abi.ReadSpecFile(jr.path(abiFile))
+		if err != nil {
+			return fmt.Errorf("Could not read ABI: %s", err)
+		}
+		if spec.Constructor != nil {
+			constructor = spec.Constructor
+		}
+	} else if len(deploy.Args) > 0 {
+		return fmt.Errorf("Constructor arguments need an abi")
+	}
+	args, err := substituteArgs(deploy.Args, jr.lookup)
+	if err != nil {
+		return err
+	}
+	encodedArgs, err := constructor.PackConstructor(args...)
+	if err != nil {
+		return err
+	}
+
+	txResult, err := jr.transact(func(nonce string) (txs.Tx, error) {
+		return rpc.Call(jr.nodeClient, jr.keyClient, "", jr.account, "",
+			withDefault(amount, "0"), nonce, withDefault(gas, defaultGas),
+			withDefault(fee, defaultFee), bytecode+hex.EncodeToString(encodedArgs))
+	})
+	if err != nil {
+		return err
+	}
+	if len(txResult.Address) == 0 {
+		return fmt.Errorf("Transaction did not create a contract")
+	}
+	contract, err := jr.nodeClient.GetAccount(txResult.Address)
+	if err != nil {
+		return fmt.Errorf("Could not get contract account %X: %s", txResult.Address, err)
+	}
+	if contract == nil || len(contract.Code) == 0 {
+		return fmt.Errorf("No code at contract address %X after deployment",
+			txResult.Address)
+	}
+	jr.result.Value = fmt.Sprintf("%X", txResult.Address)
+	return nil
+}
+
+func (jr *jobRunner) call(call *Call) error {
+	ss, err := jr.substituteAll(call.To, call.Amount, call.Fee, call.Gas)
+	if err != nil {
+		return err
+	}
+	to, amount, fee, gas := ss[0], ss[1], ss[2], ss[3]
+	if _, err := toAddress(to); err != nil {
+		return fmt.Errorf("Invalid contract address: %s", err)
+	}
+	data, function, err := jr.callData(call.Abi, call.Function, call.Data, call.Args)
+	if err != nil {
+		return err
+	}
+	txResult, err := jr.transact(func(nonce string) (txs.Tx, error) {
+		return rpc.Call(jr.nodeClient, jr.keyClient, "", jr.account, to,
+			withDefault(amount, "0"), nonce, withDefault(gas, defaultGas),
+			withDefault(fee, defaultFee), hex.EncodeToString(data))
+	})
+	if err != nil {
+		return err
+	}
+	return jr.setReturn(function, txResult.Return)
+}
+
+func (jr *jobRunner) query(query *Query) error {
+	to, err := substitute(query.To, jr.lookup)
+	if err != nil {
+		return err
+	}
+	address, err := toAddress(to)
+	if err != nil {
+		return fmt.Errorf("Invalid contract address: %s", err)
+	}
+	data, function, err := jr.callData(query.Abi, query.Function, query.Data, query.Args)
+	if err != nil {
+		return err
+	}
+	var caller []byte
+	if jr.account != "" {
+		caller, err = hex.DecodeString(jr.account)
+		if err != nil {
+			return err
+		}
+	}
+	ret, _, err := jr.nodeClient.QueryContract(caller, address[:], data)
+	if err != nil {
+		return err
+	}
+	return jr.setReturn(function, ret)
+}
+
+func (jr *jobRunner) send(send *Send) error {
+	ss, err := jr.substituteAll(send.To, send.Amount)
+	if err != nil {
+		return err
+	}
+	txResult, err := jr.transact(func(nonce string) (txs.Tx, error) {
+		return rpc.Send(jr.nodeClient, jr.keyClient, "", jr.account, ss[0], ss[1],
+			nonce)
+	})
+	if err != nil {
+		return err
+	}
+	jr.result.Value = jr.result.TxHash
+	return nil
+}
+
+func (jr *jobRunner) register(register *Register) error {
+	ss, err := jr.substituteAll(register.Name, register.Data, register.Amount,
+		register.Fee)
+	if err != nil {
+		return err
+	}
+	name, data, amount, fee := ss[0], ss[1], ss[2], ss[3]
+	_, err = jr.transact(func(nonce string) (txs.Tx, error) {
+		return rpc.Name(jr.nodeClient, jr.keyClient, "", jr.account, amount, nonce,
+			withDefault(fee, defaultFee), name, data)
+	})
+	if err != nil {
+		return err
+	}
+	jr.result.Value = jr.result.TxHash
+	return nil
+}
+
+func (jr *jobRunner) permission(permission *Permission) error {
+	args, err := jr.substituteAll(permission.Args...)
+	if err != nil {
+		return err
+	}
+	_, err = jr.transact(func(nonce string) (txs.Tx, error) {
+		return rpc.Permissions(jr.nodeClient, jr.keyClient, "", jr.account, nonce,
+			permission.Function, args)
+	})
+	if err != nil {
+		return err
+	}
+	jr.result.Value = jr.result.TxHash
+	return nil
+}
+
+func (jr *jobRunner) assert(assert *Assert) error {
+	ss, err := jr.substituteAll(assert.Value, assert.Equals)
+	if err != nil {
+		return err
+	}
+	value, expected := strings.TrimSpace(ss[0]), strings.TrimSpace(ss[1])
+	if value != expected {
+		return fmt.Errorf("Assertion failed: expected '%s' but got '%s'",
+			expected, value)
+	}
+	jr.result.Value = value
+	return nil
+}
+
+// transact forms a transaction with the job's nonce using formTx, then signs
+// and broadcasts it in nonce order and waits for it to be committed
+func (jr *jobRunner) transact(formTx func(nonce string) (txs.Tx, error)) (*rpc.TxResult, error) {
+	tx, err := formTx(strconv.FormatInt(jr.result.Nonce, 10))
+	if err != nil {
+		return nil, err
+	}
+	inputAddr, tx, err := rpc.SignTx(jr.keyClient, jr.chainID, tx)
+	if err != nil {
+		return nil, err
+	}
+	jr.result.TxHash = fmt.Sprintf("%X", txs.TxHash(jr.chainID, tx))
+	confirm, err := rpc.BroadcastAsync(jr.chainID, jr.nodeClient, tx, inputAddr, true)
+	if err != nil {
+		return nil, err
+	}
+	return confirm()
+}
+
+// callData encodes a call from an ABI function and arguments, or from raw hex
+// data when there is no ABI
+func (jr *jobRunner) callData(abiFile, functionName, data string,
+	args []interface{}) ([]byte, *abi.Function, error) {
+	ss, err := jr.substituteAll(abiFile, functionName, data)
+	if err != nil {
+		return nil, nil, err
+	}
+	abiFile, functionName, data = ss[0], ss[1], ss[2]
+	if abiFile == "" {
+		if functionName != "" || len(args) > 0 {
+			return nil, nil, fmt.Errorf("Calling a function by name with " +
+				"arguments needs an abi")
+		}
+		dataBytes, err := hex.DecodeString(data)
+		if err != nil {
+			return nil, nil, fmt.Errorf("Data (%s) is not valid hex: %s", data, err)
+		}
+		return dataBytes, nil, nil
+	}
+	spec, err := abi.ReadSpecFile(jr.path(abiFile))
+	if err != nil {
+		return nil, nil, fmt.Errorf("Could not read ABI: %s", err)
+	}
+	function, err := spec.Function(functionName)
+	if err != nil {
+		return nil, nil, err
+	}
+	values, err := substituteArgs(args, jr.lookup)
+	if err != nil {
+		return nil, nil, err
+	}
+	dataBytes, err := function.Pack(values...)
+	if err != nil {
+		return nil, nil, err
+	}
+	return dataBytes, function, nil
+}
+
+// setReturn sets the job's value to the first output of function decoded from
+// ret, or to ret in hex without a function
+func (jr *jobRunner) setReturn(function *abi.Function, ret []byte) error {
+	jr.result.Value = fmt.Sprintf("%X", ret)
+	if function == nil {
+		return nil
+	}
+	values, err := function.UnpackOutputs(ret)
+	if err != nil {
+		return fmt.Errorf("Could not decode return value %X of %s: %s", ret,
+			function.Signature(), err)
+	}
+	jr.result.Outputs = make(map[string]string)
+	for i, output := range function.Outputs {
+		value := abi.Format(output.Type, values[i])
+		if i == 0 {
+			jr.result.Value = value
+		}
+		jr.result.Outputs[strconv.Itoa(i)] = value
+		if output.Name != "" {
+			jr.result.Outputs[output.Name] = value
+		}
+	}
+	return nil
+}
+
+func (jr *jobRunner) substituteAll(ss ...string) ([]string, error) {
+	substituted := make([]string, len(ss))
+	for i, s := range ss {
+		var err error
+		substituted[i], err = substitute(s, jr.lookup)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return substituted, nil
+}
+
+// Resolve paths relative to the jobs file
+func (jr *jobRunner) path(fileName string) string {
+	if filepath.IsAbs(fileName) || jr.baseDir == "" {
+		return fileName
+	}
+	return filepath.Join(jr.baseDir, fileName)
+}
+
+func toAddress(s string) (abi.Address, error) {
+	var address abi.Address
+	bs, err := hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(s), "0x"))
+	if err != nil {
+		return address, err
+	}
+	if len(bs) != abi.AddressLength {
+		return address, fmt.Errorf("%s is not a %v byte address", s, abi.AddressLength)
+	}
+	copy(address[:], bs)
+	return address, nil
+}
+
+func withDefault(value, defaultValue string) string {
+	if value == "" {
+		return defaultValue
+	}
+	return value
+}
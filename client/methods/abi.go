@@ -0,0 +1,119 @@
+// Copyright 2017 Monax Industries Limited
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package methods
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/burrow/definitions"
+	"github.com/hyperledger/burrow/logging"
+	logging_types "github.com/hyperledger/burrow/logging/types"
+	"github.com/hyperledger/burrow/manager/burrow-mint/evm/abi"
+	"github.com/hyperledger/burrow/txs"
+)
+
+// callData returns the hex encoded call data to send: --data as given, or the
+// encoding of a call to --function with args when an --abi is provided. The
+// ABI and function are returned for decoding the result and are nil when
+// --data is used.
+func callData(do *definitions.ClientDo, args []string) (string, *abi.Spec,
+	*abi.Function, error) {
+	if do.AbiFlag == "" {
+		if do.FunctionFlag != "" || len(args) > 0 {
+			return "", nil, nil, fmt.Errorf("Please provide the contract ABI " +
+				"with --abi to call a function by name with arguments")
+		}
+		return do.DataFlag, nil, nil, nil
+	}
+	if do.DataFlag != "" {
+		return "", nil, nil, fmt.Errorf("Please provide either --data or " +
+			"--abi with --function, not both")
+	}
+	if do.FunctionFlag == "" {
+		return "", nil, nil, fmt.Errorf("Please provide the function to call " +
+			"with --function")
+	}
+	spec, err := abi.ReadSpecFile(do.AbiFlag)
+	if err != nil {
+		return "", nil, nil, fmt.Errorf("Could not read ABI from %s: %s",
+			do.AbiFlag, err)
+	}
+	function, err := spec.Function(do.FunctionFlag)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	values := make([]interface{}, len(args))
+	for i, arg := range args {
+		values[i] = arg
+	}
+	data, err := function.Pack(values...)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	return fmt.Sprintf("%X", data), spec, function, nil
+}
+
+// logReturn logs the values returned by a call to function, by output name
+func logReturn(function *abi.Function, ret []byte,
+	logger logging_types.InfoTraceLogger) error {
+	values, err := function.UnpackOutputs(ret)
+	if err != nil {
+		return fmt.Errorf("Could not decode return value %X of %s: %s", ret,
+			function.Signature(), err)
+	}
+	logging.InfoMsg(logger, "Return values",
+		append([]interface{}{"function", function.Signature()},
+			namedValues(function.Outputs, values)...)...)
+	return nil
+}
+
+// logEvents logs the logs that are instances of events in spec, by input name
+func logEvents(spec *abi.Spec, logs []txs.EventDataLog,
+	logger logging_types.InfoTraceLogger) {
+	for _, log := range logs {
+		if len(log.Topics) == 0 {
+			continue
+		}
+		event, ok := spec.EventByID(log.Topics[0])
+		if !ok {
+			logging.TraceMsg(logger, "Log is not an event in the ABI",
+				"topics", log.Topics,
+				"data", fmt.Sprintf("%X", log.Data))
+			continue
+		}
+		values, err := event.Decode(log.Topics, log.Data)
+		if err != nil {
+			logging.InfoMsg(logger, "Could not decode event",
+				"event", event.Signature(),
+				"error", err)
+			continue
+		}
+		logging.InfoMsg(logger, "Event",
+			append([]interface{}{"event", event.Signature(), "height", log.Height},
+				namedValues(event.Inputs, values)...)...)
+	}
+}
+
+func namedValues(arguments []abi.Argument, values []interface{}) []interface{} {
+	keyvals := make([]interface{}, 0, 2*len(values))
+	for i, argument := range arguments {
+		name := argument.Name
+		if name == "" {
+			name = fmt.Sprintf("%v", i)
+		}
+		keyvals = append(keyvals, name, abi.Format(argument.Type, values[i]))
+	}
+	return keyvals
+}
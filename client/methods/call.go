@@ -20,25 +20,37 @@ import (
 	"github.com/hyperledger/burrow/client"
 	"github.com/hyperledger/burrow/client/rpc"
 	"github.com/hyperledger/burrow/definitions"
-	"github.com/hyperledger/burrow/keys"
 )
 
-func Call(do *definitions.ClientDo) error {
+// Call sends a CallTx to the contract at --to. The call data is either given
+// with --data or, when --abi is provided, encoded from --function and args, in
+// which case the return value and any events emitted are decoded.
+func Call(do *definitions.ClientDo, args []string) error {
 	// construct two clients to call out to keys server and
 	// blockchain node.
 	logger, err := loggerFromClientDo(do, "Call")
 	if err != nil {
 		return fmt.Errorf("Could not generate logging config from ClientDo: %s", err)
 	}
-	burrowKeyClient := keys.NewBurrowKeyClient(do.SignAddrFlag, logger)
+	burrowKeyClient, err := keyClientFromClientDo(do, logger)
+	if err != nil {
+		return err
+	}
+	data, spec, function, err := callData(do, args)
+	if err != nil {
+		return err
+	}
 	burrowNodeClient := client.NewBurrowNodeClient(do.NodeAddrFlag, logger)
 	// form the call transaction
 	callTransaction, err := rpc.Call(burrowNodeClient, burrowKeyClient,
 		do.PubkeyFlag, do.AddrFlag, do.ToFlag, do.AmtFlag, do.NonceFlag,
-		do.GasFlag, do.FeeFlag, do.DataFlag)
+		do.GasFlag, do.FeeFlag, data)
 	if err != nil {
 		return fmt.Errorf("Failed on forming Call Transaction: %s", err)
 	}
+	if do.UnsignedFlag {
+		return writeUnsignedTx(do, callTransaction, logger)
+	}
 	// TODO: [ben] we carry over the sign bool, but always set it to true,
 	// as we move away from and deprecate the api that allows sending unsigned
 	// transactions and relying on (our) receiving node to sign it.
@@ -49,5 +61,14 @@ func Call(do *definitions.ClientDo) error {
 		return fmt.Errorf("Failed on signing (and broadcasting) transaction: %s", err)
 	}
 	unpackSignAndBroadcast(txResult, logger)
+	if function == nil || txResult == nil {
+		return nil
+	}
+	if txResult.Exception == "" && txResult.Return != nil {
+		if err := logReturn(function, txResult.Return, logger); err != nil {
+			return err
+		}
+	}
+	logEvents(spec, txResult.Logs, logger)
 	return nil
 }
@@ -0,0 +1,169 @@
+// Copyright 2017 Monax Industries Limited
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package methods
+
+import (
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/hyperledger/burrow/client"
+	"github.com/hyperledger/burrow/client/rpc"
+	"github.com/hyperledger/burrow/definitions"
+	"github.com/hyperledger/burrow/logging"
+	"github.com/hyperledger/burrow/manager/burrow-mint/evm/abi"
+)
+
+const defaultDeployGas = "1000000"
+
+// Deploy creates the contract with the bytecode in --bin, linked against the
+// libraries in --libraries and with args encoded for the constructor in
+// --abi. It waits for the contract to be committed, checks that code exists
+// at the new address, and prints the address to stdout.
+func Deploy(do *definitions.ClientDo, args []string) error {
+	logger, err := loggerFromClientDo(do, "Deploy")
+	if err != nil {
+		return fmt.Errorf("Could not generate logging config from ClientDo: %s", err)
+	}
+	data, err := deployData(do, args)
+	if err != nil {
+		return err
+	}
+	burrowKeyClient, err := keyClientFromClientDo(do, logger)
+	if err != nil {
+		return err
+	}
+	burrowNodeClient := client.NewBurrowNodeClient(do.NodeAddrFlag, logger)
+	// a CallTx without a callee creates a contract
+	createTransaction, err := rpc.Call(burrowNodeClient, burrowKeyClient,
+		do.PubkeyFlag, do.AddrFlag, "", defaultString(do.AmtFlag, "0"),
+		do.NonceFlag, defaultString(do.GasFlag, defaultDeployGas),
+		defaultString(do.FeeFlag, "0"), data)
+	if err != nil {
+		return fmt.Errorf("Failed on forming contract creation transaction: %s", err)
+	}
+	// always wait so that we can check the contract was created
+	txResult, err := rpc.SignAndBroadcast(do.ChainidFlag, burrowNodeClient,
+		burrowKeyClient, createTransaction, true, true, true)
+	if err != nil {
+		return fmt.Errorf("Failed on signing (and broadcasting) transaction: %s", err)
+	}
+	unpackSignAndBroadcast(txResult, logger)
+	if txResult == nil || len(txResult.Address) == 0 {
+		return fmt.Errorf("Transaction did not create a contract")
+	}
+
+	account, err := burrowNodeClient.GetAccount(txResult.Address)
+	if err != nil {
+		return fmt.Errorf("Could not get contract account %X: %s",
+			txResult.Address, err)
+	}
+	if account == nil || len(account.Code) == 0 {
+		return fmt.Errorf("No code at contract address %X after deployment; "+
+			"the constructor may have failed or run out of gas", txResult.Address)
+	}
+	logging.InfoMsg(logger, "Deployed contract",
+		"Contract Address", fmt.Sprintf("%X", txResult.Address),
+		"Code Length", len(account.Code))
+	// Logging goes to stderr so the address alone on stdout can be captured
+	// by scripts
+	fmt.Printf("%X\n", txResult.Address)
+	return nil
+}
+
+// deployData returns the hex encoded contract creation data: the linked
+// bytecode followed by the encoded constructor arguments
+func deployData(do *definitions.ClientDo, args []string) (string, error) {
+	if do.BinFlag == "" {
+		return "", fmt.Errorf("Please provide the contract bytecode file with --bin")
+	}
+	bin, err := ioutil.ReadFile(do.BinFlag)
+	if err != nil {
+		return "", fmt.Errorf("Could not read bytecode from %s: %s", do.BinFlag, err)
+	}
+	libraries, err := parseLibraries(do.LibrariesFlag)
+	if err != nil {
+		return "", err
+	}
+	bytecode, err := abi.Link(strings.TrimPrefix(strings.TrimSpace(string(bin)), "0x"),
+		libraries)
+	if err != nil {
+		return "", fmt.Errorf("Could not link %s: %s", do.BinFlag, err)
+	}
+	if _, err := hex.DecodeString(bytecode); err != nil {
+		return "", fmt.Errorf("Bytecode in %s is not valid hex: %s", do.BinFlag, err)
+	}
+
+	if do.AbiFlag == "" {
+		if len(args) > 0 {
+			return "", fmt.Errorf("Please provide the contract ABI with --abi " +
+				"to pass constructor arguments")
+		}
+		return bytecode, nil
+	}
+	spec, err := abi.ReadSpecFile(do.AbiFlag)
+	if err != nil {
+		return "", fmt.Errorf("Could not read ABI from %s: %s", do.AbiFlag, err)
+	}
+	constructor := spec.Constructor
+	if constructor == nil {
+		// the default constructor takes no arguments
+		constructor = &abi.Function{}
+	}
+	values := make([]interface{}, len(args))
+	for i, arg := range args {
+		values[i] = arg
+	}
+	encodedArgs, err := constructor.PackConstructor(values...)
+	if err != nil {
+		return "", err
+	}
+	return bytecode + fmt.Sprintf("%x", encodedArgs), nil
+}
+
+// parseLibraries parses library addresses given as Name:Address pairs
+// separated by commas
+func parseLibraries(librariesString string) (map[string]abi.Address, error) {
+	libraries := make(map[string]abi.Address)
+	for _, library := range strings.Split(librariesString, ",") {
+		library = strings.TrimSpace(library)
+		if library == "" {
+			continue
+		}
+		// split on the last colon since names may be qualified as file.sol:Name
+		i := strings.LastIndex(library, ":")
+		if i < 1 {
+			return nil, fmt.Errorf("Library '%s' should be given as Name:Address",
+				library)
+		}
+		addressBytes, err := hex.DecodeString(library[i+1:])
+		if err != nil || len(addressBytes) != abi.AddressLength {
+			return nil, fmt.Errorf("Address of library '%s' should be %v hex "+
+				"encoded bytes", library, abi.AddressLength)
+		}
+		var address abi.Address
+		copy(address[:], addressBytes)
+		libraries[library[:i]] = address
+	}
+	return libraries, nil
+}
+
+func defaultString(value, defaultValue string) string {
+	if value == "" {
+		return defaultValue
+	}
+	return value
+}
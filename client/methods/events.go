@@ -0,0 +1,176 @@
+// Copyright 2017 Monax Industries Limited
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package methods
+
+import (
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/hyperledger/burrow/client"
+	"github.com/hyperledger/burrow/definitions"
+	"github.com/hyperledger/burrow/logging"
+	logging_types "github.com/hyperledger/burrow/logging/types"
+	"github.com/hyperledger/burrow/manager/burrow-mint/evm/abi"
+	"github.com/hyperledger/burrow/txs"
+
+	"github.com/tendermint/go-wire"
+)
+
+// Event types that can be streamed
+const (
+	EventTypeLog      = "log"
+	EventTypeCall     = "call"
+	EventTypeInput    = "input"
+	EventTypeOutput   = "output"
+	EventTypeNewBlock = "newblock"
+)
+
+// A line of output of Events
+type eventLine struct {
+	Event      string        `json:"event"`
+	Height     int           `json:"height,omitempty"`
+	Backfilled bool          `json:"backfilled,omitempty"`
+	Data       txs.EventData `json:"data"`
+	// Log decoded with --abi
+	Decoded *decodedLog `json:"decoded,omitempty"`
+}
+
+type decodedLog struct {
+	Event  string         `json:"event"`
+	Values []decodedValue `json:"values"`
+}
+
+type decodedValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// Events streams the events of --type for --address to stdout as JSON lines
+// until interrupted
+func Events(do *definitions.ClientDo) error {
+	logger, err := loggerFromClientDo(do, "Events")
+	if err != nil {
+		return fmt.Errorf("Could not generate logging config from ClientDo: %s", err)
+	}
+	eventId, err := eventIdFromClientDo(do)
+	if err != nil {
+		return err
+	}
+	fromHeight := 0
+	if do.FromHeightFlag != "" {
+		fromHeight, err = strconv.Atoi(do.FromHeightFlag)
+		if err != nil || fromHeight < 1 {
+			return fmt.Errorf("Height to stream events from (%s) should be a "+
+				"positive integer", do.FromHeightFlag)
+		}
+	}
+	var spec *abi.Spec
+	if do.AbiFlag != "" {
+		if do.EventTypeFlag != EventTypeLog {
+			return fmt.Errorf("An ABI can only be used to decode log events")
+		}
+		spec, err = abi.ReadSpecFile(do.AbiFlag)
+		if err != nil {
+			return fmt.Errorf("Could not read ABI from %s: %s", do.AbiFlag, err)
+		}
+	}
+
+	burrowNodeClient := client.NewBurrowNodeClient(do.NodeAddrFlag, logger)
+	// Stream until the process is interrupted
+	events, err := client.StreamEvents(burrowNodeClient, eventId, fromHeight,
+		make(chan struct{}))
+	if err != nil {
+		return err
+	}
+	logging.TraceMsg(logger, "Streaming events",
+		"event", eventId,
+		"from_height", fromHeight)
+	for event := range events {
+		line := &eventLine{
+			Event:      event.Event,
+			Height:     event.Height,
+			Backfilled: event.Backfilled,
+			Data:       event.Data,
+		}
+		if spec != nil {
+			line.Decoded = decodeLog(spec, event.Data, logger)
+		}
+		if _, err := fmt.Fprintf(os.Stdout, "%s\n", wire.JSONBytes(line)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func eventIdFromClientDo(do *definitions.ClientDo) (string, error) {
+	if do.EventTypeFlag == EventTypeNewBlock {
+		return txs.EventStringNewBlock(), nil
+	}
+	address, err := hex.DecodeString(strings.TrimPrefix(do.EventAddressFlag, "0x"))
+	if err != nil || len(address) != 20 {
+		return "", fmt.Errorf("Please provide the 20 byte hex address of the "+
+			"account to stream %s events of with --address, got '%s'",
+			do.EventTypeFlag, do.EventAddressFlag)
+	}
+	switch do.EventTypeFlag {
+	case EventTypeLog:
+		return txs.EventStringLogEvent(address), nil
+	case EventTypeCall:
+		return txs.EventStringAccCall(address), nil
+	case EventTypeInput:
+		return txs.EventStringAccInput(address), nil
+	case EventTypeOutput:
+		return txs.EventStringAccOutput(address), nil
+	}
+	return "", fmt.Errorf("Unknown event type '%s', expected one of %s, %s, %s, "+
+		"%s, or %s", do.EventTypeFlag, EventTypeLog, EventTypeCall,
+		EventTypeInput, EventTypeOutput, EventTypeNewBlock)
+}
+
+// decodeLog decodes a log by the event of spec it is an instance of, or
+// returns nil for logs of unknown or anonymous events
+func decodeLog(spec *abi.Spec, data txs.EventData,
+	logger logging_types.InfoTraceLogger) *decodedLog {
+	eventLog, ok := data.(txs.EventDataLog)
+	if !ok || len(eventLog.Topics) == 0 {
+		return nil
+	}
+	event, ok := spec.EventByID(eventLog.Topics[0])
+	if !ok {
+		return nil
+	}
+	values, err := event.Decode(eventLog.Topics, eventLog.Data)
+	if err != nil {
+		logging.InfoMsg(logger, "Could not decode log",
+			"event", event.Signature(),
+			"error", err)
+		return nil
+	}
+	decoded := &decodedLog{Event: event.Signature()}
+	for i, input := range event.Inputs {
+		name := input.Name
+		if name == "" {
+			name = strconv.Itoa(i)
+		}
+		decoded.Values = append(decoded.Values, decodedValue{
+			Name:  name,
+			Value: abi.Format(input.Type, values[i]),
+		})
+	}
+	return decoded
+}
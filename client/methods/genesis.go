@@ -0,0 +1,191 @@
+// Copyright 2017 Monax Industries Limited
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package methods
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/hyperledger/burrow/genesis"
+	"github.com/hyperledger/burrow/manager/burrow-mint/state"
+
+	dbm "github.com/tendermint/go-db"
+	"github.com/tendermint/go-wire"
+)
+
+// MakeGenesisFromSpec generates the keys of the chain the TOML genesis spec at
+// specPath declares (see genesis.GenesisSpec) and writes to outputDir its
+// genesis.json, a priv_validator.json in a directory named for each validator,
+// and the keys of the other accounts in keys/
+func MakeGenesisFromSpec(specPath, outputDir string) error {
+	spec, err := genesis.LoadGenesisSpec(specPath)
+	if err != nil {
+		return err
+	}
+	genDoc, keys, err := spec.GenesisDoc(time.Now())
+	if err != nil {
+		return fmt.Errorf("Invalid genesis spec %s: %v", specPath, err)
+	}
+	genesisBytes, err := genesis.GetGenesisFileBytes(genDoc)
+	if err != nil {
+		return err
+	}
+	if err := validateGenesis(genesisBytes, genDoc); err != nil {
+		return fmt.Errorf("Generated genesis is invalid: %v", err)
+	}
+
+	if err := os.MkdirAll(outputDir, 0700); err != nil {
+		return err
+	}
+	genesisPath := filepath.Join(outputDir, "genesis.json")
+	if err := ioutil.WriteFile(genesisPath, genesisBytes, 0644); err != nil {
+		return err
+	}
+	fmt.Println(genesisPath)
+	for _, key := range keys {
+		var keyPath string
+		var keyBytes []byte
+		if key.Validator {
+			keyPath = filepath.Join(outputDir, key.Name, "priv_validator.json")
+			keyBytes, err = json.MarshalIndent(
+				genesis.NewGenesisPrivateValidator(key.PrivAccount), "", "\t")
+		} else {
+			keyPath = filepath.Join(outputDir, "keys", key.Name+".json")
+			keyBytes, err = indentJSON(wire.JSONBytes(key.PrivAccount))
+		}
+		if err != nil {
+			return err
+		}
+		if err := os.MkdirAll(filepath.Dir(keyPath), 0700); err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(keyPath, keyBytes, 0600); err != nil {
+			return err
+		}
+		fmt.Println(keyPath)
+	}
+	return nil
+}
+
+// The outcome of verifying a genesis file, with the hash of its genesis state
+// as reported by nodes when it is valid and any warnings (see genesis.Warnings)
+type GenesisVerification struct {
+	Valid       bool                    `json:"valid"`
+	GenesisHash string                  `json:"genesis_hash,omitempty"`
+	Problems    genesis.GenesisProblems `json:"problems"`
+	Warnings    genesis.GenesisProblems `json:"warnings,omitempty"`
+}
+
+// VerifyGenesis checks the genesis file at genesisPath could start a chain,
+// printing the problems found with it, or its genesis hash if there are none,
+// as text or as a GenesisVerification in JSON. An error is returned if the
+// file is invalid.
+func VerifyGenesis(genesisPath string, jsonOutput bool) error {
+	genesisBytes, err := ioutil.ReadFile(genesisPath)
+	if err != nil {
+		return err
+	}
+	verification := verifyGenesis(genesisBytes)
+	if jsonOutput {
+		if verification.Problems == nil {
+			verification.Problems = genesis.GenesisProblems{}
+		}
+		verificationBytes, err := json.MarshalIndent(verification, "", "\t")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(verificationBytes))
+	} else if verification.Valid {
+		for _, warning := range verification.Warnings {
+			fmt.Printf("Warning: %v\n", warning)
+		}
+		fmt.Printf("%s is valid with genesis hash %s\n", genesisPath, verification.GenesisHash)
+	} else {
+		for _, problem := range verification.Problems {
+			fmt.Println(problem)
+		}
+	}
+	if !verification.Valid {
+		return fmt.Errorf("%s has %d problem(s)", genesisPath, len(verification.Problems))
+	}
+	return nil
+}
+
+func verifyGenesis(genesisBytes []byte) *GenesisVerification {
+	genDoc, err := genesis.GenesisDocFromJSON(genesisBytes)
+	if err != nil {
+		return &GenesisVerification{Problems: err.(genesis.GenesisProblems)}
+	}
+	if problems := genesis.Validate(genDoc); len(problems) > 0 {
+		return &GenesisVerification{Problems: problems}
+	}
+	genesisState, err := state.MakeGenesisState(dbm.NewMemDB(), genDoc)
+	if err != nil {
+		return &GenesisVerification{Problems: genesis.GenesisProblems{
+			{Message: err.Error()},
+		}}
+	}
+	return &GenesisVerification{
+		Valid:       true,
+		GenesisHash: fmt.Sprintf("%X", genesisState.Hash()),
+		Warnings:    genesis.Warnings(genDoc),
+	}
+}
+
+// Check the genesis file reads back as genDoc and makes a genesis state
+// holding its accounts
+func validateGenesis(genesisBytes []byte, genDoc *genesis.GenesisDoc) error {
+	loadedGenDoc, err := genesis.GenesisDocFromJSON(genesisBytes)
+	if err != nil {
+		return err
+	}
+	if problems := genesis.Validate(loadedGenDoc); len(problems) > 0 {
+		return problems
+	}
+	if len(loadedGenDoc.Accounts) != len(genDoc.Accounts) ||
+		len(loadedGenDoc.Validators) != len(genDoc.Validators) {
+		return fmt.Errorf("genesis file does not read back as the genesis it was written from")
+	}
+	genesisState, err := state.MakeGenesisState(dbm.NewMemDB(), loadedGenDoc)
+	if err != nil {
+		return err
+	}
+	for _, genAcc := range genDoc.Accounts {
+		acc := genesisState.GetAccount(genAcc.Address)
+		if acc == nil || acc.Balance != genAcc.Amount {
+			return fmt.Errorf("account %s (%X) is missing from the genesis state",
+				genAcc.Name, genAcc.Address)
+		}
+	}
+	for _, role := range genDoc.Params.Roles {
+		if genesisState.GetRole(role.Name) == nil {
+			return fmt.Errorf("role %s is missing from the genesis state", role.Name)
+		}
+	}
+	return nil
+}
+
+func indentJSON(jsonBytes []byte) ([]byte, error) {
+	buf := new(bytes.Buffer)
+	if err := json.Indent(buf, jsonBytes, "", "\t"); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,118 @@
+// Copyright 2017 Monax Industries Limited
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package methods
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	acm "github.com/hyperledger/burrow/account"
+	"github.com/hyperledger/burrow/genesis"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tendermint/go-wire"
+	tm_types "github.com/tendermint/tendermint/types"
+)
+
+const genesisSpecTOML = `
+chain_id = "make-genesis-test"
+
+[permission_sets.validator]
+grant = ["bond"]
+
+[[validators]]
+name = "validator"
+count = 2
+bond = 1000
+permissions = "validator"
+
+[[accounts]]
+name = "user"
+count = 2
+amount = 500
+`
+
+func TestMakeGenesisFromSpec(t *testing.T) {
+	dir, err := ioutil.TempDir(os.TempDir(), "make-genesis-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	specPath := filepath.Join(dir, "chain.toml")
+	if err := ioutil.WriteFile(specPath, []byte(genesisSpecTOML), 0600); err != nil {
+		t.Fatal(err)
+	}
+	outputDir := filepath.Join(dir, "chain")
+	if !assert.NoError(t, MakeGenesisFromSpec(specPath, outputDir)) {
+		return
+	}
+
+	genesisBytes, err := ioutil.ReadFile(filepath.Join(outputDir, "genesis.json"))
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.True(t, verifyGenesis(genesisBytes).Valid)
+	genDoc, err := genesis.GenesisDocFromJSON(genesisBytes)
+	if !assert.NoError(t, err) || !assert.Len(t, genDoc.Validators, 2) ||
+		!assert.Len(t, genDoc.Accounts, 4) {
+		return
+	}
+
+	// Each validator gets a priv_validator.json tendermint can load, holding the
+	// key of its genesis validator
+	for _, validator := range genDoc.Validators {
+		keyPath := filepath.Join(outputDir, validator.Name, "priv_validator.json")
+		assertPrivate(t, keyPath)
+		keyBytes, err := ioutil.ReadFile(keyPath)
+		if !assert.NoError(t, err) {
+			continue
+		}
+		privValidator := new(tm_types.PrivValidator)
+		wire.ReadJSONPtr(privValidator, keyBytes, &err)
+		if assert.NoError(t, err, keyPath) {
+			assert.Equal(t, validator.PubKey, privValidator.PubKey)
+			assert.Equal(t, validator.PubKey.Address(), privValidator.Address)
+			assert.Equal(t, validator.PubKey, privValidator.PrivKey.PubKey())
+		}
+	}
+
+	// and the other accounts get a key file in keys/
+	for _, genAcc := range genDoc.Accounts[2:] {
+		keyPath := filepath.Join(outputDir, "keys", genAcc.Name+".json")
+		assertPrivate(t, keyPath)
+		keyBytes, err := ioutil.ReadFile(keyPath)
+		if !assert.NoError(t, err) {
+			continue
+		}
+		privAccount := new(acm.PrivAccount)
+		wire.ReadJSONPtr(privAccount, keyBytes, &err)
+		if assert.NoError(t, err, keyPath) {
+			assert.Equal(t, genAcc.Address, privAccount.Address)
+			assert.Equal(t, privAccount.PubKey, privAccount.PrivKey.PubKey())
+		}
+	}
+	_, err = os.Stat(filepath.Join(outputDir, "keys", genDoc.Accounts[0].Name+".json"))
+	assert.True(t, os.IsNotExist(err), "Expected no key file for a validator in keys/")
+}
+
+// Keys must only be readable by their owner
+func assertPrivate(t *testing.T, keyPath string) {
+	info, err := os.Stat(keyPath)
+	if assert.NoError(t, err) {
+		assert.Equal(t, os.FileMode(0600), info.Mode().Perm(), keyPath)
+	}
+}
@@ -15,9 +15,12 @@
 package methods
 
 import (
+	"fmt"
+
 	"github.com/hyperledger/burrow/client/rpc"
 	"github.com/hyperledger/burrow/core"
 	"github.com/hyperledger/burrow/definitions"
+	"github.com/hyperledger/burrow/keys"
 	"github.com/hyperledger/burrow/logging"
 	"github.com/hyperledger/burrow/logging/lifecycle"
 	logging_types "github.com/hyperledger/burrow/logging/types"
@@ -59,3 +62,22 @@ func loggerFromClientDo(do *definitions.ClientDo, scope string) (logging_types.I
 	lifecycle.CaptureTendermintLog15Output(logger)
 	return logger, nil
 }
+
+// keyClientFromClientDo constructs the KeyClient for the signing backend
+// selected with --keys-backend
+func keyClientFromClientDo(do *definitions.ClientDo,
+	logger logging_types.InfoTraceLogger) (keys.KeyClient, error) {
+	switch do.KeysBackendFlag {
+	case keys.MonaxKeysBackend, "":
+		return keys.NewBurrowKeyClient(do.SignAddrFlag, logger), nil
+	case keys.KeyStoreBackend:
+		return keys.NewKeyStoreKeyClient(do.KeyStoreDirFlag,
+			do.KeyStorePasswordFlag, logger)
+	case keys.RemoteSignerBackend:
+		return keys.NewRemoteSignerKeyClient(do.SignAddrFlag, logger)
+	default:
+		return nil, fmt.Errorf("Unknown keys backend '%s', expected one of "+
+			"%s, %s, or %s", do.KeysBackendFlag, keys.MonaxKeysBackend,
+			keys.KeyStoreBackend, keys.RemoteSignerBackend)
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright 2017 Monax Industries Limited
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package methods
+
+import (
+	"fmt"
+
+	acm "github.com/hyperledger/burrow/account"
+	"github.com/hyperledger/burrow/definitions"
+	"github.com/hyperledger/burrow/keys"
+	"github.com/hyperledger/burrow/logging"
+)
+
+// GenerateKey generates a key of --key-type in the keystore directory and
+// prints its address under --address-scheme on stdout
+func GenerateKey(do *definitions.ClientDo) error {
+	logger, err := loggerFromClientDo(do, "GenerateKey")
+	if err != nil {
+		return fmt.Errorf("Could not generate logging config from ClientDo: %s", err)
+	}
+	keyType, err := acm.KeyTypeFromString(do.KeyTypeFlag)
+	if err != nil {
+		return err
+	}
+	addressScheme, err := keyAddressScheme(keyType, do.AddressSchemeFlag)
+	if err != nil {
+		return err
+	}
+	if do.KeyStoreDirFlag == "" {
+		return fmt.Errorf("Please provide the keystore directory with " +
+			"--keystore-dir or $BURROW_CLIENT_KEYSTORE_DIR")
+	}
+	keyStore, err := keys.NewKeyStoreKeyClient(do.KeyStoreDirFlag,
+		do.KeyStorePasswordFlag, logger)
+	if err != nil {
+		return err
+	}
+	address, err := keyStore.GenerateWithType(keyType, addressScheme)
+	if err != nil {
+		return err
+	}
+	logging.TraceMsg(logger, "Generated key",
+		"key type", keyType,
+		"address scheme", addressScheme,
+		"address", fmt.Sprintf("%X", address))
+	fmt.Printf("%X\n", address)
+	return nil
+}
+
+// keyAddressScheme returns the address scheme to give a key of keyType its
+// address by. Secp256k1 keys have different addresses under each scheme so
+// the scheme of the chain must be given rather than guessed.
+func keyAddressScheme(keyType acm.KeyType, addressScheme string) (string, error) {
+	if err := acm.ValidateAddressScheme(addressScheme); err != nil {
+		return "", err
+	}
+	if addressScheme == "" {
+		if keyType == acm.KeyTypeSecp256k1 {
+			return "", fmt.Errorf("The address of a secp256k1 key depends on the "+
+				"address scheme of the chain it is used on, so please provide it with "+
+				"--address-scheme (%s or %s) or $BURROW_CLIENT_ADDRESS_SCHEME",
+				acm.AddressSchemeTendermint, acm.AddressSchemeEthereum)
+		}
+		return acm.AddressSchemeTendermint, nil
+	}
+	return addressScheme, nil
+}
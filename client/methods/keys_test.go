@@ -0,0 +1,44 @@
+// Copyright 2017 Monax Industries Limited
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package methods
+
+import (
+	"testing"
+
+	acm "github.com/hyperledger/burrow/account"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeyAddressScheme(t *testing.T) {
+	// ed25519 keys have the same address under either scheme
+	scheme, err := keyAddressScheme(acm.KeyTypeEd25519, "")
+	assert.NoError(t, err)
+	assert.Equal(t, acm.AddressSchemeTendermint, scheme)
+
+	// but secp256k1 keys do not, so the scheme must be given
+	_, err = keyAddressScheme(acm.KeyTypeSecp256k1, "")
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "--address-scheme")
+	}
+	for _, given := range []string{acm.AddressSchemeTendermint, acm.AddressSchemeEthereum} {
+		scheme, err = keyAddressScheme(acm.KeyTypeSecp256k1, given)
+		assert.NoError(t, err)
+		assert.Equal(t, given, scheme)
+	}
+
+	_, err = keyAddressScheme(acm.KeyTypeSecp256k1, "bitcoin")
+	assert.Error(t, err)
+}
@@ -0,0 +1,105 @@
+// Copyright 2017 Monax Industries Limited
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package methods
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"github.com/hyperledger/burrow/client"
+	"github.com/hyperledger/burrow/client/rpc"
+	"github.com/hyperledger/burrow/definitions"
+)
+
+// Name sends a NameTx registering, updating or (with no data and an amount
+// covering only the fee) removing the entry for --name. Names nested beneath
+// a registered name, such as a/b beneath a, can only be registered by an
+// account controlling the name they are nested beneath, whose owner may also
+// remove them.
+func Name(do *definitions.ClientDo) error {
+	logger, err := loggerFromClientDo(do, "Name")
+	if err != nil {
+		return fmt.Errorf("Could not generate logging config from ClientDo: %s", err)
+	}
+	data, err := nameData(do)
+	if err != nil {
+		return err
+	}
+	burrowKeyClient, err := keyClientFromClientDo(do, logger)
+	if err != nil {
+		return err
+	}
+	burrowNodeClient := client.NewBurrowNodeClient(do.NodeAddrFlag, logger)
+	nameTransaction, err := rpc.Name(burrowNodeClient, burrowKeyClient,
+		do.PubkeyFlag, do.AddrFlag, do.AmtFlag, do.NonceFlag, do.FeeFlag,
+		do.NameFlag, data)
+	if err != nil {
+		return fmt.Errorf("Failed on forming Name Transaction: %s", err)
+	}
+	if do.UnsignedFlag {
+		return writeUnsignedTx(do, nameTransaction, logger)
+	}
+	txResult, err := rpc.SignAndBroadcast(do.ChainidFlag, burrowNodeClient, burrowKeyClient,
+		nameTransaction, true, do.BroadcastFlag, do.WaitFlag)
+	if err != nil {
+		return fmt.Errorf("Failed on signing (and broadcasting) transaction: %s", err)
+	}
+	unpackSignAndBroadcast(txResult, logger)
+	return nil
+}
+
+// TransferName sends a NameTransferTx handing --name to the account at --to.
+// It can be sent by the owner of the name or of any name it is nested beneath.
+func TransferName(do *definitions.ClientDo) error {
+	logger, err := loggerFromClientDo(do, "TransferName")
+	if err != nil {
+		return fmt.Errorf("Could not generate logging config from ClientDo: %s", err)
+	}
+	burrowKeyClient, err := keyClientFromClientDo(do, logger)
+	if err != nil {
+		return err
+	}
+	burrowNodeClient := client.NewBurrowNodeClient(do.NodeAddrFlag, logger)
+	transferTransaction, err := rpc.NameTransfer(burrowNodeClient, burrowKeyClient,
+		do.PubkeyFlag, do.AddrFlag, do.AmtFlag, do.NonceFlag, do.NameFlag, do.ToFlag)
+	if err != nil {
+		return fmt.Errorf("Failed on forming Name Transfer Transaction: %s", err)
+	}
+	if do.UnsignedFlag {
+		return writeUnsignedTx(do, transferTransaction, logger)
+	}
+	txResult, err := rpc.SignAndBroadcast(do.ChainidFlag, burrowNodeClient, burrowKeyClient,
+		transferTransaction, true, do.BroadcastFlag, do.WaitFlag)
+	if err != nil {
+		return fmt.Errorf("Failed on signing (and broadcasting) transaction: %s", err)
+	}
+	unpackSignAndBroadcast(txResult, logger)
+	return nil
+}
+
+// The data for a name is given either with --data or read from --data-file
+func nameData(do *definitions.ClientDo) (string, error) {
+	if do.DataFileFlag == "" {
+		return do.DataFlag, nil
+	}
+	if do.DataFlag != "" {
+		return "", fmt.Errorf("Only one of --data and --data-file may be given")
+	}
+	data, err := ioutil.ReadFile(do.DataFileFlag)
+	if err != nil {
+		return "", fmt.Errorf("Could not read data file %s: %s", do.DataFileFlag, err)
+	}
+	return string(data), nil
+}
@@ -0,0 +1,174 @@
+// Copyright 2017 Monax Industries Limited
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package methods
+
+import (
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/hyperledger/burrow/client"
+	"github.com/hyperledger/burrow/client/rpc"
+	"github.com/hyperledger/burrow/definitions"
+	"github.com/hyperledger/burrow/logging"
+	logging_types "github.com/hyperledger/burrow/logging/types"
+	"github.com/hyperledger/burrow/txs"
+)
+
+// Transactions are passed between building, signing, and broadcasting as the
+// go-wire JSON encoding of txs.Tx in a file, or on stdin and stdout for "-".
+// The transaction hash does not depend on signatures so is logged at every
+// step to check that the same transaction is being handled.
+
+// writeUnsignedTx writes tx to --output to be signed with burrow-client sign
+func writeUnsignedTx(do *definitions.ClientDo, tx txs.Tx,
+	logger logging_types.InfoTraceLogger) error {
+	if err := writeTxFile(do.OutputFlag, tx); err != nil {
+		return err
+	}
+	logging.InfoMsg(logger, "Wrote unsigned transaction",
+		"transaction hash", fmt.Sprintf("%X", txs.TxHash(do.ChainidFlag, tx)),
+		"transaction", tx,
+		"file", fileNameOrStdout(do.OutputFlag))
+	return nil
+}
+
+// Sign signs the transaction in txFile, which need not have been built on
+// this machine, and writes it to --output or back to txFile. The inputs of a
+// SendTx may belong to different key holders so only the input for --addr,
+// or every input whose key is available, is signed and the inputs still to be
+// signed are logged.
+func Sign(do *definitions.ClientDo, txFile string) error {
+	logger, err := loggerFromClientDo(do, "Sign")
+	if err != nil {
+		return fmt.Errorf("Could not generate logging config from ClientDo: %s", err)
+	}
+	burrowKeyClient, err := keyClientFromClientDo(do, logger)
+	if err != nil {
+		return err
+	}
+	tx, err := readTxFile(txFile)
+	if err != nil {
+		return err
+	}
+	txHash := fmt.Sprintf("%X", txs.TxHash(do.ChainidFlag, tx))
+	logging.InfoMsg(logger, "Signing transaction",
+		"transaction hash", txHash,
+		"transaction", tx)
+	var signedBy [][]byte
+	if sendTx, ok := tx.(*txs.SendTx); ok {
+		var addresses [][]byte
+		if do.AddrFlag != "" {
+			address, err := hex.DecodeString(do.AddrFlag)
+			if err != nil {
+				return fmt.Errorf("Bad hex string for address (%s): %v", do.AddrFlag, err)
+			}
+			addresses = [][]byte{address}
+		}
+		signedBy, err = rpc.SignSendTxInputs(burrowKeyClient, do.ChainidFlag, sendTx,
+			addresses)
+	} else {
+		var inputAddr []byte
+		inputAddr, tx, err = rpc.SignTx(burrowKeyClient, do.ChainidFlag, tx)
+		signedBy = [][]byte{inputAddr}
+	}
+	if err != nil {
+		return fmt.Errorf("Failed on signing transaction: %s", err)
+	}
+	output := do.OutputFlag
+	if output == "" {
+		output = txFile
+	}
+	if err := writeTxFile(output, tx); err != nil {
+		return err
+	}
+	logging.InfoMsg(logger, "Wrote signed transaction",
+		"transaction hash", txHash,
+		"signed by", fmt.Sprintf("%X", signedBy),
+		"file", fileNameOrStdout(output))
+	if unsigned := rpc.UnsignedInputs(tx); len(unsigned) > 0 {
+		logging.InfoMsg(logger, "Transaction needs more signatures before it can be broadcast",
+			"transaction hash", txHash,
+			"unsigned inputs", fmt.Sprintf("%X", unsigned))
+	}
+	return nil
+}
+
+// Broadcast checks the signatures of the transaction in txFile and broadcasts
+// it, waiting for it to be committed if --wait is set
+func Broadcast(do *definitions.ClientDo, txFile string) error {
+	logger, err := loggerFromClientDo(do, "Broadcast")
+	if err != nil {
+		return fmt.Errorf("Could not generate logging config from ClientDo: %s", err)
+	}
+	tx, err := readTxFile(txFile)
+	if err != nil {
+		return err
+	}
+	logging.InfoMsg(logger, "Broadcasting transaction",
+		"transaction hash", fmt.Sprintf("%X", txs.TxHash(do.ChainidFlag, tx)),
+		"transaction", tx)
+	if unsigned := rpc.UnsignedInputs(tx); len(unsigned) > 0 {
+		return fmt.Errorf("Transaction still needs to be signed for inputs %X "+
+			"with burrow-client sign", unsigned)
+	}
+	if err := rpc.VerifyTxSignatures(do.ChainidFlag, tx); err != nil {
+		return fmt.Errorf("Transaction is not ready to broadcast for chain %s: %s",
+			do.ChainidFlag, err)
+	}
+	burrowNodeClient := client.NewBurrowNodeClient(do.NodeAddrFlag, logger)
+	txResult, err := rpc.BroadcastTx(do.ChainidFlag, burrowNodeClient, tx, do.WaitFlag)
+	if err != nil {
+		return fmt.Errorf("Failed on broadcasting transaction: %s", err)
+	}
+	unpackSignAndBroadcast(txResult, logger)
+	return nil
+}
+
+func readTxFile(fileName string) (txs.Tx, error) {
+	var txJSON []byte
+	var err error
+	if fileName == "-" {
+		txJSON, err = ioutil.ReadAll(os.Stdin)
+	} else {
+		txJSON, err = ioutil.ReadFile(fileName)
+	}
+	if err != nil {
+		return nil, err
+	}
+	tx, err := txs.DecodeTxJSON(txJSON)
+	if err != nil {
+		return nil, fmt.Errorf("Could not read transaction from %s: %s",
+			fileNameOrStdout(fileName), err)
+	}
+	return tx, nil
+}
+
+func writeTxFile(fileName string, tx txs.Tx) error {
+	txJSON := append(txs.EncodeTxJSON(tx), '\n')
+	if fileName == "" || fileName == "-" {
+		_, err := os.Stdout.Write(txJSON)
+		return err
+	}
+	return ioutil.WriteFile(fileName, txJSON, 0600)
+}
+
+func fileNameOrStdout(fileName string) string {
+	if fileName == "" || fileName == "-" {
+		return "stdout"
+	}
+	return fileName
+}
@@ -0,0 +1,53 @@
+// Copyright 2017 Monax Industries Limited
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package methods
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/burrow/client"
+	"github.com/hyperledger/burrow/client/rpc"
+	"github.com/hyperledger/burrow/definitions"
+)
+
+func Permissions(do *definitions.ClientDo, permFunc string, args []string) error {
+	// construct two clients to call out to keys server and
+	// blockchain node.
+	logger, err := loggerFromClientDo(do, "Permissions")
+	if err != nil {
+		return fmt.Errorf("Could not generate logging config from ClientDo: %s", err)
+	}
+	burrowKeyClient, err := keyClientFromClientDo(do, logger)
+	if err != nil {
+		return err
+	}
+	burrowNodeClient := client.NewBurrowNodeClient(do.NodeAddrFlag, logger)
+	// form the permissions transaction
+	permissionsTransaction, err := rpc.Permissions(burrowNodeClient, burrowKeyClient,
+		do.PubkeyFlag, do.AddrFlag, do.NonceFlag, permFunc, args)
+	if err != nil {
+		return fmt.Errorf("Failed on forming Permissions Transaction: %s", err)
+	}
+	if do.UnsignedFlag {
+		return writeUnsignedTx(do, permissionsTransaction, logger)
+	}
+	txResult, err := rpc.SignAndBroadcast(do.ChainidFlag, burrowNodeClient, burrowKeyClient,
+		permissionsTransaction, true, do.BroadcastFlag, do.WaitFlag)
+	if err != nil {
+		return fmt.Errorf("Failed on signing (and broadcasting) transaction: %s", err)
+	}
+	unpackSignAndBroadcast(txResult, logger)
+	return nil
+}
@@ -0,0 +1,65 @@
+// Copyright 2017 Monax Industries Limited
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package methods
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"github.com/hyperledger/burrow/client"
+	"github.com/hyperledger/burrow/definitions"
+	"github.com/hyperledger/burrow/logging"
+)
+
+// Query runs a call against the contract at --to on the node without sending
+// a transaction, so no state is changed and nothing needs to be signed.
+func Query(do *definitions.ClientDo, args []string) error {
+	logger, err := loggerFromClientDo(do, "Query")
+	if err != nil {
+		return fmt.Errorf("Could not generate logging config from ClientDo: %s", err)
+	}
+	data, _, function, err := callData(do, args)
+	if err != nil {
+		return err
+	}
+	dataBytes, err := hex.DecodeString(data)
+	if err != nil {
+		return fmt.Errorf("Data (%s) is not valid hex: %s", data, err)
+	}
+	toAddress, err := hex.DecodeString(do.ToFlag)
+	if err != nil || len(toAddress) != 20 {
+		return fmt.Errorf("Please provide the 20 byte hex address of the "+
+			"contract to query with --to, got '%s'", do.ToFlag)
+	}
+	// The caller is optional and only affects contracts that inspect it
+	callerAddress, err := hex.DecodeString(do.AddrFlag)
+	if err != nil {
+		return fmt.Errorf("Caller address (%s) is not valid hex: %s",
+			do.AddrFlag, err)
+	}
+	burrowNodeClient := client.NewBurrowNodeClient(do.NodeAddrFlag, logger)
+	ret, gasUsed, err := burrowNodeClient.QueryContract(callerAddress,
+		toAddress, dataBytes)
+	if err != nil {
+		return err
+	}
+	logging.InfoMsg(logger, "Query result",
+		"Return Value", fmt.Sprintf("%X", ret),
+		"Gas Used", gasUsed)
+	if function == nil {
+		return nil
+	}
+	return logReturn(function, ret, logger)
+}
@@ -0,0 +1,404 @@
+// Copyright 2017 Monax Industries Limited
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package methods
+
+import (
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	acm "github.com/hyperledger/burrow/account"
+	"github.com/hyperledger/burrow/client"
+	consensus_types "github.com/hyperledger/burrow/consensus/types"
+	core_types "github.com/hyperledger/burrow/core/types"
+	"github.com/hyperledger/burrow/definitions"
+	ptypes "github.com/hyperledger/burrow/permission/types"
+	"github.com/hyperledger/burrow/txs"
+
+	"github.com/tendermint/go-wire"
+	tm_types "github.com/tendermint/tendermint/types"
+)
+
+// Output formats of the read commands
+const (
+	FormatTable = "table"
+	FormatJSON  = "json"
+)
+
+// GetAccount prints the account at the address given as argument or with --addr
+func GetAccount(do *definitions.ClientDo, args []string) error {
+	nodeClient, err := readNodeClient(do, "Account")
+	if err != nil {
+		return err
+	}
+	return writeAccount(os.Stdout, nodeClient, do, args)
+}
+
+func writeAccount(out io.Writer, nodeClient client.NodeClient, do *definitions.ClientDo,
+	args []string) error {
+	address, err := addressArgument(args, do.AddrFlag)
+	if err != nil {
+		return err
+	}
+	account, err := nodeClient.GetAccount(address)
+	if err != nil {
+		return err
+	}
+	if account == nil {
+		return fmt.Errorf("There is no account at %X", address)
+	}
+	return writeOutput(out, do.FormatFlag, account, func(w io.Writer) {
+		fmt.Fprintf(w, "Address\t%X\n", account.Address)
+		if account.PubKey != nil {
+			fmt.Fprintf(w, "Public key\t%X\n", acm.PubKeyBytes(account.PubKey))
+		} else {
+			fmt.Fprintf(w, "Public key\tnone\n")
+		}
+		fmt.Fprintf(w, "Balance\t%v\n", account.Balance)
+		fmt.Fprintf(w, "Sequence\t%v\n", account.Sequence)
+		fmt.Fprintf(w, "Code\t%X\n", account.Code)
+		fmt.Fprintf(w, "Storage root\t%X\n", account.StorageRoot)
+		fmt.Fprintf(w, "Permissions\t%s\n", formatBasePermissions(&account.Permissions))
+		fmt.Fprintf(w, "Roles\t%s\n", formatRoles(&account.Permissions))
+		if cal := account.Permissions.CallAllowList; cal != nil {
+			fmt.Fprintf(w, "May call\t%s\n", formatCallAllowList(cal))
+		}
+	})
+}
+
+// GetStorage prints the storage of the contract at the address given as argument
+// or with --addr
+func GetStorage(do *definitions.ClientDo, args []string) error {
+	nodeClient, err := readNodeClient(do, "Storage")
+	if err != nil {
+		return err
+	}
+	return writeStorage(os.Stdout, nodeClient, do, args)
+}
+
+func writeStorage(out io.Writer, nodeClient client.NodeClient, do *definitions.ClientDo,
+	args []string) error {
+	address, err := addressArgument(args, do.AddrFlag)
+	if err != nil {
+		return err
+	}
+	storage, err := nodeClient.DumpStorage(address)
+	if err != nil {
+		return err
+	}
+	if storage == nil {
+		return fmt.Errorf("There is no account at %X", address)
+	}
+	return writeOutput(out, do.FormatFlag, storage, func(w io.Writer) {
+		fmt.Fprintf(w, "KEY\tVALUE\n")
+		for _, item := range storage.StorageItems {
+			fmt.Fprintf(w, "%X\t%X\n", item.Key, item.Value)
+		}
+	})
+}
+
+// GetName prints the name registry entry of the name given as argument, or all
+// entries without one
+func GetName(do *definitions.ClientDo, args []string) error {
+	nodeClient, err := readNodeClient(do, "Name")
+	if err != nil {
+		return err
+	}
+	return writeName(os.Stdout, nodeClient, do, args)
+}
+
+func writeName(out io.Writer, nodeClient client.NodeClient, do *definitions.ClientDo,
+	args []string) error {
+	if len(args) > 1 {
+		return fmt.Errorf("Please provide at most one name, got %v", len(args))
+	}
+	var output interface{}
+	var entries []*core_types.NameRegEntry
+	if len(args) == 1 {
+		owner, data, expires, err := nodeClient.GetName(args[0])
+		if err != nil {
+			return err
+		}
+		entry := &core_types.NameRegEntry{
+			Name:    args[0],
+			Owner:   owner,
+			Data:    data,
+			Expires: expires,
+		}
+		output, entries = entry, []*core_types.NameRegEntry{entry}
+	} else {
+		blockHeight, names, err := nodeClient.ListNames()
+		if err != nil {
+			return err
+		}
+		output = &struct {
+			BlockHeight int                        `json:"block_height"`
+			Names       []*core_types.NameRegEntry `json:"names"`
+		}{blockHeight, names}
+		entries = names
+	}
+	return writeOutput(out, do.FormatFlag, output, func(w io.Writer) {
+		fmt.Fprintf(w, "NAME\tOWNER\tEXPIRES\tDATA\n")
+		for _, entry := range entries {
+			fmt.Fprintf(w, "%s\t%X\t%v\t%s\n", entry.Name, entry.Owner,
+				entry.Expires, entry.Data)
+		}
+	})
+}
+
+// GetBlock prints the block at the height given as argument, or the latest block
+// without one
+func GetBlock(do *definitions.ClientDo, args []string) error {
+	nodeClient, err := readNodeClient(do, "Block")
+	if err != nil {
+		return err
+	}
+	var height int
+	switch len(args) {
+	case 0:
+		_, _, _, height, _, err = nodeClient.Status()
+		if err != nil {
+			return fmt.Errorf("Could not get the latest block height: %s", err)
+		}
+	case 1:
+		height, err = strconv.Atoi(args[0])
+		if err != nil || height < 1 {
+			return fmt.Errorf("Block height (%s) should be a positive integer", args[0])
+		}
+	default:
+		return fmt.Errorf("Please provide at most one block height, got %v", len(args))
+	}
+	blockMeta, block, err := nodeClient.GetBlock(height)
+	if err != nil {
+		return err
+	}
+	if blockMeta == nil || block == nil {
+		return fmt.Errorf("Node returned no block at height %v", height)
+	}
+	output := &struct {
+		BlockMeta *tm_types.BlockMeta `json:"block_meta"`
+		Block     *tm_types.Block     `json:"block"`
+	}{blockMeta, block}
+	return writeOutput(os.Stdout, do.FormatFlag, output, func(w io.Writer) {
+		header := block.Header
+		fmt.Fprintf(w, "Height\t%v\n", header.Height)
+		fmt.Fprintf(w, "Hash\t%X\n", blockMeta.Hash)
+		fmt.Fprintf(w, "Chain ID\t%s\n", header.ChainID)
+		fmt.Fprintf(w, "Time\t%s\n", header.Time)
+		fmt.Fprintf(w, "Last block hash\t%X\n", header.LastBlockID.Hash)
+		fmt.Fprintf(w, "App hash\t%X\n", header.AppHash)
+		fmt.Fprintf(w, "Transactions\t%v\n", header.NumTxs)
+		if len(block.Data.Txs) == 0 {
+			return
+		}
+		fmt.Fprintf(w, "\nTYPE\tFROM\tSEQUENCE\n")
+		for _, txBytes := range block.Data.Txs {
+			tx, err := txs.DecodeTx(txBytes)
+			if err != nil {
+				fmt.Fprintf(w, "undecodable\t%X\t\n", []byte(txBytes))
+				continue
+			}
+			writeTxRow(w, tx)
+		}
+	})
+}
+
+// ListValidators prints the bonded and unbonding validators
+func ListValidators(do *definitions.ClientDo) error {
+	nodeClient, err := readNodeClient(do, "Validators")
+	if err != nil {
+		return err
+	}
+	blockHeight, bonded, unbonding, err := nodeClient.ListValidators()
+	if err != nil {
+		return err
+	}
+	output := &struct {
+		BlockHeight         int                         `json:"block_height"`
+		BondedValidators    []consensus_types.Validator `json:"bonded_validators"`
+		UnbondingValidators []consensus_types.Validator `json:"unbonding_validators"`
+	}{blockHeight, bonded, unbonding}
+	return writeOutput(os.Stdout, do.FormatFlag, output, func(w io.Writer) {
+		fmt.Fprintf(w, "STATUS\tADDRESS\tPUBLIC KEY\tVOTING POWER\n")
+		for _, validator := range bonded {
+			writeValidatorRow(w, "bonded", validator)
+		}
+		for _, validator := range unbonding {
+			writeValidatorRow(w, "unbonding", validator)
+		}
+	})
+}
+
+// ListUnconfirmed prints the transactions in the mempool of the node
+func ListUnconfirmed(do *definitions.ClientDo) error {
+	nodeClient, err := readNodeClient(do, "Unconfirmed")
+	if err != nil {
+		return err
+	}
+	transactions, err := nodeClient.ListUnconfirmedTxs()
+	if err != nil {
+		return err
+	}
+	output := &struct {
+		N   int      `json:"n_txs"`
+		Txs []txs.Tx `json:"txs"`
+	}{len(transactions), transactions}
+	return writeOutput(os.Stdout, do.FormatFlag, output, func(w io.Writer) {
+		fmt.Fprintf(w, "TYPE\tFROM\tSEQUENCE\n")
+		for _, tx := range transactions {
+			writeTxRow(w, tx)
+		}
+	})
+}
+
+//------------------------------------------------------------------------------
+// Helpers
+
+// readNodeClient checks the output format before returning a node client so
+// that a wrong format is reported without contacting the node
+func readNodeClient(do *definitions.ClientDo, scope string) (client.NodeClient, error) {
+	switch do.FormatFlag {
+	case FormatTable, FormatJSON, "":
+	default:
+		return nil, fmt.Errorf("Unknown output format '%s' (use %s or %s)",
+			do.FormatFlag, FormatTable, FormatJSON)
+	}
+	logger, err := loggerFromClientDo(do, scope)
+	if err != nil {
+		return nil, fmt.Errorf("Could not generate logging config from ClientDo: %s", err)
+	}
+	return client.NewBurrowNodeClient(do.NodeAddrFlag, logger), nil
+}
+
+// writeOutput writes output as go-wire JSON to out, or as the table written
+// by table
+func writeOutput(out io.Writer, format string, output interface{}, table func(w io.Writer)) error {
+	if format == FormatJSON {
+		_, err := fmt.Fprintf(out, "%s\n", wire.JSONBytesPretty(output))
+		return err
+	}
+	w := tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+	table(w)
+	return w.Flush()
+}
+
+func addressArgument(args []string, addressFlag string) ([]byte, error) {
+	address := addressFlag
+	switch len(args) {
+	case 0:
+	case 1:
+		address = args[0]
+	default:
+		return nil, fmt.Errorf("Please provide at most one address, got %v", len(args))
+	}
+	if address == "" {
+		return nil, fmt.Errorf("Please provide an address as argument or with --addr")
+	}
+	addressBytes, err := hex.DecodeString(strings.TrimPrefix(address, "0x"))
+	if err != nil || len(addressBytes) != 20 {
+		return nil, fmt.Errorf("Address (%s) should be 20 bytes of hex", address)
+	}
+	return addressBytes, nil
+}
+
+// formatBasePermissions lists the permissions that are set, unset ones falling
+// back to the global permissions, along with the block height at which any set
+// until a block height lapse
+func formatBasePermissions(permissions *ptypes.AccountPermissions) string {
+	base := permissions.Base
+	var perms []string
+	for i := uint(0); i < ptypes.NumPermissions; i++ {
+		permFlag := ptypes.PermFlag(1) << i
+		if base.SetBit&permFlag == 0 {
+			continue
+		}
+		perms = append(perms, fmt.Sprintf("%s=%v%s", ptypes.PermFlagToString(permFlag),
+			base.Perms&permFlag != 0, formatExpiry(permissions.BaseExpiry(permFlag))))
+	}
+	if len(perms) == 0 {
+		return "global"
+	}
+	return strings.Join(perms, " ")
+}
+
+// Roles are stored right padded to 32 bytes
+func formatRoles(permissions *ptypes.AccountPermissions) string {
+	trimmed := make([]string, len(permissions.Roles))
+	for i, role := range permissions.Roles {
+		trimmed[i] = strings.TrimRight(role, "\x00") +
+			formatExpiry(permissions.RoleExpiry(role))
+	}
+	return strings.Join(trimmed, " ")
+}
+
+// An account with a CallAllowList may call only the contracts it lists
+func formatCallAllowList(cal *ptypes.CallAllowList) string {
+	if len(cal.Contracts) == 0 {
+		return "none"
+	}
+	contracts := make([]string, len(cal.Contracts))
+	for i, contract := range cal.Contracts {
+		contracts[i] = fmt.Sprintf("%X", contract)
+	}
+	return strings.Join(contracts, " ")
+}
+
+func formatExpiry(expires int64) string {
+	if expires == 0 {
+		return ""
+	}
+	return fmt.Sprintf("(until %v)", expires)
+}
+
+func writeTxRow(w io.Writer, tx txs.Tx) {
+	switch tx := tx.(type) {
+	case *txs.SendTx:
+		for _, input := range tx.Inputs {
+			fmt.Fprintf(w, "send\t%X\t%v\n", input.Address, input.Sequence)
+		}
+	case *txs.CallTx:
+		fmt.Fprintf(w, "call\t%X\t%v\n", tx.Input.Address, tx.Input.Sequence)
+	case *txs.NameTx:
+		fmt.Fprintf(w, "name\t%X\t%v\n", tx.Input.Address, tx.Input.Sequence)
+	case *txs.NameTransferTx:
+		fmt.Fprintf(w, "name-transfer\t%X\t%v\n", tx.Input.Address, tx.Input.Sequence)
+	case *txs.PermissionsTx:
+		fmt.Fprintf(w, "permissions\t%X\t%v\n", tx.Input.Address, tx.Input.Sequence)
+	case *txs.BondTx:
+		for _, input := range tx.Inputs {
+			fmt.Fprintf(w, "bond\t%X\t%v\n", input.Address, input.Sequence)
+		}
+	case *txs.UnbondTx:
+		fmt.Fprintf(w, "unbond\t%X\t\n", tx.Address)
+	case *txs.RebondTx:
+		fmt.Fprintf(w, "rebond\t%X\t\n", tx.Address)
+	default:
+		fmt.Fprintf(w, "%T\t\t\n", tx)
+	}
+}
+
+func writeValidatorRow(w io.Writer, status string, validator consensus_types.Validator) {
+	tmValidator, ok := validator.(*consensus_types.TendermintValidator)
+	if !ok || tmValidator.Validator == nil {
+		fmt.Fprintf(w, "%s\t%X\t\t\n", status, validator.Address())
+		return
+	}
+	fmt.Fprintf(w, "%s\t%X\t%X\t%v\n", status, tmValidator.Validator.Address,
+		acm.PubKeyBytes(tmValidator.PubKey), tmValidator.VotingPower)
+}
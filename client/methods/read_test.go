@@ -0,0 +1,152 @@
+// Copyright 2017 Monax Industries Limited
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package methods
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	acm "github.com/hyperledger/burrow/account"
+	"github.com/hyperledger/burrow/client/mock"
+	core_types "github.com/hyperledger/burrow/core/types"
+	"github.com/hyperledger/burrow/definitions"
+	ptypes "github.com/hyperledger/burrow/permission/types"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// A node holding the given accounts and names, which returns nil for those it
+// does not hold as the node RPC does
+type readNodeClientFake struct {
+	*mock.MockNodeClient
+	accounts map[string]*acm.Account
+	names    map[string]*core_types.NameRegEntry
+}
+
+func (fake *readNodeClientFake) GetAccount(address []byte) (*acm.Account, error) {
+	return fake.accounts[string(address)], nil
+}
+
+func (fake *readNodeClientFake) DumpStorage(address []byte) (*core_types.Storage, error) {
+	if fake.accounts[string(address)] == nil {
+		return nil, nil
+	}
+	return &core_types.Storage{StorageItems: []core_types.StorageItem{
+		{Key: []byte{1}, Value: []byte{2}},
+	}}, nil
+}
+
+func (fake *readNodeClientFake) GetName(name string) ([]byte, string, int, error) {
+	entry := fake.names[name]
+	if entry == nil {
+		return nil, "", 0, fmt.Errorf("Unknown name %s", name)
+	}
+	return entry.Owner, entry.Data, entry.Expires, nil
+}
+
+func (fake *readNodeClientFake) ListNames() (int, []*core_types.NameRegEntry, error) {
+	var names []*core_types.NameRegEntry
+	for _, entry := range fake.names {
+		names = append(names, entry)
+	}
+	return 10, names, nil
+}
+
+func newReadNodeClientFake() (*readNodeClientFake, []byte) {
+	address := bytes.Repeat([]byte{0xAB}, 20)
+	permissions := ptypes.ZeroAccountPermissions
+	permissions.Base.Set(ptypes.Send, true)
+	permissions.AddRole("auditors")
+	return &readNodeClientFake{
+		MockNodeClient: mock.NewMockNodeClient(),
+		accounts: map[string]*acm.Account{
+			string(address): {
+				Address:     address,
+				Balance:     1234,
+				Sequence:    5,
+				Permissions: permissions,
+			},
+		},
+		names: map[string]*core_types.NameRegEntry{
+			"greeting": {Name: "greeting", Owner: address, Data: "hello", Expires: 20},
+		},
+	}, address
+}
+
+func TestWriteAccount(t *testing.T) {
+	nodeClient, address := newReadNodeClientFake()
+	do := definitions.NewClientDo()
+
+	out := new(bytes.Buffer)
+	assert.NoError(t, writeAccount(out, nodeClient, do, []string{fmt.Sprintf("%X", address)}))
+	assert.Contains(t, out.String(), fmt.Sprintf("%X", address))
+	assert.Contains(t, out.String(), "1234")
+	assert.Contains(t, out.String(), "auditors")
+
+	// the address may also be given by flag, and output as JSON
+	do.AddrFlag = fmt.Sprintf("0x%X", address)
+	do.FormatFlag = FormatJSON
+	out.Reset()
+	assert.NoError(t, writeAccount(out, nodeClient, do, nil))
+	assert.Contains(t, out.String(), `"balance": 1234`)
+
+	// unknown accounts are an error rather than a panic
+	err := writeAccount(out, nodeClient, do, []string{fmt.Sprintf("%X", bytes.Repeat([]byte{1}, 20))})
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "There is no account at 0101")
+	}
+
+	do.AddrFlag = ""
+	assert.Error(t, writeAccount(out, nodeClient, do, nil))
+	assert.Error(t, writeAccount(out, nodeClient, do, []string{"ABCD"}))
+	assert.Error(t, writeAccount(out, nodeClient, do, []string{"a", "b"}))
+}
+
+func TestWriteStorage(t *testing.T) {
+	nodeClient, address := newReadNodeClientFake()
+	do := definitions.NewClientDo()
+
+	out := new(bytes.Buffer)
+	assert.NoError(t, writeStorage(out, nodeClient, do, []string{fmt.Sprintf("%X", address)}))
+	assert.Contains(t, out.String(), "KEY")
+	assert.Contains(t, out.String(), "01")
+
+	assert.Error(t, writeStorage(out, nodeClient, do, []string{fmt.Sprintf("%X", bytes.Repeat([]byte{1}, 20))}))
+}
+
+func TestWriteName(t *testing.T) {
+	nodeClient, address := newReadNodeClientFake()
+	do := definitions.NewClientDo()
+
+	out := new(bytes.Buffer)
+	assert.NoError(t, writeName(out, nodeClient, do, []string{"greeting"}))
+	assert.Contains(t, out.String(), "hello")
+	assert.Contains(t, out.String(), fmt.Sprintf("%X", address))
+
+	out.Reset()
+	assert.NoError(t, writeName(out, nodeClient, do, nil))
+	assert.Contains(t, out.String(), "greeting")
+
+	assert.Error(t, writeName(out, nodeClient, do, []string{"missing"}))
+	assert.Error(t, writeName(out, nodeClient, do, []string{"a", "b"}))
+}
+
+func TestReadNodeClientFormat(t *testing.T) {
+	do := definitions.NewClientDo()
+	do.FormatFlag = "xml"
+	_, err := readNodeClient(do, "Account")
+	assert.Error(t, err)
+}
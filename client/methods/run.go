@@ -0,0 +1,65 @@
+// Copyright 2017 Monax Industries Limited
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package methods
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/hyperledger/burrow/client"
+	"github.com/hyperledger/burrow/client/jobs"
+	"github.com/hyperledger/burrow/definitions"
+	"github.com/hyperledger/burrow/logging"
+)
+
+// Run runs the jobs in jobsFile and writes their results to --results, or
+// next to the jobs file by default
+func Run(do *definitions.ClientDo, jobsFile string) error {
+	logger, err := loggerFromClientDo(do, "Run")
+	if err != nil {
+		return fmt.Errorf("Could not generate logging config from ClientDo: %s", err)
+	}
+	jobsToRun, err := jobs.ReadJobsFile(jobsFile)
+	if err != nil {
+		return err
+	}
+	// --addr takes precedence over the account in the jobs file
+	if do.AddrFlag != "" {
+		jobsToRun.Account = do.AddrFlag
+	}
+	burrowKeyClient, err := keyClientFromClientDo(do, logger)
+	if err != nil {
+		return err
+	}
+	burrowNodeClient := client.NewBurrowNodeClient(do.NodeAddrFlag, logger)
+	runner := jobs.NewRunner(do.ChainidFlag, burrowNodeClient, burrowKeyClient, logger)
+	results, runErr := runner.Run(jobsToRun)
+	if results == nil {
+		return runErr
+	}
+
+	resultsFile := do.ResultsFileFlag
+	if resultsFile == "" {
+		resultsFile = strings.TrimSuffix(jobsFile, filepath.Ext(jobsFile)) +
+			".results.json"
+	}
+	if err := jobs.WriteResults(resultsFile, results); err != nil {
+		return fmt.Errorf("Could not write results to %s: %s", resultsFile, err)
+	}
+	logging.InfoMsg(logger, "Wrote job results",
+		"results file", resultsFile)
+	return runErr
+}
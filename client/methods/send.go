@@ -16,11 +16,11 @@ package methods
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/hyperledger/burrow/client"
 	"github.com/hyperledger/burrow/client/rpc"
 	"github.com/hyperledger/burrow/definitions"
-	"github.com/hyperledger/burrow/keys"
 )
 
 func Send(do *definitions.ClientDo) error {
@@ -30,13 +30,19 @@ func Send(do *definitions.ClientDo) error {
 	if err != nil {
 		return fmt.Errorf("Could not generate logging config from ClientDo: %s", err)
 	}
-	burrowKeyClient := keys.NewBurrowKeyClient(do.SignAddrFlag, logger)
+	burrowKeyClient, err := keyClientFromClientDo(do, logger)
+	if err != nil {
+		return err
+	}
 	burrowNodeClient := client.NewBurrowNodeClient(do.NodeAddrFlag, logger)
 	// form the send transaction
 	sendTransaction, err := rpc.Send(burrowNodeClient, burrowKeyClient,
 		do.PubkeyFlag, do.AddrFlag, do.ToFlag, do.AmtFlag, do.NonceFlag)
 	if err != nil {
-		fmt.Errorf("Failed on forming Send Transaction: %s", err)
+		return fmt.Errorf("Failed on forming Send Transaction: %s", err)
+	}
+	if do.UnsignedFlag {
+		return writeUnsignedTx(do, sendTransaction, logger)
 	}
 	// TODO: [ben] we carry over the sign bool, but always set it to true,
 	// as we move away from and deprecate the api that allows sending unsigned
@@ -49,3 +55,58 @@ func Send(do *definitions.ClientDo) error {
 	unpackSignAndBroadcast(txResult, logger)
 	return nil
 }
+
+// SendMulti sends a SendTx from the accounts in --from to the accounts in
+// --to. When the keys for all the inputs are available it is signed and
+// broadcast; otherwise write it with --unsigned and pass the file between
+// the key holders, each of whom signs their inputs with burrow-client sign,
+// before broadcasting it with burrow-client broadcast.
+func SendMulti(do *definitions.ClientDo) error {
+	logger, err := loggerFromClientDo(do, "SendMulti")
+	if err != nil {
+		return fmt.Errorf("Could not generate logging config from ClientDo: %s", err)
+	}
+	burrowNodeClient := client.NewBurrowNodeClient(do.NodeAddrFlag, logger)
+	sendTransaction, err := rpc.SendMulti(burrowNodeClient,
+		splitList(do.InputsFlag), splitList(do.OutputsFlag))
+	if err != nil {
+		return fmt.Errorf("Failed on forming Send Transaction: %s", err)
+	}
+	if do.UnsignedFlag {
+		return writeUnsignedTx(do, sendTransaction, logger)
+	}
+	burrowKeyClient, err := keyClientFromClientDo(do, logger)
+	if err != nil {
+		return err
+	}
+	_, err = rpc.SignSendTxInputs(burrowKeyClient, do.ChainidFlag, sendTransaction, nil)
+	if err != nil {
+		return fmt.Errorf("Failed on signing transaction: %s", err)
+	}
+	if unsigned := rpc.UnsignedInputs(sendTransaction); len(unsigned) > 0 {
+		return fmt.Errorf("No key is available for inputs %X; use --unsigned to "+
+			"write the transaction for each key holder to sign with burrow-client sign",
+			unsigned)
+	}
+	if !do.BroadcastFlag {
+		return writeTxFile(do.OutputFlag, sendTransaction)
+	}
+	txResult, err := rpc.BroadcastTx(do.ChainidFlag, burrowNodeClient, sendTransaction,
+		do.WaitFlag)
+	if err != nil {
+		return fmt.Errorf("Failed on broadcasting transaction: %s", err)
+	}
+	unpackSignAndBroadcast(txResult, logger)
+	return nil
+}
+
+// splitList splits a comma separated list, ignoring space around the items
+func splitList(list string) []string {
+	var items []string
+	for _, item := range strings.Split(list, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
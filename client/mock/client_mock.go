@@ -16,6 +16,7 @@ package mock
 
 import (
 	"github.com/tendermint/go-crypto"
+	tm_types "github.com/tendermint/tendermint/types"
 
 	acc "github.com/hyperledger/burrow/account"
 	. "github.com/hyperledger/burrow/client"
@@ -30,12 +31,15 @@ var _ NodeClient = (*MockNodeClient)(nil)
 
 type MockNodeClient struct {
 	accounts map[string]*acc.Account
+	nonces   NonceManager
 }
 
 func NewMockNodeClient() *MockNodeClient {
-	return &MockNodeClient{
+	mock := &MockNodeClient{
 		accounts: make(map[string]*acc.Account),
 	}
+	mock.nonces = NewNonceManager(mock)
+	return mock
 }
 
 func (mock *MockNodeClient) Broadcast(transaction txs.Tx) (*txs.Receipt, error) {
@@ -112,10 +116,26 @@ func (mock *MockNodeClient) GetName(name string) (owner []byte, data string, exp
 	return nil, "", 0, nil
 }
 
+func (mock *MockNodeClient) ListNames() (blockHeight int, names []*core_types.NameRegEntry, err error) {
+	return 0, nil, nil
+}
+
+func (mock *MockNodeClient) GetBlock(height int) (blockMeta *tm_types.BlockMeta, block *tm_types.Block, err error) {
+	return nil, nil, nil
+}
+
+func (mock *MockNodeClient) ListUnconfirmedTxs() (transactions []txs.Tx, err error) {
+	return nil, nil
+}
+
 func (mock *MockNodeClient) ListValidators() (blockHeight int, bondedValidators, unbondingValidators []consensus_types.Validator, err error) {
 	return 0, nil, nil, nil
 }
 
+func (mock *MockNodeClient) NonceManager() NonceManager {
+	return mock.nonces
+}
+
 func (mock *MockNodeClient) Logger() logging_types.InfoTraceLogger {
 	return loggers.NewNoopInfoTraceLogger()
 }
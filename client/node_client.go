@@ -29,6 +29,7 @@ import (
 	tendermint_types "github.com/hyperledger/burrow/rpc/tendermint/core/types"
 	"github.com/hyperledger/burrow/txs"
 	tmLog15 "github.com/tendermint/log15"
+	tm_types "github.com/tendermint/tendermint/types"
 )
 
 type NodeClient interface {
@@ -43,8 +44,16 @@ type NodeClient interface {
 
 	DumpStorage(address []byte) (storage *core_types.Storage, err error)
 	GetName(name string) (owner []byte, data string, expirationBlock int, err error)
+	ListNames() (blockHeight int, names []*core_types.NameRegEntry, err error)
 	ListValidators() (blockHeight int, bondedValidators, unbondingValidators []consensus_types.Validator, err error)
 
+	GetBlock(height int) (blockMeta *tm_types.BlockMeta, block *tm_types.Block, err error)
+	ListUnconfirmedTxs() (transactions []txs.Tx, err error)
+
+	// NonceManager returns the NonceManager shared by all those sending
+	// transactions through this NodeClient
+	NonceManager() NonceManager
+
 	// Logging context for this NodeClient
 	Logger() logging_types.InfoTraceLogger
 }
@@ -54,6 +63,10 @@ type NodeWebsocketClient interface {
 	Unsubscribe(eventId string) error
 
 	WaitForConfirmation(tx txs.Tx, chainId string, inputAddr []byte) (chan Confirmation, error)
+	// Events returns a channel of the events of all subscriptions, which is
+	// closed when the websocket is closed or its connection is lost. It
+	// cannot be used together with WaitForConfirmation.
+	Events() (<-chan Event, error)
 	Close()
 }
 
@@ -64,16 +77,19 @@ var _ NodeClient = (*burrowNodeClient)(nil)
 // burrow-client is a simple struct exposing the client rpc methods
 type burrowNodeClient struct {
 	broadcastRPC string
+	nonces       NonceManager
 	logger       logging_types.InfoTraceLogger
 }
 
 // BurrowKeyClient.New returns a new monax-keys client for provided rpc location
 // Monax-keys connects over http request-responses
 func NewBurrowNodeClient(rpcString string, logger logging_types.InfoTraceLogger) *burrowNodeClient {
-	return &burrowNodeClient{
+	nodeClient := &burrowNodeClient{
 		broadcastRPC: rpcString,
 		logger:       logging.WithScope(logger, "BurrowNodeClient"),
 	}
+	nodeClient.nonces = NewNonceManager(nodeClient)
+	return nodeClient
 }
 
 // Note [Ben]: This is a hack to silence Tendermint logger from tendermint/go-rpc
@@ -245,6 +261,45 @@ func (burrowNodeClient *burrowNodeClient) GetName(name string) (owner []byte, da
 	return
 }
 
+// ListNames returns all entries of the name registry
+func (burrowNodeClient *burrowNodeClient) ListNames() (blockHeight int, names []*core_types.NameRegEntry, err error) {
+	client := rpcclient.NewJSONRPCClient(burrowNodeClient.broadcastRPC)
+	namesResult, err := tendermint_client.ListNames(client)
+	if err != nil {
+		err = fmt.Errorf("Error connecting to node (%s) to list name registrar entries: %s",
+			burrowNodeClient.broadcastRPC, err.Error())
+		return 0, nil, err
+	}
+	return namesResult.BlockHeight, namesResult.Names, nil
+}
+
+//--------------------------------------------------------------------------------------------
+// Blockchain
+
+// GetBlock returns the block at height and its metadata
+func (burrowNodeClient *burrowNodeClient) GetBlock(height int) (blockMeta *tm_types.BlockMeta, block *tm_types.Block, err error) {
+	client := rpcclient.NewJSONRPCClient(burrowNodeClient.broadcastRPC)
+	blockResult, err := tendermint_client.GetBlock(client, height)
+	if err != nil {
+		err = fmt.Errorf("Error connecting to node (%s) to get block at height %v: %s",
+			burrowNodeClient.broadcastRPC, height, err.Error())
+		return nil, nil, err
+	}
+	return blockResult.BlockMeta, blockResult.Block, nil
+}
+
+// ListUnconfirmedTxs returns the transactions in the mempool of the node
+func (burrowNodeClient *burrowNodeClient) ListUnconfirmedTxs() (transactions []txs.Tx, err error) {
+	client := rpcclient.NewJSONRPCClient(burrowNodeClient.broadcastRPC)
+	unconfirmedResult, err := tendermint_client.ListUnconfirmedTxs(client)
+	if err != nil {
+		err = fmt.Errorf("Error connecting to node (%s) to list unconfirmed transactions: %s",
+			burrowNodeClient.broadcastRPC, err.Error())
+		return nil, err
+	}
+	return unconfirmedResult.Txs, nil
+}
+
 //--------------------------------------------------------------------------------------------
 
 func (burrowNodeClient *burrowNodeClient) ListValidators() (blockHeight int,
@@ -263,6 +318,10 @@ func (burrowNodeClient *burrowNodeClient) ListValidators() (blockHeight int,
 	return
 }
 
+func (burrowNodeClient *burrowNodeClient) NonceManager() NonceManager {
+	return burrowNodeClient.nonces
+}
+
 func (burrowNodeClient *burrowNodeClient) Logger() logging_types.InfoTraceLogger {
 	return burrowNodeClient.logger
 }
@@ -0,0 +1,382 @@
+// Copyright 2017 Monax Industries Limited
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"sync"
+
+	acc "github.com/hyperledger/burrow/account"
+	"github.com/hyperledger/burrow/logging"
+	logging_types "github.com/hyperledger/burrow/logging/types"
+	"github.com/hyperledger/burrow/txs"
+)
+
+// A NonceManager hands out the sequence numbers (nonces) of the transactions
+// sent from an account so that many transactions can be formed and broadcast
+// from it without waiting for each to be committed. The sequence of an
+// account is fetched from the node once and then reserved locally; a
+// transaction broadcast with a reserved sequence is tracked as pending over
+// the websocket until it is committed. Transactions from an account are
+// broadcast in sequence order so that the node accepts each of them, which
+// means every reservation must be broadcast or released. When the node rejects
+// a transaction for its sequence the manager resyncs the account so that later
+// reservations are accepted.
+//
+// A NonceManager is safe to use from many goroutines but assumes that it is
+// the only sender from the accounts it manages; transactions sent from the
+// same account by other means will cause sequence errors and resyncs.
+type NonceManager interface {
+	// Reserve returns the next unused sequence for address, fetching the
+	// account from the node when it has not been synced yet
+	Reserve(address []byte) (sequence int, err error)
+	// Release returns a reserved sequence that will not be broadcast, for
+	// instance because forming or signing the transaction failed
+	Release(address []byte, sequence int)
+	// Broadcast broadcasts tx whose input from address has sequence. When the
+	// sequence was reserved it first waits for the transactions with earlier
+	// reserved sequences to be broadcast, and fails without broadcasting when
+	// an earlier sequence has been released since the node would not accept
+	// tx until another transaction takes that sequence. When the node accepts
+	// tx it is pending until its confirmation (or the reason there is none)
+	// is sent on the returned channel, which is nil when the node client
+	// offers no websocket. When the node rejects tx for its sequence the
+	// account is resynced before the error is returned so that the
+	// transaction can be formed again with a new reservation.
+	Broadcast(tx txs.Tx, chainID string, address []byte, sequence int) (*txs.Receipt,
+		chan Confirmation, error)
+	// Resync discards what is known locally about the sequence of address and
+	// fetches it from the node
+	Resync(address []byte) error
+	// Pending returns the number of transactions from address that have been
+	// broadcast but not yet confirmed
+	Pending(address []byte) int
+}
+
+// NonceNode is the part of a NodeClient that a NonceManager syncs sequences
+// from and broadcasts through
+type NonceNode interface {
+	GetAccount(address []byte) (*acc.Account, error)
+	Broadcast(transaction txs.Tx) (*txs.Receipt, error)
+	// May return a nil client when there is no websocket to confirm
+	// transactions over
+	DeriveWebsocketClient() (NodeWebsocketClient, error)
+	Logger() logging_types.InfoTraceLogger
+}
+
+// NOTE: compiler check that nonceManager implements NonceManager
+var _ NonceManager = (*nonceManager)(nil)
+
+type nonceManager struct {
+	sync.Mutex
+	// signalled whenever a reservation is broadcast or dropped
+	turn     *sync.Cond
+	node     NonceNode
+	accounts map[string]*accountNonces
+	logger   logging_types.InfoTraceLogger
+}
+
+// The local view of the sequence of an account
+type accountNonces struct {
+	// next sequence to reserve
+	next int
+	// sequences reserved but not yet broadcast
+	reserved map[int]bool
+	// sequences broadcast but not yet confirmed
+	pending map[int]bool
+}
+
+// Matches the error of txs.ErrTxInvalidSequence, which reaches clients as text
+var invalidSequenceRegexp = regexp.MustCompile(`invalid sequence\. Got (\d+), expected (\d+)`)
+
+// NewNonceManager returns a NonceManager for the accounts sending through
+// node, which is usually a NodeClient
+func NewNonceManager(node NonceNode) *nonceManager {
+	nm := &nonceManager{
+		node:     node,
+		accounts: make(map[string]*accountNonces),
+		logger:   logging.WithScope(node.Logger(), "NonceManager"),
+	}
+	nm.turn = sync.NewCond(&nm.Mutex)
+	return nm
+}
+
+// InvalidSequence returns the sequence expected by the node when err reports
+// that a transaction was rejected for its sequence
+func InvalidSequence(err error) (expected int, ok bool) {
+	if err == nil {
+		return 0, false
+	}
+	matches := invalidSequenceRegexp.FindStringSubmatch(err.Error())
+	if matches == nil {
+		return 0, false
+	}
+	expected, err = strconv.Atoi(matches[2])
+	if err != nil {
+		return 0, false
+	}
+	return expected, true
+}
+
+func (nm *nonceManager) Reserve(address []byte) (int, error) {
+	nm.Lock()
+	defer nm.Unlock()
+	nonces, err := nm.account(address)
+	if err != nil {
+		return 0, err
+	}
+	// Skip the sequences still held by others after a resync
+	for nonces.reserved[nonces.next] || nonces.pending[nonces.next] {
+		nonces.next++
+	}
+	sequence := nonces.next
+	nonces.next++
+	nonces.reserved[sequence] = true
+	logging.TraceMsg(nm.logger, "Reserved sequence",
+		"address", fmt.Sprintf("%X", address),
+		"sequence", sequence)
+	return sequence, nil
+}
+
+func (nm *nonceManager) Release(address []byte, sequence int) {
+	nm.Lock()
+	defer nm.Unlock()
+	nonces, ok := nm.accounts[string(address)]
+	if !ok || !nonces.reserved[sequence] {
+		return
+	}
+	delete(nonces.reserved, sequence)
+	nm.turn.Broadcast()
+	if sequence < nonces.next-1 {
+		// The released sequence leaves a gap that the node will not fill, so
+		// transactions with later sequences would not be accepted
+		logging.InfoMsg(nm.logger, "Released sequence before later reservations",
+			"address", fmt.Sprintf("%X", address),
+			"sequence", sequence,
+			"next_sequence", nonces.next)
+	}
+	// Hand out the released sequence again next
+	if sequence < nonces.next {
+		nonces.next = sequence
+	}
+}
+
+func (nm *nonceManager) Broadcast(tx txs.Tx, chainID string, address []byte,
+	sequence int) (*txs.Receipt, chan Confirmation, error) {
+	// Subscribe before broadcasting so the confirmation cannot be missed
+	wsClient, err := nm.node.DeriveWebsocketClient()
+	if err != nil {
+		nm.Release(address, sequence)
+		return nil, nil, err
+	}
+	var wsConfirmations chan Confirmation
+	if wsClient != nil {
+		wsConfirmations, err = wsClient.WaitForConfirmation(tx, chainID, address)
+		if err != nil {
+			wsClient.Close()
+			nm.Release(address, sequence)
+			return nil, nil, err
+		}
+	}
+
+	err = nm.waitTurn(address, sequence)
+	if err != nil {
+		if wsClient != nil {
+			wsClient.Close()
+		}
+		nm.Release(address, sequence)
+		return nil, nil, err
+	}
+	receipt, err := nm.node.Broadcast(tx)
+	if err != nil {
+		if wsClient != nil {
+			wsClient.Close()
+		}
+		if expected, ok := InvalidSequence(err); ok {
+			nm.resyncTo(address, sequence, expected)
+		} else {
+			nm.Release(address, sequence)
+		}
+		return nil, nil, err
+	}
+
+	nm.Lock()
+	if nonces, ok := nm.accounts[string(address)]; ok {
+		delete(nonces.reserved, sequence)
+		nonces.pending[sequence] = true
+	}
+	nm.turn.Broadcast()
+	nm.Unlock()
+
+	if wsClient == nil {
+		// Without a websocket we cannot tell when the transaction is committed
+		nm.confirm(address, sequence)
+		return receipt, nil, nil
+	}
+	confirmations := make(chan Confirmation, 1)
+	go func() {
+		confirmation := <-wsConfirmations
+		wsClient.Close()
+		nm.confirm(address, sequence)
+		if confirmation.Error != nil {
+			// We do not know whether the transaction was committed so our view
+			// of the sequence can no longer be trusted
+			logging.InfoMsg(nm.logger, "No confirmation for pending transaction",
+				"address", fmt.Sprintf("%X", address),
+				"sequence", sequence,
+				"error", confirmation.Error)
+			nm.forget(address)
+		}
+		confirmations <- confirmation
+	}()
+	return receipt, confirmations, nil
+}
+
+func (nm *nonceManager) Resync(address []byte) error {
+	nm.Lock()
+	defer nm.Unlock()
+	delete(nm.accounts, string(address))
+	nm.turn.Broadcast()
+	_, err := nm.account(address)
+	return err
+}
+
+func (nm *nonceManager) Pending(address []byte) int {
+	nm.Lock()
+	defer nm.Unlock()
+	if nonces, ok := nm.accounts[string(address)]; ok {
+		return len(nonces.pending)
+	}
+	return 0
+}
+
+// account returns the local view of the sequence of address, syncing it from
+// the node if needed. Must be called holding the lock.
+func (nm *nonceManager) account(address []byte) (*accountNonces, error) {
+	if nonces, ok := nm.accounts[string(address)]; ok {
+		return nonces, nil
+	}
+	account, err := nm.node.GetAccount(address)
+	if err != nil {
+		return nil, fmt.Errorf("Could not get account %X to sync its sequence: %s",
+			address, err)
+	}
+	// An account that does not exist yet starts from sequence zero
+	sequence := 0
+	if account != nil {
+		sequence = account.Sequence
+	}
+	nonces := newAccountNonces(sequence + 1)
+	nm.accounts[string(address)] = nonces
+	logging.TraceMsg(nm.logger, "Synced sequence from node",
+		"address", fmt.Sprintf("%X", address),
+		"next_sequence", nonces.next)
+	return nonces, nil
+}
+
+// resyncTo sets the next sequence of address to the one the node expected
+// when it rejected the transaction with sequence. Other reservations are
+// kept since their transactions may yet be accepted, but pending transactions
+// the node no longer counts have been dropped from its mempool.
+func (nm *nonceManager) resyncTo(address []byte, sequence, expected int) {
+	nm.Lock()
+	defer nm.Unlock()
+	nonces, ok := nm.accounts[string(address)]
+	if !ok {
+		nonces = newAccountNonces(expected)
+		nm.accounts[string(address)] = nonces
+	}
+	logging.InfoMsg(nm.logger, "Resyncing sequence after sequence error",
+		"address", fmt.Sprintf("%X", address),
+		"sequence", sequence,
+		"next_sequence", nonces.next,
+		"expected_sequence", expected)
+	delete(nonces.reserved, sequence)
+	nm.turn.Broadcast()
+	nonces.next = expected
+	for pending := range nonces.pending {
+		if pending >= expected {
+			delete(nonces.pending, pending)
+		}
+	}
+}
+
+func (nm *nonceManager) confirm(address []byte, sequence int) {
+	nm.Lock()
+	defer nm.Unlock()
+	if nonces, ok := nm.accounts[string(address)]; ok {
+		delete(nonces.pending, sequence)
+	}
+}
+
+// forget drops the local view of address so that it is synced from the node
+// on the next reservation
+func (nm *nonceManager) forget(address []byte) {
+	nm.Lock()
+	defer nm.Unlock()
+	delete(nm.accounts, string(address))
+	nm.turn.Broadcast()
+}
+
+// waitTurn blocks until the transactions from address with sequences before
+// sequence have been broadcast, failing if one of those sequences has been
+// released rather than broadcast
+func (nm *nonceManager) waitTurn(address []byte, sequence int) error {
+	nm.Lock()
+	defer nm.Unlock()
+	for {
+		nonces, ok := nm.accounts[string(address)]
+		if !ok {
+			// Forgotten after a failed confirmation, so let the node decide
+			return nil
+		}
+		if !nonces.reserved[sequence] {
+			// Not reserved here, for instance given by the user, so not ours
+			// to order
+			return nil
+		}
+		// Sequences before next have been handed out, so any from next that
+		// are neither reserved nor pending will not be broadcast
+		for free := nonces.next; free < sequence; free++ {
+			if !nonces.reserved[free] && !nonces.pending[free] {
+				return fmt.Errorf("Not broadcasting transaction from %X with sequence "+
+					"%v since earlier sequence %v was released", address, sequence, free)
+			}
+		}
+		if !nonces.reservedBefore(sequence) {
+			return nil
+		}
+		nm.turn.Wait()
+	}
+}
+
+func (nonces *accountNonces) reservedBefore(sequence int) bool {
+	for reserved := range nonces.reserved {
+		if reserved < sequence {
+			return true
+		}
+	}
+	return false
+}
+
+func newAccountNonces(next int) *accountNonces {
+	return &accountNonces{
+		next:     next,
+		reserved: make(map[int]bool),
+		pending:  make(map[int]bool),
+	}
+}
@@ -0,0 +1,263 @@
+// Copyright 2017 Monax Industries Limited
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	acc "github.com/hyperledger/burrow/account"
+	"github.com/hyperledger/burrow/logging/loggers"
+	logging_types "github.com/hyperledger/burrow/logging/types"
+	"github.com/hyperledger/burrow/txs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var address = []byte("01234567890123456789")
+
+func TestNonceManagerReserveRelease(t *testing.T) {
+	nodeClient := &fakeNodeClient{sequence: 3}
+	nm := NewNonceManager(nodeClient)
+
+	assert.Equal(t, 4, reserve(t, nm))
+	assert.Equal(t, 5, reserve(t, nm))
+	assert.Equal(t, 6, reserve(t, nm))
+	// Only fetched once
+	assert.Equal(t, 1, nodeClient.getAccounts)
+
+	nm.Release(address, 6)
+	assert.Equal(t, 6, reserve(t, nm))
+	// Releasing before later reservations fills the gap first
+	nm.Release(address, 4)
+	assert.Equal(t, 4, reserve(t, nm))
+	assert.Equal(t, 7, reserve(t, nm))
+	// Releasing an unreserved sequence does nothing
+	nm.Release(address, 2)
+	assert.Equal(t, 8, reserve(t, nm))
+
+	nodeClient.sequence = 10
+	assert.NoError(t, nm.Resync(address))
+	assert.Equal(t, 11, reserve(t, nm))
+	assert.Equal(t, 2, nodeClient.getAccounts)
+}
+
+func TestNonceManagerSequenceError(t *testing.T) {
+	nodeClient := &fakeNodeClient{sequence: 3}
+	nm := NewNonceManager(nodeClient)
+
+	first, second, third := reserve(t, nm), reserve(t, nm), reserve(t, nm)
+	assert.Equal(t, []int{4, 5, 6}, []int{first, second, third})
+
+	// Someone else sent from the account so the node expects 5
+	nodeClient.broadcastErr = fmt.Errorf("Error broadcasting transaction: %s",
+		txs.ErrTxInvalidSequence{Got: 4, Expected: 5})
+	_, _, err := nm.Broadcast(&txs.SendTx{}, "chain", address, first)
+	assert.Error(t, err)
+	expected, ok := InvalidSequence(err)
+	assert.True(t, ok)
+	assert.Equal(t, 5, expected)
+
+	// 5 and 6 are still reserved so are not handed out again
+	assert.Equal(t, 7, reserve(t, nm))
+	nodeClient.broadcastErr = nil
+	_, confirmations, err := nm.Broadcast(&txs.SendTx{}, "chain", address, second)
+	assert.NoError(t, err)
+	assert.Nil(t, confirmations)
+	assert.Equal(t, 0, nm.Pending(address))
+	// Only fetched once
+	assert.Equal(t, 1, nodeClient.getAccounts)
+
+	_, ok = InvalidSequence(fmt.Errorf("Error broadcasting transaction: " +
+		"Insufficient funds"))
+	assert.False(t, ok)
+	_, ok = InvalidSequence(nil)
+	assert.False(t, ok)
+}
+
+func TestNonceManagerPending(t *testing.T) {
+	wsClient := &fakeWebsocketClient{confirmations: make(chan Confirmation, 1)}
+	nodeClient := &fakeNodeClient{sequence: 3, wsClient: wsClient}
+	nm := NewNonceManager(nodeClient)
+
+	sequence := reserve(t, nm)
+	_, confirmations, err := nm.Broadcast(&txs.SendTx{}, "chain", address, sequence)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, nm.Pending(address))
+	assert.Equal(t, 5, reserve(t, nm))
+	nm.Release(address, 5)
+
+	wsClient.confirmations <- Confirmation{BlockHash: []byte{1}}
+	confirmation := <-confirmations
+	assert.Equal(t, []byte{1}, confirmation.BlockHash)
+	assert.Equal(t, 0, nm.Pending(address))
+	assert.True(t, wsClient.isClosed())
+
+	// Without a confirmation the account is synced again
+	sequence = reserve(t, nm)
+	assert.Equal(t, 5, sequence)
+	_, confirmations, err = nm.Broadcast(&txs.SendTx{}, "chain", address, sequence)
+	assert.NoError(t, err)
+	wsClient.confirmations <- Confirmation{Error: fmt.Errorf("timed out")}
+	<-confirmations
+	nodeClient.setSequence(5)
+	assert.Equal(t, 6, reserve(t, nm))
+	assert.Equal(t, 2, nodeClient.getAccounts)
+}
+
+func TestNonceManagerBroadcastOrder(t *testing.T) {
+	nodeClient := &fakeNodeClient{sequence: 3}
+	nm := NewNonceManager(nodeClient)
+
+	first, second, third := reserve(t, nm), reserve(t, nm), reserve(t, nm)
+	broadcasts := make(chan error)
+	for _, sequence := range []int{third, second} {
+		go func(sequence int) {
+			_, _, err := nm.Broadcast(sendTxWithSequence(sequence), "chain", address,
+				sequence)
+			broadcasts <- err
+		}(sequence)
+	}
+	// Later sequences wait for the first to be broadcast
+	select {
+	case <-broadcasts:
+		t.Fatal("Expected later sequences to wait for the first")
+	case <-time.After(50 * time.Millisecond):
+	}
+	_, _, err := nm.Broadcast(sendTxWithSequence(first), "chain", address, first)
+	assert.NoError(t, err)
+	assert.NoError(t, <-broadcasts)
+	assert.NoError(t, <-broadcasts)
+	assert.Equal(t, []int{4, 5, 6}, nodeClient.broadcastSequences())
+
+	// A released sequence leaves a gap the node would not accept later
+	// sequences across
+	first, second = reserve(t, nm), reserve(t, nm)
+	go func() {
+		_, _, err := nm.Broadcast(sendTxWithSequence(second), "chain", address, second)
+		broadcasts <- err
+	}()
+	nm.Release(address, first)
+	assert.Error(t, <-broadcasts)
+	assert.Equal(t, []int{4, 5, 6}, nodeClient.broadcastSequences())
+	// and the sequences are handed out again
+	assert.Equal(t, first, reserve(t, nm))
+	assert.Equal(t, second, reserve(t, nm))
+
+	// Sequences that were not reserved are not ordered
+	_, _, err = nm.Broadcast(sendTxWithSequence(20), "chain", address, 20)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{4, 5, 6, 20}, nodeClient.broadcastSequences())
+}
+
+func reserve(t *testing.T, nm NonceManager) int {
+	sequence, err := nm.Reserve(address)
+	assert.NoError(t, err)
+	return sequence
+}
+
+func sendTxWithSequence(sequence int) *txs.SendTx {
+	return &txs.SendTx{
+		Inputs: []*txs.TxInput{{Address: address, Amount: 1, Sequence: sequence}},
+	}
+}
+
+// Embeds the interface so unused methods need not be implemented
+type fakeNodeClient struct {
+	NodeClient
+	sync.Mutex
+	sequence     int
+	getAccounts  int
+	broadcastErr error
+	broadcasts   []txs.Tx
+	wsClient     *fakeWebsocketClient
+}
+
+func (client *fakeNodeClient) GetAccount(address []byte) (*acc.Account, error) {
+	client.Lock()
+	defer client.Unlock()
+	client.getAccounts++
+	return &acc.Account{Address: address, Sequence: client.sequence}, nil
+}
+
+func (client *fakeNodeClient) setSequence(sequence int) {
+	client.Lock()
+	defer client.Unlock()
+	client.sequence = sequence
+}
+
+func (client *fakeNodeClient) Broadcast(tx txs.Tx) (*txs.Receipt, error) {
+	client.Lock()
+	defer client.Unlock()
+	if client.broadcastErr != nil {
+		return nil, client.broadcastErr
+	}
+	client.broadcasts = append(client.broadcasts, tx)
+	return &txs.Receipt{TxHash: make([]byte, 20)}, nil
+}
+
+// The sequences of the SendTxs broadcast
+func (client *fakeNodeClient) broadcastSequences() []int {
+	client.Lock()
+	defer client.Unlock()
+	var sequences []int
+	for _, tx := range client.broadcasts {
+		if sendTx, ok := tx.(*txs.SendTx); ok && len(sendTx.Inputs) > 0 {
+			sequences = append(sequences, sendTx.Inputs[0].Sequence)
+		}
+	}
+	return sequences
+}
+
+func (client *fakeNodeClient) DeriveWebsocketClient() (NodeWebsocketClient, error) {
+	if client.wsClient == nil {
+		return nil, nil
+	}
+	return client.wsClient, nil
+}
+
+func (client *fakeNodeClient) Logger() logging_types.InfoTraceLogger {
+	return loggers.NewNoopInfoTraceLogger()
+}
+
+type fakeWebsocketClient struct {
+	NodeWebsocketClient
+	sync.Mutex
+	confirmations chan Confirmation
+	closed        bool
+}
+
+func (client *fakeWebsocketClient) WaitForConfirmation(tx txs.Tx, chainId string,
+	inputAddr []byte) (chan Confirmation, error) {
+	client.Lock()
+	defer client.Unlock()
+	client.closed = false
+	return client.confirmations, nil
+}
+
+// Called from the goroutine of the NonceManager waiting for confirmation
+func (client *fakeWebsocketClient) Close() {
+	client.Lock()
+	defer client.Unlock()
+	client.closed = true
+}
+
+func (client *fakeWebsocketClient) isClosed() bool {
+	client.Lock()
+	defer client.Unlock()
+	return client.closed
+}
@@ -18,11 +18,13 @@ import (
 	"encoding/hex"
 	"fmt"
 	"strconv"
+	"strings"
 
 	ptypes "github.com/hyperledger/burrow/permission/types"
 
 	"github.com/hyperledger/burrow/client"
 	"github.com/hyperledger/burrow/keys"
+	"github.com/hyperledger/burrow/logging"
 	"github.com/hyperledger/burrow/txs"
 )
 
@@ -30,11 +32,12 @@ import (
 // core functions with string args.
 // validates strings and forms transaction
 
-func Send(nodeClient client.NodeClient, keyClient keys.KeyClient, pubkey, addr, toAddr, amtS, nonceS string) (*txs.SendTx, error) {
-	pub, amt, nonce, err := checkCommon(nodeClient, keyClient, pubkey, addr, amtS, nonceS)
+func Send(nodeClient client.NodeClient, keyClient keys.KeyClient, pubkey, addr, toAddr, amtS, nonceS string) (tx *txs.SendTx, err error) {
+	pub, address, amt, nonce, err := checkCommon(nodeClient, keyClient, pubkey, addr, amtS, nonceS)
 	if err != nil {
 		return nil, err
 	}
+	defer releaseNonceOnError(nodeClient, nonceS, address, nonce, &err)
 
 	if toAddr == "" {
 		return nil, fmt.Errorf("destination address must be given with --to flag")
@@ -45,18 +48,98 @@ func Send(nodeClient client.NodeClient, keyClient keys.KeyClient, pubkey, addr,
 		return nil, fmt.Errorf("toAddr is bad hex: %v", err)
 	}
 
-	tx := txs.NewSendTx()
+	tx = txs.NewSendTx()
 	tx.AddInputWithNonce(pub, amt, int(nonce))
+	tx.Inputs[0].Address = address
 	tx.AddOutput(toAddrBytes, amt)
 
 	return tx, nil
 }
 
-func Call(nodeClient client.NodeClient, keyClient keys.KeyClient, pubkey, addr, toAddr, amtS, nonceS, gasS, feeS, data string) (*txs.CallTx, error) {
-	pub, amt, nonce, err := checkCommon(nodeClient, keyClient, pubkey, addr, amtS, nonceS)
+// SendMulti forms a SendTx spending from several accounts to several
+// accounts. Inputs are given as <address>:<amount>[:<nonce>] and outputs as
+// <address>:<amount>. Nonces that are not given are fetched from the node.
+// Inputs are left without public keys and signatures to be signed by each key
+// holder with SignSendTxInputs. The difference between the input and output
+// totals is paid as a fee.
+func SendMulti(nodeClient client.NodeClient, inputs, outputs []string) (*txs.SendTx, error) {
+	if len(inputs) == 0 {
+		return nil, fmt.Errorf("at least one input must be given with --from")
+	}
+	if len(outputs) == 0 {
+		return nil, fmt.Errorf("at least one output must be given with --to")
+	}
+	tx := txs.NewSendTx()
+	var inTotal, outTotal int64
+	for _, input := range inputs {
+		parts := strings.Split(input, ":")
+		if len(parts) != 2 && len(parts) != 3 {
+			return nil, fmt.Errorf("input '%s' should be <address>:<amount>[:<nonce>]",
+				input)
+		}
+		address, amount, err := decodeAddressAmount(parts[0], parts[1])
+		if err != nil {
+			return nil, fmt.Errorf("input '%s' is misformatted: %v", input, err)
+		}
+		if containsAddress(txInputAddresses(tx), address) {
+			return nil, fmt.Errorf("input address %X is given more than once", address)
+		}
+		var nonce int64
+		if len(parts) == 3 {
+			nonce, err = strconv.ParseInt(parts[2], 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("nonce of input '%s' is misformatted: %v", input, err)
+			}
+		} else {
+			if nodeClient == nil {
+				return nil, fmt.Errorf("input '%s' must specify a nonce or use --node-addr "+
+					"(or BURROW_CLIENT_NODE_ADDR) to fetch the nonce from a node", input)
+			}
+			account, err := nodeClient.GetAccount(address)
+			if err != nil {
+				return nil, err
+			}
+			if account == nil {
+				return nil, fmt.Errorf("input account %X does not exist", address)
+			}
+			nonce = int64(account.Sequence) + 1
+			logging.TraceMsg(nodeClient.Logger(), "Fetch nonce from node",
+				"nonce", nonce,
+				"account address", address,
+			)
+		}
+		tx.Inputs = append(tx.Inputs, &txs.TxInput{
+			Address:  address,
+			Amount:   amount,
+			Sequence: int(nonce),
+		})
+		inTotal += amount
+	}
+	for _, output := range outputs {
+		parts := strings.Split(output, ":")
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("output '%s' should be <address>:<amount>", output)
+		}
+		address, amount, err := decodeAddressAmount(parts[0], parts[1])
+		if err != nil {
+			return nil, fmt.Errorf("output '%s' is misformatted: %v", output, err)
+		}
+		tx.AddOutput(address, amount)
+		outTotal += amount
+	}
+	if outTotal > inTotal {
+		return nil, fmt.Errorf("outputs total %v but inputs only total %v", outTotal,
+			inTotal)
+	}
+	return tx, nil
+}
+
+func Call(nodeClient client.NodeClient, keyClient keys.KeyClient, pubkey, addr, toAddr, amtS, nonceS, gasS, feeS, data string) (tx *txs.CallTx, err error) {
+	pub, address, amt, nonce, err := checkCommon(nodeClient, keyClient, pubkey, addr, amtS, nonceS)
 	if err != nil {
 		return nil, err
 	}
+	defer releaseNonceOnError(nodeClient, nonceS, address, nonce, &err)
 
 	toAddrBytes, err := hex.DecodeString(toAddr)
 	if err != nil {
@@ -78,30 +161,95 @@ func Call(nodeClient client.NodeClient, keyClient keys.KeyClient, pubkey, addr,
 		return nil, fmt.Errorf("data is bad hex: %v", err)
 	}
 
-	tx := txs.NewCallTxWithNonce(pub, toAddrBytes, dataBytes, amt, gas, fee, int(nonce))
+	tx = txs.NewCallTxWithNonce(pub, toAddrBytes, dataBytes, amt, gas, fee, int(nonce))
+	tx.Input.Address = address
 	return tx, nil
 }
 
-func Name(nodeClient client.NodeClient, keyClient keys.KeyClient, pubkey, addr, amtS, nonceS, feeS, name, data string) (*txs.NameTx, error) {
-	pub, amt, nonce, err := checkCommon(nodeClient, keyClient, pubkey, addr, amtS, nonceS)
+func Name(nodeClient client.NodeClient, keyClient keys.KeyClient, pubkey, addr, amtS, nonceS, feeS, name, data string) (tx *txs.NameTx, err error) {
+	pub, address, amt, nonce, err := checkCommon(nodeClient, keyClient, pubkey, addr, amtS, nonceS)
 	if err != nil {
 		return nil, err
 	}
+	defer releaseNonceOnError(nodeClient, nonceS, address, nonce, &err)
 
 	fee, err := strconv.ParseInt(feeS, 10, 64)
 	if err != nil {
 		return nil, fmt.Errorf("fee is misformatted: %v", err)
 	}
 
-	tx := txs.NewNameTxWithNonce(pub, name, data, amt, fee, int(nonce))
+	tx = txs.NewNameTxWithNonce(pub, name, data, amt, fee, int(nonce))
+	tx.Input.Address = address
 	return tx, nil
 }
 
-func Permissions(nodeClient client.NodeClient, keyClient keys.KeyClient, pubkey, addrS, nonceS, permFunc string, argsS []string) (*txs.PermissionsTx, error) {
-	pub, _, nonce, err := checkCommon(nodeClient, keyClient, pubkey, addrS, "0", nonceS)
+func NameTransfer(nodeClient client.NodeClient, keyClient keys.KeyClient, pubkey, addr, amtS, nonceS, name, newOwnerS string) (tx *txs.NameTransferTx, err error) {
+	pub, address, amt, nonce, err := checkCommon(nodeClient, keyClient, pubkey, addr, amtS, nonceS)
 	if err != nil {
 		return nil, err
 	}
+	defer releaseNonceOnError(nodeClient, nonceS, address, nonce, &err)
+
+	newOwner, err := hex.DecodeString(newOwnerS)
+	if err != nil {
+		return nil, fmt.Errorf("new owner is bad hex: %v", err)
+	}
+
+	tx = txs.NewNameTransferTxWithNonce(pub, name, newOwner, amt, int(nonce))
+	tx.Input.Address = address
+	if err = tx.ValidateStrings(); err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
+
+// Permission functions accepted by Permissions and the arguments each takes,
+// optional arguments being bracketed
+var permissionsFunctionArgs = map[string][]string{
+	"setBase":   {"address", "permission", "value", "[expires]"},
+	"unsetBase": {"address", "permission"},
+	"setGlobal": {"permission", "value"},
+	"addRole":   {"address", "role", "[expires]"},
+	"rmRole":    {"address", "role"},
+	"setRole":   {"role", "permissions", "inherits", "admin"},
+
+	"allowCall":           {"address", "contract"},
+	"disallowCall":        {"address", "contract"},
+	"unrestrictCalls":     {"address"},
+	"allowRoleCall":       {"role", "contract"},
+	"disallowRoleCall":    {"role", "contract"},
+	"unrestrictRoleCalls": {"role"},
+
+	"approve": {"proposal"},
+}
+
+func Permissions(nodeClient client.NodeClient, keyClient keys.KeyClient, pubkey, addrS, nonceS, permFunc string, argsS []string) (tx *txs.PermissionsTx, err error) {
+	// accept the name of the permission flag as well
+	if permFunc == "removeRole" {
+		permFunc = "rmRole"
+	}
+	argNames, ok := permissionsFunctionArgs[permFunc]
+	if !ok {
+		return nil, fmt.Errorf("Invalid permission function for use in PermissionsTx: %s "+
+			"(use one of setBase, unsetBase, setGlobal, addRole, rmRole, setRole, "+
+			"allowCall, disallowCall, unrestrictCalls, allowRoleCall, disallowRoleCall, "+
+			"unrestrictRoleCalls, or approve)", permFunc)
+	}
+	required := 0
+	for _, argName := range argNames {
+		if !strings.HasPrefix(argName, "[") {
+			required++
+		}
+	}
+	if len(argsS) < required || len(argsS) > len(argNames) {
+		return nil, fmt.Errorf("%s takes arguments %s, but %v were given",
+			permFunc, strings.Join(argNames, ", "), len(argsS))
+	}
+	pub, address, _, nonce, err := checkCommon(nodeClient, keyClient, pubkey, addrS, "0", nonceS)
+	if err != nil {
+		return nil, err
+	}
+	defer releaseNonceOnError(nodeClient, nonceS, address, nonce, &err)
 	var args ptypes.PermArgs
 	switch permFunc {
 	case "setBase":
@@ -109,18 +257,19 @@ func Permissions(nodeClient client.NodeClient, keyClient keys.KeyClient, pubkey,
 		if err != nil {
 			return nil, err
 		}
-		if len(argsS) != 3 {
-			return nil, fmt.Errorf("setBase also takes a value (true or false)")
+		value, err := strconv.ParseBool(argsS[2])
+		if err != nil {
+			return nil, fmt.Errorf("Unknown value %s (use true or false)", argsS[2])
+		}
+		expires, err := decodeExpiry(argsS, 3)
+		if err != nil {
+			return nil, err
 		}
-		var value bool
-		if argsS[2] == "true" {
-			value = true
-		} else if argsS[2] == "false" {
-			value = false
+		if expires > 0 {
+			args = &ptypes.SetBaseUntilArgs{addr, pF, value, expires}
 		} else {
-			return nil, fmt.Errorf("Unknown value %s", argsS[2])
+			args = &ptypes.SetBaseArgs{addr, pF, value}
 		}
-		args = &ptypes.SetBaseArgs{addr, pF, value}
 	case "unsetBase":
 		addr, pF, err := decodeAddressPermFlag(argsS[0], argsS[1])
 		if err != nil {
@@ -132,13 +281,9 @@ func Permissions(nodeClient client.NodeClient, keyClient keys.KeyClient, pubkey,
 		if err != nil {
 			return nil, err
 		}
-		var value bool
-		if argsS[1] == "true" {
-			value = true
-		} else if argsS[1] == "false" {
-			value = false
-		} else {
-			return nil, fmt.Errorf("Unknown value %s", argsS[1])
+		value, err := strconv.ParseBool(argsS[1])
+		if err != nil {
+			return nil, fmt.Errorf("Unknown value %s (use true or false)", argsS[1])
 		}
 		args = &ptypes.SetGlobalArgs{pF, value}
 	case "addRole":
@@ -146,18 +291,67 @@ func Permissions(nodeClient client.NodeClient, keyClient keys.KeyClient, pubkey,
 		if err != nil {
 			return nil, err
 		}
-		args = &ptypes.AddRoleArgs{addr, argsS[1]}
-	case "removeRole":
+		expires, err := decodeExpiry(argsS, 2)
+		if err != nil {
+			return nil, err
+		}
+		if expires > 0 {
+			args = &ptypes.AddRoleUntilArgs{addr, argsS[1], expires}
+		} else {
+			args = &ptypes.AddRoleArgs{addr, argsS[1]}
+		}
+	case "rmRole":
 		addr, err := hex.DecodeString(argsS[0])
 		if err != nil {
 			return nil, err
 		}
 		args = &ptypes.RmRoleArgs{addr, argsS[1]}
-	default:
-		return nil, fmt.Errorf("Invalid permission function for use in PermissionsTx: %s", permFunc)
+	case "setRole":
+		base, err := decodeRolePermissions(argsS[1])
+		if err != nil {
+			return nil, err
+		}
+		args = &ptypes.SetRoleArgs{ptypes.Role{
+			Name:     argsS[0],
+			Base:     base,
+			Inherits: splitList(argsS[2]),
+			Admin:    argsS[3],
+		}}
+	case "allowCall", "disallowCall", "allowRoleCall", "disallowRoleCall":
+		var addr []byte
+		role := argsS[0]
+		if permFunc == "allowCall" || permFunc == "disallowCall" {
+			role = ""
+			if addr, err = hex.DecodeString(argsS[0]); err != nil {
+				return nil, err
+			}
+		}
+		contract, err := hex.DecodeString(argsS[1])
+		if err != nil {
+			return nil, err
+		}
+		if strings.HasPrefix(permFunc, "allow") {
+			args = &ptypes.AllowCallArgs{addr, role, contract}
+		} else {
+			args = &ptypes.DisallowCallArgs{addr, role, contract}
+		}
+	case "unrestrictCalls":
+		addr, err := hex.DecodeString(argsS[0])
+		if err != nil {
+			return nil, err
+		}
+		args = &ptypes.UnrestrictCallsArgs{addr, ""}
+	case "unrestrictRoleCalls":
+		args = &ptypes.UnrestrictCallsArgs{nil, argsS[0]}
+	case "approve":
+		proposal, err := hex.DecodeString(argsS[0])
+		if err != nil {
+			return nil, fmt.Errorf("proposal hash is bad hex: %v", err)
+		}
+		args = &ptypes.ApproveArgs{proposal}
 	}
-	// args := snativeArgs(
-	tx := txs.NewPermissionsTxWithNonce(pub, args, int(nonce))
+	tx = txs.NewPermissionsTxWithNonce(pub, args, int(nonce))
+	tx.Input.Address = address
 	return tx, nil
 }
 
@@ -243,6 +437,7 @@ type TxResult struct {
 	// only CallTx
 	Address   []byte // only for new contracts
 	Return    []byte
+	Logs      []txs.EventDataLog
 	Exception string
 
 	//TODO: make Broadcast() errors more responsive so we
@@ -254,65 +449,139 @@ func SignAndBroadcast(chainID string, nodeClient client.NodeClient, keyClient ke
 	broadcast, wait bool) (txResult *TxResult, err error) {
 	var inputAddr []byte
 	if sign {
-		inputAddr, tx, err = signTx(keyClient, chainID, tx)
+		inputAddr, tx, err = SignTx(keyClient, chainID, tx)
 		if err != nil {
+			releaseNonce(nodeClient, tx)
 			return nil, err
 		}
 	}
+	if !broadcast {
+		releaseNonce(nodeClient, tx)
+	}
 
 	if broadcast {
-		if wait {
-			wsClient, err := nodeClient.DeriveWebsocketClient()
-			if err != nil {
-				return nil, err
-			}
-			var confirmationChannel chan client.Confirmation
-			confirmationChannel, err = wsClient.WaitForConfirmation(tx, chainID, inputAddr)
-			if err != nil {
-				return nil, err
-			} else {
-				defer func() {
-					if err != nil {
-						// if broadcast threw an error, just return
-						return
-					}
-					confirmation := <-confirmationChannel
-					if confirmation.Error != nil {
-						err = fmt.Errorf("Encountered error waiting for event: %s", confirmation.Error)
-						return
-					}
-					if confirmation.Exception != nil {
-						err = fmt.Errorf("Encountered Exception from chain: %s", confirmation.Exception)
-						return
-					}
-					txResult.BlockHash = confirmation.BlockHash
-					txResult.Exception = ""
-					eventDataTx, ok := confirmation.Event.(*txs.EventDataTx)
-					if !ok {
-						err = fmt.Errorf("Received wrong event type.")
-						return
-					}
-					txResult.Return = eventDataTx.Return
-				}()
-			}
+		var confirm func() (*TxResult, error)
+		confirm, err = BroadcastAsync(chainID, nodeClient, tx, inputAddr, wait)
+		if err != nil {
+			return nil, err
+		}
+		return confirm()
+	}
+	return
+}
+
+// BroadcastTx broadcasts a transaction that has already been signed, for
+// example offline, optionally waiting for it to be committed in a block
+func BroadcastTx(chainID string, nodeClient client.NodeClient, tx txs.Tx, wait bool) (*TxResult, error) {
+	inputAddr, err := txInputAddress(tx)
+	if err != nil {
+		return nil, err
+	}
+	confirm, err := BroadcastAsync(chainID, nodeClient, tx, inputAddr, wait)
+	if err != nil {
+		return nil, err
+	}
+	return confirm()
+}
+
+// BroadcastAsync broadcasts a signed transaction and returns a function that
+// returns its TxResult. If wait is true that function blocks until the
+// transaction has been committed in a block. Separating the two lets callers
+// broadcast many transactions while waiting for their confirmations at once.
+// Transactions with an input from inputAddr are broadcast through the
+// NonceManager of nodeClient, so they are broadcast in nonce order after
+// those with earlier nonces reserved from it.
+func BroadcastAsync(chainID string, nodeClient client.NodeClient, tx txs.Tx, inputAddr []byte,
+	wait bool) (func() (*TxResult, error), error) {
+	sequence, ok := txInputSequence(tx, inputAddr)
+	if !ok {
+		return broadcastAsync(chainID, nodeClient, tx, inputAddr, wait)
+	}
+	receipt, confirmationChannel, err := nodeClient.NonceManager().Broadcast(tx, chainID,
+		inputAddr, sequence)
+	if err != nil {
+		return nil, err
+	}
+	if wait && confirmationChannel == nil {
+		return nil, fmt.Errorf("Broadcast transaction %X but cannot wait for it "+
+			"to be committed without a websocket to the node", receipt.TxHash)
+	}
+	txResult := newTxResult(tx, receipt)
+	return func() (*TxResult, error) {
+		if !wait {
+			return txResult, nil
 		}
+		return txResult, confirmTxResult(txResult, <-confirmationChannel)
+	}, nil
+}
 
-		var receipt *txs.Receipt
-		receipt, err = nodeClient.Broadcast(tx)
+// broadcastAsync broadcasts a transaction without a nonce to order it by
+func broadcastAsync(chainID string, nodeClient client.NodeClient, tx txs.Tx, inputAddr []byte,
+	wait bool) (func() (*TxResult, error), error) {
+	var wsClient client.NodeWebsocketClient
+	var confirmationChannel chan client.Confirmation
+	if wait {
+		var err error
+		wsClient, err = nodeClient.DeriveWebsocketClient()
 		if err != nil {
 			return nil, err
 		}
-		txResult = &TxResult{
-			Hash: receipt.TxHash,
+		confirmationChannel, err = wsClient.WaitForConfirmation(tx, chainID, inputAddr)
+		if err != nil {
+			wsClient.Close()
+			return nil, err
 		}
-		// NOTE: [ben] is this consistent with the Ethereum protocol?  It should seem
-		// reasonable to get this returned from the chain directly.  Alternatively,
-		// the benefit is that the we don't need to trust the chain node
-		if tx_, ok := tx.(*txs.CallTx); ok {
-			if len(tx_.Address) == 0 {
-				txResult.Address = txs.NewContractAddress(tx_.Input.Address, tx_.Input.Sequence)
-			}
+	}
+
+	receipt, err := nodeClient.Broadcast(tx)
+	if err != nil {
+		if wsClient != nil {
+			wsClient.Close()
 		}
+		return nil, err
 	}
-	return
+	txResult := newTxResult(tx, receipt)
+
+	return func() (*TxResult, error) {
+		if !wait {
+			return txResult, nil
+		}
+		defer wsClient.Close()
+		return txResult, confirmTxResult(txResult, <-confirmationChannel)
+	}, nil
+}
+
+func newTxResult(tx txs.Tx, receipt *txs.Receipt) *TxResult {
+	txResult := &TxResult{
+		Hash: receipt.TxHash,
+	}
+	// NOTE: [ben] is this consistent with the Ethereum protocol?  It should seem
+	// reasonable to get this returned from the chain directly.  Alternatively,
+	// the benefit is that the we don't need to trust the chain node
+	if tx_, ok := tx.(*txs.CallTx); ok {
+		if len(tx_.Address) == 0 {
+			txResult.Address = txs.NewContractAddress(tx_.Input.Address, tx_.Input.Sequence)
+		}
+	}
+	return txResult
+}
+
+// confirmTxResult fills in txResult from the confirmation that its
+// transaction was committed
+func confirmTxResult(txResult *TxResult, confirmation client.Confirmation) error {
+	if confirmation.Error != nil {
+		return fmt.Errorf("Encountered error waiting for event: %s", confirmation.Error)
+	}
+	if confirmation.Exception != nil {
+		return fmt.Errorf("Encountered Exception from chain: %s", confirmation.Exception)
+	}
+	txResult.BlockHash = confirmation.BlockHash
+	txResult.Exception = ""
+	eventDataTx, ok := confirmation.Event.(*txs.EventDataTx)
+	if !ok {
+		return fmt.Errorf("Received wrong event type.")
+	}
+	txResult.Return = eventDataTx.Return
+	txResult.Logs = confirmation.Logs
+	return nil
 }
@@ -21,27 +21,33 @@ import (
 	// "github.com/stretchr/testify/assert"
 
 	mockclient "github.com/hyperledger/burrow/client/mock"
-	mockkeys "github.com/hyperledger/burrow/keys/mock"
+	"github.com/hyperledger/burrow/keys"
+	ptypes "github.com/hyperledger/burrow/permission/types"
+	"github.com/hyperledger/burrow/txs"
 )
 
 func Test(t *testing.T) {
-	mockKeyClient := mockkeys.NewMockKeyClient()
+	memoryKeyClient := keys.NewMemoryKeyClient()
 	mockNodeClient := mockclient.NewMockNodeClient()
-	testSend(t, mockNodeClient, mockKeyClient)
-	testCall(t, mockNodeClient, mockKeyClient)
-	testName(t, mockNodeClient, mockKeyClient)
-	testPermissions(t, mockNodeClient, mockKeyClient)
+	testSend(t, mockNodeClient, memoryKeyClient)
+	testCall(t, mockNodeClient, memoryKeyClient)
+	testName(t, mockNodeClient, memoryKeyClient)
+	testNameTransfer(t, mockNodeClient, memoryKeyClient)
+	testPermissions(t, mockNodeClient, memoryKeyClient)
+	testSignOffline(t, mockNodeClient, memoryKeyClient)
+	testSendMulti(t, mockNodeClient)
+	testNonces(t, mockNodeClient, memoryKeyClient)
 	// t.Run("BondTransaction", )
 	// t.Run("UnbondTransaction", )
 	// t.Run("RebondTransaction", )
 }
 
 func testSend(t *testing.T,
-	nodeClient *mockclient.MockNodeClient, keyClient *mockkeys.MockKeyClient) {
+	nodeClient *mockclient.MockNodeClient, keyClient *keys.MemoryKeyClient) {
 
 	// generate an ED25519 key and ripemd160 address
 	addressString := fmt.Sprintf("%X", keyClient.NewKey())
-	// Public key can be queried from mockKeyClient.PublicKey(address)
+	// Public key can be queried from memoryKeyClient.PublicKey(address)
 	// but here we let the transaction factory retrieve the public key
 	// which will then also overwrite the address we provide the function.
 	// As a result we will assert whether address generated above, is identical
@@ -65,11 +71,11 @@ func testSend(t *testing.T,
 }
 
 func testCall(t *testing.T,
-	nodeClient *mockclient.MockNodeClient, keyClient *mockkeys.MockKeyClient) {
+	nodeClient *mockclient.MockNodeClient, keyClient *keys.MemoryKeyClient) {
 
 	// generate an ED25519 key and ripemd160 address
 	addressString := fmt.Sprintf("%X", keyClient.NewKey())
-	// Public key can be queried from mockKeyClient.PublicKey(address)
+	// Public key can be queried from memoryKeyClient.PublicKey(address)
 	// but here we let the transaction factory retrieve the public key
 	// which will then also overwrite the address we provide the function.
 	// As a result we will assert whether address generated above, is identical
@@ -98,11 +104,11 @@ func testCall(t *testing.T,
 }
 
 func testName(t *testing.T,
-	nodeClient *mockclient.MockNodeClient, keyClient *mockkeys.MockKeyClient) {
+	nodeClient *mockclient.MockNodeClient, keyClient *keys.MemoryKeyClient) {
 
 	// generate an ED25519 key and ripemd160 address
 	addressString := fmt.Sprintf("%X", keyClient.NewKey())
-	// Public key can be queried from mockKeyClient.PublicKey(address)
+	// Public key can be queried from memoryKeyClient.PublicKey(address)
 	// but here we let the transaction factory retrieve the public key
 	// which will then also overwrite the address we provide the function.
 	// As a result we will assert whether address generated above, is identical
@@ -128,12 +134,43 @@ func testName(t *testing.T,
 	// TODO: test content of Transaction
 }
 
+func testNameTransfer(t *testing.T,
+	nodeClient *mockclient.MockNodeClient, keyClient *keys.MemoryKeyClient) {
+
+	// generate an ED25519 key and ripemd160 address
+	addressString := fmt.Sprintf("%X", keyClient.NewKey())
+	publicKeyString := ""
+	// the amount is taken as a fee
+	amountString := "100"
+	// unset nonce so that we retrieve nonce from account
+	nonceString := ""
+	// generate an address for the new owner
+	newOwnerString := fmt.Sprintf("%X", keyClient.NewKey())
+	nameString := "DOUG/jr"
+
+	tx, err := NameTransfer(nodeClient, keyClient, publicKeyString, addressString,
+		amountString, nonceString, nameString, newOwnerString)
+	if err != nil {
+		t.Fatalf("Error in NameTransferTx: %s", err)
+	}
+	if tx.Name != nameString || fmt.Sprintf("%X", tx.NewOwner) != newOwnerString {
+		t.Errorf("Unexpected NameTransferTx %v", tx)
+	}
+
+	// the new owner must be an address
+	_, err = NameTransfer(nodeClient, keyClient, publicKeyString, addressString,
+		amountString, nonceString, nameString, "DEADBEEF")
+	if err == nil {
+		t.Error("Expected error for a new owner that is not an address")
+	}
+}
+
 func testPermissions(t *testing.T,
-	nodeClient *mockclient.MockNodeClient, keyClient *mockkeys.MockKeyClient) {
+	nodeClient *mockclient.MockNodeClient, keyClient *keys.MemoryKeyClient) {
 
 	// generate an ED25519 key and ripemd160 address
 	addressString := fmt.Sprintf("%X", keyClient.NewKey())
-	// Public key can be queried from mockKeyClient.PublicKey(address)
+	// Public key can be queried from memoryKeyClient.PublicKey(address)
 	// but here we let the transaction factory retrieve the public key
 	// which will then also overwrite the address we provide the function.
 	// As a result we will assert whether address generated above, is identical
@@ -144,11 +181,245 @@ func testPermissions(t *testing.T,
 	// unset nonce so that we retrieve nonce from account
 	nonceString := ""
 
-	_, err := Permissions(nodeClient, keyClient, publicKeyString, addressString,
-		nonceString, "setBase", []string{permAddressString, "root", "true"})
+	for permFunc, args := range map[string][]string{
+		"setBase":    {permAddressString, "root", "true"},
+		"unsetBase":  {permAddressString, "create_contract"},
+		"setGlobal":  {"send", "false"},
+		"addRole":    {permAddressString, "validators"},
+		"rmRole":     {permAddressString, "validators"},
+		"removeRole": {permAddressString, "validators"},
+		"setRole":    {"developers", "call,create_contract,!send", "users", "leads"},
+
+		"allowCall":           {permAddressString, addressString},
+		"disallowCall":        {permAddressString, addressString},
+		"unrestrictCalls":     {permAddressString},
+		"allowRoleCall":       {"regulated", addressString},
+		"disallowRoleCall":    {"regulated", addressString},
+		"unrestrictRoleCalls": {"regulated"},
+
+		"approve": {"B2C9F5D4A7E8E4B5E0E3A35B6D4E5C3C2E1F0A9B"},
+	} {
+		tx, err := Permissions(nodeClient, keyClient, publicKeyString, addressString,
+			nonceString, permFunc, args)
+		if err != nil {
+			t.Logf("Error in PermissionsTx %s: %s", permFunc, err)
+			t.Fail()
+			continue
+		}
+		if fmt.Sprintf("%X", tx.Input.Address) != addressString {
+			t.Logf("PermissionsTx %s has input address %X, expected %s", permFunc,
+				tx.Input.Address, addressString)
+			t.Fail()
+		}
+	}
+
+	// Grants may lapse at a block height
+	tx, err := Permissions(nodeClient, keyClient, publicKeyString, addressString,
+		nonceString, "addRole", []string{permAddressString, "contractors", "100"})
 	if err != nil {
-		t.Logf("Error in PermissionsTx: %s", err)
-		t.Fail()
+		t.Fatalf("Error in PermissionsTx addRole with expiry: %s", err)
+	}
+	if args, ok := tx.PermArgs.(*ptypes.AddRoleUntilArgs); !ok || args.Expires != 100 {
+		t.Errorf("PermissionsTx addRole has args %v, expected expiry 100", tx.PermArgs)
+	}
+	// and grants that do not lapse are encoded as they were before expiries
+	tx, err = Permissions(nodeClient, keyClient, publicKeyString, addressString,
+		nonceString, "addRole", []string{permAddressString, "contractors"})
+	if err != nil {
+		t.Fatalf("Error in PermissionsTx addRole: %s", err)
+	}
+	if _, ok := tx.PermArgs.(*ptypes.AddRoleArgs); !ok {
+		t.Errorf("PermissionsTx addRole has args %v, expected AddRoleArgs", tx.PermArgs)
+	}
+	_, err = Permissions(nodeClient, keyClient, publicKeyString, addressString,
+		nonceString, "setBase", []string{permAddressString, "call", "true", "soon"})
+	if err == nil {
+		t.Errorf("Expected error from PermissionsTx setBase with malformed expiry")
+	}
+
+	// Malformed invocations should be rejected rather than panic
+	for permFunc, args := range map[string][]string{
+		"setBase":      {permAddressString, "root"},
+		"setGlobal":    {"send", "maybe"},
+		"rmRole":       {permAddressString, "validators", "100"},
+		"addRole":      {permAddressString},
+		"unsetBase":    {permAddressString, "not_a_permission"},
+		"setRole":      {"developers", "call,not_a_permission", "", ""},
+		"allowCall":    {permAddressString, "not_an_address"},
+		"approve":      {"not_a_hash"},
+		"notAnSNative": {},
+	} {
+		_, err := Permissions(nodeClient, keyClient, publicKeyString, addressString,
+			nonceString, permFunc, args)
+		if err == nil {
+			t.Logf("Expected error from PermissionsTx %s with args %v", permFunc, args)
+			t.Fail()
+		}
+	}
+}
+
+func testSignOffline(t *testing.T,
+	nodeClient *mockclient.MockNodeClient, keyClient *keys.MemoryKeyClient) {
+	chainID := "testChain"
+	addressString := fmt.Sprintf("%X", keyClient.NewKey())
+	toAddressString := fmt.Sprintf("%X", keyClient.NewKey())
+	sendTx, err := Send(nodeClient, keyClient, "", addressString,
+		toAddressString, "1000", "1")
+	if err != nil {
+		t.Fatalf("Error in SendTx: %s", err)
+	}
+	if err := VerifyTxSignatures(chainID, sendTx); err == nil {
+		t.Error("Expected unsigned transaction to fail verification")
+	}
+
+	// Pass the transaction through its JSON encoding as when signing offline
+	tx, err := txs.DecodeTxJSON(txs.EncodeTxJSON(sendTx))
+	if err != nil {
+		t.Fatalf("Error decoding transaction: %s", err)
+	}
+	_, tx, err = SignTx(keyClient, chainID, tx)
+	if err != nil {
+		t.Fatalf("Error signing transaction: %s", err)
+	}
+	tx, err = txs.DecodeTxJSON(txs.EncodeTxJSON(tx))
+	if err != nil {
+		t.Fatalf("Error decoding signed transaction: %s", err)
+	}
+	if err := VerifyTxSignatures(chainID, tx); err != nil {
+		t.Errorf("Expected signed transaction to verify: %s", err)
+	}
+	if err := VerifyTxSignatures("otherChain", tx); err == nil {
+		t.Error("Expected signature for another chain to fail verification")
+	}
+
+	// Transactions without inputs are rejected rather than broadcast
+	for _, tx := range []txs.Tx{&txs.SendTx{}, &txs.BondTx{}} {
+		if err := VerifyTxSignatures(chainID, tx); err == nil {
+			t.Errorf("Expected %v without inputs to fail verification", tx)
+		}
+		if _, err := BroadcastTx(chainID, nodeClient, tx, false); err == nil {
+			t.Errorf("Expected broadcasting %v without inputs to fail", tx)
+		}
+	}
+	for _, tx := range []txs.Tx{&txs.UnbondTx{}, &txs.RebondTx{}} {
+		if err := VerifyTxSignatures(chainID, tx); err == nil {
+			t.Errorf("Expected unsigned %v to fail verification", tx)
+		}
+	}
+}
+
+func testSendMulti(t *testing.T, nodeClient *mockclient.MockNodeClient) {
+	chainID := "testChain"
+	// each input is held by a different key holder
+	aliceKeys := keys.NewMemoryKeyClient()
+	bobKeys := keys.NewMemoryKeyClient()
+	alice := aliceKeys.NewKey()
+	bob := bobKeys.NewKey()
+	to := fmt.Sprintf("%X", aliceKeys.NewKey())
+	sendTx, err := SendMulti(nodeClient,
+		[]string{fmt.Sprintf("%X:600", alice), fmt.Sprintf("%X:500:3", bob)},
+		[]string{to + ":1000"})
+	if err != nil {
+		t.Fatalf("Error in SendMulti: %s", err)
+	}
+	if len(sendTx.Inputs) != 2 || sendTx.Inputs[0].Sequence != 1 ||
+		sendTx.Inputs[1].Sequence != 3 {
+		t.Fatalf("Unexpected inputs %v", sendTx.Inputs)
+	}
+	if len(UnsignedInputs(sendTx)) != 2 {
+		t.Errorf("Expected both inputs to be unsigned")
+	}
+
+	_, err = SignSendTxInputs(aliceKeys, chainID, sendTx, [][]byte{bob})
+	if err == nil {
+		t.Error("Expected signing an input without its key to fail")
+	}
+	signed, err := SignSendTxInputs(aliceKeys, chainID, sendTx, nil)
+	if err != nil {
+		t.Fatalf("Error signing transaction: %s", err)
+	}
+	if len(signed) != 1 || !containsAddress(signed, alice) {
+		t.Errorf("Expected only the input of %X to be signed but got %X", alice,
+			signed)
+	}
+	// Pass the partially signed transaction on to the next key holder
+	tx, err := txs.DecodeTxJSON(txs.EncodeTxJSON(sendTx))
+	if err != nil {
+		t.Fatalf("Error decoding transaction: %s", err)
+	}
+	unsigned := UnsignedInputs(tx)
+	if len(unsigned) != 1 || !containsAddress(unsigned, bob) {
+		t.Errorf("Expected only the input of %X to be unsigned but got %X", bob,
+			unsigned)
+	}
+	if err := VerifyTxSignatures(chainID, tx); err == nil {
+		t.Error("Expected partially signed transaction to fail verification")
+	}
+	_, err = SignSendTxInputs(bobKeys, chainID, tx.(*txs.SendTx), [][]byte{bob})
+	if err != nil {
+		t.Fatalf("Error signing transaction: %s", err)
+	}
+	if err := VerifyTxSignatures(chainID, tx); err != nil {
+		t.Errorf("Expected fully signed transaction to verify: %s", err)
+	}
+
+	for _, invalid := range [][]string{
+		{fmt.Sprintf("%X:100", alice)},
+		{fmt.Sprintf("%X:2000", alice), fmt.Sprintf("%X:1", alice)},
+		{fmt.Sprintf("%X", alice)},
+		{"XYZ:2000"},
+	} {
+		if _, err := SendMulti(nodeClient, invalid, []string{to + ":1000"}); err == nil {
+			t.Errorf("Expected inputs %v to be invalid", invalid)
+		}
+	}
+}
+
+func testNonces(t *testing.T,
+	nodeClient *mockclient.MockNodeClient, keyClient *keys.MemoryKeyClient) {
+	chainID := "testChain"
+	addressString := fmt.Sprintf("%X", keyClient.NewKey())
+	toAddressString := fmt.Sprintf("%X", keyClient.NewKey())
+	send := func(toAddr string) (*txs.SendTx, int) {
+		tx, err := Send(nodeClient, keyClient, "", addressString, toAddr, "1000", "")
+		if err != nil {
+			return nil, 0
+		}
+		return tx, tx.Inputs[0].Sequence
+	}
+
+	// Nonces are reserved from the NonceManager of the node client so that
+	// transactions formed before the previous one is committed follow it
+	first, firstNonce := send(toAddressString)
+	second, secondNonce := send(toAddressString)
+	if firstNonce != 1 || secondNonce != 2 {
+		t.Fatalf("Expected nonces 1 and 2 but got %v and %v", firstNonce, secondNonce)
+	}
+	// Transactions that cannot be formed release their nonce
+	if tx, _ := send("not hex"); tx != nil {
+		t.Fatal("Expected SendTx with bad destination to fail")
+	}
+	third, thirdNonce := send(toAddressString)
+	if thirdNonce != 3 {
+		t.Errorf("Expected released nonce 3 to be reserved again but got %v", thirdNonce)
+	}
+
+	if _, err := SignAndBroadcast(chainID, nodeClient, keyClient, first, true, true,
+		false); err != nil {
+		t.Errorf("Error broadcasting first transaction: %s", err)
+	}
+	// as do transactions that are only signed
+	if _, err := SignAndBroadcast(chainID, nodeClient, keyClient, third, true, false,
+		false); err != nil {
+		t.Errorf("Error signing third transaction: %s", err)
+	}
+	if _, nonce := send(toAddressString); nonce != 3 {
+		t.Errorf("Expected nonce of transaction not broadcast to be reserved again "+
+			"but got %v", nonce)
+	}
+	// Waiting for confirmation needs a websocket, which the mock does not have
+	if _, err := SignAndBroadcast(chainID, nodeClient, keyClient, second, true, true,
+		true); err == nil {
+		t.Error("Expected waiting for confirmation without a websocket to fail")
 	}
-	// TODO: test content of Transaction
 }
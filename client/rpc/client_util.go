@@ -15,9 +15,11 @@
 package rpc
 
 import (
+	"bytes"
 	"encoding/hex"
 	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/tendermint/go-crypto"
 
@@ -32,50 +34,380 @@ import (
 //------------------------------------------------------------------------------------
 // sign and broadcast convenience
 
-// tx has either one input or we default to the first one (ie for send/bond)
-// TODO: better support for multisig and bonding
-func signTx(keyClient keys.KeyClient, chainID string, tx_ txs.Tx) ([]byte, txs.Tx, error) {
+// SignTx signs tx with the key held by keyClient for the address of its input,
+// returning the input address and the signed tx.
+// tx has either one input or we default to the first one (ie for send/bond);
+// use SignSendTxInputs to sign SendTxs with several inputs
+func SignTx(keyClient keys.KeyClient, chainID string, tx_ txs.Tx) ([]byte, txs.Tx, error) {
 	signBytesString := fmt.Sprintf("%X", acc.SignBytes(chainID, tx_))
 	var inputAddr []byte
-	var sigED crypto.SignatureEd25519
+	var setSignature func(sig crypto.Signature) error
 	switch tx := tx_.(type) {
 	case *txs.SendTx:
 		inputAddr = tx.Inputs[0].Address
-		defer func(s *crypto.SignatureEd25519) { tx.Inputs[0].Signature = *s }(&sigED)
+		setSignature = func(sig crypto.Signature) error {
+			tx.Inputs[0].Signature = sig
+			return nil
+		}
 	case *txs.NameTx:
 		inputAddr = tx.Input.Address
-		defer func(s *crypto.SignatureEd25519) { tx.Input.Signature = *s }(&sigED)
+		setSignature = func(sig crypto.Signature) error {
+			tx.Input.Signature = sig
+			return nil
+		}
+	case *txs.NameTransferTx:
+		inputAddr = tx.Input.Address
+		setSignature = func(sig crypto.Signature) error {
+			tx.Input.Signature = sig
+			return nil
+		}
 	case *txs.CallTx:
 		inputAddr = tx.Input.Address
-		defer func(s *crypto.SignatureEd25519) { tx.Input.Signature = *s }(&sigED)
+		setSignature = func(sig crypto.Signature) error {
+			tx.Input.Signature = sig
+			return nil
+		}
 	case *txs.PermissionsTx:
 		inputAddr = tx.Input.Address
-		defer func(s *crypto.SignatureEd25519) { tx.Input.Signature = *s }(&sigED)
+		setSignature = func(sig crypto.Signature) error {
+			tx.Input.Signature = sig
+			return nil
+		}
 	case *txs.BondTx:
 		inputAddr = tx.Inputs[0].Address
-		defer func(s *crypto.SignatureEd25519) {
-			tx.Signature = *s
-			tx.Inputs[0].Signature = *s
-		}(&sigED)
+		setSignature = func(sig crypto.Signature) error {
+			sigED, err := validatorSignature(sig)
+			tx.Signature = sigED
+			tx.Inputs[0].Signature = sig
+			return err
+		}
 	case *txs.UnbondTx:
 		inputAddr = tx.Address
-		defer func(s *crypto.SignatureEd25519) { tx.Signature = *s }(&sigED)
+		setSignature = func(sig crypto.Signature) (err error) {
+			tx.Signature, err = validatorSignature(sig)
+			return
+		}
 	case *txs.RebondTx:
 		inputAddr = tx.Address
-		defer func(s *crypto.SignatureEd25519) { tx.Signature = *s }(&sigED)
+		setSignature = func(sig crypto.Signature) (err error) {
+			tx.Signature, err = validatorSignature(sig)
+			return
+		}
+	default:
+		return nil, nil, fmt.Errorf("Cannot sign transaction of type %T", tx_)
 	}
-	sig, err := keyClient.Sign(signBytesString, inputAddr)
+	sigBytes, err := keyClient.Sign(signBytesString, inputAddr)
 	if err != nil {
 		return nil, nil, err
 	}
-	// TODO: [ben] temporarily address the type conflict here, to be cleaned up
-	// with full type restructuring
-	var sig64 [64]byte
-	copy(sig64[:], sig)
-	sigED = crypto.SignatureEd25519(sig64)
+	pubKeyBytes, err := keyClient.PublicKey(inputAddr)
+	if err != nil {
+		return nil, nil, err
+	}
+	pubKey, err := acc.PubKeyFromBytes(pubKeyBytes)
+	if err != nil {
+		return nil, nil, err
+	}
+	sig, err := acc.SignatureFromBytes(pubKey, sigBytes)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := setSignature(sig); err != nil {
+		return nil, nil, err
+	}
 	return inputAddr, tx_, nil
 }
 
+// Validators sign with ed25519 keys
+func validatorSignature(sig crypto.Signature) (crypto.SignatureEd25519, error) {
+	sigED, ok := sig.(crypto.SignatureEd25519)
+	if !ok {
+		return sigED, fmt.Errorf("Validator transactions must be signed with " +
+			"an ed25519 key")
+	}
+	return sigED, nil
+}
+
+// VerifyTxSignatures checks that the inputs of tx are signed over its sign
+// bytes for chainID by their public keys. Inputs without a public key are
+// left for the node to check against the public key it has for the account,
+// as are the signatures of UnbondTx and RebondTx against the validator's.
+func VerifyTxSignatures(chainID string, tx_ txs.Tx) error {
+	signBytes := acc.SignBytes(chainID, tx_)
+	var inputs []*txs.TxInput
+	switch tx := tx_.(type) {
+	case *txs.SendTx:
+		inputs = tx.Inputs
+	case *txs.CallTx:
+		inputs = []*txs.TxInput{tx.Input}
+	case *txs.NameTx:
+		inputs = []*txs.TxInput{tx.Input}
+	case *txs.NameTransferTx:
+		inputs = []*txs.TxInput{tx.Input}
+	case *txs.PermissionsTx:
+		inputs = []*txs.TxInput{tx.Input}
+	case *txs.BondTx:
+		inputs = tx.Inputs
+		if !tx.PubKey.VerifyBytes(signBytes, tx.Signature) {
+			return fmt.Errorf("Bond signature does not verify for validator "+
+				"public key %X", tx.PubKey[:])
+		}
+	case *txs.UnbondTx:
+		if tx.Signature == (crypto.SignatureEd25519{}) {
+			return fmt.Errorf("Unbond of validator %X is not signed", tx.Address)
+		}
+		return nil
+	case *txs.RebondTx:
+		if tx.Signature == (crypto.SignatureEd25519{}) {
+			return fmt.Errorf("Rebond of validator %X is not signed", tx.Address)
+		}
+		return nil
+	}
+	if len(inputs) == 0 {
+		return fmt.Errorf("Transaction has no inputs")
+	}
+	for _, input := range inputs {
+		if input == nil {
+			return fmt.Errorf("Transaction has a missing input")
+		}
+		if !isSigned(input) {
+			return fmt.Errorf("Input %X is not signed", input.Address)
+		}
+		if input.PubKey == nil {
+			continue
+		}
+		if !input.PubKey.VerifyBytes(signBytes, input.Signature) {
+			return fmt.Errorf("Signature of input %X does not verify for its "+
+				"public key %X", input.Address, input.PubKey.Bytes())
+		}
+	}
+	return nil
+}
+
+// SignSendTxInputs signs the inputs of tx with the keys held by keyClient so
+// that a SendTx spending from several accounts can be signed by each key
+// holder in turn. Only the inputs for addresses are signed, or when addresses
+// is empty every input keyClient can sign. Inputs are given the public key of
+// their signer so accounts that have not yet sent a transaction can be
+// spent from. The addresses of the inputs signed are returned.
+func SignSendTxInputs(keyClient keys.KeyClient, chainID string, tx *txs.SendTx,
+	addresses [][]byte) ([][]byte, error) {
+	signBytesString := fmt.Sprintf("%X", acc.SignBytes(chainID, tx))
+	var signed [][]byte
+	var lastErr error
+	for _, input := range tx.Inputs {
+		if len(addresses) > 0 && !containsAddress(addresses, input.Address) {
+			continue
+		}
+		err := signInput(keyClient, signBytesString, input)
+		if err != nil {
+			if len(addresses) > 0 {
+				return nil, fmt.Errorf("Could not sign input %X: %s", input.Address,
+					err)
+			}
+			// The key for this input is held by someone else
+			lastErr = err
+			continue
+		}
+		signed = append(signed, input.Address)
+	}
+	for _, address := range addresses {
+		if !containsAddress(signed, address) {
+			return nil, fmt.Errorf("Transaction has no input for address %X",
+				address)
+		}
+	}
+	if len(signed) == 0 {
+		return nil, fmt.Errorf("None of the inputs could be signed, last error: %v",
+			lastErr)
+	}
+	return signed, nil
+}
+
+// UnsignedInputs returns the addresses of the inputs of tx still to be signed
+func UnsignedInputs(tx_ txs.Tx) [][]byte {
+	var inputs []*txs.TxInput
+	switch tx := tx_.(type) {
+	case *txs.SendTx:
+		inputs = tx.Inputs
+	case *txs.CallTx:
+		inputs = []*txs.TxInput{tx.Input}
+	case *txs.NameTx:
+		inputs = []*txs.TxInput{tx.Input}
+	case *txs.NameTransferTx:
+		inputs = []*txs.TxInput{tx.Input}
+	case *txs.PermissionsTx:
+		inputs = []*txs.TxInput{tx.Input}
+	case *txs.BondTx:
+		inputs = tx.Inputs
+	}
+	var unsigned [][]byte
+	for _, input := range inputs {
+		if input == nil {
+			return fmt.Errorf("Transaction has a missing input")
+		}
+		if !isSigned(input) {
+			unsigned = append(unsigned, input.Address)
+		}
+	}
+	return unsigned
+}
+
+func signInput(keyClient keys.KeyClient, signBytesString string,
+	input *txs.TxInput) error {
+	pubKeyBytes, err := keyClient.PublicKey(input.Address)
+	if err != nil {
+		return err
+	}
+	pub, err := acc.PubKeyFromBytes(pubKeyBytes)
+	if err != nil {
+		return err
+	}
+	if !acc.AddressMatchesPubKey(input.Address, pub) {
+		return fmt.Errorf("Public key %X is not for address %X", pubKeyBytes,
+			input.Address)
+	}
+	sigBytes, err := keyClient.Sign(signBytesString, input.Address)
+	if err != nil {
+		return err
+	}
+	sig, err := acc.SignatureFromBytes(pub, sigBytes)
+	if err != nil {
+		return err
+	}
+	input.PubKey = pub
+	input.Signature = sig
+	return nil
+}
+
+// Inputs formed by txs.SendTx.AddInputWithNonce carry an empty signature
+// rather than none
+func isSigned(input *txs.TxInput) bool {
+	switch sig := input.Signature.(type) {
+	case nil:
+		return false
+	case crypto.SignatureEd25519:
+		return sig != crypto.SignatureEd25519{}
+	case crypto.SignatureSecp256k1:
+		return len(sig) > 0
+	}
+	return true
+}
+
+func txInputAddresses(tx *txs.SendTx) [][]byte {
+	addresses := make([][]byte, len(tx.Inputs))
+	for i, input := range tx.Inputs {
+		addresses[i] = input.Address
+	}
+	return addresses
+}
+
+func decodeAddressAmount(addrS, amtS string) ([]byte, int64, error) {
+	address, err := hex.DecodeString(addrS)
+	if err != nil {
+		return nil, 0, fmt.Errorf("address is bad hex: %v", err)
+	}
+	if len(address) != 20 {
+		return nil, 0, fmt.Errorf("address should be 20 bytes but is %v", len(address))
+	}
+	amount, err := strconv.ParseInt(amtS, 10, 64)
+	if err != nil {
+		return nil, 0, fmt.Errorf("amount is misformatted: %v", err)
+	}
+	if amount <= 0 {
+		return nil, 0, fmt.Errorf("amount should be positive")
+	}
+	return address, amount, nil
+}
+
+func containsAddress(addresses [][]byte, address []byte) bool {
+	for _, a := range addresses {
+		if bytes.Equal(a, address) {
+			return true
+		}
+	}
+	return false
+}
+
+// the address of the (first) input of tx, as signed by SignTx
+func txInputAddress(tx_ txs.Tx) ([]byte, error) {
+	var inputs []*txs.TxInput
+	switch tx := tx_.(type) {
+	case *txs.SendTx:
+		inputs = tx.Inputs
+	case *txs.CallTx:
+		inputs = []*txs.TxInput{tx.Input}
+	case *txs.NameTx:
+		inputs = []*txs.TxInput{tx.Input}
+	case *txs.NameTransferTx:
+		inputs = []*txs.TxInput{tx.Input}
+	case *txs.PermissionsTx:
+		inputs = []*txs.TxInput{tx.Input}
+	case *txs.BondTx:
+		inputs = tx.Inputs
+	case *txs.UnbondTx:
+		return tx.Address, nil
+	case *txs.RebondTx:
+		return tx.Address, nil
+	default:
+		return nil, fmt.Errorf("Unknown transaction type %T", tx_)
+	}
+	if len(inputs) == 0 || inputs[0] == nil {
+		return nil, fmt.Errorf("Transaction has no inputs")
+	}
+	return inputs[0].Address, nil
+}
+
+// txInputSequence returns the sequence of the input of tx from address
+func txInputSequence(tx_ txs.Tx, address []byte) (int, bool) {
+	var inputs []*txs.TxInput
+	switch tx := tx_.(type) {
+	case *txs.SendTx:
+		inputs = tx.Inputs
+	case *txs.CallTx:
+		inputs = []*txs.TxInput{tx.Input}
+	case *txs.NameTx:
+		inputs = []*txs.TxInput{tx.Input}
+	case *txs.NameTransferTx:
+		inputs = []*txs.TxInput{tx.Input}
+	case *txs.PermissionsTx:
+		inputs = []*txs.TxInput{tx.Input}
+	case *txs.BondTx:
+		inputs = tx.Inputs
+	}
+	for _, input := range inputs {
+		if input != nil && bytes.Equal(input.Address, address) {
+			return input.Sequence, true
+		}
+	}
+	return 0, false
+}
+
+// releaseNonce returns the nonce of the input of tx to the NonceManager of
+// nodeClient when tx will not be broadcast. Nonces that were not reserved are
+// left alone.
+func releaseNonce(nodeClient client.NodeClient, tx txs.Tx) {
+	if nodeClient == nil {
+		return
+	}
+	address, err := txInputAddress(tx)
+	if err != nil {
+		return
+	}
+	if sequence, ok := txInputSequence(tx, address); ok {
+		nodeClient.NonceManager().Release(address, sequence)
+	}
+}
+
+// releaseNonceOnError releases the nonce checkCommon reserved for address
+// when forming the transaction then fails with *err
+func releaseNonceOnError(nodeClient client.NodeClient, nonceS string, address []byte,
+	nonce int64, err *error) {
+	if *err != nil && nonceS == "" {
+		nodeClient.NonceManager().Release(address, int(nonce))
+	}
+}
+
 func decodeAddressPermFlag(addrS, permFlagS string) (addr []byte, pFlag ptypes.PermFlag, err error) {
 	if addr, err = hex.DecodeString(addrS); err != nil {
 		return
@@ -86,7 +418,53 @@ func decodeAddressPermFlag(addrS, permFlagS string) (addr []byte, pFlag ptypes.P
 	return
 }
 
-func checkCommon(nodeClient client.NodeClient, keyClient keys.KeyClient, pubkey, addr, amtS, nonceS string) (pub crypto.PubKey, amt int64, nonce int64, err error) {
+// decodeRolePermissions reads a comma separated list of the permissions a role
+// grants, where a permission prefixed with ! is denied rather than granted
+func decodeRolePermissions(permsS string) (ptypes.BasePermissions, error) {
+	base := ptypes.ZeroBasePermissions
+	for _, permS := range splitList(permsS) {
+		value := !strings.HasPrefix(permS, "!")
+		pFlag, err := ptypes.PermStringToFlag(strings.TrimPrefix(permS, "!"))
+		if err != nil {
+			return base, err
+		}
+		base.Set(pFlag, value)
+	}
+	return base, nil
+}
+
+// decodeExpiry reads the optional expiry block height at index i of args,
+// returning 0 (no expiry) if it is not given
+func decodeExpiry(args []string, i int) (int64, error) {
+	if len(args) <= i {
+		return 0, nil
+	}
+	expires, err := strconv.ParseInt(args[i], 10, 64)
+	if err != nil || expires < 0 {
+		return 0, fmt.Errorf("Expiry %s should be a block height", args[i])
+	}
+	return expires, nil
+}
+
+// splitList splits a comma separated list, which may be empty
+func splitList(listS string) []string {
+	var list []string
+	for _, item := range strings.Split(listS, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+// checkCommon resolves the public key and address of the input of a
+// transaction, its amount, and its nonce. The public key may be ed25519 or
+// secp256k1. The address is --addr when it belongs to the public key under
+// either address scheme, so that Ethereum addresses of secp256k1 keys can be
+// used, and otherwise the address derived from the public key. A nonce that
+// is not given is reserved from the NonceManager of nodeClient, so it must be
+// broadcast through BroadcastAsync or released.
+func checkCommon(nodeClient client.NodeClient, keyClient keys.KeyClient, pubkey, addr, amtS, nonceS string) (pub crypto.PubKey, addrBytes []byte, amt int64, nonce int64, err error) {
 	if amtS == "" {
 		err = fmt.Errorf("input must specify an amount with the --amt flag")
 		return
@@ -130,25 +508,33 @@ func checkCommon(nodeClient client.NodeClient, keyClient keys.KeyClient, pubkey,
 	amt, err = strconv.ParseInt(amtS, 10, 64)
 	if err != nil {
 		err = fmt.Errorf("amt is misformatted: %v", err)
+		return
 	}
 
-	var pubArray [32]byte
-	copy(pubArray[:], pubKeyBytes)
-	pub = crypto.PubKeyEd25519(pubArray)
-	addrBytes := pub.Address()
+	pub, err = acc.PubKeyFromBytes(pubKeyBytes)
+	if err != nil {
+		return
+	}
+	addrBytes = pub.Address()
+	if addr != "" {
+		if addressBytes, err2 := hex.DecodeString(addr); err2 == nil &&
+			acc.AddressMatchesPubKey(addressBytes, pub) {
+			addrBytes = addressBytes
+		}
+	}
 
 	if nonceS == "" {
 		if nodeClient == nil {
 			err = fmt.Errorf("input must specify a nonce with the --nonce flag or use --node-addr (or BURROW_CLIENT_NODE_ADDR) to fetch the nonce from a node")
 			return
 		}
-		// fetch nonce from node
-		account, err2 := nodeClient.GetAccount(addrBytes)
+		// reserve nonce synced from node
+		sequence, err2 := nodeClient.NonceManager().Reserve(addrBytes)
 		if err2 != nil {
-			return pub, amt, nonce, err2
+			return pub, addrBytes, amt, nonce, err2
 		}
-		nonce = int64(account.Sequence) + 1
-		logging.TraceMsg(nodeClient.Logger(), "Fetch nonce from node",
+		nonce = int64(sequence)
+		logging.TraceMsg(nodeClient.Logger(), "Reserved nonce from node",
 			"nonce", nonce,
 			"account address", addrBytes,
 		)
@@ -36,6 +36,8 @@ const (
 type Confirmation struct {
 	BlockHash []byte
 	Event     txs.EventData
+	// Logs emitted by the callee of a CallTx while waiting for confirmation
+	Logs      []txs.EventDataLog
 	Exception error
 	Error     error
 }
@@ -82,9 +84,35 @@ func (burrowNodeWebsocketClient *burrowNodeWebsocketClient) WaitForConfirmation(
 	if err := burrowNodeWebsocketClient.Subscribe(txs.EventStringNewBlock()); err != nil {
 		return nil, fmt.Errorf("Error subscribing to NewBlock event: %v", err)
 	}
+	// Logs are fired during execution so arrive before the AccInput event of the
+	// transaction that emitted them, the Call events of the callee telling us
+	// which transaction that was
+	var logEventId, callEventId string
+	logCollector := &txLogCollector{txHash: txs.TxHash(chainId, tx)}
+	if callTx, ok := tx.(*txs.CallTx); ok && len(callTx.Address) > 0 {
+		logEventId = txs.EventStringLogEvent(callTx.Address)
+		if err := burrowNodeWebsocketClient.Subscribe(logEventId); err != nil {
+			return nil, fmt.Errorf("Error subscribing to Log event (%s): %v", logEventId, err)
+		}
+		callEventId = txs.EventStringAccCall(callTx.Address)
+		if err := burrowNodeWebsocketClient.Subscribe(callEventId); err != nil {
+			return nil, fmt.Errorf("Error subscribing to Call event (%s): %v", callEventId, err)
+		}
+	}
 	// Read the incoming events
 	go func() {
 		var err error
+		// Subscriptions to the logs of the callee are only for this confirmation
+		var logSubscriptionIds []string
+		defer func() {
+			for _, subscriptionId := range logSubscriptionIds {
+				if err := burrowNodeWebsocketClient.Unsubscribe(subscriptionId); err != nil {
+					logging.InfoMsg(burrowNodeWebsocketClient.logger, "Failed to unsubscribe",
+						"subscription_id", subscriptionId,
+						"error", err)
+				}
+			}
+		}()
 		for {
 			resultBytes := <-burrowNodeWebsocketClient.tendermintWebsocket.ResultsCh
 			result := new(ctypes.BurrowResult)
@@ -103,6 +131,10 @@ func (burrowNodeWebsocketClient *burrowNodeWebsocketClient) WaitForConfirmation(
 				logging.InfoMsg(burrowNodeWebsocketClient.logger, "Received confirmation for event",
 					"event", subscription.Event,
 					"subscription_id", subscription.SubscriptionId)
+				if logEventId != "" && (subscription.Event == logEventId ||
+					subscription.Event == callEventId) {
+					logSubscriptionIds = append(logSubscriptionIds, subscription.SubscriptionId)
+				}
 				continue
 			}
 
@@ -117,6 +149,7 @@ func (burrowNodeWebsocketClient *burrowNodeWebsocketClient) WaitForConfirmation(
 			blockData, ok := event.Data.(txs.EventDataNewBlock)
 			if ok {
 				latestBlockHash = blockData.Block.Hash()
+				logCollector.newBlock()
 				logging.TraceMsg(burrowNodeWebsocketClient.logger, "Registered new block",
 					"block", blockData.Block,
 					"latest_block_hash", latestBlockHash,
@@ -136,6 +169,20 @@ func (burrowNodeWebsocketClient *burrowNodeWebsocketClient) WaitForConfirmation(
 			// 	continue
 			// }
 
+			if logEventId != "" && event.Event == logEventId {
+				if logData, ok := event.Data.(txs.EventDataLog); ok {
+					logCollector.log(logData)
+				}
+				continue
+			}
+
+			if callEventId != "" && event.Event == callEventId {
+				if callData, ok := event.Data.(txs.EventDataCall); ok {
+					logCollector.call(callData)
+				}
+				continue
+			}
+
 			if event.Event != eid {
 				logging.InfoMsg(burrowNodeWebsocketClient.logger, "Received unsolicited event",
 					"event_received", event.Event,
@@ -166,6 +213,7 @@ func (burrowNodeWebsocketClient *burrowNodeWebsocketClient) WaitForConfirmation(
 				confirmationChannel <- Confirmation{
 					BlockHash: latestBlockHash,
 					Event:     &data,
+					Logs:      logCollector.logs,
 					Exception: fmt.Errorf("Transaction confirmed with exception: %v", data.Exception),
 					Error:     nil,
 				}
@@ -175,6 +223,7 @@ func (burrowNodeWebsocketClient *burrowNodeWebsocketClient) WaitForConfirmation(
 			confirmationChannel <- Confirmation{
 				BlockHash: latestBlockHash,
 				Event:     &data,
+				Logs:      logCollector.logs,
 				Exception: nil,
 				Error:     nil,
 			}
@@ -199,6 +248,73 @@ func (burrowNodeWebsocketClient *burrowNodeWebsocketClient) WaitForConfirmation(
 	return confirmationChannel, nil
 }
 
+// txLogCollector keeps the logs a contract emitted while executing the
+// transaction with txHash. The VM fires the Call event of a contract after the
+// logs of that call, so the logs received since the last Call event (or block)
+// are those of the transaction the Call event names.
+type txLogCollector struct {
+	txHash  []byte
+	pending []txs.EventDataLog
+	logs    []txs.EventDataLog
+}
+
+func (collector *txLogCollector) newBlock() {
+	collector.pending = nil
+}
+
+func (collector *txLogCollector) log(log txs.EventDataLog) {
+	collector.pending = append(collector.pending, log)
+}
+
+func (collector *txLogCollector) call(call txs.EventDataCall) {
+	if bytes.Equal(call.TxID, collector.txHash) {
+		collector.logs = append(collector.logs, collector.pending...)
+	}
+	collector.pending = nil
+}
+
+func (burrowNodeWebsocketClient *burrowNodeWebsocketClient) Events() (<-chan Event, error) {
+	if err := burrowNodeWebsocketClient.assertNoErrors(); err != nil {
+		return nil, err
+	}
+	tendermintWebsocket := burrowNodeWebsocketClient.tendermintWebsocket
+	events := make(chan Event)
+	go func() {
+		// The websocket closes its channels when its connection is lost
+		defer close(events)
+		for {
+			select {
+			case resultBytes, ok := <-tendermintWebsocket.ResultsCh:
+				if !ok {
+					return
+				}
+				var err error
+				result := new(ctypes.BurrowResult)
+				if wire.ReadJSONPtr(result, resultBytes, &err); err != nil {
+					logging.InfoMsg(burrowNodeWebsocketClient.logger, "Failed to unmarshal json bytes for websocket event",
+						"error", err)
+					continue
+				}
+				switch res := (*result).(type) {
+				case *ctypes.ResultSubscribe:
+					logging.InfoMsg(burrowNodeWebsocketClient.logger, "Received confirmation for event",
+						"event", res.Event,
+						"subscription_id", res.SubscriptionId)
+				case *ctypes.ResultEvent:
+					events <- Event{Event: res.Event, Data: res.Data}
+				}
+			case err, ok := <-tendermintWebsocket.ErrorsCh:
+				if !ok {
+					return
+				}
+				logging.InfoMsg(burrowNodeWebsocketClient.logger, "Error on websocket",
+					"error", err)
+			}
+		}
+	}()
+	return events, nil
+}
+
 func (burrowNodeWebsocketClient *burrowNodeWebsocketClient) Close() {
 	if burrowNodeWebsocketClient.tendermintWebsocket != nil {
 		burrowNodeWebsocketClient.tendermintWebsocket.Stop()
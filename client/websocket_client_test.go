@@ -0,0 +1,54 @@
+// Copyright 2017 Monax Industries Limited
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"testing"
+
+	"github.com/hyperledger/burrow/txs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTxLogCollector(t *testing.T) {
+	txHash := []byte("our transaction hash")
+	otherTxHash := []byte("another transaction")
+	collector := &txLogCollector{txHash: txHash}
+	log := func(data byte) txs.EventDataLog {
+		return txs.EventDataLog{Data: []byte{data}, Height: 7}
+	}
+
+	// Logs left over from the previous block are dropped
+	collector.log(log(0))
+	collector.newBlock()
+
+	// as are those of a transaction before ours calling the same contract
+	collector.log(log(1))
+	collector.call(txs.EventDataCall{TxID: otherTxHash})
+
+	// Our transaction may call the contract more than once
+	collector.log(log(2))
+	collector.call(txs.EventDataCall{TxID: txHash})
+	collector.call(txs.EventDataCall{TxID: txHash})
+	collector.log(log(3))
+	collector.log(log(4))
+	collector.call(txs.EventDataCall{TxID: txHash})
+
+	// and the transaction after ours is not ours
+	collector.log(log(5))
+	collector.call(txs.EventDataCall{TxID: otherTxHash})
+
+	assert.Equal(t, []txs.EventDataLog{log(2), log(3), log(4)}, collector.logs)
+}
@@ -197,6 +197,13 @@ func ServeRunner(do *definitions.Do) func(*cobra.Command, []string) {
 			if err != nil {
 				util.Fatalf("Failed to start Tendermint gateway")
 			}
+			if serverConfig.Grpc.Enable {
+				grpcServer, err := newCore.NewGatewayGrpc(serverConfig)
+				if err != nil {
+					util.Fatalf("Failed to start gRPC gateway: %s.", err)
+				}
+				defer grpcServer.Shutdown()
+			}
 			<-serverProcess.StopEventChannel()
 			// Attempt graceful shutdown
 			newCore.Stop()
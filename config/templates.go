@@ -146,6 +146,8 @@ const sectionServers = `[servers]
 
   [servers.http]
   json_rpc_endpoint = "/rpc"
+  # maximum number of requests in a JSON-RPC 2.0 batch (0 for no limit)
+  max_batch_size = 100
 
   [servers.websocket]
   endpoint = "/socketrpc"
@@ -157,6 +159,13 @@ const sectionServers = `[servers]
 	# Multiple listeners can be separated with a comma
 	rpc_local_address = "0.0.0.0:46657"
 	endpoint = "/websocket"
+	# maximum number of requests in a JSON-RPC 2.0 batch (0 for no limit)
+	max_batch_size = 100
+
+  [servers.grpc]
+  # gRPC gateway serving the services defined in rpc/grpc/burrow.proto
+  enable = false
+  listen_address = "0.0.0.0:10997"
 
   `
 
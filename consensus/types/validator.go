@@ -42,7 +42,7 @@ func (tendermintValidator *TendermintValidator) AssertIsValidator() {
 }
 
 func (tendermintValidator *TendermintValidator) Address() []byte {
-	return tendermintValidator.Address()
+	return tendermintValidator.Validator.Address
 }
 
 //-------------------------------------------------------------------------------------
@@ -31,6 +31,7 @@ import (
 
 	"github.com/hyperledger/burrow/logging"
 	logging_types "github.com/hyperledger/burrow/logging/types"
+	rpc_grpc "github.com/hyperledger/burrow/rpc/grpc"
 	rpc_tendermint "github.com/hyperledger/burrow/rpc/tendermint/core"
 	"github.com/hyperledger/burrow/server"
 )
@@ -95,7 +96,8 @@ func (core *Core) NewGatewayV0(config *server.ServerConfig) (*server.ServeProces
 	eventSubscriptions := event.NewEventSubscriptions(core.pipe.Events())
 	// The services.
 	tmwss := rpc_v0.NewBurrowWsService(codec, core.pipe)
-	tmjs := rpc_v0.NewBurrowJsonService(codec, core.pipe, eventSubscriptions)
+	tmjs := rpc_v0.NewBurrowJsonService(codec, core.pipe, eventSubscriptions,
+		int(config.HTTP.MaxBatchSize))
 	// The servers.
 	jsonServer := rpc_v0.NewJsonRpcServer(tmjs)
 	restServer := rpc_v0.NewRestServer(codec, core.pipe, eventSubscriptions)
@@ -119,6 +121,11 @@ func (core *Core) NewGatewayTendermint(config *server.ServerConfig) (
 		core.tendermintPipe, core.evsw)
 }
 
+func (core *Core) NewGatewayGrpc(config *server.ServerConfig) (
+	*rpc_grpc.GrpcServer, error) {
+	return rpc_grpc.NewGrpcServer(config, core.pipe, core.logger)
+}
+
 // Stop the core allowing for a graceful shutdown of component in order.
 func (core *Core) Stop() bool {
 	return core.pipe.GetConsensusEngine().Stop()
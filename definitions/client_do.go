@@ -27,10 +27,21 @@ type ClientDo struct {
 	AddrFlag     string
 	ChainidFlag  string
 
+	// Signing backend and its options
+	KeysBackendFlag      string
+	KeyStoreDirFlag      string
+	KeyStorePasswordFlag string
+	KeyTypeFlag          string
+	AddressSchemeFlag    string
+
 	// signFlag      bool // TODO: remove; unsafe signing without monax-keys
 	BroadcastFlag bool
 	WaitFlag      bool
 
+	// Write transactions to a file without signing or broadcasting them
+	UnsignedFlag bool
+	OutputFlag   string
+
 	// Following parameters are vary for different Transaction subcommands
 	// some of these are strings rather than flags because the `core`
 	// functions have a pure string interface so they work nicely from http
@@ -44,6 +55,29 @@ type ClientDo struct {
 	GasFlag      string
 	UnbondtoFlag string
 	HeightFlag   string
+
+	// Contract ABI file and function for encoding calls and decoding results
+	AbiFlag      string
+	FunctionFlag string
+
+	// Contract bytecode file and library addresses for deployment
+	BinFlag       string
+	LibrariesFlag string
+
+	// Output file for the results of burrow-client run
+	ResultsFileFlag string
+
+	// Comma separated inputs and outputs of a SendTx with several of each
+	InputsFlag  string
+	OutputsFlag string
+
+	// Output format of the read commands: table or json
+	FormatFlag string
+
+	// Events to stream and the block height to stream them from
+	EventTypeFlag    string
+	EventAddressFlag string
+	FromHeightFlag   string
 }
 
 func NewClientDo() *ClientDo {
@@ -53,6 +87,11 @@ func NewClientDo() *ClientDo {
 
 	clientDo.SignAddrFlag = ""
 	clientDo.NodeAddrFlag = ""
+	clientDo.KeysBackendFlag = ""
+	clientDo.KeyStoreDirFlag = ""
+	clientDo.KeyStorePasswordFlag = ""
+	clientDo.KeyTypeFlag = ""
+	clientDo.AddressSchemeFlag = ""
 	clientDo.PubkeyFlag = ""
 	clientDo.AddrFlag = ""
 	clientDo.ChainidFlag = ""
@@ -60,6 +99,8 @@ func NewClientDo() *ClientDo {
 	// clientDo.signFlag = false
 	clientDo.BroadcastFlag = false
 	clientDo.WaitFlag = false
+	clientDo.UnsignedFlag = false
+	clientDo.OutputFlag = ""
 
 	clientDo.AmtFlag = ""
 	clientDo.NonceFlag = ""
@@ -71,6 +112,17 @@ func NewClientDo() *ClientDo {
 	clientDo.GasFlag = ""
 	clientDo.UnbondtoFlag = ""
 	clientDo.HeightFlag = ""
+	clientDo.AbiFlag = ""
+	clientDo.FunctionFlag = ""
+	clientDo.BinFlag = ""
+	clientDo.LibrariesFlag = ""
+	clientDo.ResultsFileFlag = ""
+	clientDo.InputsFlag = ""
+	clientDo.OutputsFlag = ""
+	clientDo.FormatFlag = ""
+	clientDo.EventTypeFlag = ""
+	clientDo.EventAddressFlag = ""
+	clientDo.FromHeightFlag = ""
 
 	return clientDo
 }
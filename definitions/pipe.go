@@ -79,6 +79,8 @@ type Transactor interface {
 		fee int64) (*txs.EventDataCall, error)
 	Send(privKey, toAddress []byte, amount int64) (*txs.Receipt, error)
 	SendAndHold(privKey, toAddress []byte, amount int64) (*txs.Receipt, error)
+	SendMulti(privKeys [][]byte, amounts []int64,
+		outputs []*txs.TxOutput) (*txs.Receipt, error)
 	TransactNameReg(privKey []byte, name, data string, amount,
 		fee int64) (*txs.Receipt, error)
 	SignTx(tx txs.Tx, privAccounts []*account.PrivAccount) (txs.Tx, error)
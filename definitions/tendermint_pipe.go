@@ -62,6 +62,10 @@ type TendermintPipe interface {
 	GetName(name string) (*rpc_tm_types.ResultGetName, error)
 	ListNames() (*rpc_tm_types.ResultListNames, error)
 
+	// Governance proposals of permission changes
+	GetProposal(hash []byte) (*rpc_tm_types.ResultGetProposal, error)
+	ListProposals() (*rpc_tm_types.ResultListProposals, error)
+
 	// Memory pool
 	BroadcastTxAsync(transaction txs.Tx) (*rpc_tm_types.ResultBroadcastTx, error)
 	BroadcastTxSync(transaction txs.Tx) (*rpc_tm_types.ResultBroadcastTx, error)
@@ -0,0 +1,292 @@
+// Copyright 2017 Monax Industries Limited
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genesis
+
+import (
+	"fmt"
+	"time"
+
+	acm "github.com/hyperledger/burrow/account"
+	ptypes "github.com/hyperledger/burrow/permission/types"
+
+	"github.com/BurntSushi/toml"
+	"github.com/tendermint/go-crypto"
+)
+
+// A GenesisSpec declares a chain by groups of accounts and validators rather
+// than individual keys, which are generated when making the GenesisDoc. For
+// example:
+//
+//	chain_id = "consortium"
+//
+//	[permission_sets.developer]
+//	grant = ["root"]
+//
+//	[permission_sets.validator]
+//	grant = ["bond"]
+//	deny = ["create_contract"]
+//
+//	[[roles]]
+//	name = "auditors"
+//	permissions = "developer"
+//
+//	[[validators]]
+//	name = "validator"
+//	count = 3
+//	bond = 1000000
+//	permissions = "validator"
+//
+//	[[accounts]]
+//	name = "developer"
+//	count = 5
+//	amount = 1000000
+//	permissions = "developer"
+//	roles = ["auditors"]
+type GenesisSpec struct {
+	ChainID string `toml:"chain_id"`
+	// Name of the permission set granting the global permissions, which default
+	// to ptypes.DefaultAccountPermissions
+	GlobalPermissions string `toml:"global_permissions"`
+	// Named permission sets that account groups and roles refer to
+	PermissionSets map[string]PermissionSetSpec `toml:"permission_sets"`
+	Roles          []RoleSpec                   `toml:"roles"`
+	Validators     []ValidatorGroupSpec         `toml:"validators"`
+	Accounts       []AccountGroupSpec           `toml:"accounts"`
+	Governance     *GovernanceSpec              `toml:"governance"`
+}
+
+// The permissions a permission set grants and denies, by the names accepted by
+// ptypes.PermStringToFlag. Permissions it neither grants nor denies fall back
+// to the global permissions.
+type PermissionSetSpec struct {
+	Grant []string `toml:"grant"`
+	Deny  []string `toml:"deny"`
+}
+
+type RoleSpec struct {
+	Name        string   `toml:"name"`
+	Permissions string   `toml:"permissions"`
+	Inherits    []string `toml:"inherits"`
+	Admin       string   `toml:"admin"`
+}
+
+// Count accounts, named <chain id>_<name>_<index>, each with a key of its own
+type AccountGroupSpec struct {
+	Name        string   `toml:"name"`
+	Count       int      `toml:"count"`
+	Amount      int64    `toml:"amount"`
+	Permissions string   `toml:"permissions"`
+	Roles       []string `toml:"roles"`
+}
+
+// Count validators bonding Bond, each also a genesis account like those of an
+// AccountGroupSpec
+type ValidatorGroupSpec struct {
+	AccountGroupSpec
+	Bond int64 `toml:"bond"`
+}
+
+type GovernanceSpec struct {
+	Threshold      int   `toml:"threshold"`
+	ProposalExpiry int64 `toml:"proposal_expiry"`
+}
+
+// A key generated for an account of a GenesisSpec
+type GenesisKey struct {
+	Name        string
+	Validator   bool
+	PrivAccount *acm.PrivAccount
+}
+
+func LoadGenesisSpec(specPath string) (*GenesisSpec, error) {
+	spec := new(GenesisSpec)
+	if _, err := toml.DecodeFile(specPath, spec); err != nil {
+		return nil, fmt.Errorf("Could not read genesis spec %s: %v", specPath, err)
+	}
+	return spec, nil
+}
+
+// Get the base permissions of the named permission set, where the empty name
+// is the set that neither grants nor denies any permission
+func (spec *GenesisSpec) BasePermissions(name string) (ptypes.BasePermissions, error) {
+	base := ptypes.ZeroBasePermissions
+	if name == "" {
+		return base, nil
+	}
+	permissionSet, ok := spec.PermissionSets[name]
+	if !ok {
+		return base, fmt.Errorf("Unknown permission set %s", name)
+	}
+	for _, perms := range []struct {
+		names []string
+		value bool
+	}{{permissionSet.Grant, true}, {permissionSet.Deny, false}} {
+		for _, permS := range perms.names {
+			pFlag, err := ptypes.PermStringToFlag(permS)
+			if err != nil {
+				return base, fmt.Errorf("Permission set %s: %v", name, err)
+			}
+			base.Set(pFlag, perms.value)
+		}
+	}
+	return base, nil
+}
+
+// Make the GenesisDoc the spec declares, generating a key for each of its
+// accounts and validators
+func (spec *GenesisSpec) GenesisDoc(genesisTime time.Time) (*GenesisDoc, []*GenesisKey, error) {
+	if spec.ChainID == "" {
+		return nil, nil, fmt.Errorf("Genesis spec must set chain_id")
+	}
+	if len(spec.Validators) == 0 {
+		return nil, nil, fmt.Errorf("Genesis spec must declare validators")
+	}
+	genDoc := &GenesisDoc{
+		GenesisTime: genesisTime,
+		ChainID:     spec.ChainID,
+		Params:      &GenesisParams{},
+	}
+
+	globalPermissions := ptypes.DefaultAccountPermissions.Clone()
+	if spec.GlobalPermissions != "" {
+		base, err := spec.BasePermissions(spec.GlobalPermissions)
+		if err != nil {
+			return nil, nil, err
+		}
+		globalPermissions = ptypes.AccountPermissions{Base: base, Roles: []string{}}
+	}
+	genDoc.Params.GlobalPermissions = &globalPermissions
+
+	roles := make(map[string]bool)
+	for _, roleSpec := range spec.Roles {
+		if roleSpec.Name == "" {
+			return nil, nil, fmt.Errorf("Roles must have a name")
+		}
+		if roles[roleSpec.Name] {
+			return nil, nil, fmt.Errorf("Role %s is declared more than once", roleSpec.Name)
+		}
+		roles[roleSpec.Name] = true
+		base, err := spec.BasePermissions(roleSpec.Permissions)
+		if err != nil {
+			return nil, nil, fmt.Errorf("Role %s: %v", roleSpec.Name, err)
+		}
+		genDoc.Params.Roles = append(genDoc.Params.Roles, &ptypes.Role{
+			Name:     roleSpec.Name,
+			Base:     base,
+			Inherits: roleSpec.Inherits,
+			Admin:    roleSpec.Admin,
+		})
+	}
+	// Roles may refer to those declared after them
+	for _, roleSpec := range spec.Roles {
+		for _, inherited := range roleSpec.Inherits {
+			if !roles[inherited] {
+				return nil, nil, fmt.Errorf("Role %s inherits undeclared role %s",
+					roleSpec.Name, inherited)
+			}
+		}
+		if roleSpec.Admin != "" && !roles[roleSpec.Admin] {
+			return nil, nil, fmt.Errorf("Role %s has undeclared admin role %s",
+				roleSpec.Name, roleSpec.Admin)
+		}
+	}
+
+	if spec.Governance != nil {
+		genDoc.Params.Governance = &ptypes.GovernanceParams{
+			Threshold:      spec.Governance.Threshold,
+			ProposalExpiry: spec.Governance.ProposalExpiry,
+		}
+		if err := genDoc.Params.Governance.Validate(); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var keys []*GenesisKey
+	names := make(map[string]bool)
+	addGroup := func(group AccountGroupSpec, validator bool, bond int64) error {
+		if group.Name == "" {
+			return fmt.Errorf("Account groups must have a name")
+		}
+		if names[group.Name] {
+			return fmt.Errorf("Account group %s is declared more than once", group.Name)
+		}
+		names[group.Name] = true
+		if group.Count < 1 {
+			return fmt.Errorf("Account group %s must have a positive count", group.Name)
+		}
+		base, err := spec.BasePermissions(group.Permissions)
+		if err != nil {
+			return fmt.Errorf("Account group %s: %v", group.Name, err)
+		}
+		for _, role := range group.Roles {
+			if !roles[role] {
+				return fmt.Errorf("Account group %s has undeclared role %s", group.Name, role)
+			}
+		}
+		for i := 0; i < group.Count; i++ {
+			key := &GenesisKey{
+				Name:        fmt.Sprintf("%s_%s_%03d", spec.ChainID, group.Name, i),
+				Validator:   validator,
+				PrivAccount: acm.GenPrivAccount(),
+			}
+			keys = append(keys, key)
+			permissions := &ptypes.AccountPermissions{
+				Base:  base,
+				Roles: append([]string{}, group.Roles...),
+			}
+			genDoc.Accounts = append(genDoc.Accounts, *NewGenesisAccount(key.PrivAccount.Address,
+				group.Amount, key.Name, permissions))
+			if validator {
+				genDoc.Validators = append(genDoc.Validators, GenesisValidator{
+					PubKey: key.PrivAccount.PubKey,
+					Amount: bond,
+					Name:   key.Name,
+					UnbondTo: []BasicAccount{
+						{
+							Address: key.PrivAccount.Address,
+							Amount:  bond,
+						},
+					},
+				})
+			}
+		}
+		return nil
+	}
+	for _, group := range spec.Validators {
+		if group.Bond <= 0 {
+			return nil, nil, fmt.Errorf("Validator group %s must have a positive bond", group.Name)
+		}
+		if err := addGroup(group.AccountGroupSpec, true, group.Bond); err != nil {
+			return nil, nil, err
+		}
+	}
+	for _, group := range spec.Accounts {
+		if err := addGroup(group, false, 0); err != nil {
+			return nil, nil, err
+		}
+	}
+	return genDoc, keys, nil
+}
+
+// Make the priv_validator.json of a validator key generated for a GenesisSpec
+func NewGenesisPrivateValidator(privAccount *acm.PrivAccount) *GenesisPrivateValidator {
+	pubKey := privAccount.PubKey.(crypto.PubKeyEd25519)
+	privKey := privAccount.PrivKey.(crypto.PrivKeyEd25519)
+	return &GenesisPrivateValidator{
+		Address: fmt.Sprintf("%X", privAccount.Address),
+		PubKey:  []interface{}{crypto.TypeEd25519, fmt.Sprintf("%X", pubKey[:])},
+		PrivKey: []interface{}{crypto.TypeEd25519, fmt.Sprintf("%X", privKey[:])},
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright 2017 Monax Industries Limited
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genesis
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	ptypes "github.com/hyperledger/burrow/permission/types"
+)
+
+var genesisSpecTOML = `
+chain_id = "genesis-spec-test"
+
+[permission_sets.developer]
+grant = ["root"]
+
+[permission_sets.validator]
+grant = ["bond"]
+deny = ["create_contract"]
+
+[[roles]]
+name = "auditors"
+permissions = "developer"
+
+[[validators]]
+name = "validator"
+count = 3
+bond = 1000000
+permissions = "validator"
+
+[[accounts]]
+name = "developer"
+count = 5
+amount = 1000000
+permissions = "developer"
+roles = ["auditors"]
+
+[governance]
+threshold = 2
+proposal_expiry = 100
+`
+
+func TestGenesisSpec(t *testing.T) {
+	dir, err := ioutil.TempDir(os.TempDir(), "genesis-spec-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	specPath := filepath.Join(dir, "chain.toml")
+	if err := ioutil.WriteFile(specPath, []byte(genesisSpecTOML), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	spec, err := LoadGenesisSpec(specPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	genDoc, keys, err := spec.GenesisDoc(time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 8 || len(genDoc.Accounts) != 8 || len(genDoc.Validators) != 3 {
+		t.Fatalf("Expected 8 keys and accounts and 3 validators but got %v, %v and %v",
+			len(keys), len(genDoc.Accounts), len(genDoc.Validators))
+	}
+	validator := genDoc.Validators[0]
+	if validator.Name != "genesis-spec-test_validator_000" || validator.Amount != 1000000 {
+		t.Fatalf("Unexpected validator %v", validator)
+	}
+	developer := genDoc.Accounts[3]
+	if developer.Name != "genesis-spec-test_developer_000" || developer.Amount != 1000000 {
+		t.Fatalf("Unexpected account %v", developer)
+	}
+	if v, err := developer.Permissions.Base.Get(ptypes.Root); err != nil || !v {
+		t.Fatalf("Expected developer to be granted root")
+	}
+	if _, err := developer.Permissions.Base.Get(ptypes.Send); err == nil {
+		t.Fatalf("Expected developer to take send from the global permissions")
+	}
+	if !genDoc.Params.Governance.Enabled() {
+		t.Fatalf("Expected governance to be enabled")
+	}
+
+	// Keys are generated afresh each time
+	_, otherKeys, err := spec.GenesisDoc(time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(otherKeys[0].PrivAccount.Address) == string(keys[0].PrivAccount.Address) {
+		t.Fatalf("Expected new keys to be generated")
+	}
+
+	spec.Accounts[0].Roles = []string{"undeclared"}
+	if _, _, err := spec.GenesisDoc(time.Time{}); err == nil {
+		t.Fatalf("Expected error from undeclared role")
+	}
+	spec.Accounts[0].Roles = []string{"auditors"}
+
+	// Roles may inherit from and be administered by roles declared after them
+	spec.Roles = append(spec.Roles, RoleSpec{Name: "admins"})
+	spec.Roles[0].Inherits = []string{"admins"}
+	spec.Roles[0].Admin = "admins"
+	if _, _, err := spec.GenesisDoc(time.Time{}); err != nil {
+		t.Fatal(err)
+	}
+	spec.Roles[0].Inherits = []string{"undeclared"}
+	if _, _, err := spec.GenesisDoc(time.Time{}); err == nil {
+		t.Fatalf("Expected error from undeclared inherited role")
+	}
+	spec.Roles[0].Inherits = nil
+	spec.Roles[0].Admin = "undeclared"
+	if _, _, err := spec.GenesisDoc(time.Time{}); err == nil {
+		t.Fatalf("Expected error from undeclared admin role")
+	}
+}
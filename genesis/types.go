@@ -16,13 +16,12 @@ package genesis
 
 import (
 	"fmt"
-	"os"
 	"time"
 
 	ptypes "github.com/hyperledger/burrow/permission/types"
+	"github.com/hyperledger/burrow/txs"
 
 	"github.com/tendermint/go-crypto"
-	"github.com/tendermint/go-wire"
 )
 
 //------------------------------------------------------------
@@ -67,6 +66,34 @@ type GenesisPrivateValidator struct {
 
 type GenesisParams struct {
 	GlobalPermissions *ptypes.AccountPermissions `json:"global_permissions"`
+	// Roles held by accounts that grant them permissions, see ptypes.Role
+	Roles []*ptypes.Role `json:"roles,omitempty"`
+	// How account addresses are derived from public keys: tendermint (the
+	// default) or ethereum for Ethereum addresses of secp256k1 keys
+	AddressScheme string `json:"address_scheme,omitempty"`
+	// Pricing and data validation of the name registry, any unset fields take
+	// their values from txs.DefaultNameRegParams
+	NameReg *txs.GenesisNameRegParams `json:"name_reg,omitempty"`
+	// M-of-N approval of changes to global permissions, grants of Root and role
+	// admins, see ptypes.GovernanceParams
+	Governance *ptypes.GovernanceParams `json:"governance,omitempty"`
+}
+
+// Get the name registry params of the chain with defaults filled in
+func (genesisParams *GenesisParams) NameRegParams() txs.NameRegParams {
+	if genesisParams == nil {
+		return txs.DefaultNameRegParams
+	}
+	return genesisParams.NameReg.NameRegParams()
+}
+
+// Get the governance params of the chain, which are nil if governance is
+// disabled
+func (genesisParams *GenesisParams) GovernanceParams() *ptypes.GovernanceParams {
+	if genesisParams == nil || !genesisParams.Governance.Enabled() {
+		return nil
+	}
+	return genesisParams.Governance
 }
 
 //------------------------------------------------------------
@@ -80,20 +107,6 @@ type GenesisDoc struct {
 	Validators  []GenesisValidator `json:"validators"`
 }
 
-//------------------------------------------------------------
-// Make genesis state from file
-
-func GenesisDocFromJSON(jsonBlob []byte) (genState *GenesisDoc) {
-	var err error
-	wire.ReadJSONPtr(&genState, jsonBlob, &err)
-	if err != nil {
-		fmt.Printf("Couldn't read GenesisDoc: %v", err)
-		// TODO: on error return error, not exit
-		os.Exit(1)
-	}
-	return
-}
-
 //------------------------------------------------------------
 // Methods for genesis types
 // NOTE: breaks formatting convention
@@ -0,0 +1,290 @@
+// Copyright 2017 Monax Industries Limited
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genesis
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	acm "github.com/hyperledger/burrow/account"
+	ptypes "github.com/hyperledger/burrow/permission/types"
+
+	"github.com/tendermint/go-wire"
+)
+
+// A GenesisProblem is something wrong with a genesis document, located by the
+// JSON path of the offending value, such as accounts[2].amount. Problems with
+// the document as a whole have an empty path.
+type GenesisProblem struct {
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+func (problem GenesisProblem) String() string {
+	if problem.Path == "" {
+		return problem.Message
+	}
+	return fmt.Sprintf("%s: %s", problem.Path, problem.Message)
+}
+
+// GenesisProblems is the error returned for an invalid genesis document,
+// listing all that is wrong with it
+type GenesisProblems []GenesisProblem
+
+func (problems GenesisProblems) Error() string {
+	lines := make([]string, len(problems))
+	for i, problem := range problems {
+		lines[i] = problem.String()
+	}
+	return fmt.Sprintf("Invalid genesis document:\n\t%s", strings.Join(lines, "\n\t"))
+}
+
+func (problems *GenesisProblems) add(path, format string, args ...interface{}) {
+	*problems = append(*problems, GenesisProblem{
+		Path:    path,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+//------------------------------------------------------------
+// Read and validate genesis documents
+
+// GenesisDocFromJSON reads a genesis document, returning GenesisProblems
+// locating the error in jsonBlob if it cannot be read. The document read is
+// not validated, see Validate.
+func GenesisDocFromJSON(jsonBlob []byte) (*GenesisDoc, error) {
+	// go-wire does not say where JSON it cannot read is malformed
+	var value interface{}
+	if err := json.Unmarshal(jsonBlob, &value); err != nil {
+		if syntaxErr, ok := err.(*json.SyntaxError); ok {
+			line, column := lineAndColumn(jsonBlob, syntaxErr.Offset)
+			return nil, GenesisProblems{{Message: fmt.Sprintf(
+				"Malformed JSON at line %d, column %d: %v", line, column, err)}}
+		}
+		return nil, GenesisProblems{{Message: fmt.Sprintf("Malformed JSON: %v", err)}}
+	}
+	var genDoc *GenesisDoc
+	var err error
+	wire.ReadJSONPtr(&genDoc, jsonBlob, &err)
+	if err != nil {
+		return nil, GenesisProblems{{Message: fmt.Sprintf("Could not read genesis document: %v", err)}}
+	}
+	if genDoc == nil {
+		return nil, GenesisProblems{{Message: "Genesis document is null"}}
+	}
+	return genDoc, nil
+}
+
+// Validate checks the genesis document can start a chain, returning all the
+// problems found with it or nil if there are none
+func Validate(genDoc *GenesisDoc) GenesisProblems {
+	var problems GenesisProblems
+	if genDoc.ChainID == "" {
+		problems.add("chain_id", "Chain ID must be set")
+	}
+
+	accountIndices := make(map[string]int)
+	for i, genAcc := range genDoc.Accounts {
+		path := fmt.Sprintf("accounts[%d]", i)
+		validateAddress(&problems, path+".address", genAcc.Address)
+		if j, ok := accountIndices[string(genAcc.Address)]; ok {
+			problems.add(path+".address", "Address %X is also that of accounts[%d]",
+				genAcc.Address, j)
+		} else if len(genAcc.Address) > 0 {
+			accountIndices[string(genAcc.Address)] = i
+		}
+		if genAcc.Amount < 0 {
+			problems.add(path+".amount", "Amount must not be negative but is %v",
+				genAcc.Amount)
+		}
+		if genAcc.Permissions != nil {
+			validateAccountPermissions(&problems, path+".permissions", genAcc.Permissions)
+		}
+	}
+
+	if len(genDoc.Validators) == 0 {
+		problems.add("validators", "There must be at least one validator")
+	}
+	validatorIndices := make(map[string]int)
+	for i, genVal := range genDoc.Validators {
+		path := fmt.Sprintf("validators[%d]", i)
+		if genVal.PubKey == nil {
+			problems.add(path+".pub_key", "Public key must be set")
+		} else {
+			address := string(genVal.PubKey.Address())
+			if j, ok := validatorIndices[address]; ok {
+				problems.add(path+".pub_key", "Public key is also that of validators[%d]", j)
+			} else {
+				validatorIndices[address] = i
+			}
+		}
+		if genVal.Amount <= 0 {
+			problems.add(path+".amount", "Amount bonded must be positive but is %v",
+				genVal.Amount)
+		}
+		if len(genVal.UnbondTo) == 0 {
+			problems.add(path+".unbond_to", "There must be at least one account to "+
+				"unbond to")
+		}
+		for j, unbondTo := range genVal.UnbondTo {
+			unbondToPath := fmt.Sprintf("%s.unbond_to[%d]", path, j)
+			validateAddress(&problems, unbondToPath+".address", unbondTo.Address)
+			if unbondTo.Amount < 0 {
+				problems.add(unbondToPath+".amount", "Amount must not be negative but is %v",
+					unbondTo.Amount)
+			}
+		}
+	}
+
+	if genDoc.Params != nil {
+		validateParams(&problems, genDoc.Params)
+	}
+	return problems
+}
+
+func validateParams(problems *GenesisProblems, params *GenesisParams) {
+	if params.GlobalPermissions != nil {
+		validateAccountPermissions(problems, "params.global_permissions",
+			params.GlobalPermissions)
+	}
+	roleIndices := make(map[string]int)
+	for i, role := range params.Roles {
+		path := fmt.Sprintf("params.roles[%d]", i)
+		if role == nil {
+			problems.add(path, "Role must not be null")
+			continue
+		}
+		if role.Name == "" {
+			problems.add(path+".name", "Role must have a name")
+		} else if j, ok := roleIndices[ptypes.RoleName(role.Name)]; ok {
+			problems.add(path+".name", "Role %s is also defined by params.roles[%d]",
+				role.Name, j)
+		} else {
+			roleIndices[ptypes.RoleName(role.Name)] = i
+		}
+		validateBasePermissions(problems, path+".base", role.Base)
+	}
+	if err := acm.ValidateAddressScheme(params.AddressScheme); err != nil {
+		problems.add("params.address_scheme", "%v", err)
+	}
+	if params.NameReg != nil {
+		if err := params.NameReg.Validate(); err != nil {
+			problems.add("params.name_reg", "%v", err)
+		}
+	}
+	if err := params.Governance.Validate(); err != nil {
+		problems.add("params.governance", "%v", err)
+	}
+}
+
+func validateAccountPermissions(problems *GenesisProblems, path string,
+	accountPermissions *ptypes.AccountPermissions) {
+	validateBasePermissions(problems, path+".base", accountPermissions.Base)
+	for i, role := range accountPermissions.Roles {
+		if role == "" {
+			problems.add(fmt.Sprintf("%s.roles[%d]", path, i), "Role name must not be empty")
+		}
+	}
+	for i, expiry := range accountPermissions.BaseExpiries {
+		expiryPath := fmt.Sprintf("%s.base_expiries[%d]", path, i)
+		if expiry.Permission == 0 || expiry.Permission&^ptypes.AllPermFlags != 0 {
+			problems.add(expiryPath+".permission", "Invalid permission %b",
+				expiry.Permission)
+		}
+		if expiry.Height <= 0 {
+			problems.add(expiryPath+".height", "Height must be positive but is %v",
+				expiry.Height)
+		}
+	}
+	for i, expiry := range accountPermissions.RoleExpiries {
+		expiryPath := fmt.Sprintf("%s.role_expiries[%d]", path, i)
+		if expiry.Role == "" {
+			problems.add(expiryPath+".role", "Role name must not be empty")
+		}
+		if expiry.Height <= 0 {
+			problems.add(expiryPath+".height", "Height must be positive but is %v",
+				expiry.Height)
+		}
+	}
+}
+
+// Warnings finds what is likely a mistake in a genesis document that can
+// nonetheless start a chain, returning nil if there is nothing. Roles are
+// free-form tags an account may hold whether or not the params define them, so
+// holding an undefined role is only warned about.
+func Warnings(genDoc *GenesisDoc) GenesisProblems {
+	var warnings GenesisProblems
+	declaredRoles := make(map[string]bool)
+	if genDoc.Params != nil {
+		for _, role := range genDoc.Params.Roles {
+			if role != nil {
+				declaredRoles[ptypes.RoleName(role.Name)] = true
+			}
+		}
+	}
+	for i, genAcc := range genDoc.Accounts {
+		if genAcc.Permissions != nil {
+			validateAccountRoles(&warnings, fmt.Sprintf("accounts[%d].permissions", i),
+				genAcc.Permissions, declaredRoles)
+		}
+	}
+	return warnings
+}
+
+func validateAccountRoles(problems *GenesisProblems, path string,
+	accountPermissions *ptypes.AccountPermissions, declaredRoles map[string]bool) {
+	for i, role := range accountPermissions.Roles {
+		if role != "" && !declaredRoles[ptypes.RoleName(role)] {
+			problems.add(fmt.Sprintf("%s.roles[%d]", path, i),
+				"Role %s is not defined by params.roles", strings.TrimRight(role, "\x00"))
+		}
+	}
+	for i, expiry := range accountPermissions.RoleExpiries {
+		if expiry.Role != "" && !declaredRoles[ptypes.RoleName(expiry.Role)] {
+			problems.add(fmt.Sprintf("%s.role_expiries[%d].role", path, i),
+				"Role %s is not defined by params.roles",
+				strings.TrimRight(expiry.Role, "\x00"))
+		}
+	}
+}
+
+func validateBasePermissions(problems *GenesisProblems, path string,
+	base ptypes.BasePermissions) {
+	if invalid := base.Perms &^ ptypes.AllPermFlags; invalid != 0 {
+		problems.add(path+".perms", "Bits %b are not permissions", invalid)
+	}
+	if invalid := base.SetBit &^ ptypes.AllPermFlags; invalid != 0 {
+		problems.add(path+".set", "Bits %b are not permissions", invalid)
+	}
+}
+
+func validateAddress(problems *GenesisProblems, path string, address []byte) {
+	if len(address) != 20 {
+		problems.add(path, "Address must be 20 bytes but is %v", len(address))
+	}
+}
+
+// Get the 1-based line and column of offset in jsonBlob
+func lineAndColumn(jsonBlob []byte, offset int64) (int, int) {
+	if offset > int64(len(jsonBlob)) {
+		offset = int64(len(jsonBlob))
+	}
+	before := jsonBlob[:offset]
+	line := bytes.Count(before, []byte("\n")) + 1
+	column := len(before) - bytes.LastIndex(before, []byte("\n"))
+	return line, column
+}
@@ -0,0 +1,133 @@
+// Copyright 2017 Monax Industries Limited
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genesis
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	ptypes "github.com/hyperledger/burrow/permission/types"
+	"github.com/hyperledger/burrow/txs"
+)
+
+func TestValidate(t *testing.T) {
+	spec := &GenesisSpec{
+		ChainID: "validate-test",
+		Roles:   []RoleSpec{{Name: "auditors"}},
+		Validators: []ValidatorGroupSpec{
+			{AccountGroupSpec: AccountGroupSpec{Name: "validator", Count: 2}, Bond: 1000},
+		},
+		Accounts: []AccountGroupSpec{{Name: "user", Count: 3, Amount: 1000}},
+	}
+	genDoc, _, err := spec.GenesisDoc(time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if problems := Validate(genDoc); problems != nil {
+		t.Fatalf("Expected genesis to be valid but got %v", problems)
+	}
+	if warnings := Warnings(genDoc); warnings != nil {
+		t.Fatalf("Expected no warnings but got %v", warnings)
+	}
+
+	genesisBytes, err := GetGenesisFileBytes(genDoc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	loadedGenDoc, err := GenesisDocFromJSON(genesisBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if problems := Validate(loadedGenDoc); problems != nil {
+		t.Fatalf("Expected genesis read back to be valid but got %v", problems)
+	}
+
+	genDoc.ChainID = ""
+	genDoc.Accounts[3].Address = genDoc.Accounts[1].Address
+	genDoc.Accounts[4].Amount = -1
+	genDoc.Accounts[4].Permissions.Base.SetBit |= ptypes.TopPermFlag << 1
+	genDoc.Accounts[4].Permissions.AddRole("auditors")
+	genDoc.Accounts[4].Permissions.AddRole("undeclared")
+	genDoc.Validators[1].UnbondTo = nil
+	genDoc.Params.Roles = append(genDoc.Params.Roles, &ptypes.Role{Name: "auditors"})
+	genDoc.Params.AddressScheme = "bitcoin"
+	genDoc.Params.Governance = &ptypes.GovernanceParams{Threshold: -1}
+
+	problems := Validate(genDoc)
+	expectedPaths := []string{
+		"chain_id",
+		"accounts[3].address",
+		"accounts[4].amount",
+		"accounts[4].permissions.base.set",
+		"validators[1].unbond_to",
+		"params.roles[1].name",
+		"params.address_scheme",
+		"params.governance",
+	}
+	if len(problems) != len(expectedPaths) {
+		t.Fatalf("Expected %v problems but got %v", len(expectedPaths), problems)
+	}
+	for i, path := range expectedPaths {
+		if problems[i].Path != path {
+			t.Errorf("Expected problem %v to be at %s but got %v", i, path, problems[i])
+		}
+	}
+	if !strings.Contains(problems[1].Message, "accounts[1]") {
+		t.Errorf("Expected duplicate address to refer to accounts[1] but got %v", problems[1])
+	}
+	// holding a role the params do not define is allowed but warned about
+	warnings := Warnings(genDoc)
+	if len(warnings) != 1 || warnings[0].Path != "accounts[4].permissions.roles[1]" {
+		t.Fatalf("Expected only undefined role warning but got %v", warnings)
+	}
+
+	genDoc = &GenesisDoc{ChainID: "validate-test"}
+	problems = Validate(genDoc)
+	if len(problems) != 1 || problems[0].Path != "validators" {
+		t.Fatalf("Expected only missing validators problem but got %v", problems)
+	}
+}
+
+func TestGenesisDocFromJSON(t *testing.T) {
+	_, err := GenesisDocFromJSON([]byte("{\n\t\"chain_id\": \"test\",\n\t\"accounts\": [,]\n}"))
+	problems, ok := err.(GenesisProblems)
+	if !ok || len(problems) != 1 {
+		t.Fatalf("Expected a single problem but got %v", err)
+	}
+	if !strings.Contains(problems[0].Message, "line 3") {
+		t.Errorf("Expected problem to be located on line 3 but got %v", problems[0])
+	}
+
+	if _, err = GenesisDocFromJSON([]byte("null")); err == nil {
+		t.Fatal("Expected error reading null genesis")
+	}
+
+	// name registry params set to zero are kept and those unset take defaults
+	genDoc, err := GenesisDocFromJSON([]byte(`{"chain_id": "test", "params": ` +
+		`{"name_reg": {"min_registration_period": 0}}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	nameRegParams := genDoc.Params.NameRegParams()
+	if nameRegParams.MinRegistrationPeriod != 0 {
+		t.Errorf("Expected minimum registration period of 0 but got %v",
+			nameRegParams.MinRegistrationPeriod)
+	}
+	if nameRegParams.MaxDataLength != txs.DefaultNameRegParams.MaxDataLength {
+		t.Errorf("Expected default maximum data length but got %v",
+			nameRegParams.MaxDataLength)
+	}
+}
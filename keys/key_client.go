@@ -18,10 +18,21 @@ import (
 	"encoding/hex"
 	"fmt"
 
+	acm "github.com/hyperledger/burrow/account"
 	"github.com/hyperledger/burrow/logging"
 	logging_types "github.com/hyperledger/burrow/logging/types"
 )
 
+// Names of the signing backends burrow-client can be configured to use
+const (
+	// monax-keys daemon over http
+	MonaxKeysBackend = "monax-keys"
+	// Encrypted JSON keystore files in a local directory
+	KeyStoreBackend = "keystore"
+	// Remote signer over a Unix socket or TCP, see remote_signer.go
+	RemoteSignerBackend = "remote"
+)
+
 type KeyClient interface {
 	// Sign needs to return the signature bytes for given message to sign
 	// and the address to sign it with.
@@ -64,7 +75,20 @@ func (monaxKeys *monaxKeyClient) Sign(signBytesString string, signAddress []byte
 	if err != nil {
 		return
 	}
-	return sigBytes, err
+	// Check the daemon signed with the key we expect
+	publicKey, err := monaxKeys.PublicKey(signAddress)
+	if err != nil {
+		return nil, err
+	}
+	signBytes, err := hex.DecodeString(signBytesString)
+	if err != nil {
+		return nil, fmt.Errorf("Sign bytes string is invalid hex string: %s", err.Error())
+	}
+	err = VerifySignature(signAddress, publicKey, signBytes, sigBytes)
+	if err != nil {
+		return nil, err
+	}
+	return sigBytes, nil
 }
 
 // Monax-keys client PublicKey requests the public key associated with an address from
@@ -77,7 +101,85 @@ func (monaxKeys *monaxKeyClient) PublicKey(address []byte) (publicKey []byte, er
 	if err != nil {
 		return
 	}
-	// TODO: [ben] assert that received public key results in
-	// address
-	return hex.DecodeString(pubS)
+	publicKey, err = hex.DecodeString(pubS)
+	if err != nil {
+		return nil, err
+	}
+	err = VerifyPublicKey(address, publicKey)
+	if err != nil {
+		return nil, err
+	}
+	return publicKey, nil
+}
+
+//------------------------------------------------------------------------------
+// Verification of what a key server returns
+
+// The public key returned for an address is not a well-formed ed25519 or
+// secp256k1 key
+type MalformedPublicKeyError struct {
+	Address   []byte
+	PublicKey []byte
+}
+
+func (err *MalformedPublicKeyError) Error() string {
+	return fmt.Sprintf("Public key %X returned for address %X has length %v "+
+		"but ed25519 public keys have length %v and secp256k1 public keys "+
+		"have length %v", err.PublicKey, err.Address, len(err.PublicKey),
+		acm.PubKeyEd25519Length, acm.PubKeySecp256k1Length)
+}
+
+// The public key returned for an address does not derive that address
+type PublicKeyAddressMismatchError struct {
+	Address        []byte
+	PublicKey      []byte
+	DerivedAddress []byte
+}
+
+func (err *PublicKeyAddressMismatchError) Error() string {
+	return fmt.Sprintf("Public key %X returned for address %X belongs to "+
+		"address %X", err.PublicKey, err.Address, err.DerivedAddress)
+}
+
+// The signature returned for an address is not a valid signature of the sign
+// bytes by the address's public key
+type InvalidSignatureError struct {
+	Address   []byte
+	Signature []byte
+}
+
+func (err *InvalidSignatureError) Error() string {
+	return fmt.Sprintf("Signature %X returned for address %X does not verify "+
+		"against its public key", err.Signature, err.Address)
+}
+
+// VerifyPublicKey checks that publicKey is an ed25519 or secp256k1 public key
+// from which address is derived by either address scheme
+func VerifyPublicKey(address, publicKey []byte) error {
+	pubKey, err := acm.PubKeyFromBytes(publicKey)
+	if err != nil {
+		return &MalformedPublicKeyError{Address: address, PublicKey: publicKey}
+	}
+	if !acm.AddressMatchesPubKey(address, pubKey) {
+		return &PublicKeyAddressMismatchError{
+			Address:        address,
+			PublicKey:      publicKey,
+			DerivedAddress: pubKey.Address(),
+		}
+	}
+	return nil
+}
+
+// VerifySignature checks that signature is the signature of signBytes by
+// publicKey, which should already have been checked to belong to address
+func VerifySignature(address, publicKey, signBytes, signature []byte) error {
+	pubKey, err := acm.PubKeyFromBytes(publicKey)
+	if err != nil {
+		return &MalformedPublicKeyError{Address: address, PublicKey: publicKey}
+	}
+	sig, err := acm.SignatureFromBytes(pubKey, signature)
+	if err != nil || !pubKey.VerifyBytes(signBytes, sig) {
+		return &InvalidSignatureError{Address: address, Signature: signature}
+	}
+	return nil
 }
@@ -0,0 +1,138 @@
+// Copyright 2017 Monax Industries Limited
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keys
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hyperledger/burrow/logging/loggers"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// A fake monax-keys daemon serving keys from a MemoryKeyClient, optionally
+// tampering with its responses
+type fakeKeyServer struct {
+	keyClient *MemoryKeyClient
+	// Address whose public key to return in place of the requested one
+	swapPublicKeyFor []byte
+	// Return a truncated public key
+	truncatePublicKey bool
+	// Flip a bit of signatures
+	corruptSignature bool
+}
+
+func (fake *fakeKeyServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	args := make(map[string]string)
+	response := new(HTTPResponse)
+	err := json.NewDecoder(r.Body).Decode(&args)
+	if err == nil {
+		response.Response, err = fake.respond(r.URL.Path, args)
+	}
+	if err != nil {
+		response.Error = err.Error()
+	}
+	json.NewEncoder(w).Encode(response)
+}
+
+func (fake *fakeKeyServer) respond(path string, args map[string]string) (string, error) {
+	address, err := hex.DecodeString(args["addr"])
+	if err != nil {
+		return "", err
+	}
+	switch path {
+	case "/pub":
+		if fake.swapPublicKeyFor != nil {
+			address = fake.swapPublicKeyFor
+		}
+		publicKey, err := fake.keyClient.PublicKey(address)
+		if err != nil {
+			return "", err
+		}
+		if fake.truncatePublicKey {
+			publicKey = publicKey[:31]
+		}
+		return fmt.Sprintf("%X", publicKey), nil
+	case "/sign":
+		signature, err := fake.keyClient.Sign(args["msg"], address)
+		if err != nil {
+			return "", err
+		}
+		if fake.corruptSignature {
+			signature[0] ^= 1
+		}
+		return fmt.Sprintf("%X", signature), nil
+	}
+	return "", fmt.Errorf("Unknown method %s", path)
+}
+
+func newFakeKeyServer() (*fakeKeyServer, *httptest.Server, KeyClient) {
+	fake := &fakeKeyServer{keyClient: NewMemoryKeyClient()}
+	server := httptest.NewServer(fake)
+	return fake, server, NewBurrowKeyClient(server.URL,
+		loggers.NewNoopInfoTraceLogger())
+}
+
+func TestMonaxKeyClientPublicKey(t *testing.T) {
+	fake, server, keyClient := newFakeKeyServer()
+	defer server.Close()
+	address := fake.keyClient.NewKey()
+
+	publicKey, err := keyClient.PublicKey(address)
+	assert.NoError(t, err)
+	assert.NoError(t, VerifyPublicKey(address, publicKey))
+
+	// A key server handing back someone else's key
+	fake.swapPublicKeyFor = fake.keyClient.NewKey()
+	_, err = keyClient.PublicKey(address)
+	if assert.IsType(t, &PublicKeyAddressMismatchError{}, err) {
+		mismatch := err.(*PublicKeyAddressMismatchError)
+		assert.Equal(t, address, mismatch.Address)
+		assert.Equal(t, fake.swapPublicKeyFor, mismatch.DerivedAddress)
+	}
+
+	// A key server handing back something that is not a key
+	fake.swapPublicKeyFor = nil
+	fake.truncatePublicKey = true
+	_, err = keyClient.PublicKey(address)
+	assert.IsType(t, &MalformedPublicKeyError{}, err)
+}
+
+func TestMonaxKeyClientSign(t *testing.T) {
+	fake, server, keyClient := newFakeKeyServer()
+	defer server.Close()
+	address := fake.keyClient.NewKey()
+	message := hex.EncodeToString([]byte("sign me"))
+
+	signature, err := keyClient.Sign(message, address)
+	assert.NoError(t, err)
+	assert.Len(t, signature, 64)
+
+	fake.corruptSignature = true
+	_, err = keyClient.Sign(message, address)
+	assert.IsType(t, &InvalidSignatureError{}, err)
+
+	// A signature by the right key is no good if the key server lies about
+	// which key it is
+	fake.corruptSignature = false
+	fake.swapPublicKeyFor = fake.keyClient.NewKey()
+	_, err = keyClient.Sign(message, address)
+	assert.IsType(t, &PublicKeyAddressMismatchError{}, err)
+}
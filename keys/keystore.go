@@ -0,0 +1,445 @@
+// Copyright 2017 Monax Industries Limited
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keys
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	acm "github.com/hyperledger/burrow/account"
+	"github.com/hyperledger/burrow/logging"
+	logging_types "github.com/hyperledger/burrow/logging/types"
+	"github.com/hyperledger/burrow/manager/burrow-mint/evm/sha3"
+
+	"github.com/tendermint/ed25519"
+	"github.com/tendermint/go-crypto"
+	"golang.org/x/crypto/scrypt"
+)
+
+// Keys are stored one per file in the Web3 Secret Storage (version 3 JSON
+// keystore) format: the key is encrypted with AES-128-CTR under a key derived
+// from the password with scrypt, and authenticated with a Keccak-256 MAC.
+// The encrypted secret is the 32 byte ed25519 seed from which the signing key
+// is expanded, or the 32 byte secp256k1 private key. Files for secp256k1 keys
+// state their Ethereum address, as Ethereum wallets write them, and files
+// written by other tools need not state the key type.
+
+const (
+	keyStoreVersion = 3
+	keyStoreCipher  = "aes-128-ctr"
+	keyStoreKDF     = "scrypt"
+	scryptR         = 8
+	scryptDKLen     = 32
+
+	// Standard scrypt parameters, taking about a second of CPU to unlock a key
+	StandardScryptN = 1 << 18
+	StandardScryptP = 1
+	// Lighter parameters for keys protecting less value, and for tests
+	LightScryptN = 1 << 12
+	LightScryptP = 6
+)
+
+type EncryptedKeyJSON struct {
+	Address string     `json:"address"`
+	KeyType string     `json:"key_type,omitempty"`
+	Crypto  CryptoJSON `json:"crypto"`
+	Id      string     `json:"id"`
+	Version int        `json:"version"`
+}
+
+type CryptoJSON struct {
+	Cipher       string                 `json:"cipher"`
+	CipherText   string                 `json:"ciphertext"`
+	CipherParams CipherParamsJSON       `json:"cipherparams"`
+	KDF          string                 `json:"kdf"`
+	KDFParams    map[string]interface{} `json:"kdfparams"`
+	MAC          string                 `json:"mac"`
+}
+
+type CipherParamsJSON struct {
+	IV string `json:"iv"`
+}
+
+// Encrypt an ed25519 or secp256k1 private key with password into the JSON
+// keystore format, stating the address of the key under addressScheme, which
+// should be the address scheme of the chain the key is used on
+func EncryptKey(privateKey crypto.PrivKey, addressScheme, password string,
+	scryptN, scryptP int) (*EncryptedKeyJSON, error) {
+
+	var secret []byte
+	var keyType acm.KeyType
+	switch key := privateKey.(type) {
+	case crypto.PrivKeyEd25519:
+		secret, keyType = key[:32], acm.KeyTypeEd25519
+	case crypto.PrivKeySecp256k1:
+		secret, keyType = key[:], acm.KeyTypeSecp256k1
+	default:
+		return nil, fmt.Errorf("Unsupported private key type %T", privateKey)
+	}
+	address, err := acm.AddressFromPubKey(addressScheme, privateKey.PubKey())
+	if err != nil {
+		return nil, err
+	}
+	salt, err := randomBytes(32)
+	if err != nil {
+		return nil, err
+	}
+	derivedKey, err := scrypt.Key([]byte(password), salt, scryptN, scryptR,
+		scryptP, scryptDKLen)
+	if err != nil {
+		return nil, err
+	}
+	iv, err := randomBytes(aes.BlockSize)
+	if err != nil {
+		return nil, err
+	}
+	cipherText, err := aesCTRXOR(derivedKey[:16], secret, iv)
+	if err != nil {
+		return nil, err
+	}
+	id, err := randomBytes(16)
+	if err != nil {
+		return nil, err
+	}
+	return &EncryptedKeyJSON{
+		Address: fmt.Sprintf("%x", address),
+		KeyType: string(keyType),
+		Crypto: CryptoJSON{
+			Cipher:       keyStoreCipher,
+			CipherText:   hex.EncodeToString(cipherText),
+			CipherParams: CipherParamsJSON{IV: hex.EncodeToString(iv)},
+			KDF:          keyStoreKDF,
+			KDFParams: map[string]interface{}{
+				"n":     scryptN,
+				"r":     scryptR,
+				"p":     scryptP,
+				"dklen": scryptDKLen,
+				"salt":  hex.EncodeToString(salt),
+			},
+			MAC: hex.EncodeToString(keyStoreMAC(derivedKey, cipherText)),
+		},
+		Id:      uuidString(id),
+		Version: keyStoreVersion,
+	}, nil
+}
+
+// Decrypt an ed25519 or secp256k1 private key from the JSON keystore format,
+// checking the MAC and that the key matches the stated address
+func DecryptKey(encryptedKey *EncryptedKeyJSON, password string) (crypto.PrivKey, error) {
+	var privateKey crypto.PrivKey
+	cryptoJSON := encryptedKey.Crypto
+	if encryptedKey.Version != keyStoreVersion {
+		return privateKey, fmt.Errorf("Unsupported keystore version %v, "+
+			"expected %v", encryptedKey.Version, keyStoreVersion)
+	}
+	if cryptoJSON.Cipher != keyStoreCipher {
+		return privateKey, fmt.Errorf("Unsupported keystore cipher %s, "+
+			"expected %s", cryptoJSON.Cipher, keyStoreCipher)
+	}
+	if cryptoJSON.KDF != keyStoreKDF {
+		return privateKey, fmt.Errorf("Unsupported keystore key derivation "+
+			"function %s, expected %s", cryptoJSON.KDF, keyStoreKDF)
+	}
+	cipherText, err := hex.DecodeString(cryptoJSON.CipherText)
+	if err != nil {
+		return privateKey, err
+	}
+	iv, err := hex.DecodeString(cryptoJSON.CipherParams.IV)
+	if err != nil {
+		return privateKey, err
+	}
+	mac, err := hex.DecodeString(cryptoJSON.MAC)
+	if err != nil {
+		return privateKey, err
+	}
+	derivedKey, err := scryptKey(password, cryptoJSON.KDFParams)
+	if err != nil {
+		return privateKey, err
+	}
+	if !bytes.Equal(keyStoreMAC(derivedKey, cipherText), mac) {
+		return privateKey, fmt.Errorf("Could not decrypt key for address %s: "+
+			"wrong password or corrupted keystore file", encryptedKey.Address)
+	}
+	secret, err := aesCTRXOR(derivedKey[:16], cipherText, iv)
+	if err != nil {
+		return privateKey, err
+	}
+	address, err := hex.DecodeString(strings.TrimPrefix(encryptedKey.Address, "0x"))
+	if err != nil {
+		return privateKey, fmt.Errorf("Keystore file states invalid address %s: %s",
+			encryptedKey.Address, err)
+	}
+	keyTypes := []acm.KeyType{acm.KeyType(encryptedKey.KeyType)}
+	if encryptedKey.KeyType == "" {
+		keyTypes = []acm.KeyType{acm.KeyTypeEd25519, acm.KeyTypeSecp256k1}
+	}
+	for _, keyType := range keyTypes {
+		privateKey, err = privateKeyFromSecret(keyType, secret)
+		if err != nil {
+			if len(keyTypes) == 1 {
+				return nil, fmt.Errorf("Decrypted key for address %s: %s",
+					encryptedKey.Address, err)
+			}
+			continue
+		}
+		if acm.AddressMatchesPubKey(address, privateKey.PubKey()) {
+			return privateKey, nil
+		}
+	}
+	return nil, fmt.Errorf("Decrypted key does not have the address %s stated "+
+		"by the keystore file", encryptedKey.Address)
+}
+
+func privateKeyFromSecret(keyType acm.KeyType, secret []byte) (crypto.PrivKey, error) {
+	switch keyType {
+	case acm.KeyTypeEd25519:
+		// We accept either the 32 byte seed or the full 64 byte expanded key
+		if len(secret) != 32 && len(secret) != ed25519.PrivateKeySize {
+			return nil, fmt.Errorf("Key has length %v but ed25519 keys have "+
+				"length 32 or %v", len(secret), ed25519.PrivateKeySize)
+		}
+		privateKeyBytes := new([ed25519.PrivateKeySize]byte)
+		copy(privateKeyBytes[:32], secret[:32])
+		publicKeyBytes := ed25519.MakePublicKey(privateKeyBytes)
+		copy(privateKeyBytes[32:], publicKeyBytes[:])
+		return crypto.PrivKeyEd25519(*privateKeyBytes), nil
+	case acm.KeyTypeSecp256k1:
+		if len(secret) != acm.PrivKeySecp256k1Length {
+			return nil, fmt.Errorf("Key has length %v but secp256k1 keys have "+
+				"length %v", len(secret), acm.PrivKeySecp256k1Length)
+		}
+		var privateKey crypto.PrivKeySecp256k1
+		copy(privateKey[:], secret)
+		return privateKey, nil
+	}
+	return nil, fmt.Errorf("Unknown key type '%s'", keyType)
+}
+
+//------------------------------------------------------------------------------
+// Keystore directory client
+
+// NOTE Compiler check to ensure keyStoreKeyClient successfully implements
+// burrow/keys.KeyClient
+var _ KeyClient = (*keyStoreKeyClient)(nil)
+
+type keyStoreKeyClient struct {
+	directory string
+	password  string
+	scryptN   int
+	scryptP   int
+	logger    logging_types.InfoTraceLogger
+}
+
+// NewKeyStoreKeyClient returns a client that signs with keys held in JSON
+// keystore files in directory, all encrypted with the same password. Keys are
+// decrypted when needed and are not cached.
+func NewKeyStoreKeyClient(directory, password string,
+	logger logging_types.InfoTraceLogger) (*keyStoreKeyClient, error) {
+	info, err := os.Stat(directory)
+	if err != nil {
+		return nil, fmt.Errorf("Could not open keystore directory: %s", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("Keystore path %s is not a directory", directory)
+	}
+	return &keyStoreKeyClient{
+		directory: directory,
+		password:  password,
+		scryptN:   StandardScryptN,
+		scryptP:   StandardScryptP,
+		logger:    logging.WithScope(logger, "KeyStoreKeyClient"),
+	}, nil
+}
+
+// Generate a new ed25519 key, store it encrypted in the keystore directory,
+// and return its address
+func (keyStore *keyStoreKeyClient) Generate() (address []byte, err error) {
+	return keyStore.GenerateWithType(acm.KeyTypeEd25519, acm.AddressSchemeTendermint)
+}
+
+// GenerateWithType generates a new key of keyType, stores it encrypted in the
+// keystore directory, and returns its address under addressScheme as stated
+// in the file. The address of a secp256k1 key depends on the scheme, so it
+// should be that of the chain the key is used on.
+func (keyStore *keyStoreKeyClient) GenerateWithType(keyType acm.KeyType,
+	addressScheme string) (address []byte, err error) {
+	privateKey, err := acm.GenPrivKey(keyType)
+	if err != nil {
+		return nil, err
+	}
+	encryptedKey, err := EncryptKey(privateKey, addressScheme, keyStore.password,
+		keyStore.scryptN, keyStore.scryptP)
+	if err != nil {
+		return nil, err
+	}
+	bs, err := json.MarshalIndent(encryptedKey, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	fileName := fmt.Sprintf("UTC--%s--%s",
+		time.Now().UTC().Format("2006-01-02T15-04-05.000000000Z"),
+		encryptedKey.Address)
+	err = ioutil.WriteFile(filepath.Join(keyStore.directory, fileName), bs, 0600)
+	if err != nil {
+		return nil, err
+	}
+	address, err = hex.DecodeString(encryptedKey.Address)
+	if err != nil {
+		return nil, err
+	}
+	logging.InfoMsg(keyStore.logger, "Generated key",
+		"key type", keyType,
+		"address", fmt.Sprintf("%X", address),
+		"file", fileName)
+	return address, nil
+}
+
+func (keyStore *keyStoreKeyClient) Sign(signBytesString string, signAddress []byte) (signature []byte, err error) {
+	privateKey, err := keyStore.privateKey(signAddress)
+	if err != nil {
+		return nil, err
+	}
+	signBytes, err := hex.DecodeString(signBytesString)
+	if err != nil {
+		return nil, fmt.Errorf("Sign bytes string is invalid hex string: %s", err.Error())
+	}
+	return acm.SignatureBytes(privateKey.Sign(signBytes)), nil
+}
+
+func (keyStore *keyStoreKeyClient) PublicKey(address []byte) (publicKey []byte, err error) {
+	privateKey, err := keyStore.privateKey(address)
+	if err != nil {
+		return nil, err
+	}
+	return acm.PubKeyBytes(privateKey.PubKey()), nil
+}
+
+func (keyStore *keyStoreKeyClient) privateKey(address []byte) (crypto.PrivKey, error) {
+	encryptedKey, err := keyStore.find(address)
+	if err != nil {
+		return nil, err
+	}
+	return DecryptKey(encryptedKey, keyStore.password)
+}
+
+// Find the keystore file for address. We read every file rather than relying
+// on file names so that files written by other tools are found.
+func (keyStore *keyStoreKeyClient) find(address []byte) (*EncryptedKeyJSON, error) {
+	files, err := ioutil.ReadDir(keyStore.directory)
+	if err != nil {
+		return nil, err
+	}
+	hexAddress := fmt.Sprintf("%x", address)
+	for _, file := range files {
+		if file.IsDir() || strings.HasPrefix(file.Name(), ".") {
+			continue
+		}
+		bs, err := ioutil.ReadFile(filepath.Join(keyStore.directory, file.Name()))
+		if err != nil {
+			return nil, err
+		}
+		encryptedKey := new(EncryptedKeyJSON)
+		if err := json.Unmarshal(bs, encryptedKey); err != nil {
+			logging.TraceMsg(keyStore.logger, "Skipping file that is not a "+
+				"JSON keystore file",
+				"file", file.Name(),
+				"error", err)
+			continue
+		}
+		if strings.EqualFold(strings.TrimPrefix(encryptedKey.Address, "0x"),
+			hexAddress) {
+			return encryptedKey, nil
+		}
+	}
+	return nil, fmt.Errorf("No key for address %X in keystore %s", address,
+		keyStore.directory)
+}
+
+//------------------------------------------------------------------------------
+// Helpers
+
+func scryptKey(password string, kdfParams map[string]interface{}) ([]byte, error) {
+	// JSON numbers decode as float64
+	intParam := func(name string) (int, error) {
+		value, ok := kdfParams[name].(float64)
+		if !ok {
+			return 0, fmt.Errorf("Missing or invalid scrypt parameter %s", name)
+		}
+		return int(value), nil
+	}
+	params := make(map[string]int)
+	for _, name := range []string{"n", "r", "p", "dklen"} {
+		value, err := intParam(name)
+		if err != nil {
+			return nil, err
+		}
+		params[name] = value
+	}
+	saltString, ok := kdfParams["salt"].(string)
+	if !ok {
+		return nil, fmt.Errorf("Missing or invalid scrypt parameter salt")
+	}
+	salt, err := hex.DecodeString(saltString)
+	if err != nil {
+		return nil, err
+	}
+	if params["dklen"] != scryptDKLen {
+		return nil, fmt.Errorf("Unsupported scrypt derived key length %v, "+
+			"expected %v", params["dklen"], scryptDKLen)
+	}
+	return scrypt.Key([]byte(password), salt, params["n"], params["r"],
+		params["p"], params["dklen"])
+}
+
+func keyStoreMAC(derivedKey, cipherText []byte) []byte {
+	return sha3.Sha3(derivedKey[16:32], cipherText)
+}
+
+func aesCTRXOR(key, input, iv []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	output := make([]byte, len(input))
+	cipher.NewCTR(block, iv).XORKeyStream(output, input)
+	return output, nil
+}
+
+func randomBytes(n int) ([]byte, error) {
+	bs := make([]byte, n)
+	_, err := rand.Read(bs)
+	if err != nil {
+		return nil, err
+	}
+	return bs, nil
+}
+
+// Format 16 random bytes as a version 4 UUID
+func uuidString(bs []byte) string {
+	bs[6] = (bs[6] & 0x0f) | 0x40
+	bs[8] = (bs[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", bs[0:4], bs[4:6], bs[6:8], bs[8:10],
+		bs[10:16])
+}
@@ -0,0 +1,155 @@
+// Copyright 2017 Monax Industries Limited
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keys
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	acm "github.com/hyperledger/burrow/account"
+	"github.com/hyperledger/burrow/logging/loggers"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tendermint/go-crypto"
+)
+
+func TestEncryptDecryptKey(t *testing.T) {
+	privateKey := crypto.GenPrivKeyEd25519()
+	encryptedKey, err := EncryptKey(privateKey, acm.AddressSchemeTendermint, "foo",
+		LightScryptN, LightScryptP)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, encryptedKey.Version)
+	assert.Equal(t, "aes-128-ctr", encryptedKey.Crypto.Cipher)
+	assert.Equal(t, "scrypt", encryptedKey.Crypto.KDF)
+
+	// Round trip through JSON as a keystore file would
+	bs, err := json.Marshal(encryptedKey)
+	assert.NoError(t, err)
+	decoded := new(EncryptedKeyJSON)
+	assert.NoError(t, json.Unmarshal(bs, decoded))
+
+	decrypted, err := DecryptKey(decoded, "foo")
+	assert.NoError(t, err)
+	assert.Equal(t, privateKey, decrypted)
+
+	_, err = DecryptKey(decoded, "bar")
+	assert.Error(t, err)
+}
+
+func TestKeyStoreKeyClient(t *testing.T) {
+	dir, err := ioutil.TempDir("", "burrow-keystore")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	keyStore, err := NewKeyStoreKeyClient(dir, "foo",
+		loggers.NewNoopInfoTraceLogger())
+	assert.NoError(t, err)
+	keyStore.scryptN, keyStore.scryptP = LightScryptN, LightScryptP
+	// Something that is not a keystore file should be skipped
+	assert.NoError(t, ioutil.WriteFile(dir+"/README", []byte("not json"), 0600))
+
+	address, err := keyStore.Generate()
+	assert.NoError(t, err)
+	publicKey, err := keyStore.PublicKey(address)
+	assert.NoError(t, err)
+	var pubKey crypto.PubKeyEd25519
+	copy(pubKey[:], publicKey)
+	assert.Equal(t, address, pubKey.Address())
+
+	message := []byte("sign me")
+	signature, err := keyStore.Sign(hex.EncodeToString(message), address)
+	assert.NoError(t, err)
+	var sig crypto.SignatureEd25519
+	copy(sig[:], signature)
+	assert.True(t, pubKey.VerifyBytes(message, sig))
+
+	_, err = keyStore.PublicKey([]byte{1, 2, 3})
+	assert.Error(t, err)
+
+	wrongPassword, err := NewKeyStoreKeyClient(dir, "bar",
+		loggers.NewNoopInfoTraceLogger())
+	assert.NoError(t, err)
+	_, err = wrongPassword.PublicKey(address)
+	assert.Error(t, err)
+}
+
+func TestEncryptDecryptSecp256k1Key(t *testing.T) {
+	privateKey := crypto.GenPrivKeySecp256k1()
+	encryptedKey, err := EncryptKey(privateKey, acm.AddressSchemeEthereum, "foo",
+		LightScryptN, LightScryptP)
+	assert.NoError(t, err)
+	assert.Equal(t, string(acm.KeyTypeSecp256k1), encryptedKey.KeyType)
+	address, err := acm.AddressFromPubKey(acm.AddressSchemeEthereum,
+		privateKey.PubKey())
+	assert.NoError(t, err)
+	assert.Equal(t, hex.EncodeToString(address), encryptedKey.Address)
+
+	decrypted, err := DecryptKey(encryptedKey, "foo")
+	assert.NoError(t, err)
+	assert.Equal(t, privateKey, decrypted)
+
+	// Files written by Ethereum wallets do not state the key type
+	encryptedKey.KeyType = ""
+	decrypted, err = DecryptKey(encryptedKey, "foo")
+	assert.NoError(t, err)
+	assert.Equal(t, privateKey, decrypted)
+
+	// On chains with the tendermint address scheme the key has its ripemd160
+	// address
+	encryptedKey, err = EncryptKey(privateKey, acm.AddressSchemeTendermint, "foo",
+		LightScryptN, LightScryptP)
+	assert.NoError(t, err)
+	assert.Equal(t, hex.EncodeToString(privateKey.PubKey().Address()),
+		encryptedKey.Address)
+	decrypted, err = DecryptKey(encryptedKey, "foo")
+	assert.NoError(t, err)
+	assert.Equal(t, privateKey, decrypted)
+
+	_, err = EncryptKey(privateKey, "bitcoin", "foo", LightScryptN, LightScryptP)
+	assert.Error(t, err)
+}
+
+func TestKeyStoreKeyClientSecp256k1(t *testing.T) {
+	dir, err := ioutil.TempDir("", "burrow-keystore")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	keyStore, err := NewKeyStoreKeyClient(dir, "foo",
+		loggers.NewNoopInfoTraceLogger())
+	assert.NoError(t, err)
+	keyStore.scryptN, keyStore.scryptP = LightScryptN, LightScryptP
+
+	for _, scheme := range []string{acm.AddressSchemeTendermint, acm.AddressSchemeEthereum} {
+		address, err := keyStore.GenerateWithType(acm.KeyTypeSecp256k1, scheme)
+		assert.NoError(t, err)
+		publicKey, err := keyStore.PublicKey(address)
+		assert.NoError(t, err)
+		assert.Len(t, publicKey, acm.PubKeySecp256k1Length)
+		assert.NoError(t, VerifyPublicKey(address, publicKey))
+		pubKey, err := acm.PubKeyFromBytes(publicKey)
+		assert.NoError(t, err)
+		schemeAddress, err := acm.AddressFromPubKey(scheme, pubKey)
+		assert.NoError(t, err)
+		assert.Equal(t, schemeAddress, address)
+
+		message := []byte("sign me")
+		signature, err := keyStore.Sign(hex.EncodeToString(message), address)
+		assert.NoError(t, err)
+		assert.NoError(t, VerifySignature(address, publicKey, message, signature))
+	}
+}
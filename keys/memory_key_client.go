@@ -0,0 +1,98 @@
+// Copyright 2017 Monax Industries Limited
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keys
+
+import (
+	"encoding/hex"
+	"fmt"
+	"sync"
+
+	acm "github.com/hyperledger/burrow/account"
+
+	"github.com/tendermint/go-crypto"
+)
+
+// Implementation assertion
+var _ KeyClient = (*MemoryKeyClient)(nil)
+
+// MemoryKeyClient holds ed25519 and secp256k1 keys in memory and signs with
+// them directly. It is intended for tests and for serving throwaway keys, keys
+// are lost when the process exits.
+type MemoryKeyClient struct {
+	mtx       sync.RWMutex
+	knownKeys map[string]crypto.PrivKey
+}
+
+func NewMemoryKeyClient() *MemoryKeyClient {
+	return &MemoryKeyClient{
+		knownKeys: make(map[string]crypto.PrivKey),
+	}
+}
+
+// Generate a new ed25519 key and return its ripemd160 address
+func (mem *MemoryKeyClient) NewKey() (address []byte) {
+	return mem.AddKey(crypto.GenPrivKeyEd25519())
+}
+
+// Generate a new key of keyType and return its ripemd160 address
+func (mem *MemoryKeyClient) NewKeyWithType(keyType acm.KeyType) (address []byte, err error) {
+	privateKey, err := acm.GenPrivKey(keyType)
+	if err != nil {
+		return nil, err
+	}
+	return mem.AddKey(privateKey), nil
+}
+
+// Add an existing private key and return its ripemd160 address. The key can
+// also be used by its Ethereum address.
+func (mem *MemoryKeyClient) AddKey(privateKey crypto.PrivKey) (address []byte) {
+	pubKey := privateKey.PubKey()
+	ethereumAddress, _ := acm.AddressFromPubKey(acm.AddressSchemeEthereum, pubKey)
+	mem.mtx.Lock()
+	defer mem.mtx.Unlock()
+	mem.knownKeys[fmt.Sprintf("%X", ethereumAddress)] = privateKey
+	mem.knownKeys[fmt.Sprintf("%X", pubKey.Address())] = privateKey
+	return pubKey.Address()
+}
+
+func (mem *MemoryKeyClient) Sign(signBytesString string, signAddress []byte) ([]byte, error) {
+	privateKey, err := mem.privateKey(signAddress)
+	if err != nil {
+		return nil, err
+	}
+	signBytes, err := hex.DecodeString(signBytesString)
+	if err != nil {
+		return nil, fmt.Errorf("Sign bytes string is invalid hex string: %s", err.Error())
+	}
+	return acm.SignatureBytes(privateKey.Sign(signBytes)), nil
+}
+
+func (mem *MemoryKeyClient) PublicKey(address []byte) (publicKey []byte, err error) {
+	privateKey, err := mem.privateKey(address)
+	if err != nil {
+		return nil, err
+	}
+	return acm.PubKeyBytes(privateKey.PubKey()), nil
+}
+
+func (mem *MemoryKeyClient) privateKey(address []byte) (crypto.PrivKey, error) {
+	mem.mtx.RLock()
+	defer mem.mtx.RUnlock()
+	privateKey, ok := mem.knownKeys[fmt.Sprintf("%X", address)]
+	if !ok {
+		return nil, fmt.Errorf("Unknown address (%X)", address)
+	}
+	return privateKey, nil
+}
@@ -0,0 +1,249 @@
+// Copyright 2017 Monax Industries Limited
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keys
+
+import (
+	"bufio"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/hyperledger/burrow/logging"
+	logging_types "github.com/hyperledger/burrow/logging/types"
+)
+
+// The remote signer protocol lets a signing service such as an HSM bridge hold
+// keys outside of burrow-client. The client connects over a Unix socket or TCP
+// and writes a single request as a line of JSON, the signer replies with a
+// single line of JSON and the connection is closed. All byte strings are hex
+// encoded.
+//
+//   -> {"method": "public_key", "address": "<address>"}
+//   <- {"public_key": "<32 byte ed25519 or 64 byte secp256k1 public key>"}
+//
+//   -> {"method": "sign", "address": "<address>", "message": "<sign bytes>"}
+//   <- {"signature": "<64 byte ed25519 or DER encoded secp256k1 signature>"}
+//
+// Secp256k1 public keys are uncompressed without the leading 0x04 byte and
+// secp256k1 signatures are over the SHA256 hash of the sign bytes. On failure the signer replies with {"error": "<message>"}.
+
+const (
+	RemoteSignerMethodPublicKey = "public_key"
+	RemoteSignerMethodSign      = "sign"
+
+	remoteSignerTimeout = 30 * time.Second
+)
+
+type RemoteSignerRequest struct {
+	Method  string `json:"method"`
+	Address string `json:"address"`
+	Message string `json:"message,omitempty"`
+}
+
+type RemoteSignerResponse struct {
+	PublicKey string `json:"public_key,omitempty"`
+	Signature string `json:"signature,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// NOTE Compiler check to ensure remoteSignerKeyClient successfully implements
+// burrow/keys.KeyClient
+var _ KeyClient = (*remoteSignerKeyClient)(nil)
+
+type remoteSignerKeyClient struct {
+	network string
+	address string
+	logger  logging_types.InfoTraceLogger
+}
+
+// NewRemoteSignerKeyClient returns a client for a remote signer listening on
+// signerAddress, which must be of the form unix:///path/to/socket or
+// tcp://host:port
+func NewRemoteSignerKeyClient(signerAddress string,
+	logger logging_types.InfoTraceLogger) (*remoteSignerKeyClient, error) {
+	network, address, err := splitSignerAddress(signerAddress)
+	if err != nil {
+		return nil, err
+	}
+	return &remoteSignerKeyClient{
+		network: network,
+		address: address,
+		logger:  logging.WithScope(logger, "RemoteSignerKeyClient"),
+	}, nil
+}
+
+func (remoteSigner *remoteSignerKeyClient) Sign(signBytesString string, signAddress []byte) (signature []byte, err error) {
+	response, err := remoteSigner.request(&RemoteSignerRequest{
+		Method:  RemoteSignerMethodSign,
+		Address: fmt.Sprintf("%X", signAddress),
+		Message: signBytesString,
+	})
+	if err != nil {
+		return nil, err
+	}
+	signature, err = hex.DecodeString(response.Signature)
+	if err != nil {
+		return nil, err
+	}
+	// Check the signer signed with the key we expect
+	publicKey, err := remoteSigner.PublicKey(signAddress)
+	if err != nil {
+		return nil, err
+	}
+	signBytes, err := hex.DecodeString(signBytesString)
+	if err != nil {
+		return nil, fmt.Errorf("Sign bytes string is invalid hex string: %s", err.Error())
+	}
+	err = VerifySignature(signAddress, publicKey, signBytes, signature)
+	if err != nil {
+		return nil, err
+	}
+	return signature, nil
+}
+
+func (remoteSigner *remoteSignerKeyClient) PublicKey(address []byte) (publicKey []byte, err error) {
+	response, err := remoteSigner.request(&RemoteSignerRequest{
+		Method:  RemoteSignerMethodPublicKey,
+		Address: fmt.Sprintf("%X", address),
+	})
+	if err != nil {
+		return nil, err
+	}
+	publicKey, err = hex.DecodeString(response.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+	err = VerifyPublicKey(address, publicKey)
+	if err != nil {
+		return nil, err
+	}
+	return publicKey, nil
+}
+
+func (remoteSigner *remoteSignerKeyClient) request(request *RemoteSignerRequest) (*RemoteSignerResponse, error) {
+	endpoint := remoteSigner.network + "://" + remoteSigner.address
+	conn, err := net.DialTimeout(remoteSigner.network, remoteSigner.address,
+		remoteSignerTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("Error connecting to remote signer at %s: %s",
+			endpoint, err)
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(remoteSignerTimeout))
+
+	logging.TraceMsg(remoteSigner.logger, "Sending request to remote signer",
+		"endpoint", endpoint,
+		"method", request.Method,
+		"address", request.Address)
+	err = json.NewEncoder(conn).Encode(request)
+	if err != nil {
+		return nil, fmt.Errorf("Error sending request to remote signer at %s: %s",
+			endpoint, err)
+	}
+	response := new(RemoteSignerResponse)
+	err = readJSONLine(conn, response)
+	if err != nil {
+		return nil, fmt.Errorf("Error reading response from remote signer at %s: %s",
+			endpoint, err)
+	}
+	if response.Error != "" {
+		return nil, fmt.Errorf("Error (string) from remote signer at %s: %s",
+			endpoint, response.Error)
+	}
+	return response, nil
+}
+
+// ServeRemoteSigner answers remote signer requests on listener using keyClient
+// until the listener is closed. It serves as a reference implementation of the
+// protocol and allows any KeyClient to be exposed to burrow-client.
+func ServeRemoteSigner(listener net.Listener, keyClient KeyClient,
+	logger logging_types.InfoTraceLogger) error {
+	logger = logging.WithScope(logger, "RemoteSigner")
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			return err
+		}
+		go func() {
+			defer conn.Close()
+			conn.SetDeadline(time.Now().Add(remoteSignerTimeout))
+			request := new(RemoteSignerRequest)
+			response := new(RemoteSignerResponse)
+			err := readJSONLine(conn, request)
+			if err == nil {
+				err = handleRemoteSignerRequest(keyClient, request, response)
+			}
+			if err != nil {
+				response = &RemoteSignerResponse{Error: err.Error()}
+				logging.InfoMsg(logger, "Could not serve remote signer request",
+					"method", request.Method,
+					"address", request.Address,
+					"error", err)
+			}
+			json.NewEncoder(conn).Encode(response)
+		}()
+	}
+}
+
+func handleRemoteSignerRequest(keyClient KeyClient, request *RemoteSignerRequest,
+	response *RemoteSignerResponse) error {
+	address, err := hex.DecodeString(request.Address)
+	if err != nil {
+		return fmt.Errorf("Address is invalid hex string: %s", err)
+	}
+	switch request.Method {
+	case RemoteSignerMethodPublicKey:
+		publicKey, err := keyClient.PublicKey(address)
+		if err != nil {
+			return err
+		}
+		response.PublicKey = fmt.Sprintf("%X", publicKey)
+	case RemoteSignerMethodSign:
+		signature, err := keyClient.Sign(request.Message, address)
+		if err != nil {
+			return err
+		}
+		response.Signature = fmt.Sprintf("%X", signature)
+	default:
+		return fmt.Errorf("Unknown remote signer method '%s'", request.Method)
+	}
+	return nil
+}
+
+func readJSONLine(conn net.Conn, v interface{}) error {
+	line, err := bufio.NewReader(conn).ReadBytes('\n')
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(line, v)
+}
+
+func splitSignerAddress(signerAddress string) (network, address string, err error) {
+	parts := strings.SplitN(signerAddress, "://", 2)
+	if len(parts) != 2 || parts[1] == "" {
+		return "", "", fmt.Errorf("Remote signer address '%s' should be of the "+
+			"form unix:///path/to/socket or tcp://host:port", signerAddress)
+	}
+	switch parts[0] {
+	case "unix", "tcp":
+		return parts[0], parts[1], nil
+	default:
+		return "", "", fmt.Errorf("Remote signer address '%s' has unsupported "+
+			"scheme '%s', use unix:// or tcp://", signerAddress, parts[0])
+	}
+}
@@ -0,0 +1,84 @@
+// Copyright 2017 Monax Industries Limited
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keys
+
+import (
+	"encoding/hex"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hyperledger/burrow/logging/loggers"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRemoteSigner(t *testing.T) {
+	dir, err := ioutil.TempDir("", "burrow-remote-signer")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	socket := filepath.Join(dir, "signer.sock")
+
+	for _, address := range []string{"unix://" + socket, "tcp://127.0.0.1:0"} {
+		network, listenAddress, err := splitSignerAddress(address)
+		assert.NoError(t, err)
+		listener, err := net.Listen(network, listenAddress)
+		if !assert.NoError(t, err) {
+			continue
+		}
+		memoryKeyClient := NewMemoryKeyClient()
+		go ServeRemoteSigner(listener, memoryKeyClient,
+			loggers.NewNoopInfoTraceLogger())
+
+		remoteSigner, err := NewRemoteSignerKeyClient(
+			network+"://"+listener.Addr().String(), loggers.NewNoopInfoTraceLogger())
+		assert.NoError(t, err)
+
+		keyAddress := memoryKeyClient.NewKey()
+		expectedPublicKey, err := memoryKeyClient.PublicKey(keyAddress)
+		assert.NoError(t, err)
+		publicKey, err := remoteSigner.PublicKey(keyAddress)
+		assert.NoError(t, err)
+		assert.Equal(t, expectedPublicKey, publicKey)
+
+		message := hex.EncodeToString([]byte("sign me"))
+		expectedSignature, err := memoryKeyClient.Sign(message, keyAddress)
+		assert.NoError(t, err)
+		signature, err := remoteSigner.Sign(message, keyAddress)
+		assert.NoError(t, err)
+		// ed25519 signatures are deterministic
+		assert.Equal(t, expectedSignature, signature)
+
+		// Errors from the signer are passed back
+		_, err = remoteSigner.PublicKey([]byte{1, 2, 3})
+		assert.Error(t, err)
+
+		listener.Close()
+	}
+}
+
+func TestSplitSignerAddress(t *testing.T) {
+	network, address, err := splitSignerAddress("unix:///tmp/signer.sock")
+	assert.NoError(t, err)
+	assert.Equal(t, "unix", network)
+	assert.Equal(t, "/tmp/signer.sock", address)
+
+	_, _, err = splitSignerAddress("http://127.0.0.1:4767")
+	assert.Error(t, err)
+	_, _, err = splitSignerAddress("127.0.0.1:4767")
+	assert.Error(t, err)
+}
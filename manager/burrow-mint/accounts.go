@@ -68,8 +68,10 @@ func (this *accounts) GenPrivAccount() (*account.PrivAccount, error) {
 // Generate a new Private Key Account.
 func (this *accounts) GenPrivAccountFromKey(privKey []byte) (
 	*account.PrivAccount, error) {
-	if len(privKey) != 64 {
-		return nil, fmt.Errorf("Private key is not 64 bytes long.")
+	if len(privKey) != account.PrivKeyEd25519Length &&
+		len(privKey) != account.PrivKeySecp256k1Length {
+		return nil, fmt.Errorf("Private key is not 64 bytes (ed25519) or 32 bytes " +
+			"(secp256k1) long.")
 	}
 	fmt.Printf("PK BYTES FROM ACCOUNTS: %x\n", privKey)
 	pa := account.GenPrivAccountFromPrivKeyBytes(privKey)
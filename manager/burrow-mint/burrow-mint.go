@@ -209,6 +209,16 @@ func (app *BurrowMint) BeginBlock(hash []byte, header *abci.Header) {
 // Signals the end of a blockchain, return value can be used to modify validator
 // set and voting power distribution see our BlockchainAware interface
 func (app *BurrowMint) EndBlock(height uint64) (respEndblock abci.ResponseEndBlock) {
+	// Clear out a bounded number of expired names each block so that the name
+	// registry does not grow without bound, when the chain's name_reg params
+	// enable it with an expired_removal_limit
+	removed := sm.RemoveExpiredNameRegEntries(app.cache,
+		app.state.NameRegParams.ExpiredRemovalLimit, app.evc, app.logger)
+	if removed > 0 {
+		logging.InfoMsg(app.logger, "Removed expired NameReg entries",
+			"height", height,
+			"removed", removed)
+	}
 	// TODO: [Silas] Bondage
 	// TODO: [Silas] this might be a better place for us to dispatch new block
 	// events particularly if we want to separate ourselves from go-events
@@ -0,0 +1,248 @@
+// Copyright 2017 Monax Industries Limited
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package abi
+
+import (
+	"encoding/hex"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/hyperledger/burrow/manager/burrow-mint/evm/sha3"
+	. "github.com/hyperledger/burrow/word256"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Examples from the Solidity ABI specification
+const exampleABI = `[
+  {"type": "function", "name": "baz", "constant": true,
+   "inputs": [{"name": "x", "type": "uint32"}, {"name": "y", "type": "bool"}],
+   "outputs": [{"name": "r", "type": "bool"}]},
+  {"type": "function", "name": "sam",
+   "inputs": [{"name": "", "type": "bytes"}, {"name": "", "type": "bool"},
+     {"name": "", "type": "uint[]"}], "outputs": []},
+  {"type": "function", "name": "f",
+   "inputs": [{"name": "", "type": "uint"}, {"name": "", "type": "uint32[]"},
+     {"name": "", "type": "bytes10"}, {"name": "", "type": "bytes"}], "outputs": []},
+  {"type": "event", "name": "Transfer", "anonymous": false,
+   "inputs": [{"name": "from", "type": "address", "indexed": true},
+     {"name": "memo", "type": "string", "indexed": true},
+     {"name": "amount", "type": "uint256", "indexed": false},
+     {"name": "note", "type": "string", "indexed": false}]}
+]`
+
+func words(ws ...string) []byte {
+	bs, err := hex.DecodeString(strings.Join(ws, ""))
+	if err != nil {
+		panic(err)
+	}
+	return bs
+}
+
+// Stop the test on unexpected errors since later steps depend on earlier ones
+func assertNoError(t *testing.T, err error) {
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+}
+
+func TestParseType(t *testing.T) {
+	for name, canonical := range map[string]string{
+		"uint":          "uint256",
+		"int8":          "int8",
+		"fixed":         "fixed128x18",
+		"ufixed64x10":   "ufixed64x10",
+		"bytes32":       "bytes32",
+		"address[2][]":  "address[2][]",
+		"string[]":      "string[]",
+		"function":      "function",
+		" bool ":        "bool",
+		"uint256[3][4]": "uint256[3][4]",
+	} {
+		typ, err := ParseType(name, nil)
+		if assert.NoError(t, err, name) {
+			assert.Equal(t, canonical, typ.String())
+		}
+	}
+	for _, name := range []string{"uint7", "uint264", "bytes33", "bytes0",
+		"fixed8x0", "int[0]", "foo"} {
+		_, err := ParseType(name, nil)
+		assert.Error(t, err, name)
+	}
+
+	tuple, err := ParseType("tuple[]", []ArgumentSpec{
+		{Name: "a", Type: "uint"}, {Name: "b", Type: "string"}})
+	assertNoError(t, err)
+	assert.Equal(t, "(uint256,string)[]", tuple.String())
+	assert.True(t, tuple.IsDynamic())
+	assert.False(t, MustParseType("uint256[2][3]").IsDynamic())
+	assert.True(t, MustParseType("bytes[2]").IsDynamic())
+}
+
+func TestFunctionPack(t *testing.T) {
+	spec, err := ReadSpec([]byte(exampleABI))
+	assertNoError(t, err)
+
+	baz, err := spec.Function("baz")
+	assertNoError(t, err)
+	assert.Equal(t, "baz(uint32,bool)", baz.Signature())
+	data, err := baz.Pack(69, true)
+	assertNoError(t, err)
+	assert.Equal(t, words("cdcd77c0",
+		"0000000000000000000000000000000000000000000000000000000000000045",
+		"0000000000000000000000000000000000000000000000000000000000000001"), data)
+
+	sam, err := spec.Function("sam")
+	assertNoError(t, err)
+	// Arguments given as strings as they would be on the command line
+	data, err = sam.Pack(hex.EncodeToString([]byte("dave")), "true", "[1, 2, 3]")
+	assertNoError(t, err)
+	assert.Equal(t, words("a5643bf2",
+		"0000000000000000000000000000000000000000000000000000000000000060",
+		"0000000000000000000000000000000000000000000000000000000000000001",
+		"00000000000000000000000000000000000000000000000000000000000000a0",
+		"0000000000000000000000000000000000000000000000000000000000000004",
+		"6461766500000000000000000000000000000000000000000000000000000000",
+		"0000000000000000000000000000000000000000000000000000000000000003",
+		"0000000000000000000000000000000000000000000000000000000000000001",
+		"0000000000000000000000000000000000000000000000000000000000000002",
+		"0000000000000000000000000000000000000000000000000000000000000003"), data)
+
+	f, err := spec.Function("f(uint256,uint32[],bytes10,bytes)")
+	assertNoError(t, err)
+	data, err = f.Pack("0x123", []int{0x456, 0x789}, []byte("1234567890"),
+		[]byte("Hello, world!"))
+	assertNoError(t, err)
+	assert.Equal(t, words("8be65246",
+		"0000000000000000000000000000000000000000000000000000000000000123",
+		"0000000000000000000000000000000000000000000000000000000000000080",
+		"3132333435363738393000000000000000000000000000000000000000000000",
+		"00000000000000000000000000000000000000000000000000000000000000e0",
+		"0000000000000000000000000000000000000000000000000000000000000002",
+		"0000000000000000000000000000000000000000000000000000000000000456",
+		"0000000000000000000000000000000000000000000000000000000000000789",
+		"000000000000000000000000000000000000000000000000000000000000000d",
+		"48656c6c6f2c20776f726c642100000000000000000000000000000000000000"), data)
+
+	// Round trip the arguments
+	values, err := Unpack(argumentTypes(f.Inputs), data[FunctionSelectorLength:])
+	assertNoError(t, err)
+	assert.Equal(t, big.NewInt(0x123), values[0])
+	assert.Equal(t, []interface{}{big.NewInt(0x456), big.NewInt(0x789)}, values[1])
+	assert.Equal(t, []byte("1234567890"), values[2])
+	assert.Equal(t, []byte("Hello, world!"), values[3])
+
+	_, err = baz.Pack(-1, true)
+	assert.Error(t, err, "uint32 cannot be negative")
+	_, err = baz.Pack(1<<32, true)
+	assert.Error(t, err, "out of range for uint32")
+	_, err = baz.Pack(1)
+	assert.Error(t, err, "too few arguments")
+}
+
+func TestPackUnpackRoundTrip(t *testing.T) {
+	types := []*Type{
+		MustParseType("int16"),
+		MustParseType("fixed128x2"),
+		MustParseType("string"),
+		MustParseType("address[2]"),
+		MustParseType("bytes[]"),
+	}
+	tuple, err := ParseType("tuple", []ArgumentSpec{
+		{Name: "ok", Type: "bool"}, {Name: "names", Type: "string[]"}})
+	assertNoError(t, err)
+	types = append(types, tuple)
+
+	var address Address
+	address[19] = 0xAB
+	values := []interface{}{
+		"-300",
+		"-12.5",
+		"héllo",
+		[]interface{}{address, "00000000000000000000000000000000000000CD"},
+		`["01", "0203"]`,
+		`[true, ["a", "bc"]]`,
+	}
+	data, err := Pack(types, values)
+	assertNoError(t, err)
+
+	decoded, err := Unpack(types, data)
+	assertNoError(t, err)
+	assert.Equal(t, "-300", Format(types[0], decoded[0]))
+	assert.Equal(t, "-12.50", Format(types[1], decoded[1]))
+	assert.Equal(t, "héllo", decoded[2])
+	assert.Equal(t, "[00000000000000000000000000000000000000AB, "+
+		"00000000000000000000000000000000000000CD]", Format(types[3], decoded[3]))
+	assert.Equal(t, []interface{}{[]byte{1}, []byte{2, 3}}, decoded[4])
+	assert.Equal(t, "(true, [a, bc])", Format(types[5], decoded[5]))
+
+	_, err = Pack(types[1:2], []interface{}{"0.125"})
+	assert.Error(t, err, "too many decimal places")
+	_, err = Unpack(types, data[:len(data)-32])
+	assert.Error(t, err, "truncated encoding")
+}
+
+func TestEventDecode(t *testing.T) {
+	spec, err := ReadSpec([]byte(exampleABI))
+	assertNoError(t, err)
+	transfer := spec.Events["Transfer(address,string,uint256,string)"]
+	if !assert.NotNil(t, transfer) {
+		return
+	}
+
+	from := LeftPadWord256([]byte{1, 2, 3})
+	memoHash := RightPadWord256([]byte("hash of memo"))
+	data, err := Pack([]*Type{MustParseType("uint256"), MustParseType("string")},
+		[]interface{}{1000, "thanks"})
+	assertNoError(t, err)
+
+	event, ok := spec.EventByID(transfer.ID())
+	if !assert.True(t, ok) {
+		return
+	}
+	values, err := event.Decode([]Word256{transfer.ID(), from, memoHash}, data)
+	assertNoError(t, err)
+	assert.Equal(t, "0000000000000000000000000000000000010203",
+		Format(event.Inputs[0].Type, values[0]))
+	assert.Equal(t, memoHash.Bytes(), values[1])
+	assert.Equal(t, big.NewInt(1000), values[2])
+	assert.Equal(t, "thanks", values[3])
+
+	_, err = event.Decode([]Word256{from, memoHash}, data)
+	assert.Error(t, err, "wrong event id")
+}
+
+func TestLink(t *testing.T) {
+	var math, strs Address
+	math[19] = 0x01
+	strs[19] = 0x02
+	legacy := "__Math__________________________________"
+	hashed := "__$" + hex.EncodeToString(sha3.Sha3([]byte("lib.sol:Strings")))[:34] + "$__"
+	bytecode := "6060" + legacy + "73" + hashed + "00" + legacy
+
+	linked, err := Link(bytecode, map[string]Address{
+		"Math":            math,
+		"lib.sol:Strings": strs,
+	})
+	assertNoError(t, err)
+	assert.Equal(t, "6060"+hex.EncodeToString(math[:])+"73"+
+		hex.EncodeToString(strs[:])+"00"+hex.EncodeToString(math[:]), linked)
+
+	_, err = Link(bytecode, map[string]Address{"Math": math})
+	assert.Error(t, err, "Strings is not linked")
+	_, err = Link("6060__Math", nil)
+	assert.Error(t, err, "truncated placeholder")
+}
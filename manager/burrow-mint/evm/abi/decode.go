@@ -0,0 +1,202 @@
+// Copyright 2017 Monax Industries Limited
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package abi
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// Unpack decodes the ABI encoding of the tuple of types. Values are returned
+// as:
+//   - integers: *big.Int
+//   - fixed point numbers: *big.Rat
+//   - address: Address
+//   - bool: bool
+//   - bytes<M>, bytes and function: []byte
+//   - string: string
+//   - arrays and tuples: []interface{}
+func Unpack(types []*Type, data []byte) ([]interface{}, error) {
+	return decodeSequence(types, data)
+}
+
+func decodeSequence(types []*Type, data []byte) ([]interface{}, error) {
+	values := make([]interface{}, len(types))
+	offset := 0
+	for i, t := range types {
+		if t.IsDynamic() {
+			word, err := readWord(data, offset)
+			if err != nil {
+				return nil, err
+			}
+			tailOffset, err := toOffset(word, len(data))
+			if err != nil {
+				return nil, err
+			}
+			values[i], err = decode(t, data[tailOffset:])
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			if offset+t.headSize() > len(data) {
+				return nil, fmt.Errorf("ABI encoding too short to decode %s", t)
+			}
+			var err error
+			values[i], err = decode(t, data[offset:])
+			if err != nil {
+				return nil, err
+			}
+		}
+		offset += t.headSize()
+	}
+	return values, nil
+}
+
+// Decode a value of type t encoded at the start of data
+func decode(t *Type, data []byte) (interface{}, error) {
+	switch t.Kind {
+	case UintKind, IntKind, FixedKind, UfixedKind:
+		word, err := readWord(data, 0)
+		if err != nil {
+			return nil, err
+		}
+		i := new(big.Int).SetBytes(word)
+		if (t.Kind == IntKind || t.Kind == FixedKind) && word[0]&0x80 != 0 {
+			i.Sub(i, twoTo256)
+		}
+		if err := checkIntRange(t, i); err != nil {
+			return nil, err
+		}
+		if t.Kind == FixedKind || t.Kind == UfixedKind {
+			return new(big.Rat).SetFrac(i, pow10(t.Decimals)), nil
+		}
+		return i, nil
+
+	case AddressKind:
+		word, err := readWord(data, 0)
+		if err != nil {
+			return nil, err
+		}
+		var address Address
+		copy(address[:], word[WordLength-AddressLength:])
+		return address, nil
+
+	case BoolKind:
+		word, err := readWord(data, 0)
+		if err != nil {
+			return nil, err
+		}
+		i := new(big.Int).SetBytes(word)
+		if i.BitLen() > 1 {
+			return nil, fmt.Errorf("Invalid ABI encoding of bool: %X", word)
+		}
+		return i.Sign() == 1, nil
+
+	case FixedBytesKind, FunctionKind:
+		word, err := readWord(data, 0)
+		if err != nil {
+			return nil, err
+		}
+		bs := make([]byte, t.Size)
+		copy(bs, word)
+		return bs, nil
+
+	case BytesKind, StringKind:
+		word, err := readWord(data, 0)
+		if err != nil {
+			return nil, err
+		}
+		length, err := toOffset(word, len(data)-WordLength)
+		if err != nil {
+			return nil, err
+		}
+		bs := make([]byte, length)
+		copy(bs, data[WordLength:WordLength+length])
+		if t.Kind == StringKind {
+			return string(bs), nil
+		}
+		return bs, nil
+
+	case ArrayKind:
+		return decodeSequence(repeatType(t.Elem, t.Length), data)
+
+	case SliceKind:
+		word, err := readWord(data, 0)
+		if err != nil {
+			return nil, err
+		}
+		// Each element takes at least a word so bound the length by the data
+		length, err := toOffset(word, (len(data)-WordLength)/WordLength)
+		if err != nil {
+			return nil, err
+		}
+		return decodeSequence(repeatType(t.Elem, length), data[WordLength:])
+
+	case TupleKind:
+		return decodeSequence(t.Components, data)
+	}
+	return nil, fmt.Errorf("Cannot ABI decode unknown kind %v", t.Kind)
+}
+
+func readWord(data []byte, offset int) ([]byte, error) {
+	if offset < 0 || offset+WordLength > len(data) {
+		return nil, fmt.Errorf("ABI encoding too short: cannot read word at "+
+			"offset %v from %v bytes", offset, len(data))
+	}
+	return data[offset : offset+WordLength], nil
+}
+
+// Interpret a word as an offset or length no greater than max
+func toOffset(word []byte, max int) (int, error) {
+	i := new(big.Int).SetBytes(word)
+	if i.BitLen() > 62 || i.Int64() > int64(max) {
+		return 0, fmt.Errorf("ABI encoding contains offset or length %v beyond "+
+			"the end of the data", i)
+	}
+	return int(i.Int64()), nil
+}
+
+// Format renders a value returned by Unpack for display
+func Format(t *Type, value interface{}) string {
+	switch v := value.(type) {
+	case *big.Int:
+		return v.String()
+	case *big.Rat:
+		return v.FloatString(t.Decimals)
+	case Address:
+		return fmt.Sprintf("%X", v[:])
+	case []byte:
+		return fmt.Sprintf("%X", v)
+	case string:
+		return v
+	case bool:
+		return fmt.Sprintf("%v", v)
+	case []interface{}:
+		elements := make([]string, len(v))
+		for i, element := range v {
+			elementType := t.Elem
+			if t.Kind == TupleKind {
+				elementType = t.Components[i]
+			}
+			elements[i] = Format(elementType, element)
+		}
+		if t.Kind == TupleKind {
+			return "(" + strings.Join(elements, ", ") + ")"
+		}
+		return "[" + strings.Join(elements, ", ") + "]"
+	}
+	return fmt.Sprintf("%v", value)
+}
@@ -0,0 +1,350 @@
+// Copyright 2017 Monax Industries Limited
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package abi
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+
+	. "github.com/hyperledger/burrow/word256"
+)
+
+// Pack encodes values as the ABI encoding of the tuple of types, as used for
+// function arguments and return values.
+//
+// Values may be given as the Go types Unpack returns or as strings, so that
+// arguments can be taken straight from the command line:
+//   - integers: *big.Int, any Go integer type, or a decimal or 0x prefixed hex
+//     string
+//   - fixed point numbers: *big.Rat or a decimal string such as "-1.25"
+//   - address: Address, 20 bytes, or a hex string
+//   - bool: bool or "true"/"false"
+//   - bytes<M>, bytes and function: []byte or a hex string
+//   - string: string
+//   - arrays and tuples: []interface{} (or any slice) of element values, or a
+//     JSON array such as ["1", "2"] or [1, [true, "0xff"]]
+func Pack(types []*Type, values []interface{}) ([]byte, error) {
+	if len(types) != len(values) {
+		return nil, fmt.Errorf("Expected %v values to ABI encode but got %v",
+			len(types), len(values))
+	}
+	return encodeSequence(types, values)
+}
+
+func encodeSequence(types []*Type, values []interface{}) ([]byte, error) {
+	headSize := 0
+	for _, t := range types {
+		headSize += t.headSize()
+	}
+	head := new(bytes.Buffer)
+	tail := new(bytes.Buffer)
+	for i, t := range types {
+		encoded, err := encode(t, values[i])
+		if err != nil {
+			return nil, err
+		}
+		if t.IsDynamic() {
+			head.Write(encodeUint(big.NewInt(int64(headSize + tail.Len()))))
+			tail.Write(encoded)
+		} else {
+			head.Write(encoded)
+		}
+	}
+	return append(head.Bytes(), tail.Bytes()...), nil
+}
+
+func encode(t *Type, value interface{}) ([]byte, error) {
+	switch t.Kind {
+	case UintKind, IntKind:
+		i, err := toBigInt(value)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkIntRange(t, i); err != nil {
+			return nil, err
+		}
+		return encodeInt(i), nil
+
+	case FixedKind, UfixedKind:
+		r, err := toRat(value)
+		if err != nil {
+			return nil, err
+		}
+		scaled := new(big.Rat).Mul(r, new(big.Rat).SetInt(pow10(t.Decimals)))
+		if !scaled.IsInt() {
+			return nil, fmt.Errorf("%s has more than %v decimal places so "+
+				"cannot be encoded as %s", r.FloatString(t.Decimals+1), t.Decimals, t)
+		}
+		i := scaled.Num()
+		if err := checkIntRange(t, i); err != nil {
+			return nil, err
+		}
+		return encodeInt(i), nil
+
+	case AddressKind:
+		bs, err := toBytes(value)
+		if err != nil {
+			return nil, err
+		}
+		if len(bs) != AddressLength {
+			return nil, fmt.Errorf("Address %X should have length %v but has "+
+				"length %v", bs, AddressLength, len(bs))
+		}
+		return LeftPadBytes(bs, WordLength), nil
+
+	case BoolKind:
+		b, err := toBool(value)
+		if err != nil {
+			return nil, err
+		}
+		if b {
+			return encodeUint(big.NewInt(1)), nil
+		}
+		return encodeUint(big.NewInt(0)), nil
+
+	case FixedBytesKind, FunctionKind:
+		bs, err := toBytes(value)
+		if err != nil {
+			return nil, err
+		}
+		if len(bs) > t.Size {
+			return nil, fmt.Errorf("Value %X is too long for %s", bs, t)
+		}
+		return RightPadBytes(bs, WordLength), nil
+
+	case BytesKind:
+		bs, err := toBytes(value)
+		if err != nil {
+			return nil, err
+		}
+		return encodeBytes(bs), nil
+
+	case StringKind:
+		s, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("Expected string value for ABI type string "+
+				"but got %T", value)
+		}
+		return encodeBytes([]byte(s)), nil
+
+	case ArrayKind, SliceKind, TupleKind:
+		elements, err := toSlice(value)
+		if err != nil {
+			return nil, err
+		}
+		switch t.Kind {
+		case ArrayKind:
+			if len(elements) != t.Length {
+				return nil, fmt.Errorf("Expected %v elements for %s but got %v",
+					t.Length, t, len(elements))
+			}
+			return encodeSequence(repeatType(t.Elem, t.Length), elements)
+		case SliceKind:
+			encoded, err := encodeSequence(repeatType(t.Elem, len(elements)), elements)
+			if err != nil {
+				return nil, err
+			}
+			return append(encodeUint(big.NewInt(int64(len(elements)))), encoded...), nil
+		default:
+			if len(elements) != len(t.Components) {
+				return nil, fmt.Errorf("Expected %v components for %s but got %v",
+					len(t.Components), t, len(elements))
+			}
+			return encodeSequence(t.Components, elements)
+		}
+	}
+	return nil, fmt.Errorf("Cannot ABI encode unknown kind %v", t.Kind)
+}
+
+func encodeBytes(bs []byte) []byte {
+	length := encodeUint(big.NewInt(int64(len(bs))))
+	padded := RightPadBytes(bs, (len(bs)+WordLength-1)/WordLength*WordLength)
+	return append(length, padded...)
+}
+
+func encodeUint(i *big.Int) []byte {
+	return LeftPadBytes(i.Bytes(), WordLength)
+}
+
+// Two's complement encoding in a 256 bit word
+func encodeInt(i *big.Int) []byte {
+	if i.Sign() >= 0 {
+		return encodeUint(i)
+	}
+	return encodeUint(new(big.Int).Add(twoTo256, i))
+}
+
+var twoTo256 = new(big.Int).Lsh(big.NewInt(1), 256)
+
+func checkIntRange(t *Type, i *big.Int) error {
+	switch t.Kind {
+	case UintKind, UfixedKind:
+		if i.Sign() < 0 || i.BitLen() > t.Size {
+			return fmt.Errorf("Value %v out of range for %s", i, t)
+		}
+	default:
+		// -2^(M-1) <= i < 2^(M-1)
+		bound := new(big.Int).Lsh(big.NewInt(1), uint(t.Size-1))
+		if i.Cmp(bound) >= 0 || i.Cmp(new(big.Int).Neg(bound)) < 0 {
+			return fmt.Errorf("Value %v out of range for %s", i, t)
+		}
+	}
+	return nil
+}
+
+func repeatType(t *Type, n int) []*Type {
+	types := make([]*Type, n)
+	for i := range types {
+		types[i] = t
+	}
+	return types
+}
+
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
+
+//------------------------------------------------------------------------------
+// Conversion of values
+
+func toBigInt(value interface{}) (*big.Int, error) {
+	switch v := value.(type) {
+	case *big.Int:
+		return v, nil
+	case string:
+		return parseBigInt(v)
+	case json.Number:
+		return parseBigInt(string(v))
+	}
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return big.NewInt(rv.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return new(big.Int).SetUint64(rv.Uint()), nil
+	}
+	return nil, fmt.Errorf("Cannot convert %v of type %T to an integer", value, value)
+}
+
+func parseBigInt(s string) (*big.Int, error) {
+	s = strings.TrimSpace(s)
+	negative := strings.HasPrefix(s, "-")
+	digits := strings.TrimPrefix(s, "-")
+	base := 10
+	if strings.HasPrefix(digits, "0x") || strings.HasPrefix(digits, "0X") {
+		digits = digits[2:]
+		base = 16
+	}
+	i, ok := new(big.Int).SetString(digits, base)
+	if !ok {
+		return nil, fmt.Errorf("Could not parse integer from '%s'", s)
+	}
+	if negative {
+		i.Neg(i)
+	}
+	return i, nil
+}
+
+func toRat(value interface{}) (*big.Rat, error) {
+	switch v := value.(type) {
+	case *big.Rat:
+		return v, nil
+	case *big.Int:
+		return new(big.Rat).SetInt(v), nil
+	case string:
+		return parseRat(v)
+	case json.Number:
+		return parseRat(string(v))
+	}
+	i, err := toBigInt(value)
+	if err != nil {
+		return nil, fmt.Errorf("Cannot convert %v of type %T to a fixed point "+
+			"number", value, value)
+	}
+	return new(big.Rat).SetInt(i), nil
+}
+
+func parseRat(s string) (*big.Rat, error) {
+	r, ok := new(big.Rat).SetString(strings.TrimSpace(s))
+	if !ok {
+		return nil, fmt.Errorf("Could not parse decimal number from '%s'", s)
+	}
+	return r, nil
+}
+
+func toBytes(value interface{}) ([]byte, error) {
+	switch v := value.(type) {
+	case []byte:
+		return v, nil
+	case Address:
+		return v[:], nil
+	case Word256:
+		return v[:], nil
+	case string:
+		s := strings.TrimPrefix(strings.TrimPrefix(strings.TrimSpace(v), "0x"), "0X")
+		bs, err := hex.DecodeString(s)
+		if err != nil {
+			return nil, fmt.Errorf("Could not parse hex bytes from '%s': %v", v, err)
+		}
+		return bs, nil
+	}
+	return nil, fmt.Errorf("Cannot convert %v of type %T to bytes", value, value)
+}
+
+func toBool(value interface{}) (bool, error) {
+	switch v := value.(type) {
+	case bool:
+		return v, nil
+	case string:
+		b, err := strconv.ParseBool(strings.TrimSpace(v))
+		if err != nil {
+			return false, fmt.Errorf("Could not parse bool from '%s'", v)
+		}
+		return b, nil
+	}
+	return false, fmt.Errorf("Cannot convert %v of type %T to bool", value, value)
+}
+
+func toSlice(value interface{}) ([]interface{}, error) {
+	switch v := value.(type) {
+	case []interface{}:
+		return v, nil
+	case string:
+		decoder := json.NewDecoder(strings.NewReader(v))
+		// Keep numbers as strings so we do not lose precision in float64
+		decoder.UseNumber()
+		var elements []interface{}
+		if err := decoder.Decode(&elements); err != nil {
+			return nil, fmt.Errorf("Could not parse JSON array from '%s': %v", v, err)
+		}
+		return elements, nil
+	}
+	rv := reflect.ValueOf(value)
+	if rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array {
+		elements := make([]interface{}, rv.Len())
+		for i := range elements {
+			elements[i] = rv.Index(i).Interface()
+		}
+		return elements, nil
+	}
+	return nil, fmt.Errorf("Cannot convert %v of type %T to a list of values",
+		value, value)
+}
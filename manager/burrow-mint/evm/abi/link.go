@@ -0,0 +1,69 @@
+// Copyright 2017 Monax Industries Limited
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package abi
+
+import (
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hyperledger/burrow/manager/burrow-mint/evm/sha3"
+)
+
+// Library link placeholders in hex bytecode output by solc take the place of
+// an address so are 40 characters long
+const placeholderLength = 2 * AddressLength
+
+// Link substitutes the addresses of libraries, keyed by library name, for the
+// link placeholders in hex encoded bytecode. Both the __Name____ placeholders
+// of older versions of solc and the __$hash$__ placeholders of newer versions
+// are replaced, where the name is the one solc used (either Name or
+// file.sol:Name). It is an error for placeholders to remain after linking.
+func Link(bytecode string, libraries map[string]Address) (string, error) {
+	bytecode = strings.TrimSpace(bytecode)
+	for name, address := range libraries {
+		addressHex := fmt.Sprintf("%x", address[:])
+		for _, placeholder := range placeholders(name) {
+			bytecode = strings.Replace(bytecode, placeholder, addressHex, -1)
+		}
+	}
+	var unresolved []string
+	for i := strings.Index(bytecode, "__"); i >= 0; i = strings.Index(bytecode, "__") {
+		if i+placeholderLength > len(bytecode) {
+			return "", fmt.Errorf("Bytecode contains truncated library "+
+				"placeholder %s", bytecode[i:])
+		}
+		placeholder := bytecode[i : i+placeholderLength]
+		unresolved = append(unresolved, placeholder)
+		bytecode = strings.Replace(bytecode, placeholder, "", -1)
+	}
+	if len(unresolved) > 0 {
+		sort.Strings(unresolved)
+		return "", fmt.Errorf("Bytecode has unresolved library placeholders %s; "+
+			"provide the addresses of these libraries", strings.Join(unresolved, ", "))
+	}
+	return bytecode, nil
+}
+
+func placeholders(name string) []string {
+	legacy := "__" + name
+	if len(legacy) > placeholderLength-2 {
+		legacy = legacy[:placeholderLength-2]
+	}
+	legacy += strings.Repeat("_", placeholderLength-len(legacy))
+	hashed := "__$" + hex.EncodeToString(sha3.Sha3([]byte(name)))[:placeholderLength-6] + "$__"
+	return []string{legacy, hashed}
+}
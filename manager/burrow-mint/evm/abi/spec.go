@@ -0,0 +1,291 @@
+// Copyright 2017 Monax Industries Limited
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package abi
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+
+	"github.com/hyperledger/burrow/manager/burrow-mint/evm/sha3"
+	. "github.com/hyperledger/burrow/word256"
+)
+
+// The JSON ABI specification of a contract as output by solc --abi
+
+type ArgumentSpec struct {
+	Name       string         `json:"name"`
+	Type       string         `json:"type"`
+	Components []ArgumentSpec `json:"components,omitempty"`
+	Indexed    bool           `json:"indexed,omitempty"`
+}
+
+type entrySpec struct {
+	Type      string         `json:"type"`
+	Name      string         `json:"name"`
+	Inputs    []ArgumentSpec `json:"inputs"`
+	Outputs   []ArgumentSpec `json:"outputs"`
+	Constant  bool           `json:"constant"`
+	Anonymous bool           `json:"anonymous"`
+}
+
+type Argument struct {
+	Name    string
+	Type    *Type
+	Indexed bool
+}
+
+type Function struct {
+	Name     string
+	Inputs   []Argument
+	Outputs  []Argument
+	Constant bool
+}
+
+type Event struct {
+	Name      string
+	Inputs    []Argument
+	Anonymous bool
+}
+
+// Spec holds the functions and events of a contract ABI
+type Spec struct {
+	Constructor *Function
+	// Functions by signature, e.g. transfer(address,uint256)
+	Functions map[string]*Function
+	// Events by signature, e.g. Transfer(address,address,uint256)
+	Events map[string]*Event
+}
+
+// ReadSpec parses a JSON ABI specification
+func ReadSpec(specJSON []byte) (*Spec, error) {
+	var entries []entrySpec
+	err := json.Unmarshal(specJSON, &entries)
+	if err != nil {
+		return nil, fmt.Errorf("Could not parse JSON ABI: %v", err)
+	}
+	spec := &Spec{
+		Functions: make(map[string]*Function),
+		Events:    make(map[string]*Event),
+	}
+	for _, entry := range entries {
+		inputs, err := readArguments(entry.Inputs)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid inputs of %s: %v", entry.Name, err)
+		}
+		outputs, err := readArguments(entry.Outputs)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid outputs of %s: %v", entry.Name, err)
+		}
+		switch entry.Type {
+		case "constructor":
+			spec.Constructor = &Function{Inputs: inputs}
+		case "function", "":
+			// Functions may omit type
+			function := &Function{
+				Name:     entry.Name,
+				Inputs:   inputs,
+				Outputs:  outputs,
+				Constant: entry.Constant,
+			}
+			spec.Functions[function.Signature()] = function
+		case "event":
+			event := &Event{
+				Name:      entry.Name,
+				Inputs:    inputs,
+				Anonymous: entry.Anonymous,
+			}
+			spec.Events[event.Signature()] = event
+		case "fallback":
+			// nothing to encode
+		default:
+			return nil, fmt.Errorf("Unknown ABI entry type %s", entry.Type)
+		}
+	}
+	return spec, nil
+}
+
+// ReadSpecFile reads a JSON ABI specification from a file
+func ReadSpecFile(fileName string) (*Spec, error) {
+	specJSON, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+	return ReadSpec(specJSON)
+}
+
+func readArguments(argumentSpecs []ArgumentSpec) ([]Argument, error) {
+	arguments := make([]Argument, len(argumentSpecs))
+	for i, argumentSpec := range argumentSpecs {
+		t, err := ParseType(argumentSpec.Type, argumentSpec.Components)
+		if err != nil {
+			return nil, err
+		}
+		arguments[i] = Argument{
+			Name:    argumentSpec.Name,
+			Type:    t,
+			Indexed: argumentSpec.Indexed,
+		}
+	}
+	return arguments, nil
+}
+
+// Function looks up a function by signature, or by name if the name is not
+// overloaded
+func (spec *Spec) Function(nameOrSignature string) (*Function, error) {
+	if function, ok := spec.Functions[nameOrSignature]; ok {
+		return function, nil
+	}
+	var matches []string
+	for signature, function := range spec.Functions {
+		if function.Name == nameOrSignature {
+			matches = append(matches, signature)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("No function %s in ABI", nameOrSignature)
+	case 1:
+		return spec.Functions[matches[0]], nil
+	}
+	sort.Strings(matches)
+	return nil, fmt.Errorf("Function %s is overloaded, use one of the "+
+		"signatures %s", nameOrSignature, strings.Join(matches, ", "))
+}
+
+// EventByID looks up a non-anonymous event by its first log topic
+func (spec *Spec) EventByID(id Word256) (*Event, bool) {
+	for _, event := range spec.Events {
+		if !event.Anonymous && event.ID() == id {
+			return event, true
+		}
+	}
+	return nil, false
+}
+
+func argumentTypes(arguments []Argument) []*Type {
+	types := make([]*Type, len(arguments))
+	for i, argument := range arguments {
+		types[i] = argument.Type
+	}
+	return types
+}
+
+func signature(name string, arguments []Argument) string {
+	return name + (&Type{Kind: TupleKind, Components: argumentTypes(arguments)}).String()
+}
+
+//------------------------------------------------------------------------------
+// Functions
+
+func (function *Function) Signature() string {
+	return signature(function.Name, function.Inputs)
+}
+
+func (function *Function) Selector() FunctionSelector {
+	var selector FunctionSelector
+	copy(selector[:], sha3.Sha3([]byte(function.Signature())))
+	return selector
+}
+
+// Pack encodes the call data for function with args: the selector followed
+// by the encoded arguments. See Pack for the values accepted.
+func (function *Function) Pack(args ...interface{}) ([]byte, error) {
+	encoded, err := Pack(argumentTypes(function.Inputs), args)
+	if err != nil {
+		return nil, fmt.Errorf("Could not encode arguments of %s: %v",
+			function.Signature(), err)
+	}
+	selector := function.Selector()
+	return append(selector[:], encoded...), nil
+}
+
+// PackConstructor encodes constructor arguments, which are appended to the
+// contract bytecode without a selector
+func (function *Function) PackConstructor(args ...interface{}) ([]byte, error) {
+	encoded, err := Pack(argumentTypes(function.Inputs), args)
+	if err != nil {
+		return nil, fmt.Errorf("Could not encode constructor arguments: %v", err)
+	}
+	return encoded, nil
+}
+
+// UnpackOutputs decodes the return value of a call to function
+func (function *Function) UnpackOutputs(data []byte) ([]interface{}, error) {
+	return Unpack(argumentTypes(function.Outputs), data)
+}
+
+//------------------------------------------------------------------------------
+// Events
+
+func (event *Event) Signature() string {
+	return signature(event.Name, event.Inputs)
+}
+
+// ID is the first topic of logs emitted for non-anonymous events
+func (event *Event) ID() Word256 {
+	return RightPadWord256(sha3.Sha3([]byte(event.Signature())))
+}
+
+// Decode the inputs of event from the topics and data of a log. Values are
+// returned in the order of the event's inputs. Indexed inputs of dynamic type
+// are logged as the hash of their encoding, so are returned as the 32 byte
+// topic.
+func (event *Event) Decode(topics []Word256, data []byte) ([]interface{}, error) {
+	if !event.Anonymous {
+		if len(topics) == 0 || topics[0] != event.ID() {
+			return nil, fmt.Errorf("Log is not an instance of event %s",
+				event.Signature())
+		}
+		topics = topics[1:]
+	}
+	var dataTypes []*Type
+	for _, input := range event.Inputs {
+		if !input.Indexed {
+			dataTypes = append(dataTypes, input.Type)
+		}
+	}
+	dataValues, err := Unpack(dataTypes, data)
+	if err != nil {
+		return nil, fmt.Errorf("Could not decode data of event %s: %v",
+			event.Signature(), err)
+	}
+	values := make([]interface{}, len(event.Inputs))
+	for i, input := range event.Inputs {
+		if !input.Indexed {
+			values[i], dataValues = dataValues[0], dataValues[1:]
+			continue
+		}
+		if len(topics) == 0 {
+			return nil, fmt.Errorf("Log has too few topics for event %s",
+				event.Signature())
+		}
+		topic := topics[0]
+		topics = topics[1:]
+		if input.Type.IsDynamic() || input.Type.Kind == ArrayKind ||
+			input.Type.Kind == TupleKind {
+			values[i] = topic.Bytes()
+			continue
+		}
+		values[i], err = decode(input.Type, topic.Bytes())
+		if err != nil {
+			return nil, fmt.Errorf("Could not decode topic %s of event %s: %v",
+				input.Name, event.Signature(), err)
+		}
+	}
+	return values, nil
+}
@@ -0,0 +1,250 @@
+// Copyright 2017 Monax Industries Limited
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package abi
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Kind classifies ABI types
+type Kind int
+
+const (
+	UintKind Kind = iota
+	IntKind
+	AddressKind
+	BoolKind
+	FixedKind
+	UfixedKind
+	FixedBytesKind
+	FunctionKind
+	BytesKind
+	StringKind
+	// T[k]
+	ArrayKind
+	// T[]
+	SliceKind
+	// (T1,T2,...,Tn)
+	TupleKind
+)
+
+// Type is a parsed ABI type
+type Type struct {
+	Kind Kind
+	// Bits for integers and fixed point numbers, bytes for bytes<M>
+	Size int
+	// Decimal places N of fixed<M>x<N> and ufixed<M>x<N>
+	Decimals int
+	// Number of elements of a T[k] array
+	Length int
+	// Element type of arrays and slices
+	Elem *Type
+	// Component types and names of tuples
+	Components     []*Type
+	ComponentNames []string
+}
+
+var (
+	arraySuffixRegex = regexp.MustCompile(`^(.*)\[([0-9]*)\]$`)
+	sizedRegex       = regexp.MustCompile(`^(u?int|bytes)([0-9]+)$`)
+	fixedRegex       = regexp.MustCompile(`^(u?fixed)([0-9]+)x([0-9]+)$`)
+)
+
+// ParseType parses an ABI type name such as uint256, bytes, fixed128x18,
+// address[2][] or tuple[]. Components describe the fields of tuple types and
+// are ignored otherwise.
+func ParseType(typeName string, components []ArgumentSpec) (*Type, error) {
+	typeName = strings.TrimSpace(typeName)
+	if matches := arraySuffixRegex.FindStringSubmatch(typeName); matches != nil {
+		elem, err := ParseType(matches[1], components)
+		if err != nil {
+			return nil, err
+		}
+		if matches[2] == "" {
+			return &Type{Kind: SliceKind, Elem: elem}, nil
+		}
+		length, err := strconv.Atoi(matches[2])
+		if err != nil || length == 0 {
+			return nil, fmt.Errorf("Invalid array length in ABI type %s", typeName)
+		}
+		return &Type{Kind: ArrayKind, Elem: elem, Length: length}, nil
+	}
+
+	switch typeName {
+	case "uint":
+		return &Type{Kind: UintKind, Size: 256}, nil
+	case "int":
+		return &Type{Kind: IntKind, Size: 256}, nil
+	case "address":
+		return &Type{Kind: AddressKind, Size: 160}, nil
+	case "bool":
+		return &Type{Kind: BoolKind}, nil
+	case "fixed":
+		return &Type{Kind: FixedKind, Size: 128, Decimals: 18}, nil
+	case "ufixed":
+		return &Type{Kind: UfixedKind, Size: 128, Decimals: 18}, nil
+	case "function":
+		// an address followed by a function selector
+		return &Type{Kind: FunctionKind, Size: AddressLength + FunctionSelectorLength}, nil
+	case "bytes":
+		return &Type{Kind: BytesKind}, nil
+	case "string":
+		return &Type{Kind: StringKind}, nil
+	case "tuple":
+		return parseTuple(components)
+	}
+
+	if matches := sizedRegex.FindStringSubmatch(typeName); matches != nil {
+		size, _ := strconv.Atoi(matches[2])
+		switch matches[1] {
+		case "bytes":
+			if size < 1 || size > 32 {
+				return nil, fmt.Errorf("Invalid size in ABI type %s, bytes<M> "+
+					"requires 0 < M <= 32", typeName)
+			}
+			return &Type{Kind: FixedBytesKind, Size: size}, nil
+		default:
+			if size < 8 || size > 256 || size%8 != 0 {
+				return nil, fmt.Errorf("Invalid size in ABI type %s, %s<M> "+
+					"requires 0 < M <= 256 and M %% 8 == 0", typeName, matches[1])
+			}
+			kind := IntKind
+			if matches[1] == "uint" {
+				kind = UintKind
+			}
+			return &Type{Kind: kind, Size: size}, nil
+		}
+	}
+
+	if matches := fixedRegex.FindStringSubmatch(typeName); matches != nil {
+		size, _ := strconv.Atoi(matches[2])
+		decimals, _ := strconv.Atoi(matches[3])
+		if size < 8 || size > 256 || size%8 != 0 || decimals < 1 || decimals > 80 {
+			return nil, fmt.Errorf("Invalid ABI type %s, %s<M>x<N> requires "+
+				"0 < M <= 256, M %% 8 == 0, and 0 < N <= 80", typeName, matches[1])
+		}
+		kind := FixedKind
+		if matches[1] == "ufixed" {
+			kind = UfixedKind
+		}
+		return &Type{Kind: kind, Size: size, Decimals: decimals}, nil
+	}
+
+	return nil, fmt.Errorf("Unknown ABI type %s", typeName)
+}
+
+// MustParseType is ParseType for types known to be valid
+func MustParseType(typeName string) *Type {
+	t, err := ParseType(typeName, nil)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func parseTuple(components []ArgumentSpec) (*Type, error) {
+	tuple := &Type{
+		Kind:           TupleKind,
+		Components:     make([]*Type, len(components)),
+		ComponentNames: make([]string, len(components)),
+	}
+	for i, component := range components {
+		componentType, err := ParseType(component.Type, component.Components)
+		if err != nil {
+			return nil, err
+		}
+		tuple.Components[i] = componentType
+		tuple.ComponentNames[i] = component.Name
+	}
+	return tuple, nil
+}
+
+// String returns the canonical name of the type as used in function and event
+// signatures
+func (t *Type) String() string {
+	switch t.Kind {
+	case UintKind:
+		return fmt.Sprintf("uint%v", t.Size)
+	case IntKind:
+		return fmt.Sprintf("int%v", t.Size)
+	case AddressKind:
+		return "address"
+	case BoolKind:
+		return "bool"
+	case FixedKind:
+		return fmt.Sprintf("fixed%vx%v", t.Size, t.Decimals)
+	case UfixedKind:
+		return fmt.Sprintf("ufixed%vx%v", t.Size, t.Decimals)
+	case FixedBytesKind:
+		return fmt.Sprintf("bytes%v", t.Size)
+	case FunctionKind:
+		return "function"
+	case BytesKind:
+		return "bytes"
+	case StringKind:
+		return "string"
+	case ArrayKind:
+		return fmt.Sprintf("%s[%v]", t.Elem, t.Length)
+	case SliceKind:
+		return fmt.Sprintf("%s[]", t.Elem)
+	case TupleKind:
+		names := make([]string, len(t.Components))
+		for i, component := range t.Components {
+			names[i] = component.String()
+		}
+		return "(" + strings.Join(names, ",") + ")"
+	}
+	return fmt.Sprintf("<unknown ABI kind %v>", t.Kind)
+}
+
+// IsDynamic returns whether values of the type are encoded in the tail of the
+// enclosing encoding and referenced by offset
+func (t *Type) IsDynamic() bool {
+	switch t.Kind {
+	case BytesKind, StringKind, SliceKind:
+		return true
+	case ArrayKind:
+		return t.Elem.IsDynamic()
+	case TupleKind:
+		for _, component := range t.Components {
+			if component.IsDynamic() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// headSize returns the number of bytes the type occupies in the head of an
+// encoding
+func (t *Type) headSize() int {
+	if t.IsDynamic() {
+		return WordLength
+	}
+	switch t.Kind {
+	case ArrayKind:
+		return t.Length * t.Elem.headSize()
+	case TupleKind:
+		size := 0
+		for _, component := range t.Components {
+			size += component.headSize()
+		}
+		return size
+	}
+	return WordLength
+}
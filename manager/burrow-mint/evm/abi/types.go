@@ -45,6 +45,8 @@ const (
 const (
 	FunctionSelectorLength = 4
 	AddressLength          = 20
+	// Values are encoded in 32 byte words
+	WordLength = 32
 )
 
 type (
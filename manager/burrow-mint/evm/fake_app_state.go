@@ -18,12 +18,19 @@ import (
 	"fmt"
 
 	"github.com/hyperledger/burrow/manager/burrow-mint/evm/sha3"
+	ptypes "github.com/hyperledger/burrow/permission/types"
+	"github.com/hyperledger/burrow/txs"
 	. "github.com/hyperledger/burrow/word256"
 )
 
 type FakeAppState struct {
 	accounts map[string]*Account
 	storage  map[string]Word256
+	names    map[string]*NameRegEntry
+	roles    map[string]*ptypes.Role
+
+	blockHeight int64
+	governance  *ptypes.GovernanceParams
 }
 
 func (fas *FakeAppState) GetAccount(addr Word256) *Account {
@@ -83,6 +90,30 @@ func (fas *FakeAppState) SetStorage(addr Word256, key Word256, value Word256) {
 	fas.storage[addr.String()+key.String()] = value
 }
 
+func (fas *FakeAppState) GetNameRegEntry(name string) *NameRegEntry {
+	return fas.names[name]
+}
+
+func (fas *FakeAppState) UpdateNameRegEntry(entry *NameRegEntry) {
+	fas.names[entry.Name] = entry
+}
+
+func (fas *FakeAppState) LastBlockHeight() int64 {
+	return fas.blockHeight
+}
+
+func (fas *FakeAppState) NameRegParams() txs.NameRegParams {
+	return txs.DefaultNameRegParams
+}
+
+func (fas *FakeAppState) GetRole(name string) *ptypes.Role {
+	return fas.roles[ptypes.RoleName(name)]
+}
+
+func (fas *FakeAppState) GovernanceParams() *ptypes.GovernanceParams {
+	return fas.governance
+}
+
 // Creates a 20 byte address and bumps the nonce.
 func createAddress(creator *Account) Word256 {
 	nonce := creator.Nonce
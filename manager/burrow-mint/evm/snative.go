@@ -20,16 +20,20 @@ import (
 	"github.com/hyperledger/burrow/common/sanity"
 	"github.com/hyperledger/burrow/manager/burrow-mint/evm/sha3"
 	ptypes "github.com/hyperledger/burrow/permission/types"
+	"github.com/hyperledger/burrow/txs"
 	. "github.com/hyperledger/burrow/word256"
 
 	"strings"
 
 	"github.com/hyperledger/burrow/manager/burrow-mint/evm/abi"
+
+	"github.com/tendermint/go-events"
 )
 
 //
 // SNative (from 'secure natives') are native (go) contracts that are dispatched
 // based on account permissions and can access and modify an account's permissions
+// and the name registry
 //
 
 // Metadata for SNative contract. Acts as a call target from the EVM. Can be
@@ -73,6 +77,7 @@ func registerSNativeContracts() {
 func SNativeContracts() map[string]*SNativeContractDescription {
 	permFlagTypeName := abi.Uint64TypeName
 	roleTypeName := abi.Bytes32TypeName
+	nameTypeName := abi.StringTypeName
 	contracts := []*SNativeContractDescription{
 		NewSNativeContract(`
 		* Interface for managing Secure Native authorizations.
@@ -80,7 +85,7 @@ func SNativeContracts() map[string]*SNativeContractDescription {
 		`,
 			"Permissions",
 			&SNativeFunctionDescription{`
-			* @notice Adds a role to an account
+			* @notice Adds a role to an account. Members of the admin role of the role may call this without the addRole permission
 			* @param _account account address
 			* @param _role role name
 			* @return result whether role was added
@@ -95,7 +100,24 @@ func SNativeContracts() map[string]*SNativeContractDescription {
 				addRole},
 
 			&SNativeFunctionDescription{`
-			* @notice Removes a role from an account
+			* @notice Adds a role to an account until a block height, after which the account no longer has it. Members of the admin role of the role may call this without the addRole permission
+			* @param _account account address
+			* @param _role role name
+			* @param _expires block height at which the role lapses, or 0 to add it permanently
+			* @return result whether role was added or its expiry changed
+			`,
+				"addRoleUntil",
+				[]abi.Arg{
+					abiArg("_account", abi.AddressTypeName),
+					abiArg("_role", roleTypeName),
+					abiArg("_expires", abi.Uint64TypeName),
+				},
+				abiReturn("result", abi.BoolTypeName),
+				ptypes.AddRole,
+				addRoleUntil},
+
+			&SNativeFunctionDescription{`
+			* @notice Removes a role from an account. Members of the admin role of the role may call this without the removeRole permission
 			* @param _account account address
 			* @param _role role name
 			* @return result whether role was removed
@@ -110,7 +132,7 @@ func SNativeContracts() map[string]*SNativeContractDescription {
 				removeRole},
 
 			&SNativeFunctionDescription{`
-			* @notice Indicates whether an account has a role
+			* @notice Indicates whether an account has a role, either itself or through a role it has that inherits it
 			* @param _account account address
 			* @param _role role name
 			* @return result whether account has role
@@ -141,6 +163,25 @@ func SNativeContracts() map[string]*SNativeContractDescription {
 				ptypes.SetBase,
 				setBase},
 
+			&SNativeFunctionDescription{`
+			* @notice Sets the permission flags for an account until a block height, after which they fall through as if unset.
+			* @param _account account address
+			* @param _permission the base permissions flags to set for the account
+			* @param _set whether to set or unset the permissions flags at the account level
+			* @param _expires block height at which the permissions flags lapse, or 0 to set them permanently
+			* @return result the effective permissions flags on the account after the call
+			`,
+				"setBaseUntil",
+				[]abi.Arg{
+					abiArg("_account", abi.AddressTypeName),
+					abiArg("_permission", permFlagTypeName),
+					abiArg("_set", abi.BoolTypeName),
+					abiArg("_expires", abi.Uint64TypeName),
+				},
+				abiReturn("result", permFlagTypeName),
+				ptypes.SetBase,
+				setBaseUntil},
+
 			&SNativeFunctionDescription{`
 			* @notice Unsets the permissions flags for an account. Causes permissions being unset to fall through to global permissions.
       * @param _account account address
@@ -182,6 +223,137 @@ func SNativeContracts() map[string]*SNativeContractDescription {
 				abiReturn("result", permFlagTypeName),
 				ptypes.SetGlobal,
 				setGlobal},
+
+			&SNativeFunctionDescription{`
+			* @notice Allows an account to call a contract. An account that may call any contract becomes restricted to calling only the contracts it is allowed.
+			* @param _account account address
+			* @param _contract address of the contract the account may call
+			* @return result whether the contract was added to those the account may call
+			`,
+				"allowCall",
+				[]abi.Arg{
+					abiArg("_account", abi.AddressTypeName),
+					abiArg("_contract", abi.AddressTypeName)},
+				abiReturn("result", abi.BoolTypeName),
+				ptypes.SetCallAllowList,
+				allowCall},
+
+			&SNativeFunctionDescription{`
+			* @notice Removes a contract from those a restricted account may call. The account stays restricted even if it is allowed no contracts.
+			* @param _account account address
+			* @param _contract address of the contract the account may no longer call
+			* @return result whether the contract was removed from those the account may call
+			`,
+				"disallowCall",
+				[]abi.Arg{
+					abiArg("_account", abi.AddressTypeName),
+					abiArg("_contract", abi.AddressTypeName)},
+				abiReturn("result", abi.BoolTypeName),
+				ptypes.SetCallAllowList,
+				disallowCall},
+
+			&SNativeFunctionDescription{`
+			* @notice Lifts the restriction on the contracts an account may call
+			* @param _account account address
+			* @return result whether the account was restricted
+			`,
+				"unrestrictCalls",
+				[]abi.Arg{
+					abiArg("_account", abi.AddressTypeName)},
+				abiReturn("result", abi.BoolTypeName),
+				ptypes.SetCallAllowList,
+				unrestrictCalls},
+
+			&SNativeFunctionDescription{`
+			* @notice Indicates whether an account may call a contract given the contracts it and its roles are restricted to calling
+			* @param _account account address
+			* @param _contract contract address
+			* @return result whether the account may call the contract
+			`,
+				"canCall",
+				[]abi.Arg{
+					abiArg("_account", abi.AddressTypeName),
+					abiArg("_contract", abi.AddressTypeName)},
+				abiReturn("result", abi.BoolTypeName),
+				ptypes.HasBase,
+				canCall},
+		),
+
+		NewSNativeContract(`
+		* Interface for reading and updating entries in the name registry.
+		* @dev This interface describes the functions exposed by the SNative name registry layer in burrow.
+		* @dev Names are registered and their registrations extended using NameTx.
+		* @dev Unregistered and expired names read as empty data, the zero address, and expiry 0.
+		`,
+			"NameReg",
+			&SNativeFunctionDescription{`
+			* @notice Gets the data stored under a name
+			* @param _name name
+			* @return data the data stored under the name
+			`,
+				"getData",
+				[]abi.Arg{
+					abiArg("_name", nameTypeName),
+				},
+				abiReturn("data", abi.StringTypeName),
+				ptypes.Call,
+				getNameData},
+
+			&SNativeFunctionDescription{`
+			* @notice Gets the owner of a name
+			* @param _name name
+			* @return owner address of the account owning the name
+			`,
+				"getOwner",
+				[]abi.Arg{
+					abiArg("_name", nameTypeName),
+				},
+				abiReturn("owner", abi.AddressTypeName),
+				ptypes.Call,
+				getNameOwner},
+
+			&SNativeFunctionDescription{`
+			* @notice Gets the block height at which a name expires
+			* @param _name name
+			* @return expiry block height at which the name expires
+			`,
+				"getExpiry",
+				[]abi.Arg{
+					abiArg("_name", nameTypeName),
+				},
+				abiReturn("expiry", abi.Uint64TypeName),
+				ptypes.Call,
+				getNameExpiry},
+
+			&SNativeFunctionDescription{`
+			* @notice Sets the data stored under a name owned by the caller. The remaining credit on the name is kept, so the expiry moves according to the size of the new data.
+			* @param _name name
+			* @param _data the data to store under the name
+			* @return expiry block height at which the name expires after the call
+			`,
+				"setData",
+				[]abi.Arg{
+					abiArg("_name", nameTypeName),
+					abiArg("_data", abi.StringTypeName),
+				},
+				abiReturn("expiry", abi.Uint64TypeName),
+				ptypes.Name,
+				setNameData},
+
+			&SNativeFunctionDescription{`
+			* @notice Transfers a name owned by the caller to another account
+			* @param _name name
+			* @param _owner address of the new owner
+			* @return result whether the name was transferred
+			`,
+				"transfer",
+				[]abi.Arg{
+					abiArg("_name", nameTypeName),
+					abiArg("_owner", abi.AddressTypeName),
+				},
+				abiReturn("result", abi.BoolTypeName),
+				ptypes.Name,
+				transferName},
 		),
 	}
 
@@ -239,12 +411,18 @@ func (contract *SNativeContractDescription) Dispatch(appState AppState,
 	remainingArgs := args[abi.FunctionSelectorLength:]
 
 	// check if we have permission to call this function
-	if !HasPermission(appState, caller, function.PermFlag) {
+	if !HasPermission(appState, caller, function.PermFlag) &&
+		!isRoleAdminCall(appState, caller, function, remainingArgs) {
 		return nil, ErrInvalidPermission{caller.Address, function.Name}
 	}
 
 	// ensure there are enough arguments
-	if len(remainingArgs) != function.NArgs()*Word256Length {
+	if function.hasDynamicArgs() {
+		if _, err := abi.Unpack(function.argTypes(), remainingArgs); err != nil {
+			return nil, fmt.Errorf("%s() could not decode arguments: %s",
+				function.Name, err)
+		}
+	} else if len(remainingArgs) != function.NArgs()*Word256Length {
 		return nil, fmt.Errorf("%s() takes %d arguments", function.Name,
 			function.NArgs())
 	}
@@ -324,6 +502,25 @@ func (function *SNativeFunctionDescription) NArgs() int {
 	return len(function.Args)
 }
 
+// Get the parsed ABI types of the function arguments
+func (function *SNativeFunctionDescription) argTypes() []*abi.Type {
+	typeNames := make([]abi.TypeName, len(function.Args))
+	for i, arg := range function.Args {
+		typeNames[i] = arg.TypeName
+	}
+	return abiTypes(typeNames...)
+}
+
+// Whether any argument has a dynamic ABI encoding (so is not a single word)
+func (function *SNativeFunctionDescription) hasDynamicArgs() bool {
+	for _, t := range function.argTypes() {
+		if t.IsDynamic() {
+			return true
+		}
+	}
+	return false
+}
+
 func abiArg(name string, abiTypeName abi.TypeName) abi.Arg {
 	return abi.Arg{
 		Name:     name,
@@ -367,7 +564,10 @@ func setBase(appState AppState, caller *Account, args []byte, gas *int64) (outpu
 		return nil, ptypes.ErrInvalidPermission(permN)
 	}
 	permV := !permVal.IsZero()
-	if err = vmAcc.Permissions.Base.Set(permN, permV); err != nil {
+	if err = checkNotGoverned(appState, &ptypes.SetBaseArgs{Permission: permN, Value: permV}); err != nil {
+		return nil, err
+	}
+	if err = vmAcc.Permissions.SetBaseUntil(permN, permV, 0); err != nil {
 		return nil, err
 	}
 	appState.UpdateAccount(vmAcc)
@@ -375,6 +575,32 @@ func setBase(appState AppState, caller *Account, args []byte, gas *int64) (outpu
 	return effectivePermBytes(vmAcc.Permissions.Base, globalPerms(appState)), nil
 }
 
+func setBaseUntil(appState AppState, caller *Account, args []byte, gas *int64) (output []byte, err error) {
+	addr, permNum, permVal, expiresNum := returnFourArgs(args)
+	vmAcc := appState.GetAccount(addr)
+	if vmAcc == nil {
+		return nil, fmt.Errorf("Unknown account %X", addr)
+	}
+	permN := ptypes.PermFlag(Uint64FromWord256(permNum))
+	if !ValidPermN(permN) {
+		return nil, ptypes.ErrInvalidPermission(permN)
+	}
+	permV := !permVal.IsZero()
+	if err = checkNotGoverned(appState, &ptypes.SetBaseArgs{Permission: permN, Value: permV}); err != nil {
+		return nil, err
+	}
+	expires, err := expiryFromWord256(appState, expiresNum)
+	if err != nil {
+		return nil, err
+	}
+	if err = vmAcc.Permissions.SetBaseUntil(permN, permV, expires); err != nil {
+		return nil, err
+	}
+	appState.UpdateAccount(vmAcc)
+	dbg.Printf("snative.setBasePermUntil(0x%X, %b, %v, %v)\n", addr.Postfix(20), permN, permV, expires)
+	return effectivePermBytes(vmAcc.Permissions.Base, globalPerms(appState)), nil
+}
+
 func unsetBase(appState AppState, caller *Account, args []byte, gas *int64) (output []byte, err error) {
 	addr, permNum := returnTwoArgs(args)
 	vmAcc := appState.GetAccount(addr)
@@ -385,7 +611,7 @@ func unsetBase(appState AppState, caller *Account, args []byte, gas *int64) (out
 	if !ValidPermN(permN) {
 		return nil, ptypes.ErrInvalidPermission(permN)
 	}
-	if err = vmAcc.Permissions.Base.Unset(permN); err != nil {
+	if err = vmAcc.Permissions.UnsetBase(permN); err != nil {
 		return nil, err
 	}
 	appState.UpdateAccount(vmAcc)
@@ -404,6 +630,9 @@ func setGlobal(appState AppState, caller *Account, args []byte, gas *int64) (out
 		return nil, ptypes.ErrInvalidPermission(permN)
 	}
 	permV := !permVal.IsZero()
+	if err = checkNotGoverned(appState, &ptypes.SetGlobalArgs{Permission: permN, Value: permV}); err != nil {
+		return nil, err
+	}
 	if err = vmAcc.Permissions.Base.Set(permN, permV); err != nil {
 		return nil, err
 	}
@@ -419,7 +648,8 @@ func hasRole(appState AppState, caller *Account, args []byte, gas *int64) (outpu
 		return nil, fmt.Errorf("Unknown account %X", addr)
 	}
 	roleS := string(role.Bytes())
-	permInt := byteFromBool(vmAcc.Permissions.HasRole(roleS))
+	permissions := vmAcc.Permissions.AtHeight(appState.LastBlockHeight())
+	permInt := byteFromBool(permissions.HasRoleInherited(appState, roleS))
 	dbg.Printf("snative.hasRole(0x%X, %s) = %v\n", addr.Postfix(20), roleS, permInt > 0)
 	return LeftPadWord256([]byte{permInt}).Bytes(), nil
 }
@@ -431,12 +661,35 @@ func addRole(appState AppState, caller *Account, args []byte, gas *int64) (outpu
 		return nil, fmt.Errorf("Unknown account %X", addr)
 	}
 	roleS := string(role.Bytes())
-	permInt := byteFromBool(vmAcc.Permissions.AddRole(roleS))
+	if err = checkNotGoverned(appState, &ptypes.AddRoleArgs{Role: roleS}); err != nil {
+		return nil, err
+	}
+	permInt := byteFromBool(vmAcc.Permissions.AddRoleUntil(roleS, 0))
 	appState.UpdateAccount(vmAcc)
 	dbg.Printf("snative.addRole(0x%X, %s) = %v\n", addr.Postfix(20), roleS, permInt > 0)
 	return LeftPadWord256([]byte{permInt}).Bytes(), nil
 }
 
+func addRoleUntil(appState AppState, caller *Account, args []byte, gas *int64) (output []byte, err error) {
+	addr, role, expiresNum := returnThreeArgs(args)
+	vmAcc := appState.GetAccount(addr)
+	if vmAcc == nil {
+		return nil, fmt.Errorf("Unknown account %X", addr)
+	}
+	roleS := string(role.Bytes())
+	if err = checkNotGoverned(appState, &ptypes.AddRoleArgs{Role: roleS}); err != nil {
+		return nil, err
+	}
+	expires, err := expiryFromWord256(appState, expiresNum)
+	if err != nil {
+		return nil, err
+	}
+	permInt := byteFromBool(vmAcc.Permissions.AddRoleUntil(roleS, expires))
+	appState.UpdateAccount(vmAcc)
+	dbg.Printf("snative.addRoleUntil(0x%X, %s, %v) = %v\n", addr.Postfix(20), roleS, expires, permInt > 0)
+	return LeftPadWord256([]byte{permInt}).Bytes(), nil
+}
+
 func removeRole(appState AppState, caller *Account, args []byte, gas *int64) (output []byte, err error) {
 	addr, role := returnTwoArgs(args)
 	vmAcc := appState.GetAccount(addr)
@@ -450,6 +703,160 @@ func removeRole(appState AppState, caller *Account, args []byte, gas *int64) (ou
 	return LeftPadWord256([]byte{permInt}).Bytes(), nil
 }
 
+func allowCall(appState AppState, caller *Account, args []byte, gas *int64) (output []byte, err error) {
+	addr, contract := returnTwoArgs(args)
+	vmAcc := appState.GetAccount(addr)
+	if vmAcc == nil {
+		return nil, fmt.Errorf("Unknown account %X", addr)
+	}
+	permInt := byteFromBool(vmAcc.Permissions.AllowCall(contract.Postfix(20)))
+	appState.UpdateAccount(vmAcc)
+	dbg.Printf("snative.allowCall(0x%X, 0x%X) = %v\n", addr.Postfix(20), contract.Postfix(20), permInt > 0)
+	return LeftPadWord256([]byte{permInt}).Bytes(), nil
+}
+
+func disallowCall(appState AppState, caller *Account, args []byte, gas *int64) (output []byte, err error) {
+	addr, contract := returnTwoArgs(args)
+	vmAcc := appState.GetAccount(addr)
+	if vmAcc == nil {
+		return nil, fmt.Errorf("Unknown account %X", addr)
+	}
+	permInt := byteFromBool(vmAcc.Permissions.DisallowCall(contract.Postfix(20)))
+	appState.UpdateAccount(vmAcc)
+	dbg.Printf("snative.disallowCall(0x%X, 0x%X) = %v\n", addr.Postfix(20), contract.Postfix(20), permInt > 0)
+	return LeftPadWord256([]byte{permInt}).Bytes(), nil
+}
+
+func unrestrictCalls(appState AppState, caller *Account, args []byte, gas *int64) (output []byte, err error) {
+	var addr Word256
+	copy(addr[:], args[:32])
+	vmAcc := appState.GetAccount(addr)
+	if vmAcc == nil {
+		return nil, fmt.Errorf("Unknown account %X", addr)
+	}
+	permInt := byteFromBool(vmAcc.Permissions.UnrestrictCalls())
+	appState.UpdateAccount(vmAcc)
+	dbg.Printf("snative.unrestrictCalls(0x%X) = %v\n", addr.Postfix(20), permInt > 0)
+	return LeftPadWord256([]byte{permInt}).Bytes(), nil
+}
+
+func canCall(appState AppState, caller *Account, args []byte, gas *int64) (output []byte, err error) {
+	addr, contract := returnTwoArgs(args)
+	vmAcc := appState.GetAccount(addr)
+	if vmAcc == nil {
+		return nil, fmt.Errorf("Unknown account %X", addr)
+	}
+	permInt := byteFromBool(CanCall(appState, vmAcc, contract))
+	dbg.Printf("snative.canCall(0x%X, 0x%X) = %v\n", addr.Postfix(20), contract.Postfix(20), permInt > 0)
+	return LeftPadWord256([]byte{permInt}).Bytes(), nil
+}
+
+// Name registry function definitions
+
+func getNameData(appState AppState, caller *Account, args []byte, gas *int64) (output []byte, err error) {
+	values, err := unpackArgs(args, abi.StringTypeName)
+	if err != nil {
+		return nil, err
+	}
+	name := values[0].(string)
+	var data string
+	if entry := liveNameRegEntry(appState, name); entry != nil {
+		data = entry.Data
+	}
+	dbg.Printf("snative.getNameData(%s) = %s\n", name, data)
+	return abi.Pack(abiTypes(abi.StringTypeName), []interface{}{data})
+}
+
+func getNameOwner(appState AppState, caller *Account, args []byte, gas *int64) (output []byte, err error) {
+	values, err := unpackArgs(args, abi.StringTypeName)
+	if err != nil {
+		return nil, err
+	}
+	name := values[0].(string)
+	owner := Zero256
+	if entry := liveNameRegEntry(appState, name); entry != nil {
+		owner = entry.Owner
+	}
+	dbg.Printf("snative.getNameOwner(%s) = 0x%X\n", name, owner.Postfix(20))
+	return owner.Bytes(), nil
+}
+
+func getNameExpiry(appState AppState, caller *Account, args []byte, gas *int64) (output []byte, err error) {
+	values, err := unpackArgs(args, abi.StringTypeName)
+	if err != nil {
+		return nil, err
+	}
+	name := values[0].(string)
+	var expires int64
+	if entry := liveNameRegEntry(appState, name); entry != nil {
+		expires = entry.Expires
+	}
+	dbg.Printf("snative.getNameExpiry(%s) = %v\n", name, expires)
+	return Uint64ToWord256(uint64(expires)).Bytes(), nil
+}
+
+func setNameData(appState AppState, caller *Account, args []byte, gas *int64) (output []byte, err error) {
+	values, err := unpackArgs(args, abi.StringTypeName, abi.StringTypeName)
+	if err != nil {
+		return nil, err
+	}
+	name, data := values[0].(string), values[1].(string)
+	nameRegParams := appState.NameRegParams()
+	if err = (&txs.NameTx{Name: name, Data: data}).ValidateStrings(nameRegParams); err != nil {
+		return nil, err
+	}
+	entry, err := ownedNameRegEntry(appState, caller, name)
+	if err != nil {
+		return nil, err
+	}
+	// As with NameTx the credit remaining on the name pays for the new data
+	lastBlockHeight := appState.LastBlockHeight()
+	credit := (entry.Expires - lastBlockHeight) *
+		nameRegParams.CostPerBlock(txs.NameBaseCost(entry.Name, entry.Data))
+	expiresIn := credit / nameRegParams.CostPerBlock(txs.NameBaseCost(name, data))
+	if expiresIn < int64(nameRegParams.MinRegistrationPeriod) {
+		return nil, fmt.Errorf("Names must be registered for at least %d blocks",
+			nameRegParams.MinRegistrationPeriod)
+	}
+	entry.Data = data
+	entry.Expires = lastBlockHeight + expiresIn
+	appState.UpdateNameRegEntry(entry)
+	fireNameRegEvent(appState, entry)
+	dbg.Printf("snative.setNameData(%s, %s) = %v\n", name, data, entry.Expires)
+	return Uint64ToWord256(uint64(entry.Expires)).Bytes(), nil
+}
+
+func transferName(appState AppState, caller *Account, args []byte, gas *int64) (output []byte, err error) {
+	values, err := unpackArgs(args, abi.StringTypeName, abi.AddressTypeName)
+	if err != nil {
+		return nil, err
+	}
+	name, owner := values[0].(string), values[1].(abi.Address)
+	if owner == (abi.Address{}) {
+		return nil, fmt.Errorf("Cannot transfer name %s to the zero address", name)
+	}
+	entry, err := ownedNameRegEntry(appState, caller, name)
+	if err != nil {
+		return nil, err
+	}
+	entry.Owner = LeftPadWord256(owner[:])
+	appState.UpdateNameRegEntry(entry)
+	fireNameRegEvent(appState, entry)
+	dbg.Printf("snative.transferName(%s, 0x%X)\n", name, owner)
+	return LeftPadWord256([]byte{byteFromBool(true)}).Bytes(), nil
+}
+
+// Fire the NameReg event NameTx and NameTransferTx fire for the entry, when the
+// VM has given us an AppState that can fire events (see firingAppState)
+func fireNameRegEvent(appState AppState, entry *NameRegEntry) {
+	if evc, ok := appState.(events.Fireable); ok {
+		evc.FireEvent(txs.EventStringNameReg(entry.Name), txs.EventDataNameReg{
+			Name:  entry.Name,
+			Owner: entry.Owner.Postfix(20),
+		})
+	}
+}
+
 //------------------------------------------------------------------------------------------------
 // Errors and utility funcs
 
@@ -467,6 +874,42 @@ func ValidPermN(n ptypes.PermFlag) bool {
 	return n <= ptypes.TopPermFlag
 }
 
+// Whether function adds or removes a member of a role whose admin role the
+// caller is a member of, which it may do without the AddRole or RmRole
+// permission
+func isRoleAdminCall(appState AppState, caller *Account,
+	function *SNativeFunctionDescription, args []byte) bool {
+	if function.PermFlag != ptypes.AddRole && function.PermFlag != ptypes.RmRole {
+		return false
+	}
+	if len(args) != function.NArgs()*Word256Length {
+		return false
+	}
+	_, role := returnTwoArgs(args)
+	permissions := caller.Permissions.AtHeight(appState.LastBlockHeight())
+	return permissions.IsRoleAdmin(appState, string(role.Bytes()))
+}
+
+// Changes governed by the chain's GovernanceParams may only be made by a
+// PermissionsTx proposal approved by enough accounts, not from contracts
+func checkNotGoverned(appState AppState, args ptypes.PermArgs) error {
+	if appState.GovernanceParams().Enabled() && ptypes.Governs(appState, args) {
+		return fmt.Errorf("%v needs governance approval so must be proposed by a PermissionsTx", args)
+	}
+	return nil
+}
+
+// Read an expiry block height argument, which must be 0 (for no expiry) or
+// after the last block height
+func expiryFromWord256(appState AppState, expiresNum Word256) (int64, error) {
+	expires := int64(Uint64FromWord256(expiresNum))
+	if expires < 0 || expires != 0 && expires <= appState.LastBlockHeight() {
+		return 0, fmt.Errorf("Expiry %v is not after the last block height %v",
+			expires, appState.LastBlockHeight())
+	}
+	return expires, nil
+}
+
 // Get the global BasePermissions
 func globalPerms(appState AppState) ptypes.BasePermissions {
 	vmAcc := appState.GetAccount(ptypes.GlobalPermissionsAddress256)
@@ -476,6 +919,30 @@ func globalPerms(appState AppState) ptypes.BasePermissions {
 	return vmAcc.Permissions.Base
 }
 
+// Get the entry registered under name unless it has expired (in which case it
+// is free to be claimed by anyone with a NameTx)
+func liveNameRegEntry(appState AppState, name string) *NameRegEntry {
+	entry := appState.GetNameRegEntry(name)
+	if entry == nil || entry.Expires <= appState.LastBlockHeight() {
+		return nil
+	}
+	return entry
+}
+
+// Get the live entry registered under name ensuring it is owned by caller
+func ownedNameRegEntry(appState AppState, caller *Account,
+	name string) (*NameRegEntry, error) {
+	entry := liveNameRegEntry(appState, name)
+	if entry == nil {
+		return nil, fmt.Errorf("Name %s is not registered or has expired", name)
+	}
+	if entry.Owner != caller.Address {
+		return nil, fmt.Errorf("Account %X does not own name %s",
+			caller.Address.Postfix(20), name)
+	}
+	return entry, nil
+}
+
 // Compute the effective permissions from an Account's BasePermissions by
 // taking the bitwise or with the global BasePermissions resultant permissions
 func effectivePermBytes(basePerms ptypes.BasePermissions,
@@ -502,6 +969,27 @@ func returnThreeArgs(args []byte) (a Word256, b Word256, c Word256) {
 	return
 }
 
+// CONTRACT: length has already been checked
+func returnFourArgs(args []byte) (a Word256, b Word256, c Word256, d Word256) {
+	copy(a[:], args[:32])
+	copy(b[:], args[32:64])
+	copy(c[:], args[64:96])
+	copy(d[:], args[96:128])
+	return
+}
+
+func abiTypes(typeNames ...abi.TypeName) []*abi.Type {
+	types := make([]*abi.Type, len(typeNames))
+	for i, typeName := range typeNames {
+		types[i] = abi.MustParseType(string(typeName))
+	}
+	return types
+}
+
+func unpackArgs(args []byte, typeNames ...abi.TypeName) ([]interface{}, error) {
+	return abi.Unpack(abiTypes(typeNames...), args)
+}
+
 func byteFromBool(b bool) byte {
 	if b {
 		return 0x1
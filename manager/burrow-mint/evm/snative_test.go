@@ -24,8 +24,10 @@ import (
 	. "github.com/hyperledger/burrow/manager/burrow-mint/evm/opcodes"
 	"github.com/hyperledger/burrow/manager/burrow-mint/evm/sha3"
 	ptypes "github.com/hyperledger/burrow/permission/types"
+	"github.com/hyperledger/burrow/txs"
 	. "github.com/hyperledger/burrow/word256"
 	"github.com/stretchr/testify/assert"
+	"github.com/tendermint/go-events"
 )
 
 // Compiling the Permissions solidity contract at
@@ -35,11 +37,17 @@ import (
 // Keep this updated to drive TestPermissionsContractSignatures
 const compiledSigs = `
 a73f7f8a addRole(address,bytes32)
+dfeb3b97 addRoleUntil(address,bytes32,uint64)
+dcc082bc allowCall(address,address)
+a17c4ac2 canCall(address,address)
+3086f606 disallowCall(address,address)
 225b6574 hasBase(address,uint64)
 ac4ab3fb hasRole(address,bytes32)
 6853920e removeRole(address,bytes32)
 dbd4a8ea setBase(address,uint64,bool)
+8c2efff3 setBaseUntil(address,uint64,bool,uint64)
 c4bc7b70 setGlobal(uint64,bool)
+5cdd1a43 unrestrictCalls(address)
 b7d4dc0d unsetBase(address,uint64)
 `
 
@@ -94,6 +102,204 @@ func TestSNativeContractDescription_Dispatch(t *testing.T) {
 	assert.Equal(t, retValue, LeftPadBytes([]byte{1}, 32))
 }
 
+func TestPermissionsContract_Expiry(t *testing.T) {
+	contract := SNativeContracts()["Permissions"]
+	state := newAppState()
+	caller := &Account{
+		Address:     addr(1, 1, 1),
+		Permissions: allAccountPermissions(),
+	}
+	grantee := &Account{
+		Address: addr(2, 2, 2),
+	}
+	state.UpdateAccount(grantee)
+	state.blockHeight = 10
+	role := RightPadWord256([]byte("contractor"))
+	gas := int64(1000)
+
+	// Expiries must be after the last block height
+	_, err := contract.Dispatch(state, caller, permissionsInput(t, contract, "addRoleUntil",
+		grantee.Address, role, Int64ToWord256(10)), &gas)
+	assert.Error(t, err)
+	_, err = contract.Dispatch(state, caller, permissionsInput(t, contract, "addRoleUntil",
+		grantee.Address, role, Int64ToWord256(20)), &gas)
+	assert.NoError(t, err)
+	_, err = contract.Dispatch(state, caller, permissionsInput(t, contract, "setBaseUntil",
+		grantee.Address, permFlagToWord256(ptypes.SetGlobal), Int64ToWord256(1), Int64ToWord256(20)), &gas)
+	assert.NoError(t, err)
+
+	// Grants hold for blocks up to and including their expiry height
+	state.blockHeight = 19
+	retValue, err := contract.Dispatch(state, caller, permissionsInput(t, contract, "hasRole",
+		grantee.Address, role), &gas)
+	assert.NoError(t, err)
+	assert.Equal(t, LeftPadBytes([]byte{1}, 32), retValue)
+	retValue, err = contract.Dispatch(state, caller, permissionsInput(t, contract, "hasBase",
+		grantee.Address, permFlagToWord256(ptypes.SetGlobal)), &gas)
+	assert.NoError(t, err)
+	assert.Equal(t, LeftPadBytes([]byte{1}, 32), retValue)
+
+	state.blockHeight = 20
+	retValue, err = contract.Dispatch(state, caller, permissionsInput(t, contract, "hasRole",
+		grantee.Address, role), &gas)
+	assert.NoError(t, err)
+	assert.Equal(t, LeftPadBytes([]byte{0}, 32), retValue)
+	retValue, err = contract.Dispatch(state, caller, permissionsInput(t, contract, "hasBase",
+		grantee.Address, permFlagToWord256(ptypes.SetGlobal)), &gas)
+	assert.NoError(t, err)
+	assert.Equal(t, LeftPadBytes([]byte{0}, 32), retValue)
+}
+
+func TestPermissionsContract_Governance(t *testing.T) {
+	contract := SNativeContracts()["Permissions"]
+	state := newAppState()
+	state.governance = &ptypes.GovernanceParams{Threshold: 2, ProposalExpiry: 10}
+	caller := &Account{
+		Address:     addr(1, 1, 1),
+		Permissions: allAccountPermissions(),
+	}
+	grantee := &Account{
+		Address: addr(2, 2, 2),
+	}
+	state.UpdateAccount(grantee)
+	gas := int64(1000)
+
+	// Governed changes must be proposed by a PermissionsTx
+	_, err := contract.Dispatch(state, caller, permissionsInput(t, contract, "setGlobal",
+		permFlagToWord256(ptypes.Send), Int64ToWord256(0)), &gas)
+	assert.Error(t, err)
+	_, err = contract.Dispatch(state, caller, permissionsInput(t, contract, "setBase",
+		grantee.Address, permFlagToWord256(ptypes.Root), Int64ToWord256(1)), &gas)
+	assert.Error(t, err)
+	_, err = contract.Dispatch(state, caller, permissionsInput(t, contract, "setBaseUntil",
+		grantee.Address, permFlagToWord256(ptypes.Govern), Int64ToWord256(1), Int64ToWord256(20)), &gas)
+	assert.Error(t, err)
+	// as must membership of roles granting Root, themselves or by inheritance
+	admins := (&ptypes.Role{Name: "admins", Base: ptypes.ZeroBasePermissions}).Normalise()
+	admins.Base.Set(ptypes.Root, true)
+	leads := (&ptypes.Role{Name: "leads", Inherits: []string{"admins"}}).Normalise()
+	state.roles[admins.Name] = admins
+	state.roles[leads.Name] = leads
+	_, err = contract.Dispatch(state, caller, permissionsInput(t, contract, "addRole",
+		grantee.Address, RightPadWord256([]byte("admins"))), &gas)
+	assert.Error(t, err)
+	_, err = contract.Dispatch(state, caller, permissionsInput(t, contract, "addRoleUntil",
+		grantee.Address, RightPadWord256([]byte("leads")), Int64ToWord256(20)), &gas)
+	assert.Error(t, err)
+
+	// whereas other changes may still be made from contracts
+	_, err = contract.Dispatch(state, caller, permissionsInput(t, contract, "setBase",
+		grantee.Address, permFlagToWord256(ptypes.Root), Int64ToWord256(0)), &gas)
+	assert.NoError(t, err)
+	_, err = contract.Dispatch(state, caller, permissionsInput(t, contract, "setBase",
+		grantee.Address, permFlagToWord256(ptypes.Call), Int64ToWord256(1)), &gas)
+	assert.NoError(t, err)
+	_, err = contract.Dispatch(state, caller, permissionsInput(t, contract, "addRole",
+		grantee.Address, RightPadWord256([]byte("users"))), &gas)
+	assert.NoError(t, err)
+}
+
+func TestNameRegContract_Dispatch(t *testing.T) {
+	contract := SNativeContracts()["NameReg"]
+	state := newAppState()
+	owner := &Account{
+		Address:     addr(1, 1, 1),
+		Permissions: allAccountPermissions(),
+	}
+	other := &Account{
+		Address:     addr(2, 2, 2),
+		Permissions: allAccountPermissions(),
+	}
+	state.blockHeight = 10
+	state.UpdateNameRegEntry(&NameRegEntry{
+		Name:    "name",
+		Owner:   owner.Address,
+		Data:    "data",
+		Expires: 20,
+	})
+	gas := int64(1000)
+
+	// Dynamic arguments are validated by dispatch
+	getData, err := contract.FunctionByName("getData")
+	if err != nil {
+		t.Fatalf("Could not get function: %s", err)
+	}
+	funcID := getData.ID()
+	_, err = contract.Dispatch(state, owner, Bytecode(funcID[:], Int64ToWord256(32)), &gas)
+	assert.Error(t, err)
+
+	retValue, err := contract.Dispatch(state, other, nameRegInput(t, contract, "getData", "name"), &gas)
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{"data"}, unpackString(t, retValue))
+
+	// Only the owner may set data
+	_, err = contract.Dispatch(state, other, nameRegInput(t, contract, "setData", "name", "other"), &gas)
+	assert.Error(t, err)
+	retValue, err = contract.Dispatch(state, owner, nameRegInput(t, contract, "setData", "name", "new"), &gas)
+	assert.NoError(t, err)
+	// 10 blocks of credit for 4 bytes of data buys 10 blocks of 3 bytes
+	assert.Equal(t, Uint64ToWord256(20).Bytes(), retValue)
+	assert.Equal(t, "new", state.GetNameRegEntry("name").Data)
+
+	// Only the owner may transfer
+	_, err = contract.Dispatch(state, other, nameRegInput(t, contract, "transfer", "name", other.Address.Postfix(20)), &gas)
+	assert.Error(t, err)
+	_, err = contract.Dispatch(state, owner, nameRegInput(t, contract, "transfer", "name", other.Address.Postfix(20)), &gas)
+	assert.NoError(t, err)
+	retValue, err = contract.Dispatch(state, owner, nameRegInput(t, contract, "getOwner", "name"), &gas)
+	assert.NoError(t, err)
+	assert.Equal(t, other.Address.Bytes(), retValue)
+
+	// Expired names read as zero values and cannot be changed
+	state.blockHeight = 21
+	retValue, err = contract.Dispatch(state, other, nameRegInput(t, contract, "getExpiry", "name"), &gas)
+	assert.NoError(t, err)
+	assert.Equal(t, Zero256.Bytes(), retValue)
+	_, err = contract.Dispatch(state, other, nameRegInput(t, contract, "setData", "name", "data"), &gas)
+	assert.Error(t, err)
+}
+
+func TestNameRegContractEvents(t *testing.T) {
+	contract := SNativeContracts()["NameReg"]
+	state := newAppState()
+	owner := &Account{Address: addr(1, 1, 1), Permissions: allAccountPermissions()}
+	other := &Account{Address: addr(2, 2, 2), Permissions: allAccountPermissions()}
+	state.blockHeight = 10
+	state.UpdateNameRegEntry(&NameRegEntry{
+		Name:    "name",
+		Owner:   owner.Address,
+		Data:    "data",
+		Expires: 20,
+	})
+	evc := &fireableFake{}
+	appState := firingAppState{state, evc}
+	gas := int64(1000)
+
+	// Changes fire NameReg events as NameTx and NameTransferTx do
+	_, err := contract.Dispatch(appState, owner, nameRegInput(t, contract, "setData", "name", "new"), &gas)
+	assert.NoError(t, err)
+	_, err = contract.Dispatch(appState, owner, nameRegInput(t, contract, "transfer", "name", other.Address.Postfix(20)), &gas)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{txs.EventStringNameReg("name"), txs.EventStringNameReg("name")},
+		evc.eventIDs)
+	assert.Equal(t, []events.EventData{
+		txs.EventDataNameReg{Name: "name", Owner: owner.Address.Postfix(20)},
+		txs.EventDataNameReg{Name: "name", Owner: other.Address.Postfix(20)},
+	}, evc.eventData)
+
+	// but reads and failed changes do not
+	_, err = contract.Dispatch(appState, other, nameRegInput(t, contract, "getData", "name"), &gas)
+	assert.NoError(t, err)
+	_, err = contract.Dispatch(appState, owner, nameRegInput(t, contract, "setData", "name", "other"), &gas)
+	assert.Error(t, err)
+	assert.Len(t, evc.eventIDs, 2)
+
+	// An AppState that cannot fire events is left alone
+	_, err = contract.Dispatch(state, other, nameRegInput(t, contract, "setData", "name", "other"), &gas)
+	assert.NoError(t, err)
+	assert.Len(t, evc.eventIDs, 2)
+}
+
 func TestSNativeContractDescription_Address(t *testing.T) {
 	contract := NewSNativeContract("A comment",
 		"CoolButVeryLongNamedContractOfDoom")
@@ -103,6 +309,18 @@ func TestSNativeContractDescription_Address(t *testing.T) {
 //
 // Helpers
 //
+
+// Records the events fired through it
+type fireableFake struct {
+	eventIDs  []string
+	eventData []events.EventData
+}
+
+func (fake *fireableFake) FireEvent(eventID string, data events.EventData) {
+	fake.eventIDs = append(fake.eventIDs, eventID)
+	fake.eventData = append(fake.eventData, data)
+}
+
 func assertFunctionIDSignature(t *testing.T, contract *SNativeContractDescription,
 	funcIDHex string, expectedSignature string) {
 	function, err := contract.FunctionByID(funcIDFromHex(t, funcIDHex))
@@ -123,6 +341,42 @@ func funcIDFromHex(t *testing.T, hexString string) abi.FunctionSelector {
 	return firstFourBytes(bs)
 }
 
+func permissionsInput(t *testing.T, contract *SNativeContractDescription, name string,
+	args ...Word256) []byte {
+	function, err := contract.FunctionByName(name)
+	if err != nil {
+		t.Fatalf("Could not get function: %s", err)
+	}
+	funcID := function.ID()
+	input := funcID[:]
+	for _, arg := range args {
+		input = append(input, arg.Bytes()...)
+	}
+	return input
+}
+
+func nameRegInput(t *testing.T, contract *SNativeContractDescription, name string,
+	args ...interface{}) []byte {
+	function, err := contract.FunctionByName(name)
+	if err != nil {
+		t.Fatalf("Could not get function: %s", err)
+	}
+	packed, err := abi.Pack(function.argTypes(), args)
+	if err != nil {
+		t.Fatalf("Could not pack arguments: %s", err)
+	}
+	funcID := function.ID()
+	return append(funcID[:], packed...)
+}
+
+func unpackString(t *testing.T, bs []byte) []interface{} {
+	values, err := unpackArgs(bs, abi.StringTypeName)
+	if err != nil {
+		t.Fatalf("Could not unpack string: %s", err)
+	}
+	return values
+}
+
 func permFlagToWord256(permFlag ptypes.PermFlag) Word256 {
 	return Uint64ToWord256(uint64(permFlag))
 }
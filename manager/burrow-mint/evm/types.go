@@ -18,6 +18,7 @@ import (
 	"fmt"
 
 	ptypes "github.com/hyperledger/burrow/permission/types"
+	"github.com/hyperledger/burrow/txs"
 	. "github.com/hyperledger/burrow/word256"
 )
 
@@ -55,6 +56,27 @@ type AppState interface {
 	GetStorage(Word256, Word256) Word256
 	SetStorage(Word256, Word256, Word256) // Setting to Zero is deleting.
 
+	// Name registry
+	GetNameRegEntry(name string) *NameRegEntry // Includes expired entries
+	UpdateNameRegEntry(*NameRegEntry)
+
+	// Height of the last committed block, against which name registry
+	// expiries are compared
+	LastBlockHeight() int64
+	// Pricing and data validation of the chain's name registry
+	NameRegParams() txs.NameRegParams
+
+	// Roles, which are defined by PermissionsTx so only read here
+	GetRole(name string) *ptypes.Role
+	// Approval of governed permission changes, nil if governance is disabled
+	GovernanceParams() *ptypes.GovernanceParams
+}
+
+type NameRegEntry struct {
+	Name    string
+	Owner   Word256
+	Data    string
+	Expires int64 // Block at which this entry expires
 }
 
 type Params struct {
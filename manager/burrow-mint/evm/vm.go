@@ -106,20 +106,47 @@ func (vm *VM) SetFireable(evc events.Fireable) {
 // we do not convey if a permission is not set
 // (unlike in state/execution, where we guarantee HasPermission is called
 // on known permissions and panics else)
-// If the perm is not defined in the acc nor set by default in GlobalPermissions,
-// this function returns false.
+// If the perm is not defined in the acc, nor by its roles, nor set by default
+// in GlobalPermissions, this function returns false.
 func HasPermission(appState AppState, acc *Account, perm ptypes.PermFlag) bool {
-	v, err := acc.Permissions.Base.Get(perm)
+	permissions := &acc.Permissions
+	if appState != nil {
+		permissions = permissions.AtHeight(appState.LastBlockHeight())
+	}
+	v, err := permissions.Base.Get(perm)
 	if _, ok := err.(ptypes.ErrValueNotSet); ok {
 		if appState == nil {
 			// In this case the permission is unknown
 			return false
 		}
-		return HasPermission(nil, appState.GetAccount(ptypes.GlobalPermissionsAddress256), perm)
+		v, err = permissions.GetRolePermission(appState, perm)
+		if _, ok := err.(ptypes.ErrValueNotSet); ok {
+			return HasPermission(nil, appState.GetAccount(ptypes.GlobalPermissionsAddress256), perm)
+		}
 	}
 	return v
 }
 
+// Whether acc may call the contract at address given the CallAllowLists of the
+// account and its roles. Native contracts are not subject to CallAllowLists
+// (SNatives are guarded by their own permissions). The VM checks both the
+// calling contract and the origin of the transaction against the contract
+// called.
+func CanCall(appState AppState, acc *Account, address Word256) bool {
+	if registeredNativeContracts[address] != nil {
+		return true
+	}
+	permissions := acc.Permissions.AtHeight(appState.LastBlockHeight())
+	return permissions.CanCall(appState, address.Postfix(20))
+}
+
+// The AppState native contracts run on, which can also fire the events of the
+// VM so SNatives may fire the events of the transactions they stand in for
+type firingAppState struct {
+	AppState
+	events.Fireable
+}
+
 func (vm *VM) fireCallEvent(exception *string, output *[]byte, caller, callee *Account, input []byte, value int64, gas *int64) {
 	// fire the post call event (including exception if applicable)
 	if vm.evc != nil {
@@ -824,6 +851,16 @@ func (vm *VM) call(caller, callee *Account, code, input []byte, value int64, gas
 			inOffset, inSize := stack.Pop64(), stack.Pop64()   // inputs
 			retOffset, retSize := stack.Pop64(), stack.Pop64() // outputs
 			dbg.Printf(" => %X\n", addr)
+			if !CanCall(vm.appState, callee, addr) {
+				return nil, ErrPermission{fmt.Sprintf("call %X", addr.Postfix(20))}
+			}
+			// The allow lists of the origin bind every call it makes, so it cannot
+			// reach other contracts through ones it may call
+			if origin := vm.appState.GetAccount(vm.origin); origin != nil &&
+				!CanCall(vm.appState, origin, addr) {
+				return nil, ErrPermission{fmt.Sprintf("call %X from origin %X",
+					addr.Postfix(20), vm.origin.Postfix(20))}
+			}
 
 			// Get the arguments from the memory
 			args, memErr := memory.Read(inOffset, inSize)
@@ -845,7 +882,11 @@ func (vm *VM) call(caller, callee *Account, code, input []byte, value int64, gas
 			var err error
 			if nativeContract := registeredNativeContracts[addr]; nativeContract != nil {
 				// Native contract
-				ret, err = nativeContract(vm.appState, callee, args, &gasLimit)
+				var appState AppState = vm.appState
+				if vm.evc != nil {
+					appState = firingAppState{vm.appState, vm.evc}
+				}
+				ret, err = nativeContract(appState, callee, args, &gasLimit)
 
 				// for now we fire the Call event. maybe later we'll fire more particulars
 				var exception string
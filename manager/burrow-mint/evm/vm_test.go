@@ -40,6 +40,8 @@ func newAppState() *FakeAppState {
 	fas := &FakeAppState{
 		accounts: make(map[string]*Account),
 		storage:  make(map[string]Word256),
+		names:    make(map[string]*NameRegEntry),
+		roles:    make(map[string]*ptypes.Role),
 	}
 	// For default permissions
 	fas.accounts[ptypes.GlobalPermissionsAddress256.String()] = &Account{
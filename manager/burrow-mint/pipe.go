@@ -39,6 +39,7 @@ import (
 	vm "github.com/hyperledger/burrow/manager/burrow-mint/evm"
 	"github.com/hyperledger/burrow/manager/burrow-mint/state"
 	manager_types "github.com/hyperledger/burrow/manager/types"
+	ptypes "github.com/hyperledger/burrow/permission/types"
 	rpc_tm_types "github.com/hyperledger/burrow/rpc/tendermint/core/types"
 	"github.com/hyperledger/burrow/txs"
 	"github.com/hyperledger/burrow/word256"
@@ -75,6 +76,11 @@ func NewBurrowMintPipe(moduleConfig *config.ModuleConfig,
 	if err != nil {
 		return nil, fmt.Errorf("Failed to start state: %v", err)
 	}
+	// the state as it was at genesis, kept apart from the state we run on
+	genesisState, err := state.MakeGenesisState(db.NewMemDB(), genesisDoc)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to make genesis state: %v", err)
+	}
 	logger = logging.WithScope(logger, "BurrowMintPipe")
 	// assert ChainId matches genesis ChainId
 	logging.InfoMsg(logger, "Loaded state",
@@ -100,7 +106,7 @@ func NewBurrowMintPipe(moduleConfig *config.ModuleConfig,
 		transactor: nil,
 		// genesis cache
 		genesisDoc:   genesisDoc,
-		genesisState: nil,
+		genesisState: genesisState,
 		// consensus and blockchain should both be loaded into the pipe by a higher
 		// authority - this is a sort of dependency injection pattern
 		consensusEngine: nil,
@@ -125,6 +131,9 @@ func NewBurrowMintPipe(moduleConfig *config.ModuleConfig,
 		})
 
 	pipe.transactor = transactor
+	// so operators can compare genesis out-of-band, see burrow-client genesis verify
+	logging.InfoMsg(logger, "Loaded genesis",
+		"genesisHash", fmt.Sprintf("%X", pipe.GenesisHash()))
 	return pipe, nil
 }
 
@@ -149,7 +158,12 @@ func startState(dataDir, backend, genesisFile, chainId string) (*state.State,
 	newState := state.LoadState(stateDB)
 	var genesisDoc *genesis.GenesisDoc
 	if newState == nil {
-		genesisDoc, newState = state.MakeGenesisStateFromFile(stateDB, genesisFile)
+		var genesisErr error
+		genesisDoc, newState, genesisErr = state.MakeGenesisStateFromFile(stateDB, genesisFile)
+		if genesisErr != nil {
+			return nil, nil, fmt.Errorf("Unable to make genesis state from %s: %v",
+				genesisFile, genesisErr)
+		}
 		newState.Save()
 		buf, n, err := new(bytes.Buffer), new(int), new(error)
 		wire.WriteJSON(genesisDoc, buf, n, err)
@@ -169,6 +183,11 @@ func startState(dataDir, backend, genesisFile, chainId string) (*state.State,
 			return nil, nil, fmt.Errorf("ChainId (%s) loaded from genesis document in existing database does not match"+
 				" configuration chainId (%s).", genesisDoc.ChainID, chainId)
 		}
+		if genesisDoc.Params != nil {
+			newState.AddressScheme = genesisDoc.Params.AddressScheme
+			newState.GovernanceParams = genesisDoc.Params.GovernanceParams()
+		}
+		newState.NameRegParams = genesisDoc.Params.NameRegParams()
 	}
 
 	return newState, genesisDoc, nil
@@ -276,10 +295,6 @@ func (pipe *burrowMintPipe) Unsubscribe(subscriptionId string) (*rpc_tm_types.Re
 	return &rpc_tm_types.ResultUnsubscribe{SubscriptionId: subscriptionId}, nil
 }
 func (pipe *burrowMintPipe) GenesisState() *state.State {
-	if pipe.genesisState == nil {
-		memoryDatabase := db.NewMemDB()
-		pipe.genesisState = state.MakeGenesisState(memoryDatabase, pipe.genesisDoc)
-	}
 	return pipe.genesisState
 }
 
@@ -537,6 +552,28 @@ func (pipe *burrowMintPipe) ListNames() (*rpc_tm_types.ResultListNames, error) {
 	return &rpc_tm_types.ResultListNames{blockHeight, names}, nil
 }
 
+// Governance proposals
+func (pipe *burrowMintPipe) GetProposal(hash []byte) (*rpc_tm_types.ResultGetProposal, error) {
+	currentState := pipe.burrowMint.GetState()
+	proposal := currentState.GetProposal(hash)
+	if proposal == nil {
+		return nil, fmt.Errorf("Proposal %X not found", hash)
+	}
+	status := proposal.Status(int64(currentState.LastBlockHeight))
+	return &rpc_tm_types.ResultGetProposal{proposal, status}, nil
+}
+
+func (pipe *burrowMintPipe) ListProposals() (*rpc_tm_types.ResultListProposals, error) {
+	var proposals []*ptypes.Proposal
+	currentState := pipe.burrowMint.GetState()
+	blockHeight := currentState.LastBlockHeight
+	currentState.GetProposals().Iterate(func(key []byte, value []byte) bool {
+		proposals = append(proposals, state.DecodeProposal(value))
+		return false
+	})
+	return &rpc_tm_types.ResultListProposals{blockHeight, proposals}, nil
+}
+
 func (pipe *burrowMintPipe) broadcastTx(tx txs.Tx,
 	callback func(res *abci_types.Response)) (*rpc_tm_types.ResultBroadcastTx, error) {
 
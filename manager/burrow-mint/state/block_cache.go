@@ -22,6 +22,7 @@ import (
 	acm "github.com/hyperledger/burrow/account"
 	"github.com/hyperledger/burrow/common/sanity"
 	core_types "github.com/hyperledger/burrow/core/types"
+	ptypes "github.com/hyperledger/burrow/permission/types"
 	. "github.com/hyperledger/burrow/word256"
 
 	dbm "github.com/tendermint/go-db"
@@ -36,20 +37,24 @@ func makeStorage(db dbm.DB, root []byte) merkle.Tree {
 
 // The blockcache helps prevent unnecessary IAVLTree updates and garbage generation.
 type BlockCache struct {
-	db       dbm.DB
-	backend  *State
-	accounts map[string]accountInfo
-	storages map[Tuple256]storageInfo
-	names    map[string]nameInfo
+	db        dbm.DB
+	backend   *State
+	accounts  map[string]accountInfo
+	storages  map[Tuple256]storageInfo
+	names     map[string]nameInfo
+	roles     map[string]roleInfo
+	proposals map[string]proposalInfo
 }
 
 func NewBlockCache(backend *State) *BlockCache {
 	return &BlockCache{
-		db:       backend.DB,
-		backend:  backend,
-		accounts: make(map[string]accountInfo),
-		storages: make(map[Tuple256]storageInfo),
-		names:    make(map[string]nameInfo),
+		db:        backend.DB,
+		backend:   backend,
+		accounts:  make(map[string]accountInfo),
+		storages:  make(map[Tuple256]storageInfo),
+		names:     make(map[string]nameInfo),
+		roles:     make(map[string]roleInfo),
+		proposals: make(map[string]proposalInfo),
 	}
 }
 
@@ -57,6 +62,10 @@ func (cache *BlockCache) State() *State {
 	return cache.backend
 }
 
+func (cache *BlockCache) LastBlockHeight() int64 {
+	return int64(cache.backend.LastBlockHeight)
+}
+
 //-------------------------------------
 // BlockCache.account
 
@@ -164,6 +173,43 @@ func (cache *BlockCache) RemoveNameRegEntry(name string) {
 
 // BlockCache.names
 //-------------------------------------
+// BlockCache.roles
+
+// Implements ptypes.RoleGetter
+func (cache *BlockCache) GetRole(name string) *ptypes.Role {
+	name = ptypes.RoleName(name)
+	if rInfo, ok := cache.roles[name]; ok {
+		return rInfo.role
+	}
+	role := cache.backend.GetRole(name)
+	cache.roles[name] = roleInfo{role, false}
+	return role
+}
+
+func (cache *BlockCache) UpdateRole(role *ptypes.Role) {
+	role = role.Normalise()
+	cache.roles[role.Name] = roleInfo{role, true}
+}
+
+// BlockCache.roles
+//-------------------------------------
+// BlockCache.proposals
+
+func (cache *BlockCache) GetProposal(hash []byte) *ptypes.Proposal {
+	if pInfo, ok := cache.proposals[string(hash)]; ok {
+		return pInfo.proposal
+	}
+	proposal := cache.backend.GetProposal(hash)
+	cache.proposals[string(hash)] = proposalInfo{proposal, false}
+	return proposal
+}
+
+func (cache *BlockCache) UpdateProposal(proposal *ptypes.Proposal) {
+	cache.proposals[string(proposal.Hash)] = proposalInfo{proposal, true}
+}
+
+// BlockCache.proposals
+//-------------------------------------
 
 // CONTRACT the updates are in deterministic order.
 func (cache *BlockCache) Sync() {
@@ -269,6 +315,36 @@ func (cache *BlockCache) Sync() {
 		}
 	}
 
+	// Determine order for roles
+	roleStrs := []string{}
+	for roleStr := range cache.roles {
+		roleStrs = append(roleStrs, roleStr)
+	}
+	sort.Strings(roleStrs)
+
+	// Update roles
+	for _, roleStr := range roleStrs {
+		role, dirty := cache.roles[roleStr].unpack()
+		if dirty {
+			cache.backend.UpdateRole(role)
+		}
+	}
+
+	// Determine order for proposals
+	proposalStrs := []string{}
+	for proposalStr := range cache.proposals {
+		proposalStrs = append(proposalStrs, proposalStr)
+	}
+	sort.Strings(proposalStrs)
+
+	// Update proposals
+	for _, proposalStr := range proposalStrs {
+		proposal, dirty := cache.proposals[proposalStr].unpack()
+		if dirty {
+			cache.backend.UpdateProposal(proposal)
+		}
+	}
+
 }
 
 //-----------------------------------------------------------------------------
@@ -302,3 +378,21 @@ type nameInfo struct {
 func (nInfo nameInfo) unpack() (*core_types.NameRegEntry, bool, bool) {
 	return nInfo.name, nInfo.removed, nInfo.dirty
 }
+
+type roleInfo struct {
+	role  *ptypes.Role
+	dirty bool
+}
+
+func (rInfo roleInfo) unpack() (*ptypes.Role, bool) {
+	return rInfo.role, rInfo.dirty
+}
+
+type proposalInfo struct {
+	proposal *ptypes.Proposal
+	dirty    bool
+}
+
+func (pInfo proposalInfo) unpack() (*ptypes.Proposal, bool) {
+	return pInfo.proposal, pInfo.dirty
+}
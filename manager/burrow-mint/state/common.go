@@ -17,6 +17,7 @@ package state
 import (
 	acm "github.com/hyperledger/burrow/account"
 	"github.com/hyperledger/burrow/manager/burrow-mint/evm"
+	ptypes "github.com/hyperledger/burrow/permission/types"
 	. "github.com/hyperledger/burrow/word256"
 )
 
@@ -24,6 +25,15 @@ type AccountGetter interface {
 	GetAccount(addr []byte) *acm.Account
 }
 
+// Gets what is needed to resolve the permissions of an account
+type PermissionsGetter interface {
+	AccountGetter
+	ptypes.RoleGetter
+	// Height of the last committed block, against which the expiry of
+	// permissions and roles granted until a block height is compared
+	LastBlockHeight() int64
+}
+
 type VMAccountState interface {
 	GetAccount(addr Word256) *vm.Account
 	UpdateAccount(acc *vm.Account)
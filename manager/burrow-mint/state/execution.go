@@ -164,7 +164,8 @@ func execBlock(s *State, block *txs.Block, blockPartsHeader txs.PartSetHeader) e
 // acm.PubKey.(type) != nil, (it must be known),
 // or it must be specified in the TxInput.  If redeclared,
 // the TxInput is modified and input.PubKey set to nil.
-func getInputs(state AccountGetter, ins []*txs.TxInput) (map[string]*acm.Account, error) {
+func getInputs(state AccountGetter, addressScheme string,
+	ins []*txs.TxInput) (map[string]*acm.Account, error) {
 	accounts := map[string]*acm.Account{}
 	for _, in := range ins {
 		// Account shouldn't be duplicated
@@ -176,7 +177,7 @@ func getInputs(state AccountGetter, ins []*txs.TxInput) (map[string]*acm.Account
 			return nil, txs.ErrTxInvalidAddress
 		}
 		// PubKey should be present in either "account" or "in"
-		if err := checkInputPubKey(acc, in); err != nil {
+		if err := checkInputPubKey(addressScheme, acc, in); err != nil {
 			return nil, err
 		}
 		accounts[string(in.Address)] = acc
@@ -184,7 +185,7 @@ func getInputs(state AccountGetter, ins []*txs.TxInput) (map[string]*acm.Account
 	return accounts, nil
 }
 
-func getOrMakeOutputs(state AccountGetter, accounts map[string]*acm.Account,
+func getOrMakeOutputs(state PermissionsGetter, accounts map[string]*acm.Account,
 	outs []*txs.TxOutput, logger logging_types.InfoTraceLogger) (map[string]*acm.Account, error) {
 	if accounts == nil {
 		accounts = make(map[string]*acm.Account)
@@ -224,13 +225,18 @@ func getOrMakeOutputs(state AccountGetter, accounts map[string]*acm.Account,
 // be a deterministic hash of its associated public key) and not its public key. When we eventually receive a
 // transaction acting on behalf of that account we will be given a public key that we can check matches the address.
 // If it does then we will associate the public key with the stub account already registered in the system once and
-// for all time.
-func checkInputPubKey(acc *acm.Account, in *txs.TxInput) error {
+// for all time. Public keys may be ed25519 or secp256k1 keys, with addresses
+// derived by the address scheme of the chain.
+func checkInputPubKey(addressScheme string, acc *acm.Account, in *txs.TxInput) error {
 	if acc.PubKey == nil {
 		if in.PubKey == nil {
 			return txs.ErrTxUnknownPubKey
 		}
-		if !bytes.Equal(in.PubKey.Address(), acc.Address) {
+		if _, err := acm.KeyTypeOf(in.PubKey); err != nil {
+			return txs.ErrTxInvalidPubKey
+		}
+		address, err := acm.AddressFromPubKey(addressScheme, in.PubKey)
+		if err != nil || !bytes.Equal(address, acc.Address) {
 			return txs.ErrTxInvalidPubKey
 		}
 		acc.PubKey = in.PubKey
@@ -261,8 +267,9 @@ func validateInput(acc *acm.Account, signBytes []byte, in *txs.TxInput) (err err
 	if err := in.ValidateBasic(); err != nil {
 		return err
 	}
-	// Check signatures
-	if !acc.PubKey.VerifyBytes(signBytes, in.Signature) {
+	// Check signatures, which must be of the type of the account's key
+	if !acm.SignatureMatchesPubKey(acc.PubKey, in.Signature) ||
+		!acc.PubKey.VerifyBytes(signBytes, in.Signature) {
 		return txs.ErrTxInvalidSignature
 	}
 	// Check sequences
@@ -329,7 +336,7 @@ func ExecTx(blockCache *BlockCache, tx txs.Tx, runCall bool, evc events.Fireable
 	// Exec tx
 	switch tx := tx.(type) {
 	case *txs.SendTx:
-		accounts, err := getInputs(blockCache, tx.Inputs)
+		accounts, err := getInputs(blockCache, _s.AddressScheme, tx.Inputs)
 		if err != nil {
 			return err
 		}
@@ -403,7 +410,7 @@ func ExecTx(blockCache *BlockCache, tx txs.Tx, runCall bool, evc events.Fireable
 		}
 
 		// pubKey should be present in either "inAcc" or "tx.Input"
-		if err := checkInputPubKey(inAcc, tx.Input); err != nil {
+		if err := checkInputPubKey(_s.AddressScheme, inAcc, tx.Input); err != nil {
 			logging.InfoMsg(logger, "Cannot find public key for input account",
 				"tx_input", tx.Input)
 			return err
@@ -435,6 +442,9 @@ func ExecTx(blockCache *BlockCache, tx txs.Tx, runCall bool, evc events.Fireable
 					"contract that calls the native contract or the appropriate tx "+
 					"type (eg. PermissionsTx, NameTx).", tx.Address)
 			}
+			if !canCall(blockCache, inAcc, tx.Address) {
+				return fmt.Errorf("Account %X is not allowed to call %X", tx.Input.Address, tx.Address)
+			}
 
 			// Output account may be nil if we are still in mempool and contract was created in same block as this tx
 			// but that's fine, because the account will be created properly when the create tx runs in the block
@@ -581,7 +591,7 @@ func ExecTx(blockCache *BlockCache, tx txs.Tx, runCall bool, evc events.Fireable
 			return fmt.Errorf("Account %X does not have Name permission", tx.Input.Address)
 		}
 		// pubKey should be present in either "inAcc" or "tx.Input"
-		if err := checkInputPubKey(inAcc, tx.Input); err != nil {
+		if err := checkInputPubKey(_s.AddressScheme, inAcc, tx.Input); err != nil {
 			logging.InfoMsg(logger, "Cannot find public key for input account",
 				"tx_input", tx.Input)
 			return err
@@ -599,15 +609,17 @@ func ExecTx(blockCache *BlockCache, tx txs.Tx, runCall bool, evc events.Fireable
 			return txs.ErrTxInsufficientFunds
 		}
 
+		nameRegParams := _s.NameRegParams
+
 		// validate the input strings
-		if err := tx.ValidateStrings(); err != nil {
+		if err := tx.ValidateStrings(nameRegParams); err != nil {
 			return err
 		}
 
 		value := tx.Input.Amount - tx.Fee
 
 		// let's say cost of a name for one block is len(data) + 32
-		costPerBlock := txs.NameCostPerBlock(txs.NameBaseCost(tx.Name, tx.Data))
+		costPerBlock := nameRegParams.CostPerBlock(txs.NameBaseCost(tx.Name, tx.Data))
 		expiresIn := int(value / costPerBlock)
 		lastBlockHeight := _s.LastBlockHeight
 
@@ -619,18 +631,25 @@ func ExecTx(blockCache *BlockCache, tx txs.Tx, runCall bool, evc events.Fireable
 
 		// check if the name exists
 		entry := blockCache.GetNameRegEntry(tx.Name)
+		// unused credit of a live entry that is deleted, returned to its owner
+		var refund int64
 
 		if entry != nil {
 			var expired bool
 
 			// if the entry already exists, and hasn't expired, we must be owner
+			// or be revoking a name nested beneath one we control
 			if entry.Expires > lastBlockHeight {
 				// ensure we are owner
 				if !bytes.Equal(entry.Owner, tx.Input.Address) {
-					logging.InfoMsg(logger, "Sender is trying to update a name for which they are not an owner",
-						"sender_address", tx.Input.Address,
-						"name", tx.Name)
-					return txs.ErrTxPermissionDenied
+					revoking := value == 0 && len(tx.Data) == 0
+					if !revoking || !hasParentNameAuthority(blockCache, tx.Name,
+						tx.Input.Address, lastBlockHeight) {
+						logging.InfoMsg(logger, "Sender is trying to update a name for which they are not an owner",
+							"sender_address", tx.Input.Address,
+							"name", tx.Name)
+						return txs.ErrTxPermissionDenied
+					}
 				}
 			} else {
 				expired = true
@@ -640,15 +659,21 @@ func ExecTx(blockCache *BlockCache, tx txs.Tx, runCall bool, evc events.Fireable
 			if value == 0 && len(tx.Data) == 0 {
 				// maybe we reward you for telling us we can delete this crap
 				// (owners if not expired, anyone if expired)
+				refund = nameRegEntryCredit(nameRegParams, entry, lastBlockHeight)
 				logging.TraceMsg(logger, "Removing NameReg entry (no value and empty data in tx requests this)",
-					"name", entry.Name)
+					"name", entry.Name,
+					"refund", refund)
 				blockCache.RemoveNameRegEntry(entry.Name)
 			} else {
 				// update the entry by bumping the expiry
 				// and changing the data
 				if expired {
-					if expiresIn < txs.MinNameRegistrationPeriod {
-						return fmt.Errorf("Names must be registered for at least %d blocks", txs.MinNameRegistrationPeriod)
+					if err := checkParentNameAuthority(blockCache, tx.Name,
+						tx.Input.Address, lastBlockHeight); err != nil {
+						return err
+					}
+					if expiresIn < nameRegParams.MinRegistrationPeriod {
+						return fmt.Errorf("Names must be registered for at least %d blocks", nameRegParams.MinRegistrationPeriod)
 					}
 					entry.Expires = lastBlockHeight + expiresIn
 					entry.Owner = tx.Input.Address
@@ -659,11 +684,11 @@ func ExecTx(blockCache *BlockCache, tx txs.Tx, runCall bool, evc events.Fireable
 				} else {
 					// since the size of the data may have changed
 					// we use the total amount of "credit"
-					oldCredit := int64(entry.Expires-lastBlockHeight) * txs.NameBaseCost(entry.Name, entry.Data)
+					oldCredit := nameRegEntryCredit(nameRegParams, entry, lastBlockHeight)
 					credit := oldCredit + value
 					expiresIn = int(credit / costPerBlock)
-					if expiresIn < txs.MinNameRegistrationPeriod {
-						return fmt.Errorf("Names must be registered for at least %d blocks", txs.MinNameRegistrationPeriod)
+					if expiresIn < nameRegParams.MinRegistrationPeriod {
+						return fmt.Errorf("Names must be registered for at least %d blocks", nameRegParams.MinRegistrationPeriod)
 					}
 					entry.Expires = lastBlockHeight + expiresIn
 					logging.TraceMsg(logger, "Updated NameReg entry",
@@ -677,8 +702,12 @@ func ExecTx(blockCache *BlockCache, tx txs.Tx, runCall bool, evc events.Fireable
 				blockCache.UpdateNameRegEntry(entry)
 			}
 		} else {
-			if expiresIn < txs.MinNameRegistrationPeriod {
-				return fmt.Errorf("Names must be registered for at least %d blocks", txs.MinNameRegistrationPeriod)
+			if err := checkParentNameAuthority(blockCache, tx.Name,
+				tx.Input.Address, lastBlockHeight); err != nil {
+				return err
+			}
+			if expiresIn < nameRegParams.MinRegistrationPeriod {
+				return fmt.Errorf("Names must be registered for at least %d blocks", nameRegParams.MinRegistrationPeriod)
 			}
 			// entry does not exist, so create it
 			entry = &core_types.NameRegEntry{
@@ -693,15 +722,95 @@ func ExecTx(blockCache *BlockCache, tx txs.Tx, runCall bool, evc events.Fireable
 			blockCache.UpdateNameRegEntry(entry)
 		}
 
-		// TODO: something with the value sent?
-
 		// Good!
 		inAcc.Sequence += 1
 		inAcc.Balance -= value
 		blockCache.UpdateAccount(inAcc)
 
+		// the value sent is spent on the registration, but whatever is left
+		// when a live entry is deleted goes back to its owner (who may not be
+		// the sender when the owner of a parent name revokes it)
+		if refund > 0 {
+			ownerAcc := blockCache.GetAccount(entry.Owner)
+			if ownerAcc != nil {
+				ownerAcc.Balance += refund
+				blockCache.UpdateAccount(ownerAcc)
+			}
+		}
+
 		// TODO: maybe we want to take funds on error and allow txs in that don't do anythingi?
 
+		if evc != nil {
+			evc.FireEvent(txs.EventStringAccInput(tx.Input.Address), txs.EventDataTx{tx, nil, ""})
+			evc.FireEvent(txs.EventStringNameReg(tx.Name), txs.EventDataTx{tx, nil, ""})
+			if refund > 0 {
+				evc.FireEvent(txs.EventStringNameReg(tx.Name), txs.EventDataNameReg{
+					Name:   entry.Name,
+					Owner:  entry.Owner,
+					Refund: refund,
+				})
+			}
+		}
+
+		return nil
+
+	case *txs.NameTransferTx:
+		var inAcc *acm.Account
+
+		// Validate input
+		inAcc = blockCache.GetAccount(tx.Input.Address)
+		if inAcc == nil {
+			logging.InfoMsg(logger, "Cannot find input account",
+				"tx_input", tx.Input)
+			return txs.ErrTxInvalidAddress
+		}
+		// check permission
+		if !hasNamePermission(blockCache, inAcc, logger) {
+			return fmt.Errorf("Account %X does not have Name permission", tx.Input.Address)
+		}
+		// pubKey should be present in either "inAcc" or "tx.Input"
+		if err := checkInputPubKey(_s.AddressScheme, inAcc, tx.Input); err != nil {
+			logging.InfoMsg(logger, "Cannot find public key for input account",
+				"tx_input", tx.Input)
+			return err
+		}
+		signBytes := acm.SignBytes(_s.ChainID, tx)
+		err := validateInput(inAcc, signBytes, tx.Input)
+		if err != nil {
+			logging.InfoMsg(logger, "validateInput failed",
+				"tx_input", tx.Input, "error", err)
+			return err
+		}
+
+		if err := tx.ValidateStrings(); err != nil {
+			return err
+		}
+
+		lastBlockHeight := _s.LastBlockHeight
+		entry := blockCache.GetNameRegEntry(tx.Name)
+		if entry == nil || entry.Expires <= lastBlockHeight {
+			return fmt.Errorf("Cannot transfer %s because it is not registered or has expired", tx.Name)
+		}
+		// the owner, or the owner of a name this one is nested beneath, may transfer it
+		if !hasNameAuthority(blockCache, tx.Name, tx.Input.Address, lastBlockHeight) {
+			logging.InfoMsg(logger, "Sender is trying to transfer a name they do not control",
+				"sender_address", tx.Input.Address,
+				"name", tx.Name)
+			return txs.ErrTxPermissionDenied
+		}
+
+		logging.TraceMsg(logger, "Transferring NameReg entry",
+			"name", entry.Name,
+			"owner", entry.Owner,
+			"new_owner", tx.NewOwner)
+		entry.Owner = tx.NewOwner
+		blockCache.UpdateNameRegEntry(entry)
+
+		// Good!
+		inAcc.Sequence += 1
+		inAcc.Balance -= tx.Input.Amount
+		blockCache.UpdateAccount(inAcc)
+
 		if evc != nil {
 			evc.FireEvent(txs.EventStringAccInput(tx.Input.Address), txs.EventDataTx{tx, nil, ""})
 			evc.FireEvent(txs.EventStringNameReg(tx.Name), txs.EventDataTx{tx, nil, ""})
@@ -720,7 +829,7 @@ func ExecTx(blockCache *BlockCache, tx txs.Tx, runCall bool, evc events.Fireable
 							return errors.New("Adding coins to existing validators not yet supported")
 						}
 
-						accounts, err := getInputs(blockCache, tx.Inputs)
+						accounts, err := getInputs(blockCache, _s.AddressScheme, tx.Inputs)
 						if err != nil {
 							return err
 						}
@@ -898,13 +1007,20 @@ func ExecTx(blockCache *BlockCache, tx txs.Tx, runCall bool, evc events.Fireable
 		}
 
 		permFlag := tx.PermArgs.PermFlag()
+		// governed changes are proposed and approved by accounts with Govern
+		// rather than made by those with the moderator permission
+		governed := _s.GovernanceParams.Enabled() && ptypes.Governs(blockCache, tx.PermArgs)
+		if governed {
+			permFlag = ptypes.Govern
+		}
 		// check permission
-		if !HasPermission(blockCache, inAcc, permFlag, logger) {
+		if !HasPermission(blockCache, inAcc, permFlag, logger) &&
+			(governed || !isRoleAdminArgs(blockCache, inAcc, tx.PermArgs)) {
 			return fmt.Errorf("Account %X does not have moderator permission %s (%b)", tx.Input.Address, ptypes.PermFlagToString(permFlag), permFlag)
 		}
 
 		// pubKey should be present in either "inAcc" or "tx.Input"
-		if err := checkInputPubKey(inAcc, tx.Input); err != nil {
+		if err := checkInputPubKey(_s.AddressScheme, inAcc, tx.Input); err != nil {
 			logging.InfoMsg(logger, "Cannot find public key for input account",
 				"tx_input", tx.Input)
 			return err
@@ -925,43 +1041,14 @@ func ExecTx(blockCache *BlockCache, tx txs.Tx, runCall bool, evc events.Fireable
 			"perm_args", tx.PermArgs)
 
 		var permAcc *acm.Account
-		switch args := tx.PermArgs.(type) {
-		case *ptypes.HasBaseArgs:
-			// this one doesn't make sense from txs
-			return fmt.Errorf("HasBase is for contracts, not humans. Just look at the blockchain")
-		case *ptypes.SetBaseArgs:
-			if permAcc = blockCache.GetAccount(args.Address); permAcc == nil {
-				return fmt.Errorf("Trying to update permissions for unknown account %X", args.Address)
-			}
-			err = permAcc.Permissions.Base.Set(args.Permission, args.Value)
-		case *ptypes.UnsetBaseArgs:
-			if permAcc = blockCache.GetAccount(args.Address); permAcc == nil {
-				return fmt.Errorf("Trying to update permissions for unknown account %X", args.Address)
-			}
-			err = permAcc.Permissions.Base.Unset(args.Permission)
-		case *ptypes.SetGlobalArgs:
-			if permAcc = blockCache.GetAccount(ptypes.GlobalPermissionsAddress); permAcc == nil {
-				sanity.PanicSanity("can't find global permissions account")
-			}
-			err = permAcc.Permissions.Base.Set(args.Permission, args.Value)
-		case *ptypes.HasRoleArgs:
-			return fmt.Errorf("HasRole is for contracts, not humans. Just look at the blockchain")
-		case *ptypes.AddRoleArgs:
-			if permAcc = blockCache.GetAccount(args.Address); permAcc == nil {
-				return fmt.Errorf("Trying to update roles for unknown account %X", args.Address)
-			}
-			if !permAcc.Permissions.AddRole(args.Role) {
-				return fmt.Errorf("Role (%s) already exists for account %X", args.Role, args.Address)
-			}
-		case *ptypes.RmRoleArgs:
-			if permAcc = blockCache.GetAccount(args.Address); permAcc == nil {
-				return fmt.Errorf("Trying to update roles for unknown account %X", args.Address)
-			}
-			if !permAcc.Permissions.RmRole(args.Role) {
-				return fmt.Errorf("Role (%s) does not exist for account %X", args.Role, args.Address)
-			}
-		default:
-			sanity.PanicSanity(fmt.Sprintf("invalid permission function: %s", ptypes.PermFlagToString(permFlag)))
+		var proposal *ptypes.Proposal
+		if args, ok := tx.PermArgs.(*ptypes.ApproveArgs); ok {
+			proposal, permAcc, err = approveProposal(blockCache, args.Proposal, tx.Input.Address)
+		} else if governed {
+			proposal, permAcc, err = proposePermArgs(blockCache, txs.TxHash(_s.ChainID, tx),
+				tx.Input.Address, tx.PermArgs)
+		} else {
+			permAcc, err = execPermArgs(blockCache, tx.PermArgs)
 		}
 
 		// TODO: maybe we want to take funds on error and allow txs in that don't do anythingi?
@@ -980,6 +1067,10 @@ func ExecTx(blockCache *BlockCache, tx txs.Tx, runCall bool, evc events.Fireable
 		if evc != nil {
 			evc.FireEvent(txs.EventStringAccInput(tx.Input.Address), txs.EventDataTx{tx, nil, ""})
 			evc.FireEvent(txs.EventStringPermissions(ptypes.PermFlagToString(permFlag)), txs.EventDataTx{tx, nil, ""})
+			if proposal != nil && proposal.ExecutedHeight == blockCache.LastBlockHeight()+1 {
+				evc.FireEvent(txs.EventStringPermissions(ptypes.PermFlagToString(proposal.PermArgs.PermFlag())),
+					txs.EventDataTx{tx, nil, ""})
+			}
 		}
 
 		return nil
@@ -994,7 +1085,7 @@ func ExecTx(blockCache *BlockCache, tx txs.Tx, runCall bool, evc events.Fireable
 //---------------------------------------------------------------
 
 // Get permission on an account or fall back to global value
-func HasPermission(state AccountGetter, acc *acm.Account, perm ptypes.PermFlag,
+func HasPermission(state PermissionsGetter, acc *acm.Account, perm ptypes.PermFlag,
 	logger logging_types.InfoTraceLogger) bool {
 	if perm > ptypes.AllPermFlags {
 		sanity.PanicSanity("Checking an unknown permission in state should never happen")
@@ -1007,14 +1098,29 @@ func HasPermission(state AccountGetter, acc *acm.Account, perm ptypes.PermFlag,
 	//}
 	permString := ptypes.PermFlagToString(perm)
 
-	v, err := acc.Permissions.Base.Get(perm)
+	permissions := &acc.Permissions
+	if state != nil {
+		permissions = permissions.AtHeight(state.LastBlockHeight())
+	}
+	v, err := permissions.Base.Get(perm)
 	if _, ok := err.(ptypes.ErrValueNotSet); ok {
 		if state == nil {
-			sanity.PanicSanity("All known global permissions should be set!")
+			// Global permissions set at genesis before a permission was
+			// introduced do not set it, so it is denied as in the VM
+			logging.TraceMsg(logger, "Global permission is not set, denying it",
+				"perm_flag", permString)
+			return false
 		}
-		logging.TraceMsg(logger, "Permission for account is not set. Querying GlobalPermissionsAddres.",
-			"perm_flag", permString)
-		return HasPermission(nil, state.GetAccount(ptypes.GlobalPermissionsAddress), perm, logger)
+		v, err = permissions.GetRolePermission(state, perm)
+		if _, ok := err.(ptypes.ErrValueNotSet); ok {
+			logging.TraceMsg(logger, "Permission for account is not set. Querying GlobalPermissionsAddres.",
+				"perm_flag", permString)
+			return HasPermission(nil, state.GetAccount(ptypes.GlobalPermissionsAddress), perm, logger)
+		}
+		logging.TraceMsg(logger, "Permission for account is set by its roles",
+			"account_address", acc.Address,
+			"perm_flag", permString,
+			"value", v)
 	} else if v {
 		logging.TraceMsg(logger, "Account has permission",
 			"account_address", acc.Address,
@@ -1027,8 +1133,207 @@ func HasPermission(state AccountGetter, acc *acm.Account, perm ptypes.PermFlag,
 	return v
 }
 
+// Whether args add or remove a member of a role whose admin role acc is a
+// member of, which it may do without the AddRole or RmRole permission
+func isRoleAdminArgs(state PermissionsGetter, acc *acm.Account, args ptypes.PermArgs) bool {
+	permissions := acc.Permissions.AtHeight(state.LastBlockHeight())
+	switch args := args.(type) {
+	case *ptypes.AddRoleArgs:
+		return permissions.IsRoleAdmin(state, args.Role)
+	case *ptypes.AddRoleUntilArgs:
+		return permissions.IsRoleAdmin(state, args.Role)
+	case *ptypes.RmRoleArgs:
+		return permissions.IsRoleAdmin(state, args.Role)
+	}
+	return false
+}
+
+// Execute the permission change args make, returning the account whose
+// permissions changed for saving
+func execPermArgs(blockCache *BlockCache, args ptypes.PermArgs) (permAcc *acm.Account, err error) {
+	// the args from before expiries are those that never expire
+	switch legacyArgs := args.(type) {
+	case *ptypes.SetBaseArgs:
+		args = &ptypes.SetBaseUntilArgs{legacyArgs.Address, legacyArgs.Permission,
+			legacyArgs.Value, 0}
+	case *ptypes.AddRoleArgs:
+		args = &ptypes.AddRoleUntilArgs{legacyArgs.Address, legacyArgs.Role, 0}
+	}
+	switch args := args.(type) {
+	case *ptypes.HasBaseArgs:
+		// this one doesn't make sense from txs
+		return nil, fmt.Errorf("HasBase is for contracts, not humans. Just look at the blockchain")
+	case *ptypes.SetBaseUntilArgs:
+		if permAcc = blockCache.GetAccount(args.Address); permAcc == nil {
+			return nil, fmt.Errorf("Trying to update permissions for unknown account %X", args.Address)
+		}
+		if args.Expires != 0 && args.Expires <= blockCache.LastBlockHeight() {
+			return nil, fmt.Errorf("Permission expiry %v is not after the last block height %v",
+				args.Expires, blockCache.LastBlockHeight())
+		}
+		err = permAcc.Permissions.SetBaseUntil(args.Permission, args.Value, args.Expires)
+	case *ptypes.UnsetBaseArgs:
+		if permAcc = blockCache.GetAccount(args.Address); permAcc == nil {
+			return nil, fmt.Errorf("Trying to update permissions for unknown account %X", args.Address)
+		}
+		err = permAcc.Permissions.UnsetBase(args.Permission)
+	case *ptypes.SetGlobalArgs:
+		if permAcc = blockCache.GetAccount(ptypes.GlobalPermissionsAddress); permAcc == nil {
+			sanity.PanicSanity("can't find global permissions account")
+		}
+		err = permAcc.Permissions.Base.Set(args.Permission, args.Value)
+	case *ptypes.HasRoleArgs:
+		return nil, fmt.Errorf("HasRole is for contracts, not humans. Just look at the blockchain")
+	case *ptypes.AddRoleUntilArgs:
+		if permAcc = blockCache.GetAccount(args.Address); permAcc == nil {
+			return nil, fmt.Errorf("Trying to update roles for unknown account %X", args.Address)
+		}
+		if args.Expires != 0 && args.Expires <= blockCache.LastBlockHeight() {
+			return nil, fmt.Errorf("Role expiry %v is not after the last block height %v",
+				args.Expires, blockCache.LastBlockHeight())
+		}
+		if !permAcc.Permissions.AddRoleUntil(args.Role, args.Expires) {
+			return nil, fmt.Errorf("Role (%s) already exists for account %X", args.Role, args.Address)
+		}
+	case *ptypes.RmRoleArgs:
+		if permAcc = blockCache.GetAccount(args.Address); permAcc == nil {
+			return nil, fmt.Errorf("Trying to update roles for unknown account %X", args.Address)
+		}
+		if !permAcc.Permissions.RmRole(args.Role) {
+			return nil, fmt.Errorf("Role (%s) does not exist for account %X", args.Role, args.Address)
+		}
+	case *ptypes.SetRoleArgs:
+		if args.Role.Name == "" {
+			return nil, fmt.Errorf("Role must have a name")
+		}
+		blockCache.UpdateRole(&args.Role)
+	case *ptypes.AllowCallArgs:
+		if len(args.Contract) != 20 {
+			return nil, txs.ErrTxInvalidAddress
+		}
+		permAcc, err = updateCallAllowList(blockCache, args.Address, args.Role,
+			func(cal *ptypes.CallAllowList) (*ptypes.CallAllowList, bool) {
+				if cal == nil {
+					cal = &ptypes.CallAllowList{}
+				}
+				return cal, cal.Add(args.Contract)
+			})
+	case *ptypes.DisallowCallArgs:
+		permAcc, err = updateCallAllowList(blockCache, args.Address, args.Role,
+			func(cal *ptypes.CallAllowList) (*ptypes.CallAllowList, bool) {
+				if cal == nil {
+					return nil, false
+				}
+				return cal, cal.Remove(args.Contract)
+			})
+	case *ptypes.UnrestrictCallsArgs:
+		permAcc, err = updateCallAllowList(blockCache, args.Address, args.Role,
+			func(cal *ptypes.CallAllowList) (*ptypes.CallAllowList, bool) {
+				return nil, cal != nil
+			})
+	default:
+		sanity.PanicSanity(fmt.Sprintf("invalid permission function: %s", ptypes.PermFlagToString(args.PermFlag())))
+	}
+	return permAcc, err
+}
+
+// Record the proposal of governed args by the PermissionsTx with hash, which
+// the proposer approves, executing them straight away if that meets the
+// governance threshold
+func proposePermArgs(blockCache *BlockCache, hash, proposer []byte,
+	args ptypes.PermArgs) (*ptypes.Proposal, *acm.Account, error) {
+	if blockCache.GetProposal(hash) != nil {
+		return nil, nil, fmt.Errorf("Proposal %X already exists", hash)
+	}
+	proposal := &ptypes.Proposal{
+		Hash:     hash,
+		Proposer: proposer,
+		PermArgs: args,
+		Expires:  blockCache.LastBlockHeight() + blockCache.State().GovernanceParams.ProposalExpiry,
+	}
+	proposal.Approve(proposer)
+	return execProposalIfApproved(blockCache, proposal)
+}
+
+// Add the approval of approver to the pending proposal with hash, executing it
+// if that meets the governance threshold
+func approveProposal(blockCache *BlockCache, hash, approver []byte) (*ptypes.Proposal, *acm.Account, error) {
+	if !blockCache.State().GovernanceParams.Enabled() {
+		return nil, nil, fmt.Errorf("Governance is not enabled on this chain")
+	}
+	proposal := blockCache.GetProposal(hash)
+	if proposal == nil {
+		return nil, nil, fmt.Errorf("Trying to approve unknown proposal %X", hash)
+	}
+	if proposal.Executed() {
+		return nil, nil, fmt.Errorf("Proposal %X was executed at height %v",
+			hash, proposal.ExecutedHeight)
+	}
+	if proposal.Expired(blockCache.LastBlockHeight()) {
+		return nil, nil, fmt.Errorf("Proposal %X expired at height %v", hash, proposal.Expires)
+	}
+	// Clone to leave the cached proposal untouched should execution fail
+	proposal = proposal.Clone()
+	if !proposal.Approve(approver) {
+		return nil, nil, fmt.Errorf("Account %X has already approved proposal %X", approver, hash)
+	}
+	return execProposalIfApproved(blockCache, proposal)
+}
+
+func execProposalIfApproved(blockCache *BlockCache,
+	proposal *ptypes.Proposal) (*ptypes.Proposal, *acm.Account, error) {
+	var permAcc *acm.Account
+	if len(proposal.Approvals) >= blockCache.State().GovernanceParams.Threshold {
+		var err error
+		if permAcc, err = execPermArgs(blockCache, proposal.PermArgs); err != nil {
+			return nil, nil, err
+		}
+		proposal.ExecutedHeight = blockCache.LastBlockHeight() + 1
+	}
+	blockCache.UpdateProposal(proposal)
+	return proposal, permAcc, nil
+}
+
+// Update the CallAllowList of the account at address, which is returned for
+// saving, or if address is empty of the role named role. Fails if update does
+// not change the CallAllowList.
+func updateCallAllowList(blockCache *BlockCache, address []byte, role string,
+	update func(cal *ptypes.CallAllowList) (*ptypes.CallAllowList, bool)) (*acm.Account, error) {
+	if len(address) > 0 {
+		acc := blockCache.GetAccount(address)
+		if acc == nil {
+			return nil, fmt.Errorf("Trying to update permissions for unknown account %X", address)
+		}
+		cal, changed := update(acc.Permissions.CallAllowList.Clone())
+		if !changed {
+			return nil, fmt.Errorf("Call allow list of account %X is unchanged", address)
+		}
+		acc.Permissions.CallAllowList = cal
+		return acc, nil
+	}
+	r := blockCache.GetRole(role)
+	if r == nil {
+		return nil, fmt.Errorf("Trying to update unknown role %s", role)
+	}
+	// Normalise returns a copy, leaving the cached role untouched
+	r = r.Normalise()
+	cal, changed := update(r.CallAllowList)
+	if !changed {
+		return nil, fmt.Errorf("Call allow list of role %s is unchanged", role)
+	}
+	r.CallAllowList = cal
+	blockCache.UpdateRole(r)
+	return nil, nil
+}
+
+// Whether acc may call the contract at address given the CallAllowLists of the
+// account and its roles
+func canCall(state PermissionsGetter, acc *acm.Account, address []byte) bool {
+	return acc.Permissions.AtHeight(state.LastBlockHeight()).CanCall(state, address)
+}
+
 // TODO: for debug log the failed accounts
-func hasSendPermission(state AccountGetter, accs map[string]*acm.Account,
+func hasSendPermission(state PermissionsGetter, accs map[string]*acm.Account,
 	logger logging_types.InfoTraceLogger) bool {
 	for _, acc := range accs {
 		if !HasPermission(state, acc, ptypes.Send, logger) {
@@ -1038,22 +1343,111 @@ func hasSendPermission(state AccountGetter, accs map[string]*acm.Account,
 	return true
 }
 
-func hasNamePermission(state AccountGetter, acc *acm.Account,
+func hasNamePermission(state PermissionsGetter, acc *acm.Account,
 	logger logging_types.InfoTraceLogger) bool {
 	return HasPermission(state, acc, ptypes.Name, logger)
 }
 
-func hasCallPermission(state AccountGetter, acc *acm.Account,
+// Whether address owns the live entry for name or for any name it is nested
+// beneath (see txs.NameAncestors), which gives it control over the name
+func hasNameAuthority(blockCache *BlockCache, name string, address []byte,
+	lastBlockHeight int) bool {
+	for _, n := range append([]string{name}, txs.NameAncestors(name)...) {
+		entry := blockCache.GetNameRegEntry(n)
+		if entry != nil && entry.Expires > lastBlockHeight &&
+			bytes.Equal(entry.Owner, address) {
+			return true
+		}
+	}
+	return false
+}
+
+// Whether address controls a name that name is nested beneath
+func hasParentNameAuthority(blockCache *BlockCache, name string, address []byte,
+	lastBlockHeight int) bool {
+	ancestors := txs.NameAncestors(name)
+	return len(ancestors) > 0 &&
+		hasNameAuthority(blockCache, ancestors[0], address, lastBlockHeight)
+}
+
+// The credit remaining on an entry for the blocks until it expires, which is
+// nothing once it has expired
+func nameRegEntryCredit(params txs.NameRegParams, entry *core_types.NameRegEntry,
+	lastBlockHeight int) int64 {
+	if entry.Expires <= lastBlockHeight {
+		return 0
+	}
+	return int64(entry.Expires-lastBlockHeight) *
+		params.CostPerBlock(txs.NameBaseCost(entry.Name, entry.Data))
+}
+
+// Remove up to limit name registry entries that expired by the last block,
+// which could otherwise only be removed by a NameTx for their name. Entries are
+// found in order of expiry, so only those that have expired are visited. A
+// limit of 0 or less removes none. Returns the number of entries removed.
+func RemoveExpiredNameRegEntries(blockCache *BlockCache, limit int,
+	evc events.Fireable, logger logging_types.InfoTraceLogger) (removed int) {
+	if limit <= 0 {
+		return 0
+	}
+	lastBlockHeight := blockCache.State().LastBlockHeight
+	blockCache.State().GetNameRegExpiries().Iterate(func(key, value []byte) bool {
+		if nameRegExpiryFromKey(key) > lastBlockHeight {
+			return true
+		}
+		// the entry may have been reclaimed or removed by a tx in this block
+		entry := blockCache.GetNameRegEntry(string(value))
+		if entry == nil || entry.Expires > lastBlockHeight {
+			return false
+		}
+		logging.TraceMsg(logger, "Removing expired NameReg entry",
+			"name", entry.Name,
+			"expires", entry.Expires,
+			"last_block_height", lastBlockHeight)
+		blockCache.RemoveNameRegEntry(entry.Name)
+		if evc != nil {
+			evc.FireEvent(txs.EventStringNameReg(entry.Name), txs.EventDataNameReg{
+				Name:    entry.Name,
+				Owner:   entry.Owner,
+				Expired: true,
+			})
+		}
+		removed++
+		return removed >= limit
+	})
+	return removed
+}
+
+// Names nested beneath a registered name can only be claimed by an account
+// controlling the nearest such name. Names with no registered ancestor are free
+// to be claimed, but can be revoked once an ancestor is registered.
+func checkParentNameAuthority(blockCache *BlockCache, name string, address []byte,
+	lastBlockHeight int) error {
+	for _, ancestor := range txs.NameAncestors(name) {
+		entry := blockCache.GetNameRegEntry(ancestor)
+		if entry == nil || entry.Expires <= lastBlockHeight {
+			continue
+		}
+		if !hasNameAuthority(blockCache, ancestor, address, lastBlockHeight) {
+			return fmt.Errorf("Cannot register %s because account %X does not control %s",
+				name, address, ancestor)
+		}
+		return nil
+	}
+	return nil
+}
+
+func hasCallPermission(state PermissionsGetter, acc *acm.Account,
 	logger logging_types.InfoTraceLogger) bool {
 	return HasPermission(state, acc, ptypes.Call, logger)
 }
 
-func hasCreateContractPermission(state AccountGetter, acc *acm.Account,
+func hasCreateContractPermission(state PermissionsGetter, acc *acm.Account,
 	logger logging_types.InfoTraceLogger) bool {
 	return HasPermission(state, acc, ptypes.CreateContract, logger)
 }
 
-func hasCreateAccountPermission(state AccountGetter, accs map[string]*acm.Account,
+func hasCreateAccountPermission(state PermissionsGetter, accs map[string]*acm.Account,
 	logger logging_types.InfoTraceLogger) bool {
 	for _, acc := range accs {
 		if !HasPermission(state, acc, ptypes.CreateAccount, logger) {
@@ -1063,12 +1457,12 @@ func hasCreateAccountPermission(state AccountGetter, accs map[string]*acm.Accoun
 	return true
 }
 
-func hasBondPermission(state AccountGetter, acc *acm.Account,
+func hasBondPermission(state PermissionsGetter, acc *acm.Account,
 	logger logging_types.InfoTraceLogger) bool {
 	return HasPermission(state, acc, ptypes.Bond, logger)
 }
 
-func hasBondOrSendPermission(state AccountGetter, accs map[string]*acm.Account,
+func hasBondOrSendPermission(state PermissionsGetter, accs map[string]*acm.Account,
 	logger logging_types.InfoTraceLogger) bool {
 	for _, acc := range accs {
 		if !HasPermission(state, acc, ptypes.Bond, logger) {
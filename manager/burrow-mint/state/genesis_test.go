@@ -74,7 +74,10 @@ var g1 = fmt.Sprintf(`
 `, chain_id, addr1, amt1, accName, perms, setbit, roles1[0], roles1[1])
 
 func TestGenesisReadable(t *testing.T) {
-	genDoc := genesis.GenesisDocFromJSON([]byte(g1))
+	genDoc, err := genesis.GenesisDocFromJSON([]byte(g1))
+	if err != nil {
+		t.Fatal(err)
+	}
 	if genDoc.ChainID != chain_id {
 		t.Fatalf("Incorrect chain id. Got %d, expected %d\n", genDoc.ChainID, chain_id)
 	}
@@ -96,9 +99,12 @@ func TestGenesisReadable(t *testing.T) {
 }
 
 func TestGenesisMakeState(t *testing.T) {
-	genDoc := genesis.GenesisDocFromJSON([]byte(g1))
+	genDoc, err := genesis.GenesisDocFromJSON([]byte(g1))
+	if err != nil {
+		t.Fatal(err)
+	}
 	db := tdb.NewMemDB()
-	st := MakeGenesisState(db, genDoc)
+	st := makeGenesisState(t, db, genDoc)
 	acc := st.GetAccount(addr1)
 	v, _ := acc.Permissions.Base.Get(ptypes.Send)
 	if v != (send1 > 0) {
@@ -106,16 +112,79 @@ func TestGenesisMakeState(t *testing.T) {
 	}
 }
 
+func TestGenesisMakeStateFromSpec(t *testing.T) {
+	spec := &genesis.GenesisSpec{
+		ChainID: chain_id,
+		PermissionSets: map[string]genesis.PermissionSetSpec{
+			"developer": {Grant: []string{"root"}},
+			"validator": {Grant: []string{"bond"}, Deny: []string{"create_contract"}},
+		},
+		Roles: []genesis.RoleSpec{{Name: "auditors", Permissions: "developer"}},
+		Validators: []genesis.ValidatorGroupSpec{{
+			AccountGroupSpec: genesis.AccountGroupSpec{Name: "validator", Count: 3,
+				Permissions: "validator"},
+			Bond: 1000000,
+		}},
+		Accounts: []genesis.AccountGroupSpec{{Name: "developer", Count: 5, Amount: 1000000,
+			Permissions: "developer", Roles: []string{"auditors"}}},
+	}
+	genDoc, keys, err := spec.GenesisDoc(time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 8 || len(genDoc.Validators) != 3 {
+		t.Fatalf("Expected 8 keys and 3 validators but got %v and %v", len(keys), len(genDoc.Validators))
+	}
+	st := makeGenesisState(t, tdb.NewMemDB(), genDoc)
+	for _, key := range keys {
+		acc := st.GetAccount(key.PrivAccount.Address)
+		if acc == nil {
+			t.Fatalf("Account %s is missing from the genesis state", key.Name)
+		}
+		if key.Validator {
+			if v, _ := acc.Permissions.Base.Get(ptypes.Bond); !v {
+				t.Fatalf("Expected validator %s to have bond permission", key.Name)
+			}
+			continue
+		}
+		if acc.Balance != 1000000 || !acc.Permissions.HasRoleInherited(st, "auditors") {
+			t.Fatalf("Expected developer %s to have its amount and role", key.Name)
+		}
+		if v, _ := acc.Permissions.Base.Get(ptypes.Root); !v {
+			t.Fatalf("Expected developer %s to have root permission", key.Name)
+		}
+	}
+	if st.GetRole("auditors") == nil {
+		t.Fatal("Expected role auditors to be defined")
+	}
+
+	spec.Accounts[0].Permissions = "unknown"
+	if _, _, err := spec.GenesisDoc(time.Now()); err == nil {
+		t.Fatal("Expected error from unknown permission set")
+	}
+}
+
 //-------------------------------------------------------
 
 func RandGenesisState(numAccounts int, randBalance bool, minBalance int64, numValidators int, randBonded bool, minBonded int64) (*State, []*acm.PrivAccount, []*types.PrivValidator) {
 	db := tdb.NewMemDB()
 	genDoc, privAccounts, privValidators := RandGenesisDoc(numAccounts, randBalance, minBalance, numValidators, randBonded, minBonded)
-	s0 := MakeGenesisState(db, genDoc)
+	s0, err := MakeGenesisState(db, genDoc)
+	if err != nil {
+		panic(fmt.Sprintf("failed to make genesis state: %s", err))
+	}
 	s0.Save()
 	return s0, privAccounts, privValidators
 }
 
+func makeGenesisState(t *testing.T, db tdb.DB, genDoc *genesis.GenesisDoc) *State {
+	st, err := MakeGenesisState(db, genDoc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return st
+}
+
 func RandAccount(randBalance bool, minBalance int64) (*acm.Account, *acm.PrivAccount) {
 	privAccount := acm.GenPrivAccount()
 	perms := ptypes.DefaultAccountPermissions
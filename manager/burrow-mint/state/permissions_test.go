@@ -22,8 +22,10 @@ import (
 	"time"
 
 	acm "github.com/hyperledger/burrow/account"
+	core_types "github.com/hyperledger/burrow/core/types"
 	genesis "github.com/hyperledger/burrow/genesis"
 	"github.com/hyperledger/burrow/manager/burrow-mint/evm"
+	"github.com/hyperledger/burrow/manager/burrow-mint/evm/abi"
 	. "github.com/hyperledger/burrow/manager/burrow-mint/evm/opcodes"
 	ptypes "github.com/hyperledger/burrow/permission/types"
 	"github.com/hyperledger/burrow/txs"
@@ -44,6 +46,7 @@ var (
 	dbBackend           = "memdb"
 	dbDir               = ""
 	permissionsContract = vm.SNativeContracts()["Permissions"]
+	nameRegContract     = vm.SNativeContracts()["NameReg"]
 )
 
 /*
@@ -61,6 +64,7 @@ x	- contract runs call but doesn't have call perm
 x	- contract runs call and has call perm
 x	- contract runs call (with perm), runs contract that runs call (without perm)
 x	- contract runs call (with perm), runs contract that runs call (with perm)
+x	- account and role call allow lists, contract runs call not on its allow list
 
 - CallTx for Create, CREATE
 x	- 1 input, no perm, send perm, call perm
@@ -103,6 +107,9 @@ x		- call each snative with permission, pass
 x		- base: has,set,unset
 x		- globals: set
 x 		- roles: has, add, rm
+x		- role definitions: set, inherit, admin
+x		- grants until a block height: set base, add role, expire
+x		- name registry: getData, getOwner, getExpiry, setData, transfer
 
 
 */
@@ -164,7 +171,7 @@ func TestSendFails(t *testing.T) {
 	genDoc.Accounts[1].Permissions.Base.Set(ptypes.Send, true)
 	genDoc.Accounts[2].Permissions.Base.Set(ptypes.Call, true)
 	genDoc.Accounts[3].Permissions.Base.Set(ptypes.CreateContract, true)
-	st := MakeGenesisState(stateDB, &genDoc)
+	st := makeGenesisState(t, stateDB, &genDoc)
 	blockCache := NewBlockCache(st)
 
 	//-------------------
@@ -231,7 +238,7 @@ func TestName(t *testing.T) {
 	genDoc := newBaseGenDoc(PermsAllFalse, PermsAllFalse)
 	genDoc.Accounts[0].Permissions.Base.Set(ptypes.Send, true)
 	genDoc.Accounts[1].Permissions.Base.Set(ptypes.Name, true)
-	st := MakeGenesisState(stateDB, &genDoc)
+	st := makeGenesisState(t, stateDB, &genDoc)
 	blockCache := NewBlockCache(st)
 
 	//-------------------
@@ -266,7 +273,7 @@ func TestCallFails(t *testing.T) {
 	genDoc.Accounts[1].Permissions.Base.Set(ptypes.Send, true)
 	genDoc.Accounts[2].Permissions.Base.Set(ptypes.Call, true)
 	genDoc.Accounts[3].Permissions.Base.Set(ptypes.CreateContract, true)
-	st := MakeGenesisState(stateDB, &genDoc)
+	st := makeGenesisState(t, stateDB, &genDoc)
 	blockCache := NewBlockCache(st)
 
 	//-------------------
@@ -334,7 +341,7 @@ func TestSendPermission(t *testing.T) {
 	stateDB := dbm.NewDB("state", dbBackend, dbDir)
 	genDoc := newBaseGenDoc(PermsAllFalse, PermsAllFalse)
 	genDoc.Accounts[0].Permissions.Base.Set(ptypes.Send, true) // give the 0 account permission
-	st := MakeGenesisState(stateDB, &genDoc)
+	st := makeGenesisState(t, stateDB, &genDoc)
 	blockCache := NewBlockCache(st)
 
 	// A single input, having the permission, should succeed
@@ -370,7 +377,7 @@ func TestCallPermission(t *testing.T) {
 	stateDB := dbm.NewDB("state", dbBackend, dbDir)
 	genDoc := newBaseGenDoc(PermsAllFalse, PermsAllFalse)
 	genDoc.Accounts[0].Permissions.Base.Set(ptypes.Call, true) // give the 0 account permission
-	st := MakeGenesisState(stateDB, &genDoc)
+	st := makeGenesisState(t, stateDB, &genDoc)
 	blockCache := NewBlockCache(st)
 
 	//------------------------------
@@ -488,12 +495,149 @@ func TestCallPermission(t *testing.T) {
 	}
 }
 
+func TestCallAllowList(t *testing.T) {
+	stateDB := dbm.NewDB("state", dbBackend, dbDir)
+	genDoc := newBaseGenDoc(PermsAllFalse, PermsAllFalse)
+	genDoc.Accounts[0].Permissions.Base.Set(ptypes.Call, true)
+	genDoc.Accounts[0].Permissions.Base.Set(ptypes.SetCallAllowList, true)
+	genDoc.Accounts[1].Permissions.Base.Set(ptypes.Call, true)
+	genDoc.Accounts[2].Permissions.Base.Set(ptypes.Call, true)
+	genDoc.Accounts[2].Permissions.AddRole("regulated")
+
+	simpleContractAddr := NewContractAddress(user[0].Address, 100)
+	otherContractAddr := NewContractAddress(user[0].Address, 101)
+	regulated := &ptypes.Role{Name: "regulated", Base: ptypes.ZeroBasePermissions,
+		CallAllowList: &ptypes.CallAllowList{Contracts: [][]byte{simpleContractAddr}}}
+	genDoc.Params.Roles = []*ptypes.Role{regulated}
+	st := makeGenesisState(t, stateDB, &genDoc)
+	blockCache := NewBlockCache(st)
+
+	for _, addr := range [][]byte{simpleContractAddr, otherContractAddr} {
+		blockCache.UpdateAccount(&acm.Account{
+			Address:     addr,
+			Code:        []byte{0x60},
+			StorageRoot: Zero256.Bytes(),
+			Permissions: ptypes.ZeroAccountPermissions,
+		})
+	}
+	execCallTx := func(u int, addr []byte) error {
+		tx, _ := txs.NewCallTx(blockCache, user[u].PubKey, addr, nil, 100, 100, 100)
+		tx.Sign(chainID, user[u])
+		return ExecTx(blockCache, tx, true, nil, logger)
+	}
+	execPermissionsTx := func(args ptypes.PermArgs) error {
+		tx, _ := txs.NewPermissionsTx(blockCache, user[0].PubKey, args)
+		tx.Sign(chainID, user[0])
+		return ExecTx(blockCache, tx, true, nil, logger)
+	}
+
+	fmt.Println("\n##### ACCOUNT ALLOW LIST")
+	// Without an allow list an account may call any contract
+	if err := execCallTx(1, otherContractAddr); err != nil {
+		t.Fatal("Transaction failed", err)
+	}
+	if err := execPermissionsTx(&ptypes.AllowCallArgs{Address: user[1].Address,
+		Contract: simpleContractAddr}); err != nil {
+		t.Fatal(err)
+	}
+	if err := execCallTx(1, simpleContractAddr); err != nil {
+		t.Fatal("Transaction failed", err)
+	}
+	if err := execCallTx(1, otherContractAddr); err == nil {
+		t.Fatal("Expected exception calling a contract not on the allow list")
+	}
+	if err := execPermissionsTx(&ptypes.UnrestrictCallsArgs{Address: user[1].Address}); err != nil {
+		t.Fatal(err)
+	}
+	if err := execCallTx(1, otherContractAddr); err != nil {
+		t.Fatal("Transaction failed", err)
+	}
+
+	fmt.Println("\n##### ROLE ALLOW LIST")
+	if err := execCallTx(2, simpleContractAddr); err != nil {
+		t.Fatal("Transaction failed", err)
+	}
+	if err := execCallTx(2, otherContractAddr); err == nil {
+		t.Fatal("Expected exception calling a contract not on the role's allow list")
+	}
+	// Emptying the allow list leaves the role restricted
+	if err := execPermissionsTx(&ptypes.DisallowCallArgs{Role: "regulated",
+		Contract: simpleContractAddr}); err != nil {
+		t.Fatal(err)
+	}
+	if err := execCallTx(2, simpleContractAddr); err == nil {
+		t.Fatal("Expected exception calling a contract removed from the role's allow list")
+	}
+
+	fmt.Println("\n##### CALL FROM CONTRACT")
+	callerContractAddr := NewContractAddress(user[0].Address, 102)
+	callerAcc := &acm.Account{
+		Address:     callerContractAddr,
+		Balance:     10000,
+		Code:        callContractCode(simpleContractAddr),
+		StorageRoot: Zero256.Bytes(),
+		Permissions: ptypes.ZeroAccountPermissions.Clone(),
+	}
+	callerAcc.Permissions.Base.Set(ptypes.Call, true)
+	callerAcc.Permissions.AllowCall(otherContractAddr)
+	blockCache.UpdateAccount(callerAcc)
+	tx, _ := txs.NewCallTx(blockCache, user[0].PubKey, callerContractAddr, nil, 100, 10000, 100)
+	tx.Sign(chainID, user[0])
+	_, exception := execTxWaitEvent(t, blockCache, tx, txs.EventStringAccCall(callerContractAddr))
+	if exception == "" {
+		t.Fatal("Expected exception")
+	}
+	if err := execPermissionsTx(&ptypes.AllowCallArgs{Address: callerContractAddr,
+		Contract: simpleContractAddr}); err != nil {
+		t.Fatal(err)
+	}
+	tx, _ = txs.NewCallTx(blockCache, user[0].PubKey, callerContractAddr, nil, 100, 10000, 100)
+	tx.Sign(chainID, user[0])
+	_, exception = execTxWaitEvent(t, blockCache, tx, txs.EventStringAccCall(callerContractAddr))
+	if exception != "" {
+		t.Fatal("Unexpected exception", exception)
+	}
+
+	fmt.Println("\n##### CALL THROUGH UNRESTRICTED CONTRACT")
+	// A restricted origin cannot reach a contract through one that may call it
+	proxyContractAddr := NewContractAddress(user[0].Address, 103)
+	proxyAcc := &acm.Account{
+		Address:     proxyContractAddr,
+		Balance:     10000,
+		Code:        callContractCode(otherContractAddr),
+		StorageRoot: Zero256.Bytes(),
+		Permissions: ptypes.ZeroAccountPermissions.Clone(),
+	}
+	proxyAcc.Permissions.Base.Set(ptypes.Call, true)
+	blockCache.UpdateAccount(proxyAcc)
+	if err := execPermissionsTx(&ptypes.AllowCallArgs{Address: user[1].Address,
+		Contract: proxyContractAddr}); err != nil {
+		t.Fatal(err)
+	}
+	tx, _ = txs.NewCallTx(blockCache, user[1].PubKey, proxyContractAddr, nil, 100, 10000, 100)
+	tx.Sign(chainID, user[1])
+	_, exception = execTxWaitEvent(t, blockCache, tx, txs.EventStringAccCall(proxyContractAddr))
+	if exception == "" {
+		t.Fatal("Expected exception calling a contract not on the origin's allow list")
+	}
+	if err := execPermissionsTx(&ptypes.AllowCallArgs{Address: user[1].Address,
+		Contract: otherContractAddr}); err != nil {
+		t.Fatal(err)
+	}
+	tx, _ = txs.NewCallTx(blockCache, user[1].PubKey, proxyContractAddr, nil, 100, 10000, 100)
+	tx.Sign(chainID, user[1])
+	_, exception = execTxWaitEvent(t, blockCache, tx, txs.EventStringAccCall(proxyContractAddr))
+	if exception != "" {
+		t.Fatal("Unexpected exception", exception)
+	}
+}
+
 func TestCreatePermission(t *testing.T) {
 	stateDB := dbm.NewDB("state", dbBackend, dbDir)
 	genDoc := newBaseGenDoc(PermsAllFalse, PermsAllFalse)
 	genDoc.Accounts[0].Permissions.Base.Set(ptypes.CreateContract, true) // give the 0 account permission
 	genDoc.Accounts[0].Permissions.Base.Set(ptypes.Call, true)           // give the 0 account permission
-	st := MakeGenesisState(stateDB, &genDoc)
+	st := makeGenesisState(t, stateDB, &genDoc)
 	blockCache := NewBlockCache(st)
 
 	//------------------------------
@@ -609,7 +753,7 @@ func TestCreatePermission(t *testing.T) {
 func TestBondPermission(t *testing.T) {
 	stateDB := dbm.NewDB("state",dbBackend,dbDir)
 	genDoc := newBaseGenDoc(PermsAllFalse, PermsAllFalse)
-	st := MakeGenesisState(stateDB, &genDoc)
+	st := makeGenesisState(t, stateDB, &genDoc)
 	blockCache := NewBlockCache(st)
 	var bondAcc *acm.Account
 
@@ -639,7 +783,7 @@ func TestBondPermission(t *testing.T) {
 
 	// reset state (we can only bond with an account once ..)
 	genDoc = newBaseGenDoc(PermsAllFalse, PermsAllFalse)
-	st = MakeGenesisState(stateDB, &genDoc)
+	st = makeGenesisState(t, stateDB, &genDoc)
 	blockCache = NewBlockCache(st)
 	bondAcc = blockCache.GetAccount(user[1].Address)
 	bondAcc.Permissions.Base.Set(ptypes.Bond, true)
@@ -661,7 +805,7 @@ func TestBondPermission(t *testing.T) {
 
 	// reset state (we can only bond with an account once ..)
 	genDoc = newBaseGenDoc(PermsAllFalse, PermsAllFalse)
-	st = MakeGenesisState(stateDB, &genDoc)
+	st = makeGenesisState(t, stateDB, &genDoc)
 	blockCache = NewBlockCache(st)
 	bondAcc = blockCache.GetAccount(user[1].Address)
 	bondAcc.Permissions.Base.Set(ptypes.Bond, true)
@@ -684,7 +828,7 @@ func TestBondPermission(t *testing.T) {
 
 	// reset state (we can only bond with an account once ..)
 	genDoc = newBaseGenDoc(PermsAllFalse, PermsAllFalse)
-	st = MakeGenesisState(stateDB, &genDoc)
+	st = makeGenesisState(t, stateDB, &genDoc)
 	blockCache = NewBlockCache(st)
 	bondAcc = blockCache.GetAccount(user[1].Address)
 	bondAcc.Permissions.Base.Set(ptypes.Bond, true)
@@ -706,7 +850,7 @@ func TestBondPermission(t *testing.T) {
 
 	// reset state (we can only bond with an account once ..)
 	genDoc = newBaseGenDoc(PermsAllFalse, PermsAllFalse)
-	st = MakeGenesisState(stateDB, &genDoc)
+	st = makeGenesisState(t, stateDB, &genDoc)
 	blockCache = NewBlockCache(st)
 	bondAcc = blockCache.GetAccount(user[1].Address)
 	bondAcc.Permissions.Base.Set(ptypes.Bond, true)
@@ -736,7 +880,7 @@ func TestCreateAccountPermission(t *testing.T) {
 	genDoc.Accounts[0].Permissions.Base.Set(ptypes.Send, true)          // give the 0 account permission
 	genDoc.Accounts[1].Permissions.Base.Set(ptypes.Send, true)          // give the 0 account permission
 	genDoc.Accounts[0].Permissions.Base.Set(ptypes.CreateAccount, true) // give the 0 account permission
-	st := MakeGenesisState(stateDB, &genDoc)
+	st := makeGenesisState(t, stateDB, &genDoc)
 	blockCache := NewBlockCache(st)
 
 	//----------------------------------------------------------
@@ -880,7 +1024,7 @@ func TestSNativeCALL(t *testing.T) {
 	genDoc.Accounts[3].Permissions.Base.Set(ptypes.Bond, true) // some arbitrary permission to play with
 	genDoc.Accounts[3].Permissions.AddRole("bumble")
 	genDoc.Accounts[3].Permissions.AddRole("bee")
-	st := MakeGenesisState(stateDB, &genDoc)
+	st := makeGenesisState(t, stateDB, &genDoc)
 	blockCache := NewBlockCache(st)
 
 	//----------------------------------------------------------
@@ -1007,6 +1151,80 @@ func TestSNativeCALL(t *testing.T) {
 	})
 }
 
+func TestSNativeNameRegCALL(t *testing.T) {
+	stateDB := dbm.NewDB("state", dbBackend, dbDir)
+	genDoc := newBaseGenDoc(PermsAllFalse, PermsAllFalse)
+	genDoc.Accounts[0].Permissions.Base.Set(ptypes.Call, true) // give the 0 account permission
+	st := makeGenesisState(t, stateDB, &genDoc)
+	blockCache := NewBlockCache(st)
+
+	doug := &acm.Account{
+		Address:     DougAddress,
+		Balance:     0,
+		Code:        nil,
+		Sequence:    0,
+		StorageRoot: Zero256.Bytes(),
+		Permissions: ptypes.ZeroAccountPermissions,
+	}
+	doug.Permissions.Base.Set(ptypes.Call, true)
+	blockCache.UpdateAccount(doug)
+
+	name := "doug.name"
+	blockCache.UpdateNameRegEntry(&core_types.NameRegEntry{
+		Name:    name,
+		Owner:   DougAddress,
+		Data:    "data",
+		Expires: st.LastBlockHeight + 100,
+	})
+
+	fmt.Println("\n#### GetOwner")
+	snativeAddress, data := snativeNameRegTestInputCALL("getOwner", name)
+	testSNativeCALLExpectPass(t, blockCache, doug, ptypes.Call, snativeAddress, data, func(ret []byte) error {
+		if !bytes.Equal(ret, LeftPadBytes(DougAddress, 32)) {
+			return fmt.Errorf("Expected %X. Got %X", DougAddress, ret)
+		}
+		return nil
+	})
+
+	fmt.Println("\n#### SetData")
+	snativeAddress, data = snativeNameRegTestInputCALL("setData", name, "new data")
+	testSNativeCALLExpectFail(t, blockCache, doug, snativeAddress, data)
+	testSNativeCALLExpectPass(t, blockCache, doug, ptypes.Name, snativeAddress, data, func(ret []byte) error {
+		// the credit of 100 blocks of 4 bytes of data is spread over 8 bytes
+		expires := Uint64ToWord256(uint64(st.LastBlockHeight + 90)).Bytes()
+		if !bytes.Equal(ret, expires) {
+			return fmt.Errorf("Expected %X. Got %X", expires, ret)
+		}
+		return nil
+	})
+	snativeAddress, data = snativeNameRegTestInputCALL("getData", name)
+	testSNativeCALLExpectPass(t, blockCache, doug, ptypes.Call, snativeAddress, data, func(ret []byte) error {
+		values, err := abi.Unpack([]*abi.Type{abi.MustParseType("string")}, ret)
+		if err != nil {
+			return err
+		}
+		if values[0] != "new data" {
+			return fmt.Errorf("Expected 'new data'. Got %v", values[0])
+		}
+		return nil
+	})
+
+	fmt.Println("\n#### Transfer")
+	snativeAddress, data = snativeNameRegTestInputCALL("transfer", name, user[1].Address)
+	testSNativeCALLExpectPass(t, blockCache, doug, ptypes.Name, snativeAddress, data, func(ret []byte) error {
+		if !IsZeros(ret[:31]) || ret[31] != byte(1) {
+			return fmt.Errorf("Expected 1. Got %X", ret)
+		}
+		return nil
+	})
+	if entry := blockCache.GetNameRegEntry(name); !bytes.Equal(entry.Owner, user[1].Address) {
+		t.Fatalf("Expected name to be owned by %X. Got %X", user[1].Address, entry.Owner)
+	}
+	// doug no longer owns the name so cannot change it
+	snativeAddress, data = snativeNameRegTestInputCALL("setData", name, "more data")
+	testSNativeCALL(t, false, blockCache, doug, ptypes.Name, snativeAddress, data, nil)
+}
+
 func TestSNativeTx(t *testing.T) {
 	stateDB := dbm.NewDB("state", dbBackend, dbDir)
 	genDoc := newBaseGenDoc(PermsAllFalse, PermsAllFalse)
@@ -1014,7 +1232,7 @@ func TestSNativeTx(t *testing.T) {
 	genDoc.Accounts[3].Permissions.Base.Set(ptypes.Bond, true) // some arbitrary permission to play with
 	genDoc.Accounts[3].Permissions.AddRole("bumble")
 	genDoc.Accounts[3].Permissions.AddRole("bee")
-	st := MakeGenesisState(stateDB, &genDoc)
+	st := makeGenesisState(t, stateDB, &genDoc)
 	blockCache := NewBlockCache(st)
 
 	//----------------------------------------------------------
@@ -1077,6 +1295,326 @@ func TestSNativeTx(t *testing.T) {
 	}
 }
 
+func TestRoles(t *testing.T) {
+	stateDB := dbm.NewDB("state", dbBackend, dbDir)
+	genDoc := newBaseGenDoc(PermsAllFalse, PermsAllFalse)
+	developers := &ptypes.Role{Name: "developers", Base: ptypes.ZeroBasePermissions,
+		Inherits: []string{"users"}}
+	developers.Base.Set(ptypes.CreateContract, true)
+	users := &ptypes.Role{Name: "users", Base: ptypes.ZeroBasePermissions, Admin: "leads"}
+	users.Base.Set(ptypes.Call, true)
+	users.Base.Set(ptypes.Send, false)
+	genDoc.Params.Roles = []*ptypes.Role{developers, users}
+	genDoc.Accounts[1].Permissions.AddRole("developers")
+	genDoc.Accounts[2].Permissions.AddRole("leads")
+	genDoc.Accounts[3].Permissions.Base.Set(ptypes.Send, true)
+	genDoc.Accounts[3].Permissions.AddRole("users")
+	st := makeGenesisState(t, stateDB, &genDoc)
+	blockCache := NewBlockCache(st)
+
+	fmt.Println("\n#### Role permissions")
+	acc := blockCache.GetAccount(user[1].Address)
+	if !HasPermission(blockCache, acc, ptypes.CreateContract, logger) {
+		t.Fatal("expected permission from role")
+	}
+	if !HasPermission(blockCache, acc, ptypes.Call, logger) {
+		t.Fatal("expected permission from inherited role")
+	}
+	if HasPermission(blockCache, acc, ptypes.Send, logger) {
+		t.Fatal("expected permission to be denied by inherited role")
+	}
+	// The account's own permissions take precedence over its roles
+	acc = blockCache.GetAccount(user[3].Address)
+	if !HasPermission(blockCache, acc, ptypes.Send, logger) {
+		t.Fatal("expected account permission to override role")
+	}
+	if HasPermission(blockCache, acc, ptypes.CreateContract, logger) {
+		t.Fatal("expected no permission from a role the account does not have")
+	}
+
+	fmt.Println("\n#### Role admin")
+	// Members of the admin role may add members without the AddRole permission
+	tx, _ := txs.NewPermissionsTx(blockCache, user[2].PubKey, &ptypes.AddRoleArgs{user[4].Address, "users"})
+	tx.Sign(chainID, user[2])
+	if err := ExecTx(blockCache, tx, true, nil, logger); err != nil {
+		t.Fatal(err)
+	}
+	acc = blockCache.GetAccount(user[4].Address)
+	if !HasPermission(blockCache, acc, ptypes.Call, logger) {
+		t.Fatal("expected permission from role added by admin")
+	}
+	// but only to the roles they administer
+	tx, _ = txs.NewPermissionsTx(blockCache, user[2].PubKey, &ptypes.AddRoleArgs{user[4].Address, "developers"})
+	tx.Sign(chainID, user[2])
+	if err := ExecTx(blockCache, tx, true, nil, logger); err == nil {
+		t.Fatal("expected exception adding a role without being its admin")
+	}
+
+	fmt.Println("\n#### SetRole")
+	testers := ptypes.Role{Name: "testers", Base: ptypes.ZeroBasePermissions, Inherits: []string{"developers"}}
+	testers.Base.Set(ptypes.Name, true)
+	snativeArgs := &ptypes.SetRoleArgs{testers}
+	testSNativeTxExpectFail(t, blockCache, snativeArgs)
+	testSNativeTxExpectPass(t, blockCache, ptypes.SetRole, snativeArgs)
+	role := blockCache.GetRole("testers")
+	if role == nil {
+		t.Fatal("expected role to be set")
+	}
+	if v, _ := role.Base.Get(ptypes.Name); !v {
+		t.Fatal("expected role permission to be set true")
+	}
+	testSNativeTxExpectPass(t, blockCache, ptypes.AddRole, &ptypes.AddRoleArgs{user[4].Address, "testers"})
+	acc = blockCache.GetAccount(user[4].Address)
+	if !HasPermission(blockCache, acc, ptypes.CreateContract, logger) {
+		t.Fatal("expected permission from role inherited by new role")
+	}
+}
+
+// The global permissions of chains made before the permissions after RmRole
+// were introduced do not set them
+const legacyPermFlags = ptypes.RmRole | (ptypes.RmRole - 1)
+
+// Load a state saved with legacy global permissions, whose accounts set no
+// permissions of their own
+func loadLegacyGlobalState(t *testing.T) *State {
+	stateDB := dbm.NewDB("state", dbBackend, dbDir)
+	genDoc := newBaseGenDoc(PermsAllFalse, PermsAllFalse)
+	st := makeGenesisState(t, stateDB, &genDoc)
+	globalAcc := st.GetAccount(ptypes.GlobalPermissionsAddress)
+	globalAcc.Permissions.Base.Perms &= legacyPermFlags
+	globalAcc.Permissions.Base.SetBit &= legacyPermFlags
+	st.UpdateAccount(globalAcc)
+	st.Save()
+	st = LoadState(stateDB)
+	if st == nil {
+		t.Fatal("expected state to load")
+	}
+	return st
+}
+
+func TestLegacyGlobalPermissions(t *testing.T) {
+	blockCache := NewBlockCache(loadLegacyGlobalState(t))
+	acc := blockCache.GetAccount(user[0].Address)
+	if HasPermission(blockCache, acc, ptypes.SetRole, logger) {
+		t.Fatal("expected permission missing from global permissions to be denied")
+	}
+	testers := ptypes.Role{Name: "testers", Base: ptypes.ZeroBasePermissions}
+	tx, _ := txs.NewPermissionsTx(blockCache, user[0].PubKey, &ptypes.SetRoleArgs{testers})
+	tx.Sign(chainID, user[0])
+	if err := ExecTx(blockCache, tx, true, nil, logger); err == nil {
+		t.Fatal("expected exception setting a role without the permission")
+	}
+
+	tx, _ = txs.NewPermissionsTx(blockCache, user[0].PubKey,
+		&ptypes.AllowCallArgs{Address: user[1].Address, Contract: user[2].Address})
+	tx.Sign(chainID, user[0])
+	if err := ExecTx(blockCache, tx, true, nil, logger); err == nil {
+		t.Fatal("expected exception changing a call allow list without the permission")
+	}
+}
+
+func TestPermissionExpiry(t *testing.T) {
+	stateDB := dbm.NewDB("state", dbBackend, dbDir)
+	genDoc := newBaseGenDoc(PermsAllFalse, PermsAllFalse)
+	genDoc.Accounts[0].Permissions.Base.Set(ptypes.SetBase, true)
+	genDoc.Accounts[0].Permissions.Base.Set(ptypes.AddRole, true)
+	contractors := &ptypes.Role{Name: "contractors", Base: ptypes.ZeroBasePermissions}
+	contractors.Base.Set(ptypes.CreateContract, true)
+	genDoc.Params.Roles = []*ptypes.Role{contractors}
+	st := makeGenesisState(t, stateDB, &genDoc)
+	st.LastBlockHeight = 10
+	blockCache := NewBlockCache(st)
+
+	execPermissionsTx := func(args ptypes.PermArgs) error {
+		tx, _ := txs.NewPermissionsTx(blockCache, user[0].PubKey, args)
+		tx.Sign(chainID, user[0])
+		return ExecTx(blockCache, tx, true, nil, logger)
+	}
+
+	fmt.Println("\n#### Grant until")
+	// Expiries must be after the last block height
+	if err := execPermissionsTx(&ptypes.SetBaseUntilArgs{user[1].Address, ptypes.Call, true, 10}); err == nil {
+		t.Fatal("expected exception granting a permission that has already expired")
+	}
+	if err := execPermissionsTx(&ptypes.SetBaseUntilArgs{user[1].Address, ptypes.Call, true, 20}); err != nil {
+		t.Fatal(err)
+	}
+	// The expiry is carried by the permissions event
+	tx, _ := txs.NewPermissionsTx(blockCache, user[0].PubKey,
+		&ptypes.AddRoleUntilArgs{user[1].Address, "contractors", 20})
+	tx.Sign(chainID, user[0])
+	ev, exception := execTxWaitEvent(t, blockCache, tx, txs.EventStringPermissions("addRole"))
+	if exception != "" {
+		t.Fatal("Unexpected exception", exception)
+	}
+	evTx := ev.(txs.EventDataTx).Tx.(*txs.PermissionsTx)
+	if expires := evTx.PermArgs.(*ptypes.AddRoleUntilArgs).Expires; expires != 20 {
+		t.Fatalf("expected event to carry expiry 20 but got %v", expires)
+	}
+
+	acc := blockCache.GetAccount(user[1].Address)
+	if acc.Permissions.BaseExpiry(ptypes.Call) != 20 || acc.Permissions.RoleExpiry("contractors") != 20 {
+		t.Fatal("expected grants to expire at 20")
+	}
+	st.LastBlockHeight = 19
+	if !HasPermission(blockCache, acc, ptypes.Call, logger) {
+		t.Fatal("expected permission to hold until its expiry")
+	}
+	if !HasPermission(blockCache, acc, ptypes.CreateContract, logger) {
+		t.Fatal("expected role to hold until its expiry")
+	}
+
+	fmt.Println("\n#### Expired")
+	st.LastBlockHeight = 20
+	if HasPermission(blockCache, acc, ptypes.Call, logger) {
+		t.Fatal("expected permission to have expired")
+	}
+	if HasPermission(blockCache, acc, ptypes.CreateContract, logger) {
+		t.Fatal("expected role to have expired")
+	}
+
+	fmt.Println("\n#### Grant permanently")
+	// Adding the role again without an expiry makes it permanent
+	if err := execPermissionsTx(&ptypes.AddRoleArgs{user[1].Address, "contractors"}); err != nil {
+		t.Fatal(err)
+	}
+	acc = blockCache.GetAccount(user[1].Address)
+	if acc.Permissions.RoleExpiry("contractors") != 0 {
+		t.Fatal("expected role to no longer expire")
+	}
+	if !HasPermission(blockCache, acc, ptypes.CreateContract, logger) {
+		t.Fatal("expected role to be held permanently")
+	}
+}
+
+func TestGovernance(t *testing.T) {
+	stateDB := dbm.NewDB("state", dbBackend, dbDir)
+	genDoc := newBaseGenDoc(PermsAllFalse, PermsAllFalse)
+	genDoc.Params.Governance = &ptypes.GovernanceParams{Threshold: 2, ProposalExpiry: 5}
+	for i := 0; i < 3; i++ {
+		genDoc.Accounts[i].Permissions.Base.Set(ptypes.Govern, true)
+	}
+	genDoc.Accounts[0].Permissions.Base.Set(ptypes.SetBase, true)
+	genDoc.Accounts[0].Permissions.Base.Set(ptypes.SetGlobal, true)
+	genDoc.Accounts[3].Permissions.Base.Set(ptypes.SetGlobal, true)
+	genDoc.Accounts[0].Permissions.Base.Set(ptypes.AddRole, true)
+	genDoc.Accounts[0].Permissions.Base.Set(ptypes.SetRole, true)
+	admins := &ptypes.Role{Name: "admins", Base: ptypes.ZeroBasePermissions, Admin: "owners"}
+	admins.Base.Set(ptypes.Root, true)
+	leads := &ptypes.Role{Name: "leads", Base: ptypes.ZeroBasePermissions, Inherits: []string{"admins"}}
+	users := &ptypes.Role{Name: "users", Base: ptypes.ZeroBasePermissions}
+	genDoc.Params.Roles = []*ptypes.Role{admins, leads, users}
+	genDoc.Accounts[3].Permissions.AddRole("owners")
+	st := makeGenesisState(t, stateDB, &genDoc)
+	st.LastBlockHeight = 10
+	blockCache := NewBlockCache(st)
+
+	signPermissionsTx := func(u int, args ptypes.PermArgs) *txs.PermissionsTx {
+		tx, _ := txs.NewPermissionsTx(blockCache, user[u].PubKey, args)
+		tx.Sign(chainID, user[u])
+		return tx
+	}
+	execPermissionsTx := func(u int, args ptypes.PermArgs) error {
+		return ExecTx(blockCache, signPermissionsTx(u, args), true, nil, logger)
+	}
+	globalSend := func() bool {
+		return HasPermission(nil, blockCache.GetAccount(ptypes.GlobalPermissionsAddress), ptypes.Send, logger)
+	}
+
+	fmt.Println("\n#### Propose")
+	// SetGlobal alone no longer suffices to change global permissions
+	if err := execPermissionsTx(3, &ptypes.SetGlobalArgs{ptypes.Send, true}); err == nil {
+		t.Fatal("expected exception setting a global permission without govern")
+	}
+	tx := signPermissionsTx(0, &ptypes.SetGlobalArgs{ptypes.Send, true})
+	if err := ExecTx(blockCache, tx, true, nil, logger); err != nil {
+		t.Fatal(err)
+	}
+	hash := txs.TxHash(chainID, tx)
+	proposal := blockCache.GetProposal(hash)
+	if proposal == nil || proposal.Status(blockCache.LastBlockHeight()) != ptypes.ProposalStatusPending {
+		t.Fatalf("expected pending proposal but got %v", proposal)
+	}
+	if globalSend() {
+		t.Fatal("expected proposal not to be executed before it is approved")
+	}
+
+	fmt.Println("\n#### Approve")
+	if err := execPermissionsTx(0, &ptypes.ApproveArgs{hash}); err == nil {
+		t.Fatal("expected exception approving a proposal twice")
+	}
+	if err := execPermissionsTx(3, &ptypes.ApproveArgs{hash}); err == nil {
+		t.Fatal("expected exception approving without govern")
+	}
+	if err := execPermissionsTx(1, &ptypes.ApproveArgs{[]byte("not a proposal")}); err == nil {
+		t.Fatal("expected exception approving an unknown proposal")
+	}
+	_, exception := execTxWaitEvent(t, blockCache, signPermissionsTx(1, &ptypes.ApproveArgs{hash}),
+		txs.EventStringPermissions("setGlobal"))
+	if exception != "" {
+		t.Fatal("Unexpected exception", exception)
+	}
+	if !globalSend() {
+		t.Fatal("expected proposal to be executed once approved")
+	}
+	if proposal = blockCache.GetProposal(hash); proposal.ExecutedHeight != 11 {
+		t.Fatalf("expected proposal to be executed at height 11 but got %v", proposal.ExecutedHeight)
+	}
+	if err := execPermissionsTx(2, &ptypes.ApproveArgs{hash}); err == nil {
+		t.Fatal("expected exception approving an executed proposal")
+	}
+
+	fmt.Println("\n#### Expire")
+	tx = signPermissionsTx(0, &ptypes.SetBaseArgs{user[3].Address, ptypes.Root, true})
+	if err := ExecTx(blockCache, tx, true, nil, logger); err != nil {
+		t.Fatal(err)
+	}
+	hash = txs.TxHash(chainID, tx)
+	st.LastBlockHeight = 15
+	if err := execPermissionsTx(1, &ptypes.ApproveArgs{hash}); err == nil {
+		t.Fatal("expected exception approving an expired proposal")
+	}
+	if HasPermission(blockCache, blockCache.GetAccount(user[3].Address), ptypes.Root, logger) {
+		t.Fatal("expected expired proposal not to be executed")
+	}
+
+	fmt.Println("\n#### Roles granting Root")
+	// Role admins may not add members to roles granting Root without approval
+	if err := execPermissionsTx(3, &ptypes.AddRoleArgs{user[4].Address, "admins"}); err == nil {
+		t.Fatal("expected exception adding a member to a governed role as its admin")
+	}
+	// and adding members to roles inheriting Root is proposed like other
+	// governed changes
+	if err := execPermissionsTx(0, &ptypes.AddRoleArgs{user[4].Address, "leads"}); err != nil {
+		t.Fatal(err)
+	}
+	if blockCache.GetAccount(user[4].Address).Permissions.HasRole("leads") {
+		t.Fatal("expected member not to be added to a role inheriting Root before approval")
+	}
+	if err := execPermissionsTx(0, &ptypes.SetRoleArgs{ptypes.Role{Name: "users",
+		Base: ptypes.ZeroBasePermissions, Inherits: []string{"leads"}}}); err != nil {
+		t.Fatal(err)
+	}
+	if len(blockCache.GetRole("users").Inherits) != 0 {
+		t.Fatal("expected role not to inherit a role granting Root before approval")
+	}
+	if err := execPermissionsTx(0, &ptypes.AddRoleArgs{user[4].Address, "users"}); err != nil {
+		t.Fatal(err)
+	}
+	if !blockCache.GetAccount(user[4].Address).Permissions.HasRole("users") {
+		t.Fatal("expected member to be added straight away to a role not granting Root")
+	}
+
+	fmt.Println("\n#### Ungoverned")
+	// Changes that are not governed take effect straight away
+	if err := execPermissionsTx(0, &ptypes.SetBaseArgs{user[3].Address, ptypes.Call, true}); err != nil {
+		t.Fatal(err)
+	}
+	if !HasPermission(blockCache, blockCache.GetAccount(user[3].Address), ptypes.Call, logger) {
+		t.Fatal("expected ungoverned change to be made straight away")
+	}
+}
+
 //-------------------------------------------------------------------------------------
 // helpers
 
@@ -1229,6 +1767,24 @@ func snativePermTestInputCALL(name string, user *acm.PrivAccount, perm ptypes.Pe
 	return
 }
 
+func snativeNameRegTestInputCALL(name string, args ...interface{}) (addr []byte, data []byte) {
+	addr = nameRegContract.AddressBytes()
+	function, err := nameRegContract.FunctionByName(name)
+	if err != nil {
+		panic("didn't find snative function signature!")
+	}
+	types := make([]*abi.Type, len(function.Args))
+	for i, arg := range function.Args {
+		types[i] = abi.MustParseType(string(arg.TypeName))
+	}
+	if data, err = abi.Pack(types, args); err != nil {
+		panic(fmt.Sprintf("failed to pack arguments for %s: %s", name, err))
+	}
+	id := function.ID()
+	data = append(id[:], data...)
+	return
+}
+
 func snativePermTestInputTx(name string, user *acm.PrivAccount, perm ptypes.PermFlag, val bool) (snativeArgs ptypes.PermArgs) {
 	switch name {
 	case "hasBase":
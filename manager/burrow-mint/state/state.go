@@ -16,6 +16,7 @@ package state
 
 import (
 	"bytes"
+	"encoding/binary"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -55,12 +56,28 @@ type State struct {
 	LastBlockHash   []byte
 	LastBlockParts  types.PartSetHeader
 	LastBlockTime   time.Time
+	// How account addresses are derived from public keys, from the genesis
+	// params rather than saved with the state
+	AddressScheme string
+	// Pricing and data validation of the name registry, also from the genesis
+	// params
+	NameRegParams txs.NameRegParams
+	// Approval of governed permission changes, also from the genesis params and
+	// nil when governance is disabled
+	GovernanceParams *ptypes.GovernanceParams
 	//	BondedValidators     *types.ValidatorSet
 	//	LastBondedValidators *types.ValidatorSet
 	//	UnbondingValidators  *types.ValidatorSet
 	accounts       merkle.Tree // Shouldn't be accessed directly.
 	validatorInfos merkle.Tree // Shouldn't be accessed directly.
 	nameReg        merkle.Tree // Shouldn't be accessed directly.
+	roles          merkle.Tree // Shouldn't be accessed directly.
+	proposals      merkle.Tree // Shouldn't be accessed directly.
+
+	// nameReg indexed by expiry (see nameRegExpiryKey) so expired entries can be
+	// found without walking the registry. Being derived from nameReg it is not
+	// part of the state hash.
+	nameRegExpiries merkle.Tree
 
 	evc events.Fireable // typically an events.EventCache
 }
@@ -89,6 +106,28 @@ func LoadState(db dbm.DB) *State {
 		nameRegHash := wire.ReadByteSlice(r, maxLoadStateElementSize, n, err)
 		s.nameReg = merkle.NewIAVLTree(0, db)
 		s.nameReg.Load(nameRegHash)
+		s.roles = merkle.NewIAVLTree(0, db)
+		// states saved before roles were introduced end here
+		if r.Len() > 0 {
+			rolesHash := wire.ReadByteSlice(r, maxLoadStateElementSize, n, err)
+			s.roles.Load(rolesHash)
+		}
+		s.proposals = merkle.NewIAVLTree(0, db)
+		// and those saved before governance proposals here
+		if r.Len() > 0 {
+			proposalsHash := wire.ReadByteSlice(r, maxLoadStateElementSize, n, err)
+			s.proposals.Load(proposalsHash)
+		}
+		// and those saved before the name registry expiry index here, so it is
+		// built from the registry and saved since unsaved trees cannot be copied
+		if r.Len() > 0 {
+			nameRegExpiriesHash := wire.ReadByteSlice(r, maxLoadStateElementSize, n, err)
+			s.nameRegExpiries = merkle.NewIAVLTree(0, db)
+			s.nameRegExpiries.Load(nameRegExpiriesHash)
+		} else {
+			s.nameRegExpiries = makeNameRegExpiries(db, s.nameReg)
+			s.nameRegExpiries.Save()
+		}
 		if *err != nil {
 			// DATA HAS BEEN CORRUPTED OR THE SPEC HAS CHANGED
 			util.Fatalf("Data has been corrupted or its spec has changed: %v\n", *err)
@@ -102,6 +141,9 @@ func (s *State) Save() {
 	s.accounts.Save()
 	//s.validatorInfos.Save()
 	s.nameReg.Save()
+	s.roles.Save()
+	s.proposals.Save()
+	s.nameRegExpiries.Save()
 	buf, n, err := new(bytes.Buffer), new(int), new(error)
 	wire.WriteString(s.ChainID, buf, n, err)
 	wire.WriteVarint(s.LastBlockHeight, buf, n, err)
@@ -114,6 +156,9 @@ func (s *State) Save() {
 	wire.WriteByteSlice(s.accounts.Hash(), buf, n, err)
 	//wire.WriteByteSlice(s.validatorInfos.Hash(), buf, n, err)
 	wire.WriteByteSlice(s.nameReg.Hash(), buf, n, err)
+	wire.WriteByteSlice(s.roles.Hash(), buf, n, err)
+	wire.WriteByteSlice(s.proposals.Hash(), buf, n, err)
+	wire.WriteByteSlice(s.nameRegExpiries.Hash(), buf, n, err)
 	if *err != nil {
 		// TODO: [Silas] Do something better than this, really serialising ought to
 		// be error-free
@@ -128,31 +173,45 @@ func (s *State) Save() {
 // as if State were copied by value.
 func (s *State) Copy() *State {
 	return &State{
-		DB:              s.DB,
-		ChainID:         s.ChainID,
-		LastBlockHeight: s.LastBlockHeight,
-		LastBlockHash:   s.LastBlockHash,
-		LastBlockParts:  s.LastBlockParts,
-		LastBlockTime:   s.LastBlockTime,
+		DB:               s.DB,
+		ChainID:          s.ChainID,
+		LastBlockHeight:  s.LastBlockHeight,
+		LastBlockHash:    s.LastBlockHash,
+		LastBlockParts:   s.LastBlockParts,
+		LastBlockTime:    s.LastBlockTime,
+		AddressScheme:    s.AddressScheme,
+		NameRegParams:    s.NameRegParams,
+		GovernanceParams: s.GovernanceParams,
 		// BondedValidators:     s.BondedValidators.Copy(),     // TODO remove need for Copy() here.
 		// LastBondedValidators: s.LastBondedValidators.Copy(), // That is, make updates to the validator set
 		// UnbondingValidators: s.UnbondingValidators.Copy(), // copy the valSet lazily.
 		accounts: s.accounts.Copy(),
 		//validatorInfos:       s.validatorInfos.Copy(),
-		nameReg: s.nameReg.Copy(),
-		evc:     nil,
+		nameReg:         s.nameReg.Copy(),
+		nameRegExpiries: s.nameRegExpiries.Copy(),
+		roles:           s.roles.Copy(),
+		proposals:       s.proposals.Copy(),
+		evc:             nil,
 	}
 }
 
 // Returns a hash that represents the state data, excluding Last*
 func (s *State) Hash() []byte {
-	return merkle.SimpleHashFromMap(map[string]interface{}{
+	hashables := map[string]interface{}{
 		//"BondedValidators":    s.BondedValidators,
 		//"UnbondingValidators": s.UnbondingValidators,
 		"Accounts": s.accounts,
 		//"ValidatorInfos":      s.validatorInfos,
 		"NameRegistry": s.nameReg,
-	})
+	}
+	// so that the hash of chains that define no roles is unchanged
+	if s.roles.Size() > 0 {
+		hashables["Roles"] = s.roles
+	}
+	if s.proposals.Size() > 0 {
+		hashables["Proposals"] = s.proposals
+	}
+	return merkle.SimpleHashFromMap(hashables)
 }
 
 /* //XXX Done by tendermint core
@@ -370,6 +429,10 @@ func DecodeNameRegEntry(entryBytes []byte) *core_types.NameRegEntry {
 }
 
 func (s *State) UpdateNameRegEntry(entry *core_types.NameRegEntry) bool {
+	if existing := s.GetNameRegEntry(entry.Name); existing != nil {
+		s.nameRegExpiries.Remove(nameRegExpiryKey(existing.Expires, existing.Name))
+	}
+	s.nameRegExpiries.Set(nameRegExpiryKey(entry.Expires, entry.Name), []byte(entry.Name))
 	w := new(bytes.Buffer)
 	var n int
 	var err error
@@ -378,6 +441,9 @@ func (s *State) UpdateNameRegEntry(entry *core_types.NameRegEntry) bool {
 }
 
 func (s *State) RemoveNameRegEntry(name string) bool {
+	if existing := s.GetNameRegEntry(name); existing != nil {
+		s.nameRegExpiries.Remove(nameRegExpiryKey(existing.Expires, existing.Name))
+	}
 	_, removed := s.nameReg.Remove([]byte(name))
 	return removed
 }
@@ -386,17 +452,49 @@ func (s *State) GetNames() merkle.Tree {
 	return s.nameReg.Copy()
 }
 
-// Set the name reg tree
+// Get the names of the registry keyed by nameRegExpiryKey, so iterating it
+// visits entries in order of expiry
+func (s *State) GetNameRegExpiries() merkle.Tree {
+	return s.nameRegExpiries.Copy()
+}
+
+// Set the name reg tree. The expiry index is not rebuilt, so nameReg must hold
+// the same entries as the tree it replaces, as a copy from GetNames does.
 func (s *State) SetNameReg(nameReg merkle.Tree) {
 	s.nameReg = nameReg
 }
 
+// Keys of the name registry expiry index, ordered by the block height entries
+// expire at and then by name
+func nameRegExpiryKey(expires int, name string) []byte {
+	key := make([]byte, 8+len(name))
+	binary.BigEndian.PutUint64(key, uint64(expires))
+	copy(key[8:], name)
+	return key
+}
+
+// Get the block height at which the entry with expiry index key expires
+func nameRegExpiryFromKey(key []byte) int {
+	return int(binary.BigEndian.Uint64(key[:8]))
+}
+
+func makeNameRegExpiries(db dbm.DB, nameReg merkle.Tree) merkle.Tree {
+	nameRegExpiries := merkle.NewIAVLTree(0, db)
+	nameReg.Iterate(func(key, value []byte) bool {
+		nameRegExpiries.Set(nameRegExpiryKey(DecodeNameRegEntry(value).Expires, string(key)), key)
+		return false
+	})
+	return nameRegExpiries
+}
+
 func NameRegEncoder(o interface{}, w io.Writer, n *int, err *error) {
 	wire.WriteBinary(o.(*core_types.NameRegEntry), w, n, err)
 }
 
 func NameRegDecoder(r io.Reader, n *int, err *error) interface{} {
-	return wire.ReadBinary(&core_types.NameRegEntry{}, r, txs.MaxDataLength, n, err)
+	// the maximum data length is a chain param enforced on NameTx so entries
+	// already in state are read without a limit
+	return wire.ReadBinary(&core_types.NameRegEntry{}, r, maxLoadStateElementSize, n, err)
 }
 
 var NameRegCodec = wire.Codec{
@@ -406,6 +504,67 @@ var NameRegCodec = wire.Codec{
 
 // State.nameReg
 //-------------------------------------
+// State.roles
+
+// Returns nil if the role has not been defined
+func (s *State) GetRole(name string) *ptypes.Role {
+	_, roleBytes, _ := s.roles.Get([]byte(ptypes.RoleName(name)))
+	if roleBytes == nil {
+		return nil
+	}
+	return DecodeRole(roleBytes)
+}
+
+func DecodeRole(roleBytes []byte) *ptypes.Role {
+	var n int
+	var err error
+	role := wire.ReadBinary(&ptypes.Role{}, bytes.NewBuffer(roleBytes),
+		maxLoadStateElementSize, &n, &err)
+	return role.(*ptypes.Role)
+}
+
+// The role is normalised (see ptypes.Role.Normalise) before setting
+func (s *State) UpdateRole(role *ptypes.Role) bool {
+	role = role.Normalise()
+	return s.roles.Set([]byte(role.Name), wire.BinaryBytes(role))
+}
+
+func (s *State) GetRoles() merkle.Tree {
+	return s.roles.Copy()
+}
+
+// State.roles
+//-------------------------------------
+// State.proposals
+
+// Returns nil if no proposal has the hash
+func (s *State) GetProposal(hash []byte) *ptypes.Proposal {
+	_, proposalBytes, _ := s.proposals.Get(hash)
+	if proposalBytes == nil {
+		return nil
+	}
+	return DecodeProposal(proposalBytes)
+}
+
+func DecodeProposal(proposalBytes []byte) *ptypes.Proposal {
+	var n int
+	var err error
+	proposal := wire.ReadBinary(&ptypes.Proposal{}, bytes.NewBuffer(proposalBytes),
+		maxLoadStateElementSize, &n, &err)
+	return proposal.(*ptypes.Proposal)
+}
+
+// Proposals are kept once executed or expired as a record of governance
+func (s *State) UpdateProposal(proposal *ptypes.Proposal) bool {
+	return s.proposals.Set(proposal.Hash, wire.BinaryBytes(proposal))
+}
+
+func (s *State) GetProposals() merkle.Tree {
+	return s.proposals.Copy()
+}
+
+// State.proposals
+//-------------------------------------
 
 // Implements events.Eventable. Typically uses events.EventCache
 func (s *State) SetFireable(evc events.Fireable) {
@@ -415,18 +574,51 @@ func (s *State) SetFireable(evc events.Fireable) {
 //-----------------------------------------------------------------------------
 // Genesis
 
-func MakeGenesisStateFromFile(db dbm.DB, genDocFile string) (*genesis.GenesisDoc, *State) {
+// Make the genesis state from the genesis document in genDocFile, returning
+// genesis.GenesisProblems if the document cannot be read or is invalid
+func MakeGenesisStateFromFile(db dbm.DB, genDocFile string) (*genesis.GenesisDoc, *State, error) {
 	jsonBlob, err := ioutil.ReadFile(genDocFile)
 	if err != nil {
-		util.Fatalf("Couldn't read GenesisDoc file: %v", err)
+		return nil, nil, fmt.Errorf("Couldn't read GenesisDoc file: %v", err)
+	}
+	genDoc, err := genesis.GenesisDocFromJSON(jsonBlob)
+	if err != nil {
+		return nil, nil, err
+	}
+	if problems := genesis.Validate(genDoc); len(problems) > 0 {
+		return nil, nil, problems
 	}
-	genDoc := genesis.GenesisDocFromJSON(jsonBlob)
-	return genDoc, MakeGenesisState(db, genDoc)
+	genesisState, err := MakeGenesisState(db, genDoc)
+	if err != nil {
+		return nil, nil, err
+	}
+	return genDoc, genesisState, nil
 }
 
-func MakeGenesisState(db dbm.DB, genDoc *genesis.GenesisDoc) *State {
+// Make the genesis state of genDoc, which should have been checked by
+// genesis.Validate
+func MakeGenesisState(db dbm.DB, genDoc *genesis.GenesisDoc) (*State, error) {
 	if len(genDoc.Validators) == 0 {
-		util.Fatalf("The genesis file has no validators")
+		return nil, fmt.Errorf("The genesis file has no validators")
+	}
+
+	var addressScheme string
+	if genDoc.Params != nil {
+		addressScheme = genDoc.Params.AddressScheme
+	}
+	if err := acm.ValidateAddressScheme(addressScheme); err != nil {
+		return nil, fmt.Errorf("Invalid genesis params: %s", err)
+	}
+	nameRegParams := genDoc.Params.NameRegParams()
+	if err := nameRegParams.Validate(); err != nil {
+		return nil, fmt.Errorf("Invalid genesis params: %s", err)
+	}
+	var governanceParams *ptypes.GovernanceParams
+	if genDoc.Params != nil {
+		if err := genDoc.Params.Governance.Validate(); err != nil {
+			return nil, fmt.Errorf("Invalid genesis params: %s", err)
+		}
+		governanceParams = genDoc.Params.GovernanceParams()
 	}
 
 	if genDoc.GenesisTime.IsZero() {
@@ -443,7 +635,14 @@ func MakeGenesisState(db dbm.DB, genDoc *genesis.GenesisDoc) *State {
 	for _, genAcc := range genDoc.Accounts {
 		perm := ptypes.ZeroAccountPermissions
 		if genAcc.Permissions != nil {
-			perm = *genAcc.Permissions
+			perm = genAcc.Permissions.Clone()
+			// roles are held under their padded names (see ptypes.RoleName)
+			for i, role := range perm.Roles {
+				perm.Roles[i] = ptypes.RoleName(role)
+			}
+			for i, expiry := range perm.RoleExpiries {
+				perm.RoleExpiries[i].Role = ptypes.RoleName(expiry.Role)
+			}
 		}
 		acc := &acm.Account{
 			Address:     genAcc.Address,
@@ -461,7 +660,7 @@ func MakeGenesisState(db dbm.DB, genDoc *genesis.GenesisDoc) *State {
 	if genDoc.Params != nil && genDoc.Params.GlobalPermissions != nil {
 		globalPerms = *genDoc.Params.GlobalPermissions
 		// XXX: make sure the set bits are all true
-		// Without it the HasPermission() functions will fail
+		// Without it HasPermission() denies permissions they do not set
 		globalPerms.Base.SetBit = ptypes.AllPermFlags
 	}
 
@@ -511,23 +710,47 @@ func MakeGenesisState(db dbm.DB, genDoc *genesis.GenesisDoc) *State {
 	nameReg := merkle.NewIAVLTree(0, db)
 	// TODO: add names, contracts to genesis.json
 
+	// Make roles tree
+	roles := merkle.NewIAVLTree(0, db)
+	if genDoc.Params != nil {
+		for _, genRole := range genDoc.Params.Roles {
+			role := genRole.Normalise()
+			roles.Set([]byte(role.Name), wire.BinaryBytes(role))
+		}
+	}
+
+	// Make proposals tree
+	proposals := merkle.NewIAVLTree(0, db)
+
+	// Make namereg expiry index
+	nameRegExpiries := makeNameRegExpiries(db, nameReg)
+
 	// IAVLTrees must be persisted before copy operations.
 	accounts.Save()
 	//validatorInfos.Save()
 	nameReg.Save()
+	roles.Save()
+	proposals.Save()
+	nameRegExpiries.Save()
 
 	return &State{
-		DB:              db,
-		ChainID:         genDoc.ChainID,
-		LastBlockHeight: 0,
-		LastBlockHash:   nil,
-		LastBlockParts:  types.PartSetHeader{},
-		LastBlockTime:   genDoc.GenesisTime,
+		DB:               db,
+		ChainID:          genDoc.ChainID,
+		LastBlockHeight:  0,
+		LastBlockHash:    nil,
+		LastBlockParts:   types.PartSetHeader{},
+		LastBlockTime:    genDoc.GenesisTime,
+		AddressScheme:    addressScheme,
+		NameRegParams:    nameRegParams,
+		GovernanceParams: governanceParams,
 		//BondedValidators:     types.NewValidatorSet(validators),
 		//LastBondedValidators: types.NewValidatorSet(nil),
 		//UnbondingValidators:  types.NewValidatorSet(nil),
 		accounts: accounts,
 		//validatorInfos:       validatorInfos,
-		nameReg: nameReg,
-	}
+		nameReg:         nameReg,
+		nameRegExpiries: nameRegExpiries,
+		roles:           roles,
+		proposals:       proposals,
+	}, nil
 }
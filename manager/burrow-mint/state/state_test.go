@@ -19,11 +19,15 @@ import (
 	"encoding/hex"
 	"testing"
 
+	acm "github.com/hyperledger/burrow/account"
 	core_types "github.com/hyperledger/burrow/core/types"
 	evm "github.com/hyperledger/burrow/manager/burrow-mint/evm"
+	ptypes "github.com/hyperledger/burrow/permission/types"
 	"github.com/hyperledger/burrow/txs"
 	"github.com/hyperledger/burrow/word256"
 
+	"github.com/tendermint/go-events"
+	"github.com/tendermint/go-wire"
 	"github.com/tendermint/tendermint/config/tendermint_test"
 )
 
@@ -235,10 +239,86 @@ func TestTxSequence(t *testing.T) {
 	}
 }
 
+func TestSecp256k1Inputs(t *testing.T) {
+	for _, scheme := range []string{acm.AddressSchemeTendermint, acm.AddressSchemeEthereum} {
+		state, privAccounts, _ := RandGenesisState(1, true, 1000, 1, true, 1000)
+		state.AddressScheme = scheme
+		privAccount, err := acm.GenPrivAccountWithKeyType(acm.KeyTypeSecp256k1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		privAccount.Address, err = acm.AddressFromPubKey(scheme, privAccount.PubKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// The account is known by its address until it first sends, when its
+		// public key must give that address under the chain's address scheme
+		state.UpdateAccount(&acm.Account{
+			Address:     privAccount.Address,
+			Balance:     1000,
+			Permissions: ptypes.DefaultAccountPermissions,
+		})
+		to := privAccounts[0].Address
+
+		sendTx := txs.NewSendTx()
+		sendTx.AddInputWithNonce(privAccount.PubKey, 10, 1)
+		sendTx.Inputs[0].Address = privAccount.Address
+		sendTx.AddOutput(to, 10)
+		sendTx.Inputs[0].Signature = privAccount.Sign(state.ChainID, sendTx)
+		if err := execTxWithState(state, sendTx, true); err != nil {
+			t.Fatalf("Expected SendTx from secp256k1 key with %s address to pass: %v",
+				scheme, err)
+		}
+		acc := state.GetAccount(privAccount.Address)
+		if acc.Sequence != 1 || acc.PubKey == nil {
+			t.Errorf("Expected %s account to have sequence 1 and its public key, got %v",
+				scheme, acc)
+		}
+
+		// Once the public key is known it need not be sent again
+		callTx := txs.NewCallTxWithNonce(privAccount.PubKey, to, nil, 10, 1000, 1, 2)
+		callTx.Input.Address = privAccount.Address
+		callTx.Input.PubKey = nil
+		callTx.Input.Signature = privAccount.Sign(state.ChainID, callTx)
+		if err := execTxWithState(state, callTx, true); err != nil {
+			t.Fatalf("Expected CallTx from secp256k1 key with %s address to pass: %v",
+				scheme, err)
+		}
+		if acc := state.GetAccount(privAccount.Address); acc.Sequence != 2 {
+			t.Errorf("Expected %s account to have sequence 2, got %v", scheme,
+				acc.Sequence)
+		}
+
+		// The address of the key under the other scheme is not its account
+		otherScheme := acm.AddressSchemeEthereum
+		if scheme == acm.AddressSchemeEthereum {
+			otherScheme = acm.AddressSchemeTendermint
+		}
+		otherAddress, err := acm.AddressFromPubKey(otherScheme, privAccount.PubKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		state.UpdateAccount(&acm.Account{
+			Address:     otherAddress,
+			Balance:     1000,
+			Permissions: ptypes.DefaultAccountPermissions,
+		})
+		sendTx = txs.NewSendTx()
+		sendTx.AddInputWithNonce(privAccount.PubKey, 10, 1)
+		sendTx.Inputs[0].Address = otherAddress
+		sendTx.AddOutput(to, 10)
+		sendTx.Inputs[0].Signature = privAccount.Sign(state.ChainID, sendTx)
+		if err := execTxWithState(state, sendTx, true); err != txs.ErrTxInvalidPubKey {
+			t.Errorf("Expected SendTx from %s address of key on %s chain to fail "+
+				"with invalid public key, got %v", otherScheme, scheme, err)
+		}
+	}
+}
+
 func TestNameTxs(t *testing.T) {
 	state, privAccounts, _ := RandGenesisState(3, true, 1000, 1, true, 1000)
 
-	txs.MinNameRegistrationPeriod = 5
+	state.NameRegParams.MinRegistrationPeriod = 5
 	startingBlock := state.LastBlockHeight
 
 	// try some bad names. these should all fail
@@ -247,7 +327,7 @@ func TestNameTxs(t *testing.T) {
 	fee := int64(1000)
 	numDesiredBlocks := 5
 	for _, name := range names {
-		amt := fee + int64(numDesiredBlocks)*txs.NameByteCostMultiplier*txs.NameBlockCostMultiplier*txs.NameBaseCost(name, data)
+		amt := fee + int64(numDesiredBlocks)*state.NameRegParams.CostPerBlock(txs.NameBaseCost(name, data))
 		tx, _ := txs.NewNameTx(state, privAccounts[0].PubKey, name, data, amt, fee)
 		tx.Sign(state.ChainID, privAccounts[0])
 
@@ -260,7 +340,7 @@ func TestNameTxs(t *testing.T) {
 	name := "hold_it_chum"
 	datas := []string{"cold&warm", "!@#$%^&*()", "<<<>>>>", "because why would you ever need a ~ or a & or even a % in a json file? make your case and we'll talk"}
 	for _, data := range datas {
-		amt := fee + int64(numDesiredBlocks)*txs.NameByteCostMultiplier*txs.NameBlockCostMultiplier*txs.NameBaseCost(name, data)
+		amt := fee + int64(numDesiredBlocks)*state.NameRegParams.CostPerBlock(txs.NameBaseCost(name, data))
 		tx, _ := txs.NewNameTx(state, privAccounts[0].PubKey, name, data, amt, fee)
 		tx.Sign(state.ChainID, privAccounts[0])
 
@@ -292,7 +372,7 @@ func TestNameTxs(t *testing.T) {
 	// try a good one, check data, owner, expiry
 	name = "@looking_good/karaoke_bar.broadband"
 	data = "on this side of neptune there are 1234567890 people: first is OMNIVORE+-3. Or is it. Ok this is pretty restrictive. No exclamations :(. Faces tho :')"
-	amt := fee + int64(numDesiredBlocks)*txs.NameByteCostMultiplier*txs.NameBlockCostMultiplier*txs.NameBaseCost(name, data)
+	amt := fee + int64(numDesiredBlocks)*state.NameRegParams.CostPerBlock(txs.NameBaseCost(name, data))
 	tx, _ := txs.NewNameTx(state, privAccounts[0].PubKey, name, data, amt, fee)
 	tx.Sign(state.ChainID, privAccounts[0])
 	if err := execTxWithState(state, tx, true); err != nil {
@@ -349,7 +429,7 @@ func TestNameTxs(t *testing.T) {
 	data = "In the beginning there was no thing, not even the beginning. It hadn't been here, no there, nor for that matter anywhere, not especially because it had not to even exist, let alone to not. Nothing especially odd about that."
 	oldCredit := amt - fee
 	numDesiredBlocks = 10
-	amt = fee + (int64(numDesiredBlocks)*txs.NameByteCostMultiplier*txs.NameBlockCostMultiplier*txs.NameBaseCost(name, data) - oldCredit)
+	amt = fee + (int64(numDesiredBlocks)*state.NameRegParams.CostPerBlock(txs.NameBaseCost(name, data)) - oldCredit)
 	tx, _ = txs.NewNameTx(state, privAccounts[1].PubKey, name, data, amt, fee)
 	tx.Sign(state.ChainID, privAccounts[1])
 	if err := execTxWithState(state, tx, true); err != nil {
@@ -375,7 +455,7 @@ func TestNameTxs(t *testing.T) {
 	// test removal by key1 after expiry
 	name = "looking_good/karaoke_bar"
 	data = "some data"
-	amt = fee + int64(numDesiredBlocks)*txs.NameByteCostMultiplier*txs.NameBlockCostMultiplier*txs.NameBaseCost(name, data)
+	amt = fee + int64(numDesiredBlocks)*state.NameRegParams.CostPerBlock(txs.NameBaseCost(name, data))
 	tx, _ = txs.NewNameTx(state, privAccounts[0].PubKey, name, data, amt, fee)
 	tx.Sign(state.ChainID, privAccounts[0])
 	if err := execTxWithState(state, tx, true); err != nil {
@@ -398,6 +478,308 @@ func TestNameTxs(t *testing.T) {
 	}
 }
 
+func TestSubNameTxs(t *testing.T) {
+	state, privAccounts, _ := RandGenesisState(3, true, 1000, 1, true, 1000)
+
+	fee := int64(10)
+	numDesiredBlocks := int64(5)
+	registerName := func(privAccount *acm.PrivAccount, name, data string) error {
+		amt := fee + numDesiredBlocks*state.NameRegParams.CostPerBlock(txs.NameBaseCost(name, data))
+		if data == "" {
+			// remove the entry
+			amt = fee
+		}
+		tx, _ := txs.NewNameTx(state, privAccount.PubKey, name, data, amt, fee)
+		tx.Sign(state.ChainID, privAccount)
+		return execTxWithState(state, tx, true)
+	}
+	transferName := func(privAccount *acm.PrivAccount, name string, newOwner []byte) error {
+		tx, _ := txs.NewNameTransferTx(state, privAccount.PubKey, name, newOwner, fee)
+		tx.Sign(state.ChainID, privAccount)
+		return execTxWithState(state, tx, true)
+	}
+	assertOwner := func(name string, owner []byte) {
+		entry := state.GetNameRegEntry(name)
+		if entry == nil {
+			t.Fatalf("Could not find name %s", name)
+		}
+		if !bytes.Equal(entry.Owner, owner) {
+			t.Fatalf("Wrong owner of %s. Got %X expected %X", name, entry.Owner, owner)
+		}
+	}
+
+	if err := registerName(privAccounts[0], "parent", "data"); err != nil {
+		t.Fatal(err)
+	}
+	// only the owner of the parent may create names beneath it
+	if err := registerName(privAccounts[1], "parent/child", "data"); err == nil {
+		t.Fatal("Expected error creating a name beneath a name owned by another account")
+	}
+	if err := registerName(privAccounts[0], "parent/child", "data"); err != nil {
+		t.Fatal(err)
+	}
+	assertOwner("parent/child", privAccounts[0].Address)
+
+	// delegate the child, whose new owner may create names beneath it
+	if err := transferName(privAccounts[0], "parent/child", privAccounts[1].Address); err != nil {
+		t.Fatal(err)
+	}
+	assertOwner("parent/child", privAccounts[1].Address)
+	if err := registerName(privAccounts[1], "parent/child/grandchild", "data"); err != nil {
+		t.Fatal(err)
+	}
+	assertOwner("parent/child/grandchild", privAccounts[1].Address)
+
+	// but cannot take the parent
+	if err := transferName(privAccounts[1], "parent", privAccounts[1].Address); err == nil {
+		t.Fatal("Expected error transferring a name owned by another account")
+	}
+	if err := transferName(privAccounts[2], "parent/child", privAccounts[2].Address); err == nil {
+		t.Fatal("Expected error transferring a name owned by another account")
+	}
+
+	// the owner of the parent may revoke names beneath it but not update them
+	if err := registerName(privAccounts[0], "parent/child/grandchild", "new data"); err == nil {
+		t.Fatal("Expected error updating a name owned by another account")
+	}
+	if err := registerName(privAccounts[0], "parent/child/grandchild", ""); err != nil {
+		t.Fatal(err)
+	}
+	if state.GetNameRegEntry("parent/child/grandchild") != nil {
+		t.Fatal("Expected revoked entry to be nil")
+	}
+	// and take them back
+	if err := transferName(privAccounts[0], "parent/child", privAccounts[0].Address); err != nil {
+		t.Fatal(err)
+	}
+	assertOwner("parent/child", privAccounts[0].Address)
+
+	// cannot transfer names that are not registered
+	if err := transferName(privAccounts[0], "parent/other", privAccounts[1].Address); err == nil {
+		t.Fatal("Expected error transferring an unregistered name")
+	}
+}
+
+func TestNameTxParams(t *testing.T) {
+	state, privAccounts, _ := RandGenesisState(3, true, 1000, 1, true, 1000)
+	state.NameRegParams.ByteCostMultiplier = 3
+	state.NameRegParams.DataValidation = txs.NameDataValidationUTF8
+
+	fee := int64(10)
+	name := "document"
+	data := `{"title": "Ünïcode & friends!", "tags": ["<a>", "~b~"]}`
+	registerName := func(amt int64) error {
+		tx, _ := txs.NewNameTx(state, privAccounts[0].PubKey, name, data, amt, fee)
+		tx.Sign(state.ChainID, privAccounts[0])
+		return execTxWithState(state, tx, true)
+	}
+
+	// paying at the default price is too little for the minimum period
+	minPeriod := int64(state.NameRegParams.MinRegistrationPeriod)
+	if err := registerName(fee + minPeriod*txs.NameBaseCost(name, data)); err == nil {
+		t.Fatal("Expected error registering a name for less than the minimum period")
+	}
+	if err := registerName(fee + minPeriod*state.NameRegParams.CostPerBlock(txs.NameBaseCost(name, data))); err != nil {
+		t.Fatal(err)
+	}
+	entry := state.GetNameRegEntry(name)
+	if entry == nil || entry.Data != data {
+		t.Fatalf("Expected entry with data %s, got %v", data, entry)
+	}
+	if entry.Expires != state.LastBlockHeight+int(minPeriod) {
+		t.Fatalf("Wrong expiry. Got %d, expected %d", entry.Expires,
+			state.LastBlockHeight+int(minPeriod))
+	}
+}
+
+func TestNameTxBinaryData(t *testing.T) {
+	state, privAccounts, _ := RandGenesisState(3, true, 1000, 1, true, 1000)
+	state.NameRegParams.DataValidation = txs.NameDataValidationBinary
+
+	fee := int64(10)
+	name := "blob"
+	// not valid UTF-8, so would not survive signing or JSON unencoded
+	data := []byte{0x00, 0xff, 0xfe, 0xc3, 0x28}
+	amt := fee + int64(state.NameRegParams.MinRegistrationPeriod)*
+		state.NameRegParams.CostPerBlock(txs.NameBaseCost(name, hex.EncodeToString(data)))
+	signNameTx := func(data string) *txs.NameTx {
+		tx, _ := txs.NewNameTx(state, privAccounts[0].PubKey, name, data, amt, fee)
+		tx.Sign(state.ChainID, privAccounts[0])
+		return tx
+	}
+
+	if err := execTxWithState(state, signNameTx(string(data)), true); err == nil {
+		t.Fatal("Expected error registering binary data that is not hex encoded")
+	}
+	// the signature covers the data, so it cannot be swapped for other bytes
+	tx := signNameTx(hex.EncodeToString(data))
+	tx.Data = hex.EncodeToString([]byte{0x00, 0xff, 0xfe, 0xc3, 0x29})
+	if err := execTxWithState(state, tx, true); err == nil {
+		t.Fatal("Expected error executing a NameTx whose data was changed after signing")
+	}
+	if err := execTxWithState(state, signNameTx(hex.EncodeToString(data)), true); err != nil {
+		t.Fatal(err)
+	}
+	entry := state.GetNameRegEntry(name)
+	if entry == nil {
+		t.Fatal("Expected entry to be registered")
+	}
+	if stored, err := hex.DecodeString(entry.Data); err != nil || !bytes.Equal(stored, data) {
+		t.Fatalf("Expected entry to hold %X, got %s", data, entry.Data)
+	}
+}
+
+func TestNameTxRefund(t *testing.T) {
+	state, privAccounts, _ := RandGenesisState(3, true, 1000, 1, true, 1000)
+
+	fee := int64(10)
+	numDesiredBlocks := int64(5)
+	sendNameTx := func(privAccount *acm.PrivAccount, name, data string, amt int64) {
+		tx, _ := txs.NewNameTx(state, privAccount.PubKey, name, data, amt, fee)
+		tx.Sign(state.ChainID, privAccount)
+		if err := execTxWithState(state, tx, true); err != nil {
+			t.Fatal(err)
+		}
+	}
+	balance := func(privAccount *acm.PrivAccount) int64 {
+		return state.GetAccount(privAccount.Address).Balance
+	}
+
+	name, data := "refundable", "data"
+	costPerBlock := state.NameRegParams.CostPerBlock(txs.NameBaseCost(name, data))
+	sendNameTx(privAccounts[0], name, data, fee+numDesiredBlocks*costPerBlock)
+	startBalance := balance(privAccounts[0])
+
+	// deleting with 2 of 5 blocks used refunds the other 3
+	state.LastBlockHeight += 2
+	sendNameTx(privAccounts[0], name, "", fee)
+	if got, expected := balance(privAccounts[0]), startBalance+3*costPerBlock; got != expected {
+		t.Fatalf("Wrong balance after deleting name. Got %v expected %v", got, expected)
+	}
+
+	// the owner of a name revoked by the owner of its parent gets the refund
+	sendNameTx(privAccounts[0], "parent", data, fee+numDesiredBlocks*costPerBlock)
+	sendNameTx(privAccounts[0], "parent/child", data, fee+numDesiredBlocks*costPerBlock)
+	tx, _ := txs.NewNameTransferTx(state, privAccounts[0].PubKey, "parent/child", privAccounts[1].Address, fee)
+	tx.Sign(state.ChainID, privAccounts[0])
+	if err := execTxWithState(state, tx, true); err != nil {
+		t.Fatal(err)
+	}
+	childCost := state.NameRegParams.CostPerBlock(txs.NameBaseCost("parent/child", data))
+	ownerBalance := balance(privAccounts[1])
+	sendNameTx(privAccounts[0], "parent/child", "", fee)
+	if got, expected := balance(privAccounts[1]), ownerBalance+numDesiredBlocks*childCost; got != expected {
+		t.Fatalf("Wrong balance of revoked name owner. Got %v expected %v", got, expected)
+	}
+
+	// nothing is refunded once a name has expired
+	sendNameTx(privAccounts[0], name, data, fee+numDesiredBlocks*costPerBlock)
+	state.LastBlockHeight += int(numDesiredBlocks)
+	startBalance = balance(privAccounts[0])
+	sendNameTx(privAccounts[0], name, "", fee)
+	if got, expected := balance(privAccounts[0]), startBalance; got != expected {
+		t.Fatalf("Wrong balance after deleting expired name. Got %v expected %v", got, expected)
+	}
+}
+
+func TestRemoveExpiredNameRegEntries(t *testing.T) {
+	state, privAccounts, _ := RandGenesisState(3, true, 1000, 1, true, 1000)
+
+	fee := int64(10)
+	registerName := func(name string, numDesiredBlocks int64) {
+		data := "data"
+		amt := fee + numDesiredBlocks*state.NameRegParams.CostPerBlock(txs.NameBaseCost(name, data))
+		tx, _ := txs.NewNameTx(state, privAccounts[0].PubKey, name, data, amt, fee)
+		tx.Sign(state.ChainID, privAccounts[0])
+		if err := execTxWithState(state, tx, true); err != nil {
+			t.Fatal(err)
+		}
+	}
+	registerName("a", 5)
+	registerName("b", 5)
+	registerName("c", 5)
+	registerName("long", 50)
+
+	state.LastBlockHeight += 5
+	evsw := events.NewEventSwitch()
+	evsw.Start()
+	var expired []string
+	evsw.AddListenerForEvent("test", txs.EventStringNameReg("b"), func(data events.EventData) {
+		eventData := data.(txs.EventDataNameReg)
+		if eventData.Expired {
+			expired = append(expired, eventData.Name)
+		}
+	})
+
+	// a negative limit disables removal
+	blockCache := NewBlockCache(state)
+	if removed := RemoveExpiredNameRegEntries(blockCache, -1, evsw, logger); removed != 0 {
+		t.Fatalf("Expected no expired entries to be removed but %v were", removed)
+	}
+
+	// removals are limited per block
+	if removed := RemoveExpiredNameRegEntries(blockCache, 2, evsw, logger); removed != 2 {
+		t.Fatalf("Expected 2 expired entries to be removed but %v were", removed)
+	}
+	blockCache.Sync()
+	blockCache = NewBlockCache(state)
+	if removed := RemoveExpiredNameRegEntries(blockCache, 2, evsw, logger); removed != 1 {
+		t.Fatalf("Expected 1 expired entry to be removed but %v were", removed)
+	}
+	blockCache.Sync()
+
+	for _, name := range []string{"a", "b", "c"} {
+		if state.GetNameRegEntry(name) != nil {
+			t.Fatalf("Expected expired entry %s to be removed", name)
+		}
+	}
+	if state.GetNameRegEntry("long") == nil {
+		t.Fatal("Expected live entry to be kept")
+	}
+	if len(expired) != 1 || expired[0] != "b" {
+		t.Fatalf("Expected expiry event for b, got %v", expired)
+	}
+
+	// the expiry index follows the registry and survives saving
+	if size := state.GetNameRegExpiries().Size(); size != 1 {
+		t.Fatalf("Expected expiry index to hold only the live entry but it holds %v", size)
+	}
+	state.Save()
+	if size := LoadState(state.DB).GetNameRegExpiries().Size(); size != 1 {
+		t.Fatalf("Expected loaded expiry index to hold only the live entry but it holds %v", size)
+	}
+}
+
+func TestLoadStateBuildsNameRegExpiries(t *testing.T) {
+	state, privAccounts, _ := RandGenesisState(1, true, 1000, 1, true, 1000)
+	state.UpdateNameRegEntry(&core_types.NameRegEntry{
+		Name:    "legacy",
+		Owner:   privAccounts[0].Address,
+		Data:    "data",
+		Expires: 10,
+	})
+	state.Save()
+	// Save as a state saved before the expiry index, whose saved state ends
+	// with the hash of the proposals
+	stateBytes := state.DB.Get(stateKey)
+	indexHashBytes := wire.BinaryBytes(state.nameRegExpiries.Hash())
+	state.DB.Set(stateKey, stateBytes[:len(stateBytes)-len(indexHashBytes)])
+
+	loadedState := LoadState(state.DB)
+	if size := loadedState.GetNameRegExpiries().Size(); size != 1 {
+		t.Fatalf("Expected expiry index built from the registry to hold 1 entry but it holds %v", size)
+	}
+	// blocks and queries work on copies of the state
+	copiedState := loadedState.Copy()
+	if !bytes.Equal(copiedState.Hash(), state.Hash()) {
+		t.Fatal("Expected state loaded from the legacy layout to have the same hash")
+	}
+	loadedState.Save()
+	if size := LoadState(state.DB).GetNameRegExpiries().Size(); size != 1 {
+		t.Fatalf("Expected saved expiry index to hold 1 entry but it holds %v", size)
+	}
+}
+
 // Test creating a contract from futher down the call stack
 /*
 contract Factory {
@@ -19,6 +19,7 @@ import (
 
 	acm "github.com/hyperledger/burrow/account"
 	"github.com/hyperledger/burrow/common/sanity"
+	core_types "github.com/hyperledger/burrow/core/types"
 	"github.com/hyperledger/burrow/manager/burrow-mint/evm"
 	ptypes "github.com/hyperledger/burrow/permission/types" // for GlobalPermissionAddress ...
 	"github.com/hyperledger/burrow/txs"
@@ -31,6 +32,7 @@ type TxCache struct {
 	backend  *BlockCache
 	accounts map[Word256]vmAccountInfo
 	storages map[Tuple256]Word256
+	names    map[string]*vm.NameRegEntry
 }
 
 var _ vm.AppState = &TxCache{}
@@ -40,6 +42,7 @@ func NewTxCache(backend *BlockCache) *TxCache {
 		backend:  backend,
 		accounts: make(map[Word256]vmAccountInfo),
 		storages: make(map[Tuple256]Word256),
+		names:    make(map[string]*vm.NameRegEntry),
 	}
 }
 
@@ -135,6 +138,47 @@ func (cache *TxCache) SetStorage(addr Word256, key Word256, value Word256) {
 
 // TxCache.storage
 //-------------------------------------
+// TxCache.names
+
+func (cache *TxCache) GetNameRegEntry(name string) *vm.NameRegEntry {
+	entry, ok := cache.names[name]
+	if ok {
+		return entry
+	}
+	stateEntry := cache.backend.GetNameRegEntry(name)
+	if stateEntry == nil {
+		return nil
+	}
+	return toVMNameRegEntry(stateEntry)
+}
+
+func (cache *TxCache) UpdateNameRegEntry(entry *vm.NameRegEntry) {
+	cache.names[entry.Name] = entry
+}
+
+func (cache *TxCache) LastBlockHeight() int64 {
+	return int64(cache.backend.State().LastBlockHeight)
+}
+
+func (cache *TxCache) NameRegParams() txs.NameRegParams {
+	return cache.backend.State().NameRegParams
+}
+
+func (cache *TxCache) GovernanceParams() *ptypes.GovernanceParams {
+	return cache.backend.State().GovernanceParams
+}
+
+// TxCache.names
+//-------------------------------------
+// TxCache.roles
+
+// Roles are defined by PermissionsTx so are only read by the VM
+func (cache *TxCache) GetRole(name string) *ptypes.Role {
+	return cache.backend.GetRole(name)
+}
+
+// TxCache.roles
+//-------------------------------------
 
 // These updates do not have to be in deterministic order,
 // the backend is responsible for ordering updates.
@@ -154,6 +198,11 @@ func (cache *TxCache) Sync() {
 			cache.backend.UpdateAccount(toStateAccount(acc))
 		}
 	}
+
+	// Update name registry entries
+	for _, entry := range cache.names {
+		cache.backend.UpdateNameRegEntry(toStateNameRegEntry(entry))
+	}
 }
 
 //-----------------------------------------------------------------------------
@@ -197,6 +246,26 @@ func toStateAccount(acc *vm.Account) *acm.Account {
 	}
 }
 
+// Converts backend name registry entry to vm.NameRegEntry struct.
+func toVMNameRegEntry(entry *core_types.NameRegEntry) *vm.NameRegEntry {
+	return &vm.NameRegEntry{
+		Name:    entry.Name,
+		Owner:   LeftPadWord256(entry.Owner),
+		Data:    entry.Data,
+		Expires: int64(entry.Expires),
+	}
+}
+
+// Converts vm.NameRegEntry to backend name registry entry struct.
+func toStateNameRegEntry(entry *vm.NameRegEntry) *core_types.NameRegEntry {
+	return &core_types.NameRegEntry{
+		Name:    entry.Name,
+		Owner:   entry.Owner.Postfix(20),
+		Data:    entry.Data,
+		Expires: int(entry.Expires),
+	}
+}
+
 // Everything in acmAccount that doesn't belong in
 // exported vmAccount fields.
 type vmAccountOther struct {
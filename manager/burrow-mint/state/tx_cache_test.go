@@ -18,6 +18,7 @@ import (
 	"bytes"
 	"testing"
 
+	core_types "github.com/hyperledger/burrow/core/types"
 	"github.com/tendermint/go-wire"
 )
 
@@ -34,3 +35,22 @@ func TestStateToFromVMAccount(t *testing.T) {
 	}
 
 }
+
+func TestStateToFromVMNameRegEntry(t *testing.T) {
+	acc, _ := RandAccount(false, 0)
+	entry1 := &core_types.NameRegEntry{
+		Name:    "name",
+		Owner:   acc.Address,
+		Data:    "data",
+		Expires: 456,
+	}
+	vmEntry := toVMNameRegEntry(entry1)
+	entry2 := toStateNameRegEntry(vmEntry)
+
+	entry1Bytes := wire.BinaryBytes(entry1)
+	entry2Bytes := wire.BinaryBytes(entry2)
+	if !bytes.Equal(entry1Bytes, entry2Bytes) {
+		t.Errorf("Unexpected name registry entry wire bytes\n%X vs\n%X",
+			entry1Bytes, entry2Bytes)
+	}
+}
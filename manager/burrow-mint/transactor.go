@@ -22,8 +22,10 @@ import (
 	"time"
 
 	"github.com/hyperledger/burrow/account"
+	"github.com/hyperledger/burrow/client"
 	core_types "github.com/hyperledger/burrow/core/types"
 	"github.com/hyperledger/burrow/event"
+	logging_types "github.com/hyperledger/burrow/logging/types"
 	"github.com/hyperledger/burrow/manager/burrow-mint/evm"
 	"github.com/hyperledger/burrow/manager/burrow-mint/state"
 	"github.com/hyperledger/burrow/txs"
@@ -42,19 +44,47 @@ type transactor struct {
 	eventEmitter  event.EventEmitter
 	txMtx         *sync.Mutex
 	txBroadcaster func(tx txs.Tx) error
+	nonces        client.NonceManager
 }
 
 func newTransactor(chainID string, eventSwitch tEvents.Fireable,
 	burrowMint *BurrowMint, eventEmitter event.EventEmitter,
 	txBroadcaster func(tx txs.Tx) error) *transactor {
-	return &transactor{
+	transactor := &transactor{
 		chainID,
 		eventSwitch,
 		burrowMint,
 		eventEmitter,
 		&sync.Mutex{},
 		txBroadcaster,
+		nil,
 	}
+	transactor.nonces = client.NewNonceManager(transactorNonceNode{transactor})
+	return transactor
+}
+
+// transactorNonceNode lets the NonceManager of a transactor sync sequences
+// from the check cache and broadcast through the transactor
+type transactorNonceNode struct {
+	transactor *transactor
+}
+
+func (node transactorNonceNode) GetAccount(address []byte) (*account.Account, error) {
+	// XXX: DON'T MUTATE THIS CACHE (used internally for CheckTx)
+	return node.transactor.burrowMint.GetCheckCache().GetAccount(address), nil
+}
+
+func (node transactorNonceNode) Broadcast(tx txs.Tx) (*txs.Receipt, error) {
+	return node.transactor.BroadcastTx(tx)
+}
+
+// Transactions are confirmed through the event emitter rather than a websocket
+func (node transactorNonceNode) DeriveWebsocketClient() (client.NodeWebsocketClient, error) {
+	return nil, nil
+}
+
+func (node transactorNonceNode) Logger() logging_types.InfoTraceLogger {
+	return node.transactor.burrowMint.logger
 }
 
 // Run a contract's code on an isolated and unpersisted state
@@ -164,20 +194,12 @@ func (this *transactor) Transact(privKey, address, data []byte, gasLimit,
 	} else {
 		addr = address
 	}
-	if len(privKey) != 64 {
-		return nil, fmt.Errorf("Private key is not of the right length: %d\n", len(privKey))
+	pa, err := this.privAccount(privKey)
+	if err != nil {
+		return nil, err
 	}
 	this.txMtx.Lock()
 	defer this.txMtx.Unlock()
-	pa := account.GenPrivAccountFromPrivKeyBytes(privKey)
-	cache := this.burrowMint.GetCheckCache() // XXX: DON'T MUTATE THIS CACHE (used internally for CheckTx)
-	acc := cache.GetAccount(pa.Address)
-	var sequence int
-	if acc == nil {
-		sequence = 1
-	} else {
-		sequence = acc.Sequence + 1
-	}
 	// TODO: [Silas] we should consider revising this method and removing fee, or
 	// possibly adding an amount parameter. It is non-sensical to just be able to
 	// set the fee. Our support of fees in general is questionable since at the
@@ -189,26 +211,21 @@ func (this *transactor) Transact(privKey, address, data []byte, gasLimit,
 	// generate transfers 0 value, which is the most sensible default since in
 	// recent solidity compilers the EVM generated will throw an error if value
 	// is transferred to a non-payable function.
-	txInput := &txs.TxInput{
-		Address:  pa.Address,
-		Amount:   fee,
-		Sequence: sequence,
-		PubKey:   pa.PubKey,
-	}
-	tx := &txs.CallTx{
-		Input:    txInput,
-		Address:  addr,
-		GasLimit: gasLimit,
-		Fee:      fee,
-		Data:     data,
-	}
-
-	// Got ourselves a tx.
-	txS, errS := this.SignTx(tx, []*account.PrivAccount{pa})
-	if errS != nil {
-		return nil, errS
-	}
-	return this.BroadcastTx(txS)
+	return this.broadcastWithNonce(pa, func(sequence int) txs.Tx {
+		txInput := &txs.TxInput{
+			Address:  pa.Address,
+			Amount:   fee,
+			Sequence: sequence,
+			PubKey:   pa.PubKey,
+		}
+		return &txs.CallTx{
+			Input:    txInput,
+			Address:  addr,
+			GasLimit: gasLimit,
+			Fee:      fee,
+			Data:     data,
+		}
+	})
 }
 
 func (this *transactor) TransactAndHold(privKey, address, data []byte, gasLimit, fee int64) (*txs.EventDataCall, error) {
@@ -276,46 +293,29 @@ func (this *transactor) Send(privKey, toAddress []byte,
 		toAddr = toAddress
 	}
 
-	if len(privKey) != 64 {
-		return nil, fmt.Errorf("Private key is not of the right length: %d\n",
-			len(privKey))
+	pa, err := this.privAccount(privKey)
+	if err != nil {
+		return nil, err
 	}
-
-	pk := &[64]byte{}
-	copy(pk[:], privKey)
 	this.txMtx.Lock()
 	defer this.txMtx.Unlock()
-	pa := account.GenPrivAccountFromPrivKeyBytes(privKey)
-	cache := this.burrowMint.GetState()
-	acc := cache.GetAccount(pa.Address)
-	var sequence int
-	if acc == nil {
-		sequence = 1
-	} else {
-		sequence = acc.Sequence + 1
-	}
-
-	tx := txs.NewSendTx()
-
-	txInput := &txs.TxInput{
-		Address:  pa.Address,
-		Amount:   amount,
-		Sequence: sequence,
-		PubKey:   pa.PubKey,
-	}
-
-	tx.Inputs = append(tx.Inputs, txInput)
+	return this.broadcastWithNonce(pa, func(sequence int) txs.Tx {
+		tx := txs.NewSendTx()
+
+		txInput := &txs.TxInput{
+			Address:  pa.Address,
+			Amount:   amount,
+			Sequence: sequence,
+			PubKey:   pa.PubKey,
+		}
 
-	txOutput := &txs.TxOutput{toAddr, amount}
+		tx.Inputs = append(tx.Inputs, txInput)
 
-	tx.Outputs = append(tx.Outputs, txOutput)
+		txOutput := &txs.TxOutput{toAddr, amount}
 
-	// Got ourselves a tx.
-	txS, errS := this.SignTx(tx, []*account.PrivAccount{pa})
-	if errS != nil {
-		return nil, errS
-	}
-	return this.BroadcastTx(txS)
+		tx.Outputs = append(tx.Outputs, txOutput)
+		return tx
+	})
 }
 
 func (this *transactor) SendAndHold(privKey, toAddress []byte,
@@ -340,7 +340,10 @@ func (this *transactor) SendAndHold(privKey, toAddress []byte,
 
 	var rErr error
 
-	pa := account.GenPrivAccountFromPrivKeyBytes(privKey)
+	pa, err := this.privAccount(privKey)
+	if err != nil {
+		return nil, err
+	}
 
 	select {
 	case <-toChan:
@@ -355,30 +358,149 @@ func (this *transactor) SendAndHold(privKey, toAddress []byte,
 	return nil, rErr
 }
 
-func (this *transactor) TransactNameReg(privKey []byte, name, data string,
-	amount, fee int64) (*txs.Receipt, error) {
-
-	if len(privKey) != 64 {
-		return nil, fmt.Errorf("Private key is not of the right length: %d\n", len(privKey))
+// SendMulti sends a SendTx spending amounts[i] from the account of each of
+// privKeys to outputs. Any difference between the input and output totals is
+// paid as a fee.
+func (this *transactor) SendMulti(privKeys [][]byte, amounts []int64,
+	outputs []*txs.TxOutput) (*txs.Receipt, error) {
+	if len(privKeys) == 0 {
+		return nil, fmt.Errorf("At least one input is required")
+	}
+	if len(privKeys) != len(amounts) {
+		return nil, fmt.Errorf("Got %v private keys but %v input amounts",
+			len(privKeys), len(amounts))
+	}
+	if len(outputs) == 0 {
+		return nil, fmt.Errorf("At least one output is required")
+	}
+	var outTotal int64
+	for i, output := range outputs {
+		if len(output.Address) != 20 {
+			return nil, fmt.Errorf("Output address %v is not of the right length: %d",
+				i, len(output.Address))
+		}
+		outTotal += output.Amount
 	}
+
 	this.txMtx.Lock()
 	defer this.txMtx.Unlock()
-	pa := account.GenPrivAccountFromPrivKeyBytes(privKey)
 	cache := this.burrowMint.GetCheckCache() // XXX: DON'T MUTATE THIS CACHE (used internally for CheckTx)
-	acc := cache.GetAccount(pa.Address)
-	var sequence int
-	if acc == nil {
-		sequence = 1
-	} else {
-		sequence = acc.Sequence + 1
+	tx := txs.NewSendTx()
+	privAccounts := make([]*account.PrivAccount, len(privKeys))
+	var inTotal int64
+	for i, privKey := range privKeys {
+		pa, err := this.privAccount(privKey)
+		if err != nil {
+			return nil, fmt.Errorf("Input %v: %s", i, err)
+		}
+		if cache.GetAccount(pa.Address) == nil {
+			return nil, fmt.Errorf("Input account %X does not exist", pa.Address)
+		}
+		privAccounts[i] = pa
+		tx.Inputs = append(tx.Inputs, &txs.TxInput{
+			Address: pa.Address,
+			Amount:  amounts[i],
+		})
+		inTotal += amounts[i]
+	}
+	if inTotal < outTotal {
+		return nil, fmt.Errorf("Inputs total %v but outputs total %v", inTotal,
+			outTotal)
+	}
+	tx.Outputs = outputs
+
+	// The NonceManager orders the transactions of a single input, so each
+	// input's sequence is reserved and the inputs resynced once broadcast
+	for _, input := range tx.Inputs {
+		sequence, err := this.nonces.Reserve(input.Address)
+		if err != nil {
+			this.releaseInputs(tx)
+			return nil, err
+		}
+		input.Sequence = sequence
 	}
-	tx := txs.NewNameTxWithNonce(pa.PubKey, name, data, amount, fee, sequence)
-	// Got ourselves a tx.
-	txS, errS := this.SignTx(tx, []*account.PrivAccount{pa})
+	txS, errS := this.SignTx(tx, privAccounts)
 	if errS != nil {
+		this.releaseInputs(tx)
 		return nil, errS
 	}
-	return this.BroadcastTx(txS)
+	receipt, err := this.BroadcastTx(txS)
+	if err != nil {
+		this.releaseInputs(tx)
+		return nil, err
+	}
+	for _, input := range tx.Inputs {
+		this.nonces.Resync(input.Address)
+	}
+	return receipt, nil
+}
+
+// releaseInputs releases the sequences reserved for the inputs of tx
+func (this *transactor) releaseInputs(tx *txs.SendTx) {
+	for _, input := range tx.Inputs {
+		if input.Sequence > 0 {
+			this.nonces.Release(input.Address, input.Sequence)
+		}
+	}
+}
+
+func (this *transactor) TransactNameReg(privKey []byte, name, data string,
+	amount, fee int64) (*txs.Receipt, error) {
+
+	pa, err := this.privAccount(privKey)
+	if err != nil {
+		return nil, err
+	}
+	this.txMtx.Lock()
+	defer this.txMtx.Unlock()
+	return this.broadcastWithNonce(pa, func(sequence int) txs.Tx {
+		tx := txs.NewNameTxWithNonce(pa.PubKey, name, data, amount, fee, sequence)
+		tx.Input.Address = pa.Address
+		return tx
+	})
+}
+
+// broadcastWithNonce reserves the next sequence of pa from the NonceManager,
+// forms a transaction from pa with it using formTx, then signs and broadcasts
+// it. A transaction the node rejects for its sequence, for instance because
+// the account was sent from by other means, is formed once more with the
+// sequence the NonceManager resynced to.
+func (this *transactor) broadcastWithNonce(pa *account.PrivAccount,
+	formTx func(sequence int) txs.Tx) (*txs.Receipt, error) {
+	for attempt := 0; ; attempt++ {
+		sequence, err := this.nonces.Reserve(pa.Address)
+		if err != nil {
+			return nil, err
+		}
+		// Got ourselves a tx.
+		txS, errS := this.SignTx(formTx(sequence), []*account.PrivAccount{pa})
+		if errS != nil {
+			this.nonces.Release(pa.Address, sequence)
+			return nil, errS
+		}
+		receipt, _, err := this.nonces.Broadcast(txS, this.chainID, pa.Address, sequence)
+		if _, ok := client.InvalidSequence(err); ok && attempt == 0 {
+			continue
+		}
+		return receipt, err
+	}
+}
+
+// privAccount returns the account of an ed25519 or secp256k1 private key with
+// its address derived by the address scheme of the chain
+func (this *transactor) privAccount(privKey []byte) (*account.PrivAccount, error) {
+	if len(privKey) != account.PrivKeyEd25519Length &&
+		len(privKey) != account.PrivKeySecp256k1Length {
+		return nil, fmt.Errorf("Private key is not of the right length: %d\n", len(privKey))
+	}
+	pa := account.GenPrivAccountFromPrivKeyBytes(privKey)
+	address, err := account.AddressFromPubKey(this.burrowMint.GetState().AddressScheme,
+		pa.PubKey)
+	if err != nil {
+		return nil, err
+	}
+	pa.Address = address
+	return pa, nil
 }
 
 // Sign a transaction
@@ -395,8 +517,16 @@ func (this *transactor) SignTx(tx txs.Tx, privAccounts []*account.PrivAccount) (
 		nameTx := tx.(*txs.NameTx)
 		nameTx.Input.PubKey = privAccounts[0].PubKey
 		nameTx.Input.Signature = privAccounts[0].Sign(this.chainID, nameTx)
+	case *txs.NameTransferTx:
+		nameTransferTx := tx.(*txs.NameTransferTx)
+		nameTransferTx.Input.PubKey = privAccounts[0].PubKey
+		nameTransferTx.Input.Signature = privAccounts[0].Sign(this.chainID, nameTransferTx)
 	case *txs.SendTx:
 		sendTx := tx.(*txs.SendTx)
+		if len(privAccounts) != len(sendTx.Inputs) {
+			return nil, fmt.Errorf("Got %v privAccounts to sign %v inputs",
+				len(privAccounts), len(sendTx.Inputs))
+		}
 		for i, input := range sendTx.Inputs {
 			input.PubKey = privAccounts[i].PubKey
 			input.Signature = privAccounts[i].Sign(this.chainID, sendTx)
@@ -0,0 +1,173 @@
+// Copyright 2017 Monax Industries Limited
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package burrowmint
+
+import (
+	"testing"
+	"time"
+
+	acm "github.com/hyperledger/burrow/account"
+	"github.com/hyperledger/burrow/genesis"
+	"github.com/hyperledger/burrow/logging/loggers"
+	"github.com/hyperledger/burrow/manager/burrow-mint/state"
+	ptypes "github.com/hyperledger/burrow/permission/types"
+	"github.com/hyperledger/burrow/txs"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tendermint/go-crypto"
+	tdb "github.com/tendermint/go-db"
+	tEvents "github.com/tendermint/go-events"
+)
+
+const testChainID = "transactor_test"
+
+// A transactor whose broadcasts are checked against the check cache as by
+// CheckTx, holding the given accounts with balance 1000
+type transactorFixture struct {
+	*transactor
+	broadcasts []txs.Tx
+}
+
+func newTransactorFixture(t *testing.T, privAccounts ...*acm.PrivAccount) *transactorFixture {
+	permissions := ptypes.DefaultAccountPermissions
+	genDoc := &genesis.GenesisDoc{
+		GenesisTime: time.Now(),
+		ChainID:     testChainID,
+		Validators: []genesis.GenesisValidator{
+			{PubKey: acm.GenPrivAccount().PubKey, Amount: 10},
+		},
+	}
+	for _, privAccount := range privAccounts {
+		genDoc.Accounts = append(genDoc.Accounts, genesis.GenesisAccount{
+			Address:     privAccount.Address,
+			Amount:      1000,
+			Permissions: &permissions,
+		})
+	}
+	logger := loggers.NewNoopInfoTraceLogger()
+	evsw := tEvents.NewEventSwitch()
+	genesisState, err := state.MakeGenesisState(tdb.NewMemDB(), genDoc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	burrowMint := NewBurrowMint(genesisState, evsw, logger)
+	fixture := &transactorFixture{}
+	fixture.transactor = newTransactor(testChainID, evsw, burrowMint, nil,
+		func(tx txs.Tx) error {
+			if err := state.ExecTx(burrowMint.GetCheckCache(), tx, false, nil,
+				logger); err != nil {
+				return err
+			}
+			fixture.broadcasts = append(fixture.broadcasts, tx)
+			return nil
+		})
+	return fixture
+}
+
+func (fixture *transactorFixture) balance(address []byte) int64 {
+	return fixture.burrowMint.GetCheckCache().GetAccount(address).Balance
+}
+
+func privKeyBytes(privAccount *acm.PrivAccount) []byte {
+	switch privKey := privAccount.PrivKey.(type) {
+	case crypto.PrivKeyEd25519:
+		return privKey[:]
+	case crypto.PrivKeySecp256k1:
+		return privKey[:]
+	}
+	return nil
+}
+
+func TestTransactorSendMulti(t *testing.T) {
+	first := acm.GenPrivAccount()
+	second, err := acm.GenPrivAccountWithKeyType(acm.KeyTypeSecp256k1)
+	if !assert.NoError(t, err) {
+		return
+	}
+	fixture := newTransactorFixture(t, first, second)
+	to := acm.GenPrivAccount().Address
+
+	// Each input is signed by its own key, so the check accepts the tx
+	receipt, err := fixture.SendMulti(
+		[][]byte{privKeyBytes(first), privKeyBytes(second)}, []int64{10, 20},
+		[]*txs.TxOutput{{Address: to, Amount: 25}})
+	if !assert.NoError(t, err) || !assert.Len(t, fixture.broadcasts, 1) {
+		return
+	}
+	sendTx := fixture.broadcasts[0].(*txs.SendTx)
+	assert.Equal(t, txs.TxHash(testChainID, sendTx), receipt.TxHash)
+	if assert.Len(t, sendTx.Inputs, 2) {
+		assert.Equal(t, first.Address, sendTx.Inputs[0].Address)
+		assert.Equal(t, second.Address, sendTx.Inputs[1].Address)
+		assert.Equal(t, 1, sendTx.Inputs[0].Sequence)
+		assert.Equal(t, 1, sendTx.Inputs[1].Sequence)
+	}
+	assert.Equal(t, int64(990), fixture.balance(first.Address))
+	assert.Equal(t, int64(980), fixture.balance(second.Address))
+	assert.Equal(t, int64(25), fixture.balance(to))
+
+	// Later transactions from the inputs follow on
+	_, err = fixture.Send(privKeyBytes(first), to, 5)
+	assert.NoError(t, err)
+	_, err = fixture.SendMulti([][]byte{privKeyBytes(second), privKeyBytes(first)},
+		[]int64{5, 5}, []*txs.TxOutput{{Address: to, Amount: 10}})
+	if assert.NoError(t, err) && assert.Len(t, fixture.broadcasts, 3) {
+		sendTx = fixture.broadcasts[2].(*txs.SendTx)
+		assert.Equal(t, 2, sendTx.Inputs[0].Sequence)
+		assert.Equal(t, 3, sendTx.Inputs[1].Sequence)
+	}
+}
+
+func TestTransactorSendMultiUnsigned(t *testing.T) {
+	first := acm.GenPrivAccount()
+	second := acm.GenPrivAccount()
+	fixture := newTransactorFixture(t, first, second)
+	to := acm.GenPrivAccount().Address
+	outputs := []*txs.TxOutput{{Address: to, Amount: 10}}
+
+	// Inputs that cannot be signed are rejected before broadcasting
+	_, err := fixture.SendMulti([][]byte{privKeyBytes(first), {1, 2, 3}},
+		[]int64{5, 5}, outputs)
+	assert.Error(t, err)
+	_, err = fixture.SendMulti([][]byte{privKeyBytes(first),
+		privKeyBytes(acm.GenPrivAccount())}, []int64{5, 5}, outputs)
+	assert.Error(t, err)
+	_, err = fixture.SendMulti([][]byte{privKeyBytes(first)}, []int64{5, 5}, outputs)
+	assert.Error(t, err)
+
+	// as is a SendTx with an input left unsigned
+	sendTx := txs.NewSendTx()
+	sendTx.Inputs = []*txs.TxInput{
+		{Address: first.Address, Amount: 5, Sequence: 1},
+		{Address: second.Address, Amount: 5, Sequence: 1},
+	}
+	sendTx.Outputs = outputs
+	_, err = fixture.SignTx(sendTx, []*acm.PrivAccount{first})
+	assert.Error(t, err)
+
+	// and one the check rejects leaves the inputs' sequences to be used again
+	_, err = fixture.SendMulti([][]byte{privKeyBytes(first), privKeyBytes(second)},
+		[]int64{5, 5000}, []*txs.TxOutput{{Address: to, Amount: 5005}})
+	assert.Error(t, err)
+	assert.Len(t, fixture.broadcasts, 0)
+
+	_, err = fixture.SendMulti([][]byte{privKeyBytes(first), privKeyBytes(second)},
+		[]int64{5, 5}, outputs)
+	if assert.NoError(t, err) && assert.Len(t, fixture.broadcasts, 1) {
+		sendTx = fixture.broadcasts[0].(*txs.SendTx)
+		assert.Equal(t, 1, sendTx.Inputs[0].Sequence)
+		assert.Equal(t, 1, sendTx.Inputs[1].Sequence)
+	}
+}
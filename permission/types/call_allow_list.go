@@ -0,0 +1,127 @@
+// Copyright 2017 Monax Industries Limited
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"bytes"
+	"fmt"
+)
+
+//---------------------------------------------------------------------------------------------
+
+// A CallAllowList restricts the contracts an account, or the members of a
+// role, may call to those it lists. Accounts and roles without one may call
+// any contract (given the Call permission), whereas an empty CallAllowList
+// allows calling none.
+type CallAllowList struct {
+	Contracts [][]byte `json:"contracts"`
+}
+
+// Returns true if the contract is on the list
+func (cal *CallAllowList) Allows(contract []byte) bool {
+	for _, c := range cal.Contracts {
+		if bytes.Equal(c, contract) {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns true if the contract is added, and false if it is already on the list
+func (cal *CallAllowList) Add(contract []byte) bool {
+	if cal.Allows(contract) {
+		return false
+	}
+	cal.Contracts = append(cal.Contracts, contract)
+	return true
+}
+
+// Returns true if the contract is removed, and false if it is not on the list
+func (cal *CallAllowList) Remove(contract []byte) bool {
+	var contracts [][]byte
+	for _, c := range cal.Contracts {
+		if !bytes.Equal(c, contract) {
+			contracts = append(contracts, c)
+		}
+	}
+	removed := len(contracts) < len(cal.Contracts)
+	cal.Contracts = contracts
+	return removed
+}
+
+func (cal *CallAllowList) Clone() *CallAllowList {
+	if cal == nil {
+		return nil
+	}
+	contracts := make([][]byte, len(cal.Contracts))
+	copy(contracts, cal.Contracts)
+	return &CallAllowList{Contracts: contracts}
+}
+
+func (cal *CallAllowList) String() string {
+	return fmt.Sprintf("CallAllowList%X", cal.Contracts)
+}
+
+// Allow calling the contract, restricting the account to calling only the
+// contracts allowed if it was not restricted already. Returns true if the
+// contract is added to the account's CallAllowList.
+func (aP *AccountPermissions) AllowCall(contract []byte) bool {
+	if aP.CallAllowList == nil {
+		aP.CallAllowList = &CallAllowList{}
+	}
+	return aP.CallAllowList.Add(contract)
+}
+
+// Remove the contract from those the account may call, which leaves it
+// restricted even when no contracts remain allowed. Returns true if the
+// contract is removed from the account's CallAllowList.
+func (aP *AccountPermissions) DisallowCall(contract []byte) bool {
+	if aP.CallAllowList == nil {
+		return false
+	}
+	return aP.CallAllowList.Remove(contract)
+}
+
+// Lift any restriction on the contracts the account may call. Returns true if
+// the account was restricted.
+func (aP *AccountPermissions) UnrestrictCalls() bool {
+	restricted := aP.CallAllowList != nil
+	aP.CallAllowList = nil
+	return restricted
+}
+
+// Returns true if the account may call the contract: either neither the
+// account nor any of its roles has a CallAllowList, or one of them allows the
+// contract
+func (aP *AccountPermissions) CanCall(roles RoleGetter, contract []byte) bool {
+	restricted := false
+	if aP.CallAllowList != nil {
+		if aP.CallAllowList.Allows(contract) {
+			return true
+		}
+		restricted = true
+	}
+	for _, name := range aP.ExpandedRoles(roles) {
+		role := roles.GetRole(name)
+		if role == nil || role.CallAllowList == nil {
+			continue
+		}
+		if role.CallAllowList.Allows(contract) {
+			return true
+		}
+		restricted = true
+	}
+	return !restricted
+}
@@ -0,0 +1,180 @@
+// Copyright 2017 Monax Industries Limited
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"bytes"
+	"fmt"
+)
+
+//---------------------------------------------------------------------------------------------
+
+const (
+	ProposalStatusPending  = "pending"
+	ProposalStatusExecuted = "executed"
+	ProposalStatusExpired  = "expired"
+)
+
+// The base permissions that may only be granted through governance, since
+// either would let an account get around it
+const governedPermFlags = Root | Govern
+
+// Chains that set GovernanceParams require the approval of Threshold accounts
+// holding the Govern permission for the permission changes they govern (see
+// Governs). Such a change is proposed by a PermissionsTx from one of them,
+// which counts as its first approval, and is executed by the ApproveArgs
+// PermissionsTx that meets the threshold.
+type GovernanceParams struct {
+	// The number of approvals a proposal needs, or 0 to disable governance
+	Threshold int `json:"threshold"`
+	// The number of blocks, including the one it is proposed in, during which a
+	// proposal may be approved
+	ProposalExpiry int64 `json:"proposal_expiry"`
+}
+
+func (gp *GovernanceParams) Enabled() bool {
+	return gp != nil && gp.Threshold > 0
+}
+
+func (gp *GovernanceParams) Validate() error {
+	if gp == nil {
+		return nil
+	}
+	if gp.Threshold < 0 {
+		return fmt.Errorf("governance threshold must not be negative but is %v", gp.Threshold)
+	}
+	if gp.Threshold > 0 && gp.ProposalExpiry <= 0 {
+		return fmt.Errorf("governance proposal expiry must be positive but is %v",
+			gp.ProposalExpiry)
+	}
+	return nil
+}
+
+// Returns true if args make a change that needs approval when governance is
+// enabled: setting a global permission, granting Root or Govern to an account,
+// adding an account to a role granting them (itself or by the roles it
+// inherits), granting them to a role by its permissions or inherits, or
+// changing the admin of a role
+func Governs(roles RoleGetter, args PermArgs) bool {
+	switch args := args.(type) {
+	case *SetGlobalArgs:
+		return true
+	case *SetBaseArgs:
+		return args.Value && args.Permission&governedPermFlags != 0
+	case *SetBaseUntilArgs:
+		return args.Value && args.Permission&governedPermFlags != 0
+	case *AddRoleArgs:
+		return roleGovernedGrants(roles, args.Role) != 0
+	case *AddRoleUntilArgs:
+		return roleGovernedGrants(roles, args.Role) != 0
+	case *SetRoleArgs:
+		role := args.Role.Normalise()
+		existing := roles.GetRole(role.Name)
+		if existing == nil {
+			existing = &Role{}
+		}
+		grants := governedGrants(role.Base)
+		for _, inherited := range role.Inherits {
+			grants |= roleGovernedGrants(roles, inherited)
+		}
+		return role.Admin != existing.Admin ||
+			grants&^roleGovernedGrants(roles, role.Name) != 0
+	}
+	return false
+}
+
+func governedGrants(base BasePermissions) PermFlag {
+	return base.Perms & base.SetBit & governedPermFlags
+}
+
+// The governed permissions granted to members of the role by it and the roles
+// it inherits
+func roleGovernedGrants(roles RoleGetter, role string) PermFlag {
+	var grants PermFlag
+	member := &AccountPermissions{Roles: []string{role}}
+	for _, name := range member.ExpandedRoles(roles) {
+		if r := roles.GetRole(name); r != nil {
+			grants |= governedGrants(r.Base)
+		}
+	}
+	return grants
+}
+
+// A Proposal holds a governed PermArgs until enough accounts approve it. It is
+// identified by the hash of the PermissionsTx proposing it.
+type Proposal struct {
+	Hash     []byte   `json:"hash"`
+	Proposer []byte   `json:"proposer"`
+	PermArgs PermArgs `json:"perm_args"`
+	// Addresses of the accounts that have approved the proposal, starting with
+	// the proposer
+	Approvals [][]byte `json:"approvals"`
+	// The proposal may no longer be approved once the last block height reaches
+	// Expires
+	Expires int64 `json:"expires"`
+	// Block height at which PermArgs were executed, or 0 while pending
+	ExecutedHeight int64 `json:"executed_height"`
+}
+
+func (p *Proposal) HasApproved(address []byte) bool {
+	for _, approver := range p.Approvals {
+		if bytes.Equal(approver, address) {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns true if the approval is added, and false if the account has
+// approved the proposal already
+func (p *Proposal) Approve(address []byte) bool {
+	if p.HasApproved(address) {
+		return false
+	}
+	p.Approvals = append(p.Approvals, address)
+	return true
+}
+
+func (p *Proposal) Executed() bool {
+	return p.ExecutedHeight > 0
+}
+
+func (p *Proposal) Expired(lastBlockHeight int64) bool {
+	return !p.Executed() && p.Expires <= lastBlockHeight
+}
+
+func (p *Proposal) Status(lastBlockHeight int64) string {
+	switch {
+	case p.Executed():
+		return ProposalStatusExecuted
+	case p.Expired(lastBlockHeight):
+		return ProposalStatusExpired
+	default:
+		return ProposalStatusPending
+	}
+}
+
+func (p *Proposal) Clone() *Proposal {
+	approvals := make([][]byte, len(p.Approvals))
+	copy(approvals, p.Approvals)
+	proposalCopy := *p
+	proposalCopy.Approvals = approvals
+	return &proposalCopy
+}
+
+func (p *Proposal) String() string {
+	return fmt.Sprintf("Proposal{%X Proposer: %X %v Approvals: %X Expires: %v Executed: %v}",
+		p.Hash, p.Proposer, p.PermArgs, p.Approvals, p.Expires, p.ExecutedHeight)
+}
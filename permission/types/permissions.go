@@ -50,8 +50,11 @@ const (
 	HasRole
 	AddRole
 	RmRole
+	SetRole
+	SetCallAllowList
+	Govern
 
-	NumPermissions uint = 14 // NOTE Adjust this too. We can support upto 64
+	NumPermissions uint = 17 // NOTE Adjust this too. We can support upto 64
 
 	TopPermFlag      PermFlag = 1 << (NumPermissions - 1)
 	AllPermFlags     PermFlag = TopPermFlag | (TopPermFlag - 1)
@@ -142,11 +145,136 @@ func (p BasePermissions) String() string {
 type AccountPermissions struct {
 	Base  BasePermissions `json:"base"`
 	Roles []string        `json:"roles"`
+	// The block heights at which base permissions and roles granted for a
+	// limited time lapse, see AtHeight
+	BaseExpiries []BaseExpiry `json:"base_expiries,omitempty"`
+	RoleExpiries []RoleExpiry `json:"role_expiries,omitempty"`
+	// The contracts the account may call when restricted, see CanCall
+	CallAllowList *CallAllowList `json:"call_allow_list,omitempty"`
+}
+
+// Base permissions set until a block height, after which they fall through to
+// roles and global permissions as if unset
+type BaseExpiry struct {
+	Permission PermFlag `json:"permission"`
+	Height     int64    `json:"height"`
+}
+
+// A role held until a block height
+type RoleExpiry struct {
+	Role   string `json:"role"`
+	Height int64  `json:"height"`
+}
+
+// Returns the permissions in force once the chain has reached lastBlockHeight,
+// that is with the base permissions and roles whose expiry height is no
+// greater than lastBlockHeight removed. As with name registry entries a grant
+// expiring at height h holds for the transactions of blocks up to and
+// including h.
+func (aP *AccountPermissions) AtHeight(lastBlockHeight int64) *AccountPermissions {
+	if len(aP.BaseExpiries) == 0 && len(aP.RoleExpiries) == 0 {
+		return aP
+	}
+	current := aP.Clone()
+	for _, expiry := range aP.BaseExpiries {
+		if expiry.Height <= lastBlockHeight {
+			current.Base.Unset(expiry.Permission)
+		}
+	}
+	for _, expiry := range aP.RoleExpiries {
+		if expiry.Height <= lastBlockHeight {
+			current.RmRole(expiry.Role)
+		}
+	}
+	return &current
+}
+
+// Set a permission until the chain passes the block height expires, or
+// permanently if expires is 0, replacing any previous expiry of the permission
+func (aP *AccountPermissions) SetBaseUntil(ty PermFlag, value bool, expires int64) error {
+	if err := aP.Base.Set(ty, value); err != nil {
+		return err
+	}
+	aP.clearBaseExpiry(ty)
+	if expires > 0 {
+		aP.BaseExpiries = append(aP.BaseExpiries, BaseExpiry{ty, expires})
+	}
+	return nil
+}
+
+// Unset a permission along with any expiry it was set with
+func (aP *AccountPermissions) UnsetBase(ty PermFlag) error {
+	if err := aP.Base.Unset(ty); err != nil {
+		return err
+	}
+	aP.clearBaseExpiry(ty)
+	return nil
+}
+
+// Get the height at which a permission set until a block height expires,
+// returning 0 if it is set permanently or is not set
+func (aP *AccountPermissions) BaseExpiry(ty PermFlag) int64 {
+	for _, expiry := range aP.BaseExpiries {
+		if expiry.Permission&ty == ty {
+			return expiry.Height
+		}
+	}
+	return 0
+}
+
+// Add a role until the chain passes the block height expires, or permanently
+// if expires is 0. Returns true if the role is added or its expiry changed,
+// and false if the account already has the role with the same expiry.
+func (aP *AccountPermissions) AddRoleUntil(role string, expires int64) bool {
+	role = RoleName(role)
+	if aP.HasRole(role) && aP.RoleExpiry(role) == expires {
+		return false
+	}
+	aP.AddRole(role)
+	aP.clearRoleExpiry(role)
+	if expires > 0 {
+		aP.RoleExpiries = append(aP.RoleExpiries, RoleExpiry{role, expires})
+	}
+	return true
+}
+
+// Get the height at which a role held until a block height expires, returning
+// 0 if it is held permanently or is not held
+func (aP *AccountPermissions) RoleExpiry(role string) int64 {
+	role = RoleName(role)
+	for _, expiry := range aP.RoleExpiries {
+		if expiry.Role == role {
+			return expiry.Height
+		}
+	}
+	return 0
+}
+
+// Remove the permissions ty from the expiries, dropping any left empty
+func (aP *AccountPermissions) clearBaseExpiry(ty PermFlag) {
+	var expiries []BaseExpiry
+	for _, expiry := range aP.BaseExpiries {
+		if expiry.Permission &^= ty; expiry.Permission != 0 {
+			expiries = append(expiries, expiry)
+		}
+	}
+	aP.BaseExpiries = expiries
+}
+
+// CONTRACT: role has been padded by RoleName
+func (aP *AccountPermissions) clearRoleExpiry(role string) {
+	var expiries []RoleExpiry
+	for _, expiry := range aP.RoleExpiries {
+		if expiry.Role != role {
+			expiries = append(expiries, expiry)
+		}
+	}
+	aP.RoleExpiries = expiries
 }
 
 // Returns true if the role is found
 func (aP *AccountPermissions) HasRole(role string) bool {
-	role = string(word256.RightPadBytes([]byte(role), 32))
+	role = RoleName(role)
 	for _, r := range aP.Roles {
 		if r == role {
 			return true
@@ -157,7 +285,7 @@ func (aP *AccountPermissions) HasRole(role string) bool {
 
 // Returns true if the role is added, and false if it already exists
 func (aP *AccountPermissions) AddRole(role string) bool {
-	role = string(word256.RightPadBytes([]byte(role), 32))
+	role = RoleName(role)
 	for _, r := range aP.Roles {
 		if r == role {
 			return false
@@ -169,7 +297,7 @@ func (aP *AccountPermissions) AddRole(role string) bool {
 
 // Returns true if the role is removed, and false if it is not found
 func (aP *AccountPermissions) RmRole(role string) bool {
-	role = string(word256.RightPadBytes([]byte(role), 32))
+	role = RoleName(role)
 	for i, r := range aP.Roles {
 		if r == role {
 			post := []string{}
@@ -177,6 +305,7 @@ func (aP *AccountPermissions) RmRole(role string) bool {
 				post = aP.Roles[i+1:]
 			}
 			aP.Roles = append(aP.Roles[:i], post...)
+			aP.clearRoleExpiry(role)
 			return true
 		}
 	}
@@ -192,9 +321,23 @@ func (accountPermissions *AccountPermissions) Clone() AccountPermissions {
 	// strings are immutable so copy suffices
 	copy(rolesClone, accountPermissions.Roles)
 
+	var baseExpiriesClone []BaseExpiry
+	if len(accountPermissions.BaseExpiries) > 0 {
+		baseExpiriesClone = make([]BaseExpiry, len(accountPermissions.BaseExpiries))
+		copy(baseExpiriesClone, accountPermissions.BaseExpiries)
+	}
+	var roleExpiriesClone []RoleExpiry
+	if len(accountPermissions.RoleExpiries) > 0 {
+		roleExpiriesClone = make([]RoleExpiry, len(accountPermissions.RoleExpiries))
+		copy(roleExpiriesClone, accountPermissions.RoleExpiries)
+	}
+
 	return AccountPermissions{
-		Base:  basePermissionsClone,
-		Roles: rolesClone,
+		Base:          basePermissionsClone,
+		Roles:         rolesClone,
+		BaseExpiries:  baseExpiriesClone,
+		RoleExpiries:  roleExpiriesClone,
+		CallAllowList: accountPermissions.CallAllowList.Clone(),
 	}
 }
 
@@ -232,6 +375,12 @@ func PermFlagToString(pf PermFlag) (perm string) {
 		perm = "addRole"
 	case RmRole:
 		perm = "removeRole"
+	case SetRole:
+		perm = "setRole"
+	case SetCallAllowList:
+		perm = "setCallAllowList"
+	case Govern:
+		perm = "govern"
 	default:
 		perm = "#-UNKNOWN-#"
 	}
@@ -270,6 +419,12 @@ func PermStringToFlag(perm string) (pf PermFlag, err error) {
 		pf = AddRole
 	case "removerole", "rmrole", "rm_role":
 		pf = RmRole
+	case "setrole", "set_role":
+		pf = SetRole
+	case "setcallallowlist", "set_call_allow_list":
+		pf = SetCallAllowList
+	case "govern":
+		pf = Govern
 	default:
 		err = fmt.Errorf("Unknown permission %s", perm)
 	}
@@ -0,0 +1,150 @@
+// Copyright 2017 Monax Industries Limited
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/burrow/word256"
+)
+
+//---------------------------------------------------------------------------------------------
+
+// A Role is held by the accounts that are its members (those with the role in
+// their AccountPermissions.Roles), which get the base permissions the role sets
+// for any permission they do not set themselves
+type Role struct {
+	Name string          `json:"name"`
+	Base BasePermissions `json:"base"`
+	// Members of this role are also members of the roles it inherits, and so
+	// get their permissions too
+	Inherits []string `json:"inherits"`
+	// Members of the admin role may add and remove members of this role
+	// without holding the AddRole and RmRole permissions
+	Admin string `json:"admin"`
+	// The contracts members of this role may call when restricted, see
+	// AccountPermissions.CanCall
+	CallAllowList *CallAllowList `json:"call_allow_list,omitempty"`
+}
+
+// Looks up roles by name, returning nil for roles that have not been defined
+type RoleGetter interface {
+	GetRole(name string) *Role
+}
+
+// Role names are padded to 32 bytes so they can be passed to and from
+// contracts as bytes32
+func RoleName(role string) string {
+	return string(word256.RightPadBytes([]byte(role), 32))
+}
+
+// Returns a copy of the role with its name and the names of the roles it
+// refers to padded by RoleName
+func (role *Role) Normalise() *Role {
+	inherits := make([]string, len(role.Inherits))
+	for i, inherited := range role.Inherits {
+		inherits[i] = RoleName(inherited)
+	}
+	var admin string
+	if role.Admin != "" {
+		admin = RoleName(role.Admin)
+	}
+	return &Role{
+		Name:          RoleName(role.Name),
+		Base:          role.Base,
+		Inherits:      inherits,
+		Admin:         admin,
+		CallAllowList: role.CallAllowList.Clone(),
+	}
+}
+
+func (role *Role) String() string {
+	return fmt.Sprintf("Role{%s %v Inherits: %v Admin: %s %v}", role.Name, role.Base,
+		role.Inherits, role.Admin, role.CallAllowList)
+}
+
+// Get the roles of the account along with all the roles they inherit, each
+// once. Inheritance may be cyclic.
+func (aP *AccountPermissions) ExpandedRoles(roles RoleGetter) []string {
+	var expanded []string
+	seen := make(map[string]bool)
+	queue := make([]string, len(aP.Roles))
+	for i, role := range aP.Roles {
+		queue[i] = RoleName(role)
+	}
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		expanded = append(expanded, name)
+		if role := roles.GetRole(name); role != nil {
+			for _, inherited := range role.Inherits {
+				queue = append(queue, RoleName(inherited))
+			}
+		}
+	}
+	return expanded
+}
+
+// Returns true if the account has the role itself or through a role it has
+// that inherits it
+func (aP *AccountPermissions) HasRoleInherited(roles RoleGetter, role string) bool {
+	role = RoleName(role)
+	for _, r := range aP.ExpandedRoles(roles) {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// Get a permission value from the roles of the account. A permission granted
+// by any role wins over one denied by another. As with BasePermissions.Get,
+// ErrValueNotSet is returned if none of the roles set the permission.
+func (aP *AccountPermissions) GetRolePermission(roles RoleGetter, ty PermFlag) (bool, error) {
+	if ty == 0 {
+		return false, ErrInvalidPermission(ty)
+	}
+	set := false
+	for _, name := range aP.ExpandedRoles(roles) {
+		role := roles.GetRole(name)
+		if role == nil {
+			continue
+		}
+		if v, err := role.Base.Get(ty); err == nil {
+			if v {
+				return true, nil
+			}
+			set = true
+		}
+	}
+	if set {
+		return false, nil
+	}
+	return false, ErrValueNotSet(ty)
+}
+
+// Returns true if the account is a member of the admin role of role, so may
+// add and remove members of role
+func (aP *AccountPermissions) IsRoleAdmin(roles RoleGetter, role string) bool {
+	r := roles.GetRole(RoleName(role))
+	if r == nil || r.Admin == "" {
+		return false
+	}
+	return aP.HasRoleInherited(roles, r.Admin)
+}
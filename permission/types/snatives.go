@@ -37,6 +37,18 @@ const (
 	PermArgsTypeHasRole   = byte(0x05)
 	PermArgsTypeAddRole   = byte(0x06)
 	PermArgsTypeRmRole    = byte(0x07)
+	PermArgsTypeSetRole   = byte(0x08)
+
+	PermArgsTypeAllowCall       = byte(0x09)
+	PermArgsTypeDisallowCall    = byte(0x0A)
+	PermArgsTypeUnrestrictCalls = byte(0x0B)
+
+	PermArgsTypeApprove = byte(0x0C)
+
+	// SetBase and AddRole until a block height, given their own type bytes so
+	// PermissionsTxs encoded before expiries existed still decode
+	PermArgsTypeSetBaseUntil = byte(0x0D)
+	PermArgsTypeAddRoleUntil = byte(0x0E)
 )
 
 // TODO: [ben] this registration needs to be lifted up
@@ -53,6 +65,13 @@ var _ = wire.RegisterInterface(
 	wire.ConcreteType{&HasRoleArgs{}, PermArgsTypeHasRole},
 	wire.ConcreteType{&AddRoleArgs{}, PermArgsTypeAddRole},
 	wire.ConcreteType{&RmRoleArgs{}, PermArgsTypeRmRole},
+	wire.ConcreteType{&SetRoleArgs{}, PermArgsTypeSetRole},
+	wire.ConcreteType{&AllowCallArgs{}, PermArgsTypeAllowCall},
+	wire.ConcreteType{&DisallowCallArgs{}, PermArgsTypeDisallowCall},
+	wire.ConcreteType{&UnrestrictCallsArgs{}, PermArgsTypeUnrestrictCalls},
+	wire.ConcreteType{&ApproveArgs{}, PermArgsTypeApprove},
+	wire.ConcreteType{&SetBaseUntilArgs{}, PermArgsTypeSetBaseUntil},
+	wire.ConcreteType{&AddRoleUntilArgs{}, PermArgsTypeAddRoleUntil},
 )
 
 type HasBaseArgs struct {
@@ -74,6 +93,19 @@ func (*SetBaseArgs) PermFlag() PermFlag {
 	return SetBase
 }
 
+// SetBaseArgs with a block height at which the permission lapses, or 0 to set
+// it permanently as SetBaseArgs does
+type SetBaseUntilArgs struct {
+	Address    []byte   `json:"address"`
+	Permission PermFlag `json:"permission"`
+	Value      bool     `json:"value"`
+	Expires    int64    `json:"expires"`
+}
+
+func (*SetBaseUntilArgs) PermFlag() PermFlag {
+	return SetBase
+}
+
 type UnsetBaseArgs struct {
 	Address    []byte   `json:"address"`
 	Permission PermFlag `json:"permission"`
@@ -110,6 +142,18 @@ func (*AddRoleArgs) PermFlag() PermFlag {
 	return AddRole
 }
 
+// AddRoleArgs with a block height at which the role lapses, or 0 to add it
+// permanently as AddRoleArgs does
+type AddRoleUntilArgs struct {
+	Address []byte `json:"address"`
+	Role    string `json:"role"`
+	Expires int64  `json:"expires"`
+}
+
+func (*AddRoleUntilArgs) PermFlag() PermFlag {
+	return AddRole
+}
+
 type RmRoleArgs struct {
 	Address []byte `json:"address"`
 	Role    string `json:"role"`
@@ -118,3 +162,58 @@ type RmRoleArgs struct {
 func (*RmRoleArgs) PermFlag() PermFlag {
 	return RmRole
 }
+
+// Defines a role, replacing any previous definition of it
+type SetRoleArgs struct {
+	Role Role `json:"role"`
+}
+
+func (*SetRoleArgs) PermFlag() PermFlag {
+	return SetRole
+}
+
+// The CallAllowList args apply to the account at Address, or when it is empty
+// to the role named Role
+
+// Allows calling Contract, restricting the account or role to calling only the
+// contracts allowed if it was not restricted already
+type AllowCallArgs struct {
+	Address  []byte `json:"address,omitempty"`
+	Role     string `json:"role,omitempty"`
+	Contract []byte `json:"contract"`
+}
+
+func (*AllowCallArgs) PermFlag() PermFlag {
+	return SetCallAllowList
+}
+
+// Removes Contract from those the account or role may call
+type DisallowCallArgs struct {
+	Address  []byte `json:"address,omitempty"`
+	Role     string `json:"role,omitempty"`
+	Contract []byte `json:"contract"`
+}
+
+func (*DisallowCallArgs) PermFlag() PermFlag {
+	return SetCallAllowList
+}
+
+// Lifts any restriction on the contracts the account or role may call
+type UnrestrictCallsArgs struct {
+	Address []byte `json:"address,omitempty"`
+	Role    string `json:"role,omitempty"`
+}
+
+func (*UnrestrictCallsArgs) PermFlag() PermFlag {
+	return SetCallAllowList
+}
+
+// Approves the governed permission change proposed by the PermissionsTx with
+// hash Proposal, see GovernanceParams
+type ApproveArgs struct {
+	Proposal []byte `json:"proposal"`
+}
+
+func (*ApproveArgs) PermFlag() PermFlag {
+	return Govern
+}
@@ -0,0 +1,2085 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: burrow.proto
+
+/*
+Package grpc is a generated protocol buffer package.
+
+It is generated from these files:
+
+	burrow.proto
+
+It has these top-level messages:
+
+	Empty
+	AddressParam
+	StorageAtParam
+	FilterData
+	FiltersParam
+	AccountPermissions
+	Account
+	AccountList
+	StorageItem
+	Storage
+	BlockchainInfo
+	HeightParam
+	BlocksParam
+	StreamBlocksParam
+	Block
+	NameParam
+	NameRegEntry
+	NameRegEntryList
+	CallParam
+	CallCodeParam
+	CallResult
+	TxParam
+	Receipt
+	TransactParam
+	SendParam
+	TransactNameRegParam
+	CallData
+	EventDataCall
+	EventIdParam
+	EventDataLog
+	EventDataTx
+	Event
+*/
+package grpc
+
+import proto "github.com/golang/protobuf/proto"
+import fmt "fmt"
+import math "math"
+
+import (
+	context "golang.org/x/net/context"
+	grpc1 "google.golang.org/grpc"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+
+type Empty struct {
+}
+
+func (m *Empty) Reset()                    { *m = Empty{} }
+func (m *Empty) String() string            { return proto.CompactTextString(m) }
+func (*Empty) ProtoMessage()               {}
+func (*Empty) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{0} }
+
+type AddressParam struct {
+	Address []byte `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+}
+
+func (m *AddressParam) Reset()                    { *m = AddressParam{} }
+func (m *AddressParam) String() string            { return proto.CompactTextString(m) }
+func (*AddressParam) ProtoMessage()               {}
+func (*AddressParam) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{1} }
+
+func (m *AddressParam) GetAddress() []byte {
+	if m != nil {
+		return m.Address
+	}
+	return nil
+}
+
+type StorageAtParam struct {
+	Address []byte `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	Key     []byte `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+}
+
+func (m *StorageAtParam) Reset()                    { *m = StorageAtParam{} }
+func (m *StorageAtParam) String() string            { return proto.CompactTextString(m) }
+func (*StorageAtParam) ProtoMessage()               {}
+func (*StorageAtParam) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{2} }
+
+func (m *StorageAtParam) GetAddress() []byte {
+	if m != nil {
+		return m.Address
+	}
+	return nil
+}
+
+func (m *StorageAtParam) GetKey() []byte {
+	if m != nil {
+		return m.Key
+	}
+	return nil
+}
+
+// Same semantics as the filters of the v0 JSON-RPC and REST gateways
+type FilterData struct {
+	Field string `protobuf:"bytes,1,opt,name=field" json:"field,omitempty"`
+	Op    string `protobuf:"bytes,2,opt,name=op" json:"op,omitempty"`
+	Value string `protobuf:"bytes,3,opt,name=value" json:"value,omitempty"`
+}
+
+func (m *FilterData) Reset()                    { *m = FilterData{} }
+func (m *FilterData) String() string            { return proto.CompactTextString(m) }
+func (*FilterData) ProtoMessage()               {}
+func (*FilterData) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{3} }
+
+func (m *FilterData) GetField() string {
+	if m != nil {
+		return m.Field
+	}
+	return ""
+}
+
+func (m *FilterData) GetOp() string {
+	if m != nil {
+		return m.Op
+	}
+	return ""
+}
+
+func (m *FilterData) GetValue() string {
+	if m != nil {
+		return m.Value
+	}
+	return ""
+}
+
+type FiltersParam struct {
+	Filters []*FilterData `protobuf:"bytes,1,rep,name=filters" json:"filters,omitempty"`
+}
+
+func (m *FiltersParam) Reset()                    { *m = FiltersParam{} }
+func (m *FiltersParam) String() string            { return proto.CompactTextString(m) }
+func (*FiltersParam) ProtoMessage()               {}
+func (*FiltersParam) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{4} }
+
+func (m *FiltersParam) GetFilters() []*FilterData {
+	if m != nil {
+		return m.Filters
+	}
+	return nil
+}
+
+type AccountPermissions struct {
+	Perms  uint64   `protobuf:"varint,1,opt,name=perms" json:"perms,omitempty"`
+	SetBit uint64   `protobuf:"varint,2,opt,name=set_bit,json=setBit" json:"set_bit,omitempty"`
+	Roles  []string `protobuf:"bytes,3,rep,name=roles" json:"roles,omitempty"`
+}
+
+func (m *AccountPermissions) Reset()                    { *m = AccountPermissions{} }
+func (m *AccountPermissions) String() string            { return proto.CompactTextString(m) }
+func (*AccountPermissions) ProtoMessage()               {}
+func (*AccountPermissions) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{5} }
+
+func (m *AccountPermissions) GetPerms() uint64 {
+	if m != nil {
+		return m.Perms
+	}
+	return 0
+}
+
+func (m *AccountPermissions) GetSetBit() uint64 {
+	if m != nil {
+		return m.SetBit
+	}
+	return 0
+}
+
+func (m *AccountPermissions) GetRoles() []string {
+	if m != nil {
+		return m.Roles
+	}
+	return nil
+}
+
+type Account struct {
+	Address []byte `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	// go-wire binary encoding of the public key, including its type byte
+	PubKey      []byte              `protobuf:"bytes,2,opt,name=pub_key,json=pubKey,proto3" json:"pub_key,omitempty"`
+	Sequence    int64               `protobuf:"varint,3,opt,name=sequence" json:"sequence,omitempty"`
+	Balance     int64               `protobuf:"varint,4,opt,name=balance" json:"balance,omitempty"`
+	Code        []byte              `protobuf:"bytes,5,opt,name=code,proto3" json:"code,omitempty"`
+	StorageRoot []byte              `protobuf:"bytes,6,opt,name=storage_root,json=storageRoot,proto3" json:"storage_root,omitempty"`
+	Permissions *AccountPermissions `protobuf:"bytes,7,opt,name=permissions" json:"permissions,omitempty"`
+}
+
+func (m *Account) Reset()                    { *m = Account{} }
+func (m *Account) String() string            { return proto.CompactTextString(m) }
+func (*Account) ProtoMessage()               {}
+func (*Account) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{6} }
+
+func (m *Account) GetAddress() []byte {
+	if m != nil {
+		return m.Address
+	}
+	return nil
+}
+
+func (m *Account) GetPubKey() []byte {
+	if m != nil {
+		return m.PubKey
+	}
+	return nil
+}
+
+func (m *Account) GetSequence() int64 {
+	if m != nil {
+		return m.Sequence
+	}
+	return 0
+}
+
+func (m *Account) GetBalance() int64 {
+	if m != nil {
+		return m.Balance
+	}
+	return 0
+}
+
+func (m *Account) GetCode() []byte {
+	if m != nil {
+		return m.Code
+	}
+	return nil
+}
+
+func (m *Account) GetStorageRoot() []byte {
+	if m != nil {
+		return m.StorageRoot
+	}
+	return nil
+}
+
+func (m *Account) GetPermissions() *AccountPermissions {
+	if m != nil {
+		return m.Permissions
+	}
+	return nil
+}
+
+type AccountList struct {
+	Accounts []*Account `protobuf:"bytes,1,rep,name=accounts" json:"accounts,omitempty"`
+}
+
+func (m *AccountList) Reset()                    { *m = AccountList{} }
+func (m *AccountList) String() string            { return proto.CompactTextString(m) }
+func (*AccountList) ProtoMessage()               {}
+func (*AccountList) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{7} }
+
+func (m *AccountList) GetAccounts() []*Account {
+	if m != nil {
+		return m.Accounts
+	}
+	return nil
+}
+
+type StorageItem struct {
+	Key   []byte `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Value []byte `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+}
+
+func (m *StorageItem) Reset()                    { *m = StorageItem{} }
+func (m *StorageItem) String() string            { return proto.CompactTextString(m) }
+func (*StorageItem) ProtoMessage()               {}
+func (*StorageItem) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{8} }
+
+func (m *StorageItem) GetKey() []byte {
+	if m != nil {
+		return m.Key
+	}
+	return nil
+}
+
+func (m *StorageItem) GetValue() []byte {
+	if m != nil {
+		return m.Value
+	}
+	return nil
+}
+
+type Storage struct {
+	StorageRoot  []byte         `protobuf:"bytes,1,opt,name=storage_root,json=storageRoot,proto3" json:"storage_root,omitempty"`
+	StorageItems []*StorageItem `protobuf:"bytes,2,rep,name=storage_items,json=storageItems" json:"storage_items,omitempty"`
+}
+
+func (m *Storage) Reset()                    { *m = Storage{} }
+func (m *Storage) String() string            { return proto.CompactTextString(m) }
+func (*Storage) ProtoMessage()               {}
+func (*Storage) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{9} }
+
+func (m *Storage) GetStorageRoot() []byte {
+	if m != nil {
+		return m.StorageRoot
+	}
+	return nil
+}
+
+func (m *Storage) GetStorageItems() []*StorageItem {
+	if m != nil {
+		return m.StorageItems
+	}
+	return nil
+}
+
+type BlockchainInfo struct {
+	ChainId           string `protobuf:"bytes,1,opt,name=chain_id,json=chainId" json:"chain_id,omitempty"`
+	GenesisHash       []byte `protobuf:"bytes,2,opt,name=genesis_hash,json=genesisHash,proto3" json:"genesis_hash,omitempty"`
+	LatestBlockHeight int64  `protobuf:"varint,3,opt,name=latest_block_height,json=latestBlockHeight" json:"latest_block_height,omitempty"`
+}
+
+func (m *BlockchainInfo) Reset()                    { *m = BlockchainInfo{} }
+func (m *BlockchainInfo) String() string            { return proto.CompactTextString(m) }
+func (*BlockchainInfo) ProtoMessage()               {}
+func (*BlockchainInfo) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{10} }
+
+func (m *BlockchainInfo) GetChainId() string {
+	if m != nil {
+		return m.ChainId
+	}
+	return ""
+}
+
+func (m *BlockchainInfo) GetGenesisHash() []byte {
+	if m != nil {
+		return m.GenesisHash
+	}
+	return nil
+}
+
+func (m *BlockchainInfo) GetLatestBlockHeight() int64 {
+	if m != nil {
+		return m.LatestBlockHeight
+	}
+	return 0
+}
+
+type HeightParam struct {
+	Height int64 `protobuf:"varint,1,opt,name=height" json:"height,omitempty"`
+}
+
+func (m *HeightParam) Reset()                    { *m = HeightParam{} }
+func (m *HeightParam) String() string            { return proto.CompactTextString(m) }
+func (*HeightParam) ProtoMessage()               {}
+func (*HeightParam) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{11} }
+
+func (m *HeightParam) GetHeight() int64 {
+	if m != nil {
+		return m.Height
+	}
+	return 0
+}
+
+type BlocksParam struct {
+	MinHeight int64 `protobuf:"varint,1,opt,name=min_height,json=minHeight" json:"min_height,omitempty"`
+	MaxHeight int64 `protobuf:"varint,2,opt,name=max_height,json=maxHeight" json:"max_height,omitempty"`
+}
+
+func (m *BlocksParam) Reset()                    { *m = BlocksParam{} }
+func (m *BlocksParam) String() string            { return proto.CompactTextString(m) }
+func (*BlocksParam) ProtoMessage()               {}
+func (*BlocksParam) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{12} }
+
+func (m *BlocksParam) GetMinHeight() int64 {
+	if m != nil {
+		return m.MinHeight
+	}
+	return 0
+}
+
+func (m *BlocksParam) GetMaxHeight() int64 {
+	if m != nil {
+		return m.MaxHeight
+	}
+	return 0
+}
+
+type StreamBlocksParam struct {
+	// First block to send, blocks already committed are sent before new blocks.
+	// Zero means only stream blocks committed after the call.
+	FromHeight int64 `protobuf:"varint,1,opt,name=from_height,json=fromHeight" json:"from_height,omitempty"`
+}
+
+func (m *StreamBlocksParam) Reset()                    { *m = StreamBlocksParam{} }
+func (m *StreamBlocksParam) String() string            { return proto.CompactTextString(m) }
+func (*StreamBlocksParam) ProtoMessage()               {}
+func (*StreamBlocksParam) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{13} }
+
+func (m *StreamBlocksParam) GetFromHeight() int64 {
+	if m != nil {
+		return m.FromHeight
+	}
+	return 0
+}
+
+type Block struct {
+	Height  int64  `protobuf:"varint,1,opt,name=height" json:"height,omitempty"`
+	Hash    []byte `protobuf:"bytes,2,opt,name=hash,proto3" json:"hash,omitempty"`
+	ChainId string `protobuf:"bytes,3,opt,name=chain_id,json=chainId" json:"chain_id,omitempty"`
+	// Unix time in nanoseconds
+	Time          int64  `protobuf:"varint,4,opt,name=time" json:"time,omitempty"`
+	NumTxs        int64  `protobuf:"varint,5,opt,name=num_txs,json=numTxs" json:"num_txs,omitempty"`
+	LastBlockHash []byte `protobuf:"bytes,6,opt,name=last_block_hash,json=lastBlockHash,proto3" json:"last_block_hash,omitempty"`
+	AppHash       []byte `protobuf:"bytes,7,opt,name=app_hash,json=appHash,proto3" json:"app_hash,omitempty"`
+	// go-wire binary encoded txs
+	Txs [][]byte `protobuf:"bytes,8,rep,name=txs,proto3" json:"txs,omitempty"`
+}
+
+func (m *Block) Reset()                    { *m = Block{} }
+func (m *Block) String() string            { return proto.CompactTextString(m) }
+func (*Block) ProtoMessage()               {}
+func (*Block) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{14} }
+
+func (m *Block) GetHeight() int64 {
+	if m != nil {
+		return m.Height
+	}
+	return 0
+}
+
+func (m *Block) GetHash() []byte {
+	if m != nil {
+		return m.Hash
+	}
+	return nil
+}
+
+func (m *Block) GetChainId() string {
+	if m != nil {
+		return m.ChainId
+	}
+	return ""
+}
+
+func (m *Block) GetTime() int64 {
+	if m != nil {
+		return m.Time
+	}
+	return 0
+}
+
+func (m *Block) GetNumTxs() int64 {
+	if m != nil {
+		return m.NumTxs
+	}
+	return 0
+}
+
+func (m *Block) GetLastBlockHash() []byte {
+	if m != nil {
+		return m.LastBlockHash
+	}
+	return nil
+}
+
+func (m *Block) GetAppHash() []byte {
+	if m != nil {
+		return m.AppHash
+	}
+	return nil
+}
+
+func (m *Block) GetTxs() [][]byte {
+	if m != nil {
+		return m.Txs
+	}
+	return nil
+}
+
+type NameParam struct {
+	Name string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+}
+
+func (m *NameParam) Reset()                    { *m = NameParam{} }
+func (m *NameParam) String() string            { return proto.CompactTextString(m) }
+func (*NameParam) ProtoMessage()               {}
+func (*NameParam) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{15} }
+
+func (m *NameParam) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+type NameRegEntry struct {
+	Name    string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	Owner   []byte `protobuf:"bytes,2,opt,name=owner,proto3" json:"owner,omitempty"`
+	Data    string `protobuf:"bytes,3,opt,name=data" json:"data,omitempty"`
+	Expires int64  `protobuf:"varint,4,opt,name=expires" json:"expires,omitempty"`
+}
+
+func (m *NameRegEntry) Reset()                    { *m = NameRegEntry{} }
+func (m *NameRegEntry) String() string            { return proto.CompactTextString(m) }
+func (*NameRegEntry) ProtoMessage()               {}
+func (*NameRegEntry) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{16} }
+
+func (m *NameRegEntry) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *NameRegEntry) GetOwner() []byte {
+	if m != nil {
+		return m.Owner
+	}
+	return nil
+}
+
+func (m *NameRegEntry) GetData() string {
+	if m != nil {
+		return m.Data
+	}
+	return ""
+}
+
+func (m *NameRegEntry) GetExpires() int64 {
+	if m != nil {
+		return m.Expires
+	}
+	return 0
+}
+
+type NameRegEntryList struct {
+	BlockHeight int64           `protobuf:"varint,1,opt,name=block_height,json=blockHeight" json:"block_height,omitempty"`
+	Names       []*NameRegEntry `protobuf:"bytes,2,rep,name=names" json:"names,omitempty"`
+}
+
+func (m *NameRegEntryList) Reset()                    { *m = NameRegEntryList{} }
+func (m *NameRegEntryList) String() string            { return proto.CompactTextString(m) }
+func (*NameRegEntryList) ProtoMessage()               {}
+func (*NameRegEntryList) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{17} }
+
+func (m *NameRegEntryList) GetBlockHeight() int64 {
+	if m != nil {
+		return m.BlockHeight
+	}
+	return 0
+}
+
+func (m *NameRegEntryList) GetNames() []*NameRegEntry {
+	if m != nil {
+		return m.Names
+	}
+	return nil
+}
+
+type CallParam struct {
+	From    []byte `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"`
+	Address []byte `protobuf:"bytes,2,opt,name=address,proto3" json:"address,omitempty"`
+	Data    []byte `protobuf:"bytes,3,opt,name=data,proto3" json:"data,omitempty"`
+}
+
+func (m *CallParam) Reset()                    { *m = CallParam{} }
+func (m *CallParam) String() string            { return proto.CompactTextString(m) }
+func (*CallParam) ProtoMessage()               {}
+func (*CallParam) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{18} }
+
+func (m *CallParam) GetFrom() []byte {
+	if m != nil {
+		return m.From
+	}
+	return nil
+}
+
+func (m *CallParam) GetAddress() []byte {
+	if m != nil {
+		return m.Address
+	}
+	return nil
+}
+
+func (m *CallParam) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+type CallCodeParam struct {
+	From []byte `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"`
+	Code []byte `protobuf:"bytes,2,opt,name=code,proto3" json:"code,omitempty"`
+	Data []byte `protobuf:"bytes,3,opt,name=data,proto3" json:"data,omitempty"`
+}
+
+func (m *CallCodeParam) Reset()                    { *m = CallCodeParam{} }
+func (m *CallCodeParam) String() string            { return proto.CompactTextString(m) }
+func (*CallCodeParam) ProtoMessage()               {}
+func (*CallCodeParam) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{19} }
+
+func (m *CallCodeParam) GetFrom() []byte {
+	if m != nil {
+		return m.From
+	}
+	return nil
+}
+
+func (m *CallCodeParam) GetCode() []byte {
+	if m != nil {
+		return m.Code
+	}
+	return nil
+}
+
+func (m *CallCodeParam) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+type CallResult struct {
+	Return  []byte `protobuf:"bytes,1,opt,name=return,proto3" json:"return,omitempty"`
+	GasUsed int64  `protobuf:"varint,2,opt,name=gas_used,json=gasUsed" json:"gas_used,omitempty"`
+}
+
+func (m *CallResult) Reset()                    { *m = CallResult{} }
+func (m *CallResult) String() string            { return proto.CompactTextString(m) }
+func (*CallResult) ProtoMessage()               {}
+func (*CallResult) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{20} }
+
+func (m *CallResult) GetReturn() []byte {
+	if m != nil {
+		return m.Return
+	}
+	return nil
+}
+
+func (m *CallResult) GetGasUsed() int64 {
+	if m != nil {
+		return m.GasUsed
+	}
+	return 0
+}
+
+type TxParam struct {
+	// go-wire binary encoded tx
+	Tx []byte `protobuf:"bytes,1,opt,name=tx,proto3" json:"tx,omitempty"`
+}
+
+func (m *TxParam) Reset()                    { *m = TxParam{} }
+func (m *TxParam) String() string            { return proto.CompactTextString(m) }
+func (*TxParam) ProtoMessage()               {}
+func (*TxParam) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{21} }
+
+func (m *TxParam) GetTx() []byte {
+	if m != nil {
+		return m.Tx
+	}
+	return nil
+}
+
+type Receipt struct {
+	TxHash          []byte `protobuf:"bytes,1,opt,name=tx_hash,json=txHash,proto3" json:"tx_hash,omitempty"`
+	CreatesContract bool   `protobuf:"varint,2,opt,name=creates_contract,json=createsContract" json:"creates_contract,omitempty"`
+	ContractAddress []byte `protobuf:"bytes,3,opt,name=contract_address,json=contractAddress,proto3" json:"contract_address,omitempty"`
+}
+
+func (m *Receipt) Reset()                    { *m = Receipt{} }
+func (m *Receipt) String() string            { return proto.CompactTextString(m) }
+func (*Receipt) ProtoMessage()               {}
+func (*Receipt) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{22} }
+
+func (m *Receipt) GetTxHash() []byte {
+	if m != nil {
+		return m.TxHash
+	}
+	return nil
+}
+
+func (m *Receipt) GetCreatesContract() bool {
+	if m != nil {
+		return m.CreatesContract
+	}
+	return false
+}
+
+func (m *Receipt) GetContractAddress() []byte {
+	if m != nil {
+		return m.ContractAddress
+	}
+	return nil
+}
+
+type TransactParam struct {
+	PrivKey  []byte `protobuf:"bytes,1,opt,name=priv_key,json=privKey,proto3" json:"priv_key,omitempty"`
+	Address  []byte `protobuf:"bytes,2,opt,name=address,proto3" json:"address,omitempty"`
+	Data     []byte `protobuf:"bytes,3,opt,name=data,proto3" json:"data,omitempty"`
+	GasLimit int64  `protobuf:"varint,4,opt,name=gas_limit,json=gasLimit" json:"gas_limit,omitempty"`
+	Fee      int64  `protobuf:"varint,5,opt,name=fee" json:"fee,omitempty"`
+}
+
+func (m *TransactParam) Reset()                    { *m = TransactParam{} }
+func (m *TransactParam) String() string            { return proto.CompactTextString(m) }
+func (*TransactParam) ProtoMessage()               {}
+func (*TransactParam) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{23} }
+
+func (m *TransactParam) GetPrivKey() []byte {
+	if m != nil {
+		return m.PrivKey
+	}
+	return nil
+}
+
+func (m *TransactParam) GetAddress() []byte {
+	if m != nil {
+		return m.Address
+	}
+	return nil
+}
+
+func (m *TransactParam) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+func (m *TransactParam) GetGasLimit() int64 {
+	if m != nil {
+		return m.GasLimit
+	}
+	return 0
+}
+
+func (m *TransactParam) GetFee() int64 {
+	if m != nil {
+		return m.Fee
+	}
+	return 0
+}
+
+type SendParam struct {
+	PrivKey   []byte `protobuf:"bytes,1,opt,name=priv_key,json=privKey,proto3" json:"priv_key,omitempty"`
+	ToAddress []byte `protobuf:"bytes,2,opt,name=to_address,json=toAddress,proto3" json:"to_address,omitempty"`
+	Amount    int64  `protobuf:"varint,3,opt,name=amount" json:"amount,omitempty"`
+}
+
+func (m *SendParam) Reset()                    { *m = SendParam{} }
+func (m *SendParam) String() string            { return proto.CompactTextString(m) }
+func (*SendParam) ProtoMessage()               {}
+func (*SendParam) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{24} }
+
+func (m *SendParam) GetPrivKey() []byte {
+	if m != nil {
+		return m.PrivKey
+	}
+	return nil
+}
+
+func (m *SendParam) GetToAddress() []byte {
+	if m != nil {
+		return m.ToAddress
+	}
+	return nil
+}
+
+func (m *SendParam) GetAmount() int64 {
+	if m != nil {
+		return m.Amount
+	}
+	return 0
+}
+
+type TransactNameRegParam struct {
+	PrivKey []byte `protobuf:"bytes,1,opt,name=priv_key,json=privKey,proto3" json:"priv_key,omitempty"`
+	Name    string `protobuf:"bytes,2,opt,name=name" json:"name,omitempty"`
+	Data    string `protobuf:"bytes,3,opt,name=data" json:"data,omitempty"`
+	Amount  int64  `protobuf:"varint,4,opt,name=amount" json:"amount,omitempty"`
+	Fee     int64  `protobuf:"varint,5,opt,name=fee" json:"fee,omitempty"`
+}
+
+func (m *TransactNameRegParam) Reset()                    { *m = TransactNameRegParam{} }
+func (m *TransactNameRegParam) String() string            { return proto.CompactTextString(m) }
+func (*TransactNameRegParam) ProtoMessage()               {}
+func (*TransactNameRegParam) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{25} }
+
+func (m *TransactNameRegParam) GetPrivKey() []byte {
+	if m != nil {
+		return m.PrivKey
+	}
+	return nil
+}
+
+func (m *TransactNameRegParam) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *TransactNameRegParam) GetData() string {
+	if m != nil {
+		return m.Data
+	}
+	return ""
+}
+
+func (m *TransactNameRegParam) GetAmount() int64 {
+	if m != nil {
+		return m.Amount
+	}
+	return 0
+}
+
+func (m *TransactNameRegParam) GetFee() int64 {
+	if m != nil {
+		return m.Fee
+	}
+	return 0
+}
+
+type CallData struct {
+	Caller []byte `protobuf:"bytes,1,opt,name=caller,proto3" json:"caller,omitempty"`
+	Callee []byte `protobuf:"bytes,2,opt,name=callee,proto3" json:"callee,omitempty"`
+	Data   []byte `protobuf:"bytes,3,opt,name=data,proto3" json:"data,omitempty"`
+	Value  int64  `protobuf:"varint,4,opt,name=value" json:"value,omitempty"`
+	Gas    int64  `protobuf:"varint,5,opt,name=gas" json:"gas,omitempty"`
+}
+
+func (m *CallData) Reset()                    { *m = CallData{} }
+func (m *CallData) String() string            { return proto.CompactTextString(m) }
+func (*CallData) ProtoMessage()               {}
+func (*CallData) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{26} }
+
+func (m *CallData) GetCaller() []byte {
+	if m != nil {
+		return m.Caller
+	}
+	return nil
+}
+
+func (m *CallData) GetCallee() []byte {
+	if m != nil {
+		return m.Callee
+	}
+	return nil
+}
+
+func (m *CallData) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+func (m *CallData) GetValue() int64 {
+	if m != nil {
+		return m.Value
+	}
+	return 0
+}
+
+func (m *CallData) GetGas() int64 {
+	if m != nil {
+		return m.Gas
+	}
+	return 0
+}
+
+type EventDataCall struct {
+	CallData  *CallData `protobuf:"bytes,1,opt,name=call_data,json=callData" json:"call_data,omitempty"`
+	Origin    []byte    `protobuf:"bytes,2,opt,name=origin,proto3" json:"origin,omitempty"`
+	TxId      []byte    `protobuf:"bytes,3,opt,name=tx_id,json=txId,proto3" json:"tx_id,omitempty"`
+	Return    []byte    `protobuf:"bytes,4,opt,name=return,proto3" json:"return,omitempty"`
+	Exception string    `protobuf:"bytes,5,opt,name=exception" json:"exception,omitempty"`
+}
+
+func (m *EventDataCall) Reset()                    { *m = EventDataCall{} }
+func (m *EventDataCall) String() string            { return proto.CompactTextString(m) }
+func (*EventDataCall) ProtoMessage()               {}
+func (*EventDataCall) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{27} }
+
+func (m *EventDataCall) GetCallData() *CallData {
+	if m != nil {
+		return m.CallData
+	}
+	return nil
+}
+
+func (m *EventDataCall) GetOrigin() []byte {
+	if m != nil {
+		return m.Origin
+	}
+	return nil
+}
+
+func (m *EventDataCall) GetTxId() []byte {
+	if m != nil {
+		return m.TxId
+	}
+	return nil
+}
+
+func (m *EventDataCall) GetReturn() []byte {
+	if m != nil {
+		return m.Return
+	}
+	return nil
+}
+
+func (m *EventDataCall) GetException() string {
+	if m != nil {
+		return m.Exception
+	}
+	return ""
+}
+
+type EventIdParam struct {
+	// Event id strings as used by the other gateways, e.g. Log/<address>
+	EventId string `protobuf:"bytes,1,opt,name=event_id,json=eventId" json:"event_id,omitempty"`
+}
+
+func (m *EventIdParam) Reset()                    { *m = EventIdParam{} }
+func (m *EventIdParam) String() string            { return proto.CompactTextString(m) }
+func (*EventIdParam) ProtoMessage()               {}
+func (*EventIdParam) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{28} }
+
+func (m *EventIdParam) GetEventId() string {
+	if m != nil {
+		return m.EventId
+	}
+	return ""
+}
+
+type EventDataLog struct {
+	Address []byte   `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	Topics  [][]byte `protobuf:"bytes,2,rep,name=topics,proto3" json:"topics,omitempty"`
+	Data    []byte   `protobuf:"bytes,3,opt,name=data,proto3" json:"data,omitempty"`
+	Height  int64    `protobuf:"varint,4,opt,name=height" json:"height,omitempty"`
+}
+
+func (m *EventDataLog) Reset()                    { *m = EventDataLog{} }
+func (m *EventDataLog) String() string            { return proto.CompactTextString(m) }
+func (*EventDataLog) ProtoMessage()               {}
+func (*EventDataLog) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{29} }
+
+func (m *EventDataLog) GetAddress() []byte {
+	if m != nil {
+		return m.Address
+	}
+	return nil
+}
+
+func (m *EventDataLog) GetTopics() [][]byte {
+	if m != nil {
+		return m.Topics
+	}
+	return nil
+}
+
+func (m *EventDataLog) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+func (m *EventDataLog) GetHeight() int64 {
+	if m != nil {
+		return m.Height
+	}
+	return 0
+}
+
+type EventDataTx struct {
+	// go-wire binary encoded tx
+	Tx        []byte `protobuf:"bytes,1,opt,name=tx,proto3" json:"tx,omitempty"`
+	Return    []byte `protobuf:"bytes,2,opt,name=return,proto3" json:"return,omitempty"`
+	Exception string `protobuf:"bytes,3,opt,name=exception" json:"exception,omitempty"`
+}
+
+func (m *EventDataTx) Reset()                    { *m = EventDataTx{} }
+func (m *EventDataTx) String() string            { return proto.CompactTextString(m) }
+func (*EventDataTx) ProtoMessage()               {}
+func (*EventDataTx) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{30} }
+
+func (m *EventDataTx) GetTx() []byte {
+	if m != nil {
+		return m.Tx
+	}
+	return nil
+}
+
+func (m *EventDataTx) GetReturn() []byte {
+	if m != nil {
+		return m.Return
+	}
+	return nil
+}
+
+func (m *EventDataTx) GetException() string {
+	if m != nil {
+		return m.Exception
+	}
+	return ""
+}
+
+// At most one of the data fields is set depending on the type of event.
+// Events of other types only carry their event_id.
+type Event struct {
+	EventId  string         `protobuf:"bytes,1,opt,name=event_id,json=eventId" json:"event_id,omitempty"`
+	Call     *EventDataCall `protobuf:"bytes,2,opt,name=call" json:"call,omitempty"`
+	Log      *EventDataLog  `protobuf:"bytes,3,opt,name=log" json:"log,omitempty"`
+	Tx       *EventDataTx   `protobuf:"bytes,4,opt,name=tx" json:"tx,omitempty"`
+	NewBlock *Block         `protobuf:"bytes,5,opt,name=new_block,json=newBlock" json:"new_block,omitempty"`
+}
+
+func (m *Event) Reset()                    { *m = Event{} }
+func (m *Event) String() string            { return proto.CompactTextString(m) }
+func (*Event) ProtoMessage()               {}
+func (*Event) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{31} }
+
+func (m *Event) GetEventId() string {
+	if m != nil {
+		return m.EventId
+	}
+	return ""
+}
+
+func (m *Event) GetCall() *EventDataCall {
+	if m != nil {
+		return m.Call
+	}
+	return nil
+}
+
+func (m *Event) GetLog() *EventDataLog {
+	if m != nil {
+		return m.Log
+	}
+	return nil
+}
+
+func (m *Event) GetTx() *EventDataTx {
+	if m != nil {
+		return m.Tx
+	}
+	return nil
+}
+
+func (m *Event) GetNewBlock() *Block {
+	if m != nil {
+		return m.NewBlock
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*Empty)(nil), "burrow.Empty")
+	proto.RegisterType((*AddressParam)(nil), "burrow.AddressParam")
+	proto.RegisterType((*StorageAtParam)(nil), "burrow.StorageAtParam")
+	proto.RegisterType((*FilterData)(nil), "burrow.FilterData")
+	proto.RegisterType((*FiltersParam)(nil), "burrow.FiltersParam")
+	proto.RegisterType((*AccountPermissions)(nil), "burrow.AccountPermissions")
+	proto.RegisterType((*Account)(nil), "burrow.Account")
+	proto.RegisterType((*AccountList)(nil), "burrow.AccountList")
+	proto.RegisterType((*StorageItem)(nil), "burrow.StorageItem")
+	proto.RegisterType((*Storage)(nil), "burrow.Storage")
+	proto.RegisterType((*BlockchainInfo)(nil), "burrow.BlockchainInfo")
+	proto.RegisterType((*HeightParam)(nil), "burrow.HeightParam")
+	proto.RegisterType((*BlocksParam)(nil), "burrow.BlocksParam")
+	proto.RegisterType((*StreamBlocksParam)(nil), "burrow.StreamBlocksParam")
+	proto.RegisterType((*Block)(nil), "burrow.Block")
+	proto.RegisterType((*NameParam)(nil), "burrow.NameParam")
+	proto.RegisterType((*NameRegEntry)(nil), "burrow.NameRegEntry")
+	proto.RegisterType((*NameRegEntryList)(nil), "burrow.NameRegEntryList")
+	proto.RegisterType((*CallParam)(nil), "burrow.CallParam")
+	proto.RegisterType((*CallCodeParam)(nil), "burrow.CallCodeParam")
+	proto.RegisterType((*CallResult)(nil), "burrow.CallResult")
+	proto.RegisterType((*TxParam)(nil), "burrow.TxParam")
+	proto.RegisterType((*Receipt)(nil), "burrow.Receipt")
+	proto.RegisterType((*TransactParam)(nil), "burrow.TransactParam")
+	proto.RegisterType((*SendParam)(nil), "burrow.SendParam")
+	proto.RegisterType((*TransactNameRegParam)(nil), "burrow.TransactNameRegParam")
+	proto.RegisterType((*CallData)(nil), "burrow.CallData")
+	proto.RegisterType((*EventDataCall)(nil), "burrow.EventDataCall")
+	proto.RegisterType((*EventIdParam)(nil), "burrow.EventIdParam")
+	proto.RegisterType((*EventDataLog)(nil), "burrow.EventDataLog")
+	proto.RegisterType((*EventDataTx)(nil), "burrow.EventDataTx")
+	proto.RegisterType((*Event)(nil), "burrow.Event")
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc1.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc1.SupportPackageIsVersion4
+
+// Client API for Accounts service
+
+type AccountsClient interface {
+	GetAccount(ctx context.Context, in *AddressParam, opts ...grpc1.CallOption) (*Account, error)
+	ListAccounts(ctx context.Context, in *FiltersParam, opts ...grpc1.CallOption) (*AccountList, error)
+	GetStorage(ctx context.Context, in *AddressParam, opts ...grpc1.CallOption) (*Storage, error)
+	GetStorageAt(ctx context.Context, in *StorageAtParam, opts ...grpc1.CallOption) (*StorageItem, error)
+}
+
+type accountsClient struct {
+	cc *grpc1.ClientConn
+}
+
+func NewAccountsClient(cc *grpc1.ClientConn) AccountsClient {
+	return &accountsClient{cc}
+}
+
+func (c *accountsClient) GetAccount(ctx context.Context, in *AddressParam, opts ...grpc1.CallOption) (*Account, error) {
+	out := new(Account)
+	err := grpc1.Invoke(ctx, "/burrow.Accounts/GetAccount", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountsClient) ListAccounts(ctx context.Context, in *FiltersParam, opts ...grpc1.CallOption) (*AccountList, error) {
+	out := new(AccountList)
+	err := grpc1.Invoke(ctx, "/burrow.Accounts/ListAccounts", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountsClient) GetStorage(ctx context.Context, in *AddressParam, opts ...grpc1.CallOption) (*Storage, error) {
+	out := new(Storage)
+	err := grpc1.Invoke(ctx, "/burrow.Accounts/GetStorage", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountsClient) GetStorageAt(ctx context.Context, in *StorageAtParam, opts ...grpc1.CallOption) (*StorageItem, error) {
+	out := new(StorageItem)
+	err := grpc1.Invoke(ctx, "/burrow.Accounts/GetStorageAt", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Server API for Accounts service
+
+type AccountsServer interface {
+	GetAccount(context.Context, *AddressParam) (*Account, error)
+	ListAccounts(context.Context, *FiltersParam) (*AccountList, error)
+	GetStorage(context.Context, *AddressParam) (*Storage, error)
+	GetStorageAt(context.Context, *StorageAtParam) (*StorageItem, error)
+}
+
+func RegisterAccountsServer(s *grpc1.Server, srv AccountsServer) {
+	s.RegisterService(&_Accounts_serviceDesc, srv)
+}
+
+func _Accounts_GetAccount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc1.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddressParam)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountsServer).GetAccount(ctx, in)
+	}
+	info := &grpc1.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/burrow.Accounts/GetAccount",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountsServer).GetAccount(ctx, req.(*AddressParam))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Accounts_ListAccounts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc1.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FiltersParam)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountsServer).ListAccounts(ctx, in)
+	}
+	info := &grpc1.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/burrow.Accounts/ListAccounts",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountsServer).ListAccounts(ctx, req.(*FiltersParam))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Accounts_GetStorage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc1.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddressParam)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountsServer).GetStorage(ctx, in)
+	}
+	info := &grpc1.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/burrow.Accounts/GetStorage",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountsServer).GetStorage(ctx, req.(*AddressParam))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Accounts_GetStorageAt_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc1.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StorageAtParam)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountsServer).GetStorageAt(ctx, in)
+	}
+	info := &grpc1.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/burrow.Accounts/GetStorageAt",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountsServer).GetStorageAt(ctx, req.(*StorageAtParam))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _Accounts_serviceDesc = grpc1.ServiceDesc{
+	ServiceName: "burrow.Accounts",
+	HandlerType: (*AccountsServer)(nil),
+	Methods: []grpc1.MethodDesc{
+		{
+			MethodName: "GetAccount",
+			Handler:    _Accounts_GetAccount_Handler,
+		},
+		{
+			MethodName: "ListAccounts",
+			Handler:    _Accounts_ListAccounts_Handler,
+		},
+		{
+			MethodName: "GetStorage",
+			Handler:    _Accounts_GetStorage_Handler,
+		},
+		{
+			MethodName: "GetStorageAt",
+			Handler:    _Accounts_GetStorageAt_Handler,
+		},
+	},
+	Streams:  []grpc1.StreamDesc{},
+	Metadata: "burrow.proto",
+}
+
+// Client API for Blockchain service
+
+type BlockchainClient interface {
+	GetInfo(ctx context.Context, in *Empty, opts ...grpc1.CallOption) (*BlockchainInfo, error)
+	GetBlock(ctx context.Context, in *HeightParam, opts ...grpc1.CallOption) (*Block, error)
+	// Blocks between min_height and max_height inclusive, in ascending order
+	ListBlocks(ctx context.Context, in *BlocksParam, opts ...grpc1.CallOption) (Blockchain_ListBlocksClient, error)
+	// Blocks as they are committed, never terminates of its own accord
+	StreamBlocks(ctx context.Context, in *StreamBlocksParam, opts ...grpc1.CallOption) (Blockchain_StreamBlocksClient, error)
+}
+
+type blockchainClient struct {
+	cc *grpc1.ClientConn
+}
+
+func NewBlockchainClient(cc *grpc1.ClientConn) BlockchainClient {
+	return &blockchainClient{cc}
+}
+
+func (c *blockchainClient) GetInfo(ctx context.Context, in *Empty, opts ...grpc1.CallOption) (*BlockchainInfo, error) {
+	out := new(BlockchainInfo)
+	err := grpc1.Invoke(ctx, "/burrow.Blockchain/GetInfo", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *blockchainClient) GetBlock(ctx context.Context, in *HeightParam, opts ...grpc1.CallOption) (*Block, error) {
+	out := new(Block)
+	err := grpc1.Invoke(ctx, "/burrow.Blockchain/GetBlock", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *blockchainClient) ListBlocks(ctx context.Context, in *BlocksParam, opts ...grpc1.CallOption) (Blockchain_ListBlocksClient, error) {
+	stream, err := grpc1.NewClientStream(ctx, &_Blockchain_serviceDesc.Streams[0], c.cc, "/burrow.Blockchain/ListBlocks", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &blockchainListBlocksClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Blockchain_ListBlocksClient interface {
+	Recv() (*Block, error)
+	grpc1.ClientStream
+}
+
+type blockchainListBlocksClient struct {
+	grpc1.ClientStream
+}
+
+func (x *blockchainListBlocksClient) Recv() (*Block, error) {
+	m := new(Block)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *blockchainClient) StreamBlocks(ctx context.Context, in *StreamBlocksParam, opts ...grpc1.CallOption) (Blockchain_StreamBlocksClient, error) {
+	stream, err := grpc1.NewClientStream(ctx, &_Blockchain_serviceDesc.Streams[1], c.cc, "/burrow.Blockchain/StreamBlocks", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &blockchainStreamBlocksClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Blockchain_StreamBlocksClient interface {
+	Recv() (*Block, error)
+	grpc1.ClientStream
+}
+
+type blockchainStreamBlocksClient struct {
+	grpc1.ClientStream
+}
+
+func (x *blockchainStreamBlocksClient) Recv() (*Block, error) {
+	m := new(Block)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Server API for Blockchain service
+
+type BlockchainServer interface {
+	GetInfo(context.Context, *Empty) (*BlockchainInfo, error)
+	GetBlock(context.Context, *HeightParam) (*Block, error)
+	// Blocks between min_height and max_height inclusive, in ascending order
+	ListBlocks(*BlocksParam, Blockchain_ListBlocksServer) error
+	// Blocks as they are committed, never terminates of its own accord
+	StreamBlocks(*StreamBlocksParam, Blockchain_StreamBlocksServer) error
+}
+
+func RegisterBlockchainServer(s *grpc1.Server, srv BlockchainServer) {
+	s.RegisterService(&_Blockchain_serviceDesc, srv)
+}
+
+func _Blockchain_GetInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc1.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlockchainServer).GetInfo(ctx, in)
+	}
+	info := &grpc1.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/burrow.Blockchain/GetInfo",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlockchainServer).GetInfo(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Blockchain_GetBlock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc1.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HeightParam)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlockchainServer).GetBlock(ctx, in)
+	}
+	info := &grpc1.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/burrow.Blockchain/GetBlock",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlockchainServer).GetBlock(ctx, req.(*HeightParam))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Blockchain_ListBlocks_Handler(srv interface{}, stream grpc1.ServerStream) error {
+	m := new(BlocksParam)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BlockchainServer).ListBlocks(m, &blockchainListBlocksServer{stream})
+}
+
+type Blockchain_ListBlocksServer interface {
+	Send(*Block) error
+	grpc1.ServerStream
+}
+
+type blockchainListBlocksServer struct {
+	grpc1.ServerStream
+}
+
+func (x *blockchainListBlocksServer) Send(m *Block) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Blockchain_StreamBlocks_Handler(srv interface{}, stream grpc1.ServerStream) error {
+	m := new(StreamBlocksParam)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BlockchainServer).StreamBlocks(m, &blockchainStreamBlocksServer{stream})
+}
+
+type Blockchain_StreamBlocksServer interface {
+	Send(*Block) error
+	grpc1.ServerStream
+}
+
+type blockchainStreamBlocksServer struct {
+	grpc1.ServerStream
+}
+
+func (x *blockchainStreamBlocksServer) Send(m *Block) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+var _Blockchain_serviceDesc = grpc1.ServiceDesc{
+	ServiceName: "burrow.Blockchain",
+	HandlerType: (*BlockchainServer)(nil),
+	Methods: []grpc1.MethodDesc{
+		{
+			MethodName: "GetInfo",
+			Handler:    _Blockchain_GetInfo_Handler,
+		},
+		{
+			MethodName: "GetBlock",
+			Handler:    _Blockchain_GetBlock_Handler,
+		},
+	},
+	Streams: []grpc1.StreamDesc{
+		{
+			StreamName:    "ListBlocks",
+			Handler:       _Blockchain_ListBlocks_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "StreamBlocks",
+			Handler:       _Blockchain_StreamBlocks_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "burrow.proto",
+}
+
+// Client API for NameReg service
+
+type NameRegClient interface {
+	GetEntry(ctx context.Context, in *NameParam, opts ...grpc1.CallOption) (*NameRegEntry, error)
+	ListEntries(ctx context.Context, in *FiltersParam, opts ...grpc1.CallOption) (*NameRegEntryList, error)
+}
+
+type nameRegClient struct {
+	cc *grpc1.ClientConn
+}
+
+func NewNameRegClient(cc *grpc1.ClientConn) NameRegClient {
+	return &nameRegClient{cc}
+}
+
+func (c *nameRegClient) GetEntry(ctx context.Context, in *NameParam, opts ...grpc1.CallOption) (*NameRegEntry, error) {
+	out := new(NameRegEntry)
+	err := grpc1.Invoke(ctx, "/burrow.NameReg/GetEntry", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *nameRegClient) ListEntries(ctx context.Context, in *FiltersParam, opts ...grpc1.CallOption) (*NameRegEntryList, error) {
+	out := new(NameRegEntryList)
+	err := grpc1.Invoke(ctx, "/burrow.NameReg/ListEntries", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Server API for NameReg service
+
+type NameRegServer interface {
+	GetEntry(context.Context, *NameParam) (*NameRegEntry, error)
+	ListEntries(context.Context, *FiltersParam) (*NameRegEntryList, error)
+}
+
+func RegisterNameRegServer(s *grpc1.Server, srv NameRegServer) {
+	s.RegisterService(&_NameReg_serviceDesc, srv)
+}
+
+func _NameReg_GetEntry_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc1.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NameParam)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NameRegServer).GetEntry(ctx, in)
+	}
+	info := &grpc1.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/burrow.NameReg/GetEntry",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NameRegServer).GetEntry(ctx, req.(*NameParam))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NameReg_ListEntries_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc1.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FiltersParam)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NameRegServer).ListEntries(ctx, in)
+	}
+	info := &grpc1.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/burrow.NameReg/ListEntries",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NameRegServer).ListEntries(ctx, req.(*FiltersParam))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _NameReg_serviceDesc = grpc1.ServiceDesc{
+	ServiceName: "burrow.NameReg",
+	HandlerType: (*NameRegServer)(nil),
+	Methods: []grpc1.MethodDesc{
+		{
+			MethodName: "GetEntry",
+			Handler:    _NameReg_GetEntry_Handler,
+		},
+		{
+			MethodName: "ListEntries",
+			Handler:    _NameReg_ListEntries_Handler,
+		},
+	},
+	Streams:  []grpc1.StreamDesc{},
+	Metadata: "burrow.proto",
+}
+
+// Client API for Transactor service
+
+type TransactorClient interface {
+	Call(ctx context.Context, in *CallParam, opts ...grpc1.CallOption) (*CallResult, error)
+	CallCode(ctx context.Context, in *CallCodeParam, opts ...grpc1.CallOption) (*CallResult, error)
+	BroadcastTx(ctx context.Context, in *TxParam, opts ...grpc1.CallOption) (*Receipt, error)
+	Transact(ctx context.Context, in *TransactParam, opts ...grpc1.CallOption) (*Receipt, error)
+	TransactAndHold(ctx context.Context, in *TransactParam, opts ...grpc1.CallOption) (*EventDataCall, error)
+	Send(ctx context.Context, in *SendParam, opts ...grpc1.CallOption) (*Receipt, error)
+	SendAndHold(ctx context.Context, in *SendParam, opts ...grpc1.CallOption) (*Receipt, error)
+	TransactNameReg(ctx context.Context, in *TransactNameRegParam, opts ...grpc1.CallOption) (*Receipt, error)
+}
+
+type transactorClient struct {
+	cc *grpc1.ClientConn
+}
+
+func NewTransactorClient(cc *grpc1.ClientConn) TransactorClient {
+	return &transactorClient{cc}
+}
+
+func (c *transactorClient) Call(ctx context.Context, in *CallParam, opts ...grpc1.CallOption) (*CallResult, error) {
+	out := new(CallResult)
+	err := grpc1.Invoke(ctx, "/burrow.Transactor/Call", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *transactorClient) CallCode(ctx context.Context, in *CallCodeParam, opts ...grpc1.CallOption) (*CallResult, error) {
+	out := new(CallResult)
+	err := grpc1.Invoke(ctx, "/burrow.Transactor/CallCode", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *transactorClient) BroadcastTx(ctx context.Context, in *TxParam, opts ...grpc1.CallOption) (*Receipt, error) {
+	out := new(Receipt)
+	err := grpc1.Invoke(ctx, "/burrow.Transactor/BroadcastTx", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *transactorClient) Transact(ctx context.Context, in *TransactParam, opts ...grpc1.CallOption) (*Receipt, error) {
+	out := new(Receipt)
+	err := grpc1.Invoke(ctx, "/burrow.Transactor/Transact", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *transactorClient) TransactAndHold(ctx context.Context, in *TransactParam, opts ...grpc1.CallOption) (*EventDataCall, error) {
+	out := new(EventDataCall)
+	err := grpc1.Invoke(ctx, "/burrow.Transactor/TransactAndHold", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *transactorClient) Send(ctx context.Context, in *SendParam, opts ...grpc1.CallOption) (*Receipt, error) {
+	out := new(Receipt)
+	err := grpc1.Invoke(ctx, "/burrow.Transactor/Send", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *transactorClient) SendAndHold(ctx context.Context, in *SendParam, opts ...grpc1.CallOption) (*Receipt, error) {
+	out := new(Receipt)
+	err := grpc1.Invoke(ctx, "/burrow.Transactor/SendAndHold", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *transactorClient) TransactNameReg(ctx context.Context, in *TransactNameRegParam, opts ...grpc1.CallOption) (*Receipt, error) {
+	out := new(Receipt)
+	err := grpc1.Invoke(ctx, "/burrow.Transactor/TransactNameReg", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Server API for Transactor service
+
+type TransactorServer interface {
+	Call(context.Context, *CallParam) (*CallResult, error)
+	CallCode(context.Context, *CallCodeParam) (*CallResult, error)
+	BroadcastTx(context.Context, *TxParam) (*Receipt, error)
+	Transact(context.Context, *TransactParam) (*Receipt, error)
+	TransactAndHold(context.Context, *TransactParam) (*EventDataCall, error)
+	Send(context.Context, *SendParam) (*Receipt, error)
+	SendAndHold(context.Context, *SendParam) (*Receipt, error)
+	TransactNameReg(context.Context, *TransactNameRegParam) (*Receipt, error)
+}
+
+func RegisterTransactorServer(s *grpc1.Server, srv TransactorServer) {
+	s.RegisterService(&_Transactor_serviceDesc, srv)
+}
+
+func _Transactor_Call_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc1.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CallParam)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TransactorServer).Call(ctx, in)
+	}
+	info := &grpc1.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/burrow.Transactor/Call",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TransactorServer).Call(ctx, req.(*CallParam))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Transactor_CallCode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc1.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CallCodeParam)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TransactorServer).CallCode(ctx, in)
+	}
+	info := &grpc1.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/burrow.Transactor/CallCode",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TransactorServer).CallCode(ctx, req.(*CallCodeParam))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Transactor_BroadcastTx_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc1.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TxParam)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TransactorServer).BroadcastTx(ctx, in)
+	}
+	info := &grpc1.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/burrow.Transactor/BroadcastTx",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TransactorServer).BroadcastTx(ctx, req.(*TxParam))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Transactor_Transact_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc1.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TransactParam)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TransactorServer).Transact(ctx, in)
+	}
+	info := &grpc1.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/burrow.Transactor/Transact",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TransactorServer).Transact(ctx, req.(*TransactParam))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Transactor_TransactAndHold_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc1.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TransactParam)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TransactorServer).TransactAndHold(ctx, in)
+	}
+	info := &grpc1.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/burrow.Transactor/TransactAndHold",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TransactorServer).TransactAndHold(ctx, req.(*TransactParam))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Transactor_Send_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc1.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SendParam)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TransactorServer).Send(ctx, in)
+	}
+	info := &grpc1.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/burrow.Transactor/Send",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TransactorServer).Send(ctx, req.(*SendParam))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Transactor_SendAndHold_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc1.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SendParam)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TransactorServer).SendAndHold(ctx, in)
+	}
+	info := &grpc1.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/burrow.Transactor/SendAndHold",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TransactorServer).SendAndHold(ctx, req.(*SendParam))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Transactor_TransactNameReg_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc1.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TransactNameRegParam)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TransactorServer).TransactNameReg(ctx, in)
+	}
+	info := &grpc1.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/burrow.Transactor/TransactNameReg",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TransactorServer).TransactNameReg(ctx, req.(*TransactNameRegParam))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _Transactor_serviceDesc = grpc1.ServiceDesc{
+	ServiceName: "burrow.Transactor",
+	HandlerType: (*TransactorServer)(nil),
+	Methods: []grpc1.MethodDesc{
+		{
+			MethodName: "Call",
+			Handler:    _Transactor_Call_Handler,
+		},
+		{
+			MethodName: "CallCode",
+			Handler:    _Transactor_CallCode_Handler,
+		},
+		{
+			MethodName: "BroadcastTx",
+			Handler:    _Transactor_BroadcastTx_Handler,
+		},
+		{
+			MethodName: "Transact",
+			Handler:    _Transactor_Transact_Handler,
+		},
+		{
+			MethodName: "TransactAndHold",
+			Handler:    _Transactor_TransactAndHold_Handler,
+		},
+		{
+			MethodName: "Send",
+			Handler:    _Transactor_Send_Handler,
+		},
+		{
+			MethodName: "SendAndHold",
+			Handler:    _Transactor_SendAndHold_Handler,
+		},
+		{
+			MethodName: "TransactNameReg",
+			Handler:    _Transactor_TransactNameReg_Handler,
+		},
+	},
+	Streams:  []grpc1.StreamDesc{},
+	Metadata: "burrow.proto",
+}
+
+// Client API for Events service
+
+type EventsClient interface {
+	Subscribe(ctx context.Context, in *EventIdParam, opts ...grpc1.CallOption) (Events_SubscribeClient, error)
+}
+
+type eventsClient struct {
+	cc *grpc1.ClientConn
+}
+
+func NewEventsClient(cc *grpc1.ClientConn) EventsClient {
+	return &eventsClient{cc}
+}
+
+func (c *eventsClient) Subscribe(ctx context.Context, in *EventIdParam, opts ...grpc1.CallOption) (Events_SubscribeClient, error) {
+	stream, err := grpc1.NewClientStream(ctx, &_Events_serviceDesc.Streams[0], c.cc, "/burrow.Events/Subscribe", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &eventsSubscribeClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Events_SubscribeClient interface {
+	Recv() (*Event, error)
+	grpc1.ClientStream
+}
+
+type eventsSubscribeClient struct {
+	grpc1.ClientStream
+}
+
+func (x *eventsSubscribeClient) Recv() (*Event, error) {
+	m := new(Event)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Server API for Events service
+
+type EventsServer interface {
+	Subscribe(*EventIdParam, Events_SubscribeServer) error
+}
+
+func RegisterEventsServer(s *grpc1.Server, srv EventsServer) {
+	s.RegisterService(&_Events_serviceDesc, srv)
+}
+
+func _Events_Subscribe_Handler(srv interface{}, stream grpc1.ServerStream) error {
+	m := new(EventIdParam)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(EventsServer).Subscribe(m, &eventsSubscribeServer{stream})
+}
+
+type Events_SubscribeServer interface {
+	Send(*Event) error
+	grpc1.ServerStream
+}
+
+type eventsSubscribeServer struct {
+	grpc1.ServerStream
+}
+
+func (x *eventsSubscribeServer) Send(m *Event) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+var _Events_serviceDesc = grpc1.ServiceDesc{
+	ServiceName: "burrow.Events",
+	HandlerType: (*EventsServer)(nil),
+	Methods:     []grpc1.MethodDesc{},
+	Streams: []grpc1.StreamDesc{
+		{
+			StreamName:    "Subscribe",
+			Handler:       _Events_Subscribe_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "burrow.proto",
+}
+
+func init() { proto.RegisterFile("burrow.proto", fileDescriptor0) }
+
+var fileDescriptor0 = []byte{
+	// 1582 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x58, 0xdb, 0x6e, 0xdb, 0x46,
+	0x1a, 0x06, 0x25, 0x59, 0x94, 0x7e, 0xca, 0xa7, 0xb1, 0xe3, 0xc8, 0xde, 0x04, 0x71, 0xb8, 0xd8,
+	0xc0, 0xc9, 0x6e, 0xbc, 0x59, 0x65, 0x03, 0xec, 0x66, 0xbd, 0xc8, 0xda, 0x49, 0x36, 0x36, 0x92,
+	0x16, 0x01, 0xed, 0x5e, 0xb4, 0x40, 0x41, 0x8c, 0xa8, 0xb1, 0x4c, 0x84, 0xe4, 0xb0, 0x9c, 0x91,
+	0x4d, 0x5f, 0xb4, 0x37, 0xbd, 0xe8, 0x73, 0xf4, 0x31, 0xfa, 0x02, 0xbd, 0xee, 0x13, 0xf4, 0xb6,
+	0x37, 0x7d, 0x88, 0x62, 0x4e, 0xe2, 0x50, 0x96, 0xe3, 0xf6, 0x8e, 0xff, 0xcc, 0x7f, 0x9e, 0xef,
+	0x3f, 0x48, 0xd0, 0x1b, 0x4e, 0x8a, 0x82, 0x5e, 0xec, 0xe6, 0x05, 0xe5, 0x14, 0xb5, 0x15, 0xe5,
+	0xbb, 0xb0, 0xf0, 0x3a, 0xcd, 0xf9, 0xa5, 0xbf, 0x03, 0xbd, 0xfd, 0xd1, 0xa8, 0x20, 0x8c, 0xbd,
+	0xc7, 0x05, 0x4e, 0x51, 0x1f, 0x5c, 0xac, 0xe8, 0xbe, 0xb3, 0xed, 0xec, 0xf4, 0x02, 0x43, 0xfa,
+	0x7b, 0xb0, 0x74, 0xcc, 0x69, 0x81, 0xc7, 0x64, 0x9f, 0xdf, 0xc0, 0x8b, 0x56, 0xa0, 0xf9, 0x81,
+	0x5c, 0xf6, 0x1b, 0xf2, 0x54, 0x7c, 0xfa, 0x87, 0x00, 0xff, 0x8f, 0x13, 0x4e, 0x8a, 0x57, 0x98,
+	0x63, 0xb4, 0x0e, 0x0b, 0xa7, 0x31, 0x49, 0x46, 0x52, 0xae, 0x1b, 0x28, 0x02, 0x2d, 0x41, 0x83,
+	0xe6, 0x52, 0xa8, 0x1b, 0x34, 0x68, 0x2e, 0xb8, 0xce, 0x71, 0x32, 0x21, 0xfd, 0xa6, 0xe2, 0x92,
+	0x84, 0xbf, 0x07, 0x3d, 0xa5, 0x49, 0x7b, 0xfc, 0x37, 0x70, 0x4f, 0x15, 0xdd, 0x77, 0xb6, 0x9b,
+	0x3b, 0xde, 0x00, 0xed, 0xea, 0x90, 0x2b, 0x83, 0x81, 0x61, 0xf1, 0x3f, 0x07, 0xb4, 0x1f, 0x45,
+	0x74, 0x92, 0xf1, 0xf7, 0xa4, 0x48, 0x63, 0xc6, 0x62, 0x9a, 0x31, 0x61, 0x29, 0x27, 0x45, 0xaa,
+	0xe2, 0x68, 0x05, 0x8a, 0x40, 0xb7, 0xc1, 0x65, 0x84, 0x87, 0xc3, 0x98, 0x4b, 0xa7, 0x5a, 0x41,
+	0x9b, 0x11, 0x7e, 0x10, 0x73, 0xc1, 0x5e, 0xd0, 0x84, 0xb0, 0x7e, 0x73, 0xbb, 0x29, 0x1c, 0x93,
+	0x84, 0xff, 0x8b, 0x03, 0xae, 0xd6, 0xfd, 0x91, 0xd4, 0xdc, 0x06, 0x37, 0x9f, 0x0c, 0xc3, 0x2a,
+	0x3d, 0xed, 0x7c, 0x32, 0x7c, 0x4b, 0x2e, 0xd1, 0x16, 0x74, 0x18, 0xf9, 0x6a, 0x42, 0xb2, 0x48,
+	0x05, 0xdc, 0x0c, 0xa6, 0xb4, 0x50, 0x37, 0xc4, 0x09, 0x16, 0x57, 0x2d, 0x79, 0x65, 0x48, 0x84,
+	0xa0, 0x15, 0xd1, 0x11, 0xe9, 0x2f, 0x48, 0x5d, 0xf2, 0x1b, 0xdd, 0x87, 0x1e, 0x53, 0x2f, 0x15,
+	0x16, 0x94, 0xf2, 0x7e, 0x5b, 0xde, 0x79, 0xfa, 0x2c, 0xa0, 0x94, 0xa3, 0x3d, 0xf0, 0xf2, 0x2a,
+	0xfe, 0xbe, 0xbb, 0xed, 0xec, 0x78, 0x83, 0x2d, 0x93, 0xb8, 0xab, 0x19, 0x0a, 0x6c, 0x76, 0xff,
+	0x39, 0x78, 0x9a, 0xe5, 0x5d, 0xcc, 0x38, 0xfa, 0x2b, 0x74, 0xb0, 0x22, 0xcd, 0x13, 0x2c, 0xcf,
+	0x68, 0x0a, 0xa6, 0x0c, 0xfe, 0x33, 0xf0, 0x34, 0x8c, 0x8e, 0x38, 0x49, 0x0d, 0x52, 0x9c, 0x29,
+	0x52, 0xaa, 0x57, 0x57, 0xe9, 0xd1, 0xaf, 0x7e, 0x0a, 0xae, 0x16, 0xbb, 0x12, 0x9e, 0x73, 0x35,
+	0xbc, 0x7f, 0xc1, 0xa2, 0x61, 0x89, 0x39, 0x49, 0x59, 0xbf, 0x21, 0xdd, 0x5a, 0x33, 0x6e, 0x59,
+	0x1e, 0x04, 0x3d, 0x56, 0x11, 0xcc, 0xff, 0x06, 0x96, 0x0e, 0x12, 0x1a, 0x7d, 0x88, 0xce, 0x70,
+	0x9c, 0x1d, 0x65, 0xa7, 0x14, 0x6d, 0x42, 0x47, 0x12, 0x61, 0x6c, 0xe0, 0xea, 0xaa, 0xcb, 0x91,
+	0xf0, 0x64, 0x4c, 0x32, 0xc2, 0x62, 0x16, 0x9e, 0x61, 0x76, 0xa6, 0x3d, 0xf6, 0xf4, 0xd9, 0x21,
+	0x66, 0x67, 0x68, 0x17, 0xd6, 0x12, 0xcc, 0x09, 0xe3, 0xe1, 0x50, 0xa8, 0x0d, 0xcf, 0x48, 0x3c,
+	0x3e, 0xe3, 0xfa, 0x81, 0x57, 0xd5, 0x95, 0x34, 0x78, 0x28, 0x2f, 0xfc, 0xbf, 0x80, 0xa7, 0xbe,
+	0x14, 0xb8, 0x37, 0xa0, 0xad, 0x25, 0x1c, 0x29, 0xa1, 0x29, 0xff, 0x2d, 0x78, 0x52, 0x4a, 0xd7,
+	0xc0, 0x5d, 0x80, 0x34, 0xce, 0xc2, 0x1a, 0x6b, 0x37, 0x8d, 0x33, 0xa5, 0x4a, 0x5e, 0xe3, 0xd2,
+	0x5c, 0x37, 0xf4, 0x35, 0x2e, 0xb5, 0xcd, 0x7f, 0xc2, 0xea, 0x31, 0x2f, 0x08, 0x4e, 0x6d, 0x95,
+	0xf7, 0xc0, 0x3b, 0x2d, 0x68, 0x5a, 0xd7, 0x09, 0xe2, 0x48, 0x4b, 0xfd, 0xe4, 0xc0, 0x82, 0x14,
+	0xb8, 0xce, 0x49, 0x81, 0x4d, 0x2b, 0x2d, 0xf2, 0xbb, 0x96, 0xcd, 0x66, 0x3d, 0x9b, 0x08, 0x5a,
+	0x3c, 0x4e, 0x0d, 0xc2, 0xe5, 0xb7, 0xa8, 0x96, 0x6c, 0x92, 0x86, 0xbc, 0x64, 0x12, 0xe1, 0xcd,
+	0xa0, 0x9d, 0x4d, 0xd2, 0x93, 0x92, 0xa1, 0x07, 0xb0, 0x9c, 0xe0, 0x2a, 0xab, 0xc2, 0x8c, 0x82,
+	0xf9, 0xa2, 0x38, 0x56, 0x19, 0xd5, 0xf6, 0x70, 0x9e, 0x2b, 0x06, 0x57, 0x57, 0x62, 0x9e, 0xcb,
+	0xab, 0x15, 0x68, 0x0a, 0xbd, 0x9d, 0xed, 0xa6, 0x80, 0x1e, 0x2f, 0x99, 0x7f, 0x0f, 0xba, 0x9f,
+	0xe2, 0x94, 0xa8, 0x04, 0x20, 0x68, 0x65, 0x38, 0x25, 0xfa, 0xcd, 0xe5, 0xb7, 0x7f, 0x0a, 0x3d,
+	0xc1, 0x10, 0x90, 0xf1, 0xeb, 0x8c, 0x17, 0x97, 0xf3, 0x78, 0x04, 0x7e, 0xe9, 0x45, 0x46, 0x0a,
+	0x83, 0x5f, 0x49, 0x08, 0xce, 0x11, 0xe6, 0x58, 0xc7, 0x2c, 0xbf, 0x45, 0x55, 0x93, 0x32, 0x8f,
+	0x0b, 0xc2, 0x4c, 0x55, 0x6b, 0xd2, 0xc7, 0xb0, 0x62, 0xdb, 0x91, 0x55, 0x76, 0x1f, 0x7a, 0x35,
+	0x08, 0xa9, 0x5c, 0x7b, 0xc3, 0x0a, 0x3c, 0xe8, 0x11, 0x2c, 0x08, 0x17, 0x0c, 0xdc, 0xd7, 0x0d,
+	0xdc, 0x6d, 0x5d, 0x81, 0x62, 0xf1, 0x3f, 0x81, 0xee, 0x4b, 0x9c, 0x24, 0xd3, 0x58, 0xc5, 0xcb,
+	0xea, 0x52, 0x92, 0xdf, 0x76, 0x0b, 0x6b, 0xd4, 0x5b, 0x98, 0x1d, 0x4b, 0x4f, 0xc5, 0xe2, 0xbf,
+	0x85, 0x45, 0xa1, 0xee, 0x25, 0x1d, 0x91, 0xeb, 0x55, 0x9a, 0x66, 0xd5, 0xb0, 0x9a, 0xd5, 0x3c,
+	0x65, 0x2f, 0x00, 0x84, 0xb2, 0x80, 0xb0, 0x49, 0xc2, 0x05, 0xbc, 0x0a, 0xc2, 0x27, 0x45, 0xa6,
+	0x75, 0x69, 0x4a, 0x3c, 0xed, 0x18, 0xb3, 0x70, 0xc2, 0xc8, 0x48, 0x63, 0xda, 0x1d, 0x63, 0xf6,
+	0x19, 0x23, 0x23, 0x7f, 0x13, 0xdc, 0x93, 0x52, 0xf9, 0xb1, 0x04, 0x0d, 0x5e, 0x6a, 0xc9, 0x06,
+	0x2f, 0xfd, 0x12, 0xdc, 0x80, 0x44, 0x24, 0xce, 0xb9, 0x00, 0x17, 0x2f, 0x15, 0x34, 0xb4, 0x66,
+	0x5e, 0x4a, 0x64, 0x3c, 0x84, 0x95, 0xa8, 0x20, 0xa2, 0x36, 0xc3, 0x88, 0x66, 0xbc, 0xc0, 0x91,
+	0xaa, 0x9a, 0x4e, 0xb0, 0xac, 0xcf, 0x5f, 0xea, 0x63, 0xc9, 0xaa, 0xbf, 0x43, 0x93, 0x2e, 0x15,
+	0xca, 0xb2, 0x39, 0xd7, 0xf3, 0xd5, 0xff, 0xce, 0x81, 0xc5, 0x93, 0x02, 0x67, 0x0c, 0x47, 0xba,
+	0xba, 0x37, 0xa1, 0x93, 0x17, 0xf1, 0x79, 0x58, 0x75, 0x40, 0x57, 0xd0, 0x62, 0x1a, 0xfc, 0xa1,
+	0xec, 0xa3, 0x3f, 0x41, 0x57, 0xa4, 0x22, 0x89, 0xd3, 0x98, 0x6b, 0x2c, 0x89, 0xdc, 0xbc, 0x13,
+	0xb4, 0xc0, 0xf9, 0x29, 0x21, 0xba, 0x7e, 0xc4, 0xa7, 0xff, 0x25, 0x74, 0x8f, 0x49, 0x36, 0xba,
+	0xd1, 0x89, 0xbb, 0x00, 0x9c, 0x86, 0x75, 0x3f, 0xba, 0x9c, 0xea, 0x80, 0xc4, 0xc3, 0xe0, 0x54,
+	0x74, 0x75, 0xdd, 0xce, 0x34, 0xe5, 0x7f, 0xeb, 0xc0, 0xba, 0x09, 0x54, 0x43, 0xef, 0x46, 0x53,
+	0xa6, 0x92, 0x1a, 0x56, 0x25, 0xcd, 0xab, 0x99, 0xca, 0x66, 0xcb, 0xb6, 0x39, 0x27, 0xc8, 0x73,
+	0xe8, 0x08, 0x10, 0xbd, 0xd2, 0x52, 0x11, 0x4e, 0x12, 0x52, 0x98, 0x87, 0x56, 0xd4, 0xf4, 0xdc,
+	0x40, 0x52, 0x53, 0x73, 0x73, 0x3c, 0x9d, 0x4b, 0xca, 0xb0, 0x22, 0x84, 0xdd, 0x31, 0x36, 0xcd,
+	0x49, 0x7c, 0xfa, 0xdf, 0x3b, 0xb0, 0xf8, 0xfa, 0x9c, 0x64, 0x5c, 0x58, 0x16, 0x1e, 0xa0, 0xc7,
+	0xd0, 0x15, 0x7a, 0x43, 0xa9, 0xd2, 0x91, 0xa3, 0x76, 0xc5, 0x94, 0xa6, 0x71, 0x31, 0xe8, 0x44,
+	0x96, 0xb3, 0xb4, 0x88, 0xc7, 0x71, 0x66, 0x9c, 0x52, 0x14, 0x5a, 0x83, 0x05, 0x5e, 0x9a, 0xbe,
+	0xd9, 0x0b, 0x5a, 0xbc, 0x3c, 0x1a, 0x59, 0xc5, 0xd1, 0xaa, 0x15, 0xc7, 0x1d, 0xe8, 0x92, 0x32,
+	0x22, 0x39, 0x8f, 0x69, 0x26, 0xbd, 0xeb, 0x06, 0xd5, 0x81, 0xff, 0x10, 0x7a, 0xd2, 0xc5, 0xa3,
+	0x0a, 0x03, 0x44, 0xd0, 0xd6, 0x8c, 0x23, 0xea, 0xde, 0x4f, 0x34, 0xab, 0x70, 0xed, 0x1d, 0x1d,
+	0x7f, 0x64, 0xb3, 0xd9, 0x80, 0x36, 0xa7, 0x79, 0x1c, 0xa9, 0xf6, 0xd3, 0x0b, 0x34, 0x35, 0x37,
+	0x99, 0xd5, 0xc8, 0x68, 0xd5, 0xe6, 0xda, 0x31, 0x78, 0x53, 0x6b, 0x27, 0xe5, 0x6c, 0xf1, 0x5a,
+	0xd1, 0x36, 0xae, 0x8f, 0xb6, 0x39, 0x1b, 0xed, 0x8f, 0x0e, 0x2c, 0x48, 0xad, 0x1f, 0x89, 0x13,
+	0x3d, 0x84, 0x96, 0x78, 0x01, 0xa9, 0xd8, 0x1b, 0xdc, 0x32, 0xef, 0x53, 0x7b, 0xc9, 0x40, 0xb2,
+	0xa0, 0x07, 0xd0, 0x4c, 0xe8, 0x58, 0xda, 0xb1, 0x9a, 0xac, 0x9d, 0xa5, 0x40, 0x30, 0xa0, 0x3f,
+	0x4b, 0xef, 0x5b, 0xdb, 0x8e, 0xbd, 0x7a, 0x58, 0xe1, 0xc9, 0x90, 0x1e, 0x41, 0x37, 0x23, 0x17,
+	0x6a, 0x8e, 0xc9, 0x87, 0xf2, 0x06, 0x8b, 0x86, 0x57, 0x8e, 0xb1, 0xa0, 0x93, 0x91, 0x0b, 0xf9,
+	0x35, 0xf8, 0xd5, 0x81, 0x8e, 0xde, 0xa8, 0x18, 0x7a, 0x0a, 0xf0, 0x86, 0x70, 0x4d, 0xa2, 0xa9,
+	0x1b, 0xf6, 0x36, 0xbf, 0x35, 0xbb, 0x87, 0xa1, 0x7f, 0x43, 0x4f, 0x0c, 0x93, 0xa9, 0x92, 0xf5,
+	0xfa, 0xae, 0xac, 0xc5, 0xd6, 0x66, 0xc4, 0x84, 0x88, 0xb6, 0x67, 0x96, 0xb0, 0x1b, 0xec, 0x19,
+	0xb6, 0xff, 0x40, 0xaf, 0x12, 0xda, 0xe7, 0x68, 0x63, 0x86, 0x61, 0x9f, 0xcf, 0x58, 0xb4, 0x36,
+	0xb3, 0xc1, 0xcf, 0x0e, 0x40, 0xb5, 0x8c, 0xa1, 0x27, 0xe0, 0xbe, 0x21, 0x5c, 0xee, 0x64, 0xd3,
+	0x0c, 0xc9, 0x1f, 0x31, 0x5b, 0x1b, 0xb5, 0x84, 0x55, 0xab, 0xdb, 0x2e, 0x74, 0xde, 0x10, 0xb5,
+	0x0c, 0xa0, 0xa9, 0x05, 0x6b, 0xbd, 0xda, 0xaa, 0x67, 0x1a, 0x0d, 0x00, 0x44, 0xa8, 0x92, 0x60,
+	0x95, 0x84, 0xb5, 0x16, 0xcd, 0x48, 0x3c, 0x71, 0xd0, 0x73, 0xe8, 0xd9, 0xcb, 0x13, 0xda, 0xac,
+	0x22, 0x99, 0x59, 0xa9, 0xae, 0xc8, 0x0e, 0xbe, 0x06, 0x57, 0xf7, 0x47, 0xf4, 0x54, 0xba, 0xaa,
+	0xb6, 0x8a, 0x55, 0x7b, 0x6e, 0x2b, 0xd1, 0xb9, 0xa3, 0x1c, 0xfd, 0x17, 0x3c, 0xe1, 0xaf, 0x20,
+	0x62, 0x72, 0xdd, 0x63, 0xf6, 0xe7, 0x89, 0x0a, 0xb1, 0xc1, 0x0f, 0x4d, 0x00, 0xd3, 0xa7, 0x69,
+	0x81, 0x1e, 0x43, 0x4b, 0xb6, 0xab, 0x55, 0xbb, 0x37, 0x29, 0x1d, 0xc8, 0x3e, 0xd2, 0x63, 0xf9,
+	0x19, 0x74, 0xcc, 0xc4, 0x47, 0xb7, 0xec, 0xfb, 0xe9, 0x0e, 0x30, 0x57, 0xec, 0xef, 0xe0, 0x1d,
+	0x14, 0x14, 0x8f, 0x22, 0xcc, 0xf8, 0x49, 0x89, 0xa6, 0x88, 0x39, 0x29, 0x67, 0x20, 0x64, 0xa6,
+	0xf4, 0x00, 0x3a, 0xc6, 0xc9, 0xca, 0x4e, 0x6d, 0x8e, 0x5e, 0x95, 0x79, 0x01, 0xcb, 0x86, 0x63,
+	0x3f, 0x1b, 0x1d, 0xd2, 0x64, 0x74, 0x9d, 0xe8, 0xfc, 0x42, 0x47, 0x8f, 0xa0, 0x25, 0x26, 0x64,
+	0x95, 0x8b, 0xe9, 0xbc, 0xbc, 0x6a, 0xec, 0x1f, 0xe0, 0x89, 0x5b, 0x63, 0xe8, 0xf7, 0x88, 0xfc,
+	0xaf, 0xf2, 0xcf, 0x00, 0xe0, 0xce, 0xac, 0x7f, 0xf6, 0xe4, 0xbc, 0xa2, 0x61, 0xb0, 0x07, 0x6d,
+	0xe9, 0x31, 0x43, 0x03, 0xe8, 0x1e, 0x4f, 0x86, 0x2c, 0x2a, 0xe2, 0xa1, 0x55, 0x96, 0x76, 0x7b,
+	0xdf, 0x5a, 0xac, 0x9d, 0x3e, 0x71, 0x0e, 0xda, 0x5f, 0xb4, 0xc6, 0x45, 0x1e, 0x0d, 0xdb, 0xf2,
+	0x5f, 0x81, 0xa7, 0xbf, 0x0d, 0x00, 0x9b, 0xee, 0x8c, 0x07, 0x25, 0x10, 0x00, 0x00,
+}
@@ -0,0 +1,208 @@
+// Copyright 2017 Monax Industries Limited
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpc
+
+// Conversions between burrow's domain types and the protobuf messages of the
+// gRPC gateway
+
+import (
+	"encoding/hex"
+
+	acm "github.com/hyperledger/burrow/account"
+	core_types "github.com/hyperledger/burrow/core/types"
+	"github.com/hyperledger/burrow/event"
+	"github.com/hyperledger/burrow/txs"
+
+	tm_types "github.com/tendermint/tendermint/types"
+)
+
+func fromFilters(filtersParam *FiltersParam) []*event.FilterData {
+	filters := make([]*event.FilterData, len(filtersParam.GetFilters()))
+	for i, filter := range filtersParam.GetFilters() {
+		filters[i] = &event.FilterData{
+			Field: filter.Field,
+			Op:    filter.Op,
+			Value: filter.Value,
+		}
+	}
+	return filters
+}
+
+func toAccount(acc *acm.Account) *Account {
+	if acc == nil {
+		return nil
+	}
+	var pubKey []byte
+	if acc.PubKey != nil {
+		pubKey = acc.PubKey.Bytes()
+	}
+	roles := make([]string, len(acc.Permissions.Roles))
+	copy(roles, acc.Permissions.Roles)
+	return &Account{
+		Address:     acc.Address,
+		PubKey:      pubKey,
+		Sequence:    int64(acc.Sequence),
+		Balance:     acc.Balance,
+		Code:        acc.Code,
+		StorageRoot: acc.StorageRoot,
+		Permissions: &AccountPermissions{
+			Perms:  uint64(acc.Permissions.Base.Perms),
+			SetBit: uint64(acc.Permissions.Base.SetBit),
+			Roles:  roles,
+		},
+	}
+}
+
+func toAccountList(accountList *core_types.AccountList) *AccountList {
+	accounts := make([]*Account, len(accountList.Accounts))
+	for i, acc := range accountList.Accounts {
+		accounts[i] = toAccount(acc)
+	}
+	return &AccountList{Accounts: accounts}
+}
+
+func toStorageItem(storageItem *core_types.StorageItem) *StorageItem {
+	return &StorageItem{
+		Key:   storageItem.Key,
+		Value: storageItem.Value,
+	}
+}
+
+func toStorage(storage *core_types.Storage) *Storage {
+	storageItems := make([]*StorageItem, len(storage.StorageItems))
+	for i := range storage.StorageItems {
+		storageItems[i] = toStorageItem(&storage.StorageItems[i])
+	}
+	return &Storage{
+		StorageRoot:  storage.StorageRoot,
+		StorageItems: storageItems,
+	}
+}
+
+func toBlock(block *tm_types.Block) *Block {
+	if block == nil {
+		return nil
+	}
+	header := block.Header
+	txBytes := make([][]byte, len(block.Data.Txs))
+	for i, tx := range block.Data.Txs {
+		txBytes[i] = tx
+	}
+	return &Block{
+		Height:        int64(header.Height),
+		Hash:          block.Hash(),
+		ChainId:       header.ChainID,
+		Time:          header.Time.UnixNano(),
+		NumTxs:        int64(header.NumTxs),
+		LastBlockHash: header.LastBlockID.Hash,
+		AppHash:       header.AppHash,
+		Txs:           txBytes,
+	}
+}
+
+func toNameRegEntry(entry *core_types.NameRegEntry) *NameRegEntry {
+	return &NameRegEntry{
+		Name:    entry.Name,
+		Owner:   entry.Owner,
+		Data:    entry.Data,
+		Expires: int64(entry.Expires),
+	}
+}
+
+func toNameRegEntryList(names *core_types.ResultListNames) *NameRegEntryList {
+	entries := make([]*NameRegEntry, len(names.Names))
+	for i, entry := range names.Names {
+		entries[i] = toNameRegEntry(entry)
+	}
+	return &NameRegEntryList{
+		BlockHeight: int64(names.BlockHeight),
+		Names:       entries,
+	}
+}
+
+func toCallResult(call *core_types.Call) (*CallResult, error) {
+	ret, err := hex.DecodeString(call.Return)
+	if err != nil {
+		return nil, err
+	}
+	return &CallResult{
+		Return:  ret,
+		GasUsed: call.GasUsed,
+	}, nil
+}
+
+func toReceipt(receipt *txs.Receipt) *Receipt {
+	return &Receipt{
+		TxHash:          receipt.TxHash,
+		CreatesContract: receipt.CreatesContract != 0,
+		ContractAddress: receipt.ContractAddr,
+	}
+}
+
+func toEventDataCall(eventDataCall *txs.EventDataCall) *EventDataCall {
+	var callData *CallData
+	if eventDataCall.CallData != nil {
+		callData = &CallData{
+			Caller: eventDataCall.CallData.Caller,
+			Callee: eventDataCall.CallData.Callee,
+			Data:   eventDataCall.CallData.Data,
+			Value:  eventDataCall.CallData.Value,
+			Gas:    eventDataCall.CallData.Gas,
+		}
+	}
+	return &EventDataCall{
+		CallData:  callData,
+		Origin:    eventDataCall.Origin,
+		TxId:      eventDataCall.TxID,
+		Return:    eventDataCall.Return,
+		Exception: eventDataCall.Exception,
+	}
+}
+
+func toEventDataLog(eventDataLog *txs.EventDataLog) *EventDataLog {
+	topics := make([][]byte, len(eventDataLog.Topics))
+	for i, topic := range eventDataLog.Topics {
+		topics[i] = topic.Bytes()
+	}
+	return &EventDataLog{
+		Address: eventDataLog.Address.Bytes(),
+		Topics:  topics,
+		Data:    eventDataLog.Data,
+		Height:  eventDataLog.Height,
+	}
+}
+
+func toEvent(eventId string, eventData txs.EventData) (*Event, error) {
+	ev := &Event{EventId: eventId}
+	switch eventData := eventData.(type) {
+	case txs.EventDataCall:
+		ev.Call = toEventDataCall(&eventData)
+	case txs.EventDataLog:
+		ev.Log = toEventDataLog(&eventData)
+	case txs.EventDataTx:
+		txBytes, err := txs.EncodeTx(eventData.Tx)
+		if err != nil {
+			return nil, err
+		}
+		ev.Tx = &EventDataTx{
+			Tx:        txBytes,
+			Return:    eventData.Return,
+			Exception: eventData.Exception,
+		}
+	case txs.EventDataNewBlock:
+		ev.NewBlock = toBlock(eventData.Block)
+	}
+	return ev, nil
+}
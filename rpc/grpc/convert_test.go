@@ -0,0 +1,99 @@
+// Copyright 2017 Monax Industries Limited
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpc
+
+import (
+	"testing"
+
+	acm "github.com/hyperledger/burrow/account"
+	core_types "github.com/hyperledger/burrow/core/types"
+	ptypes "github.com/hyperledger/burrow/permission/types"
+	"github.com/hyperledger/burrow/txs"
+	"github.com/hyperledger/burrow/word256"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToAccount(t *testing.T) {
+	privAccount := acm.GenPrivAccount()
+	acc := &acm.Account{
+		Address:     privAccount.Address,
+		PubKey:      privAccount.PubKey,
+		Sequence:    3,
+		Balance:     1000,
+		Code:        []byte{0x60, 0x01},
+		Permissions: ptypes.DefaultAccountPermissions,
+	}
+	acc.Permissions.Roles = []string{"bar"}
+
+	grpcAccount := toAccount(acc)
+	assert.Equal(t, acc.Address, grpcAccount.Address)
+	assert.Equal(t, acc.PubKey.Bytes(), grpcAccount.PubKey)
+	assert.Equal(t, int64(3), grpcAccount.Sequence)
+	assert.Equal(t, int64(1000), grpcAccount.Balance)
+	assert.Equal(t, uint64(ptypes.DefaultPermFlags), grpcAccount.Permissions.Perms)
+	assert.Equal(t, []string{"bar"}, grpcAccount.Permissions.Roles)
+
+	// Check we survive a round trip over the wire
+	bs, err := proto.Marshal(grpcAccount)
+	assert.NoError(t, err)
+	decoded := new(Account)
+	assert.NoError(t, proto.Unmarshal(bs, decoded))
+	assert.Equal(t, grpcAccount, decoded)
+}
+
+func TestToCallResult(t *testing.T) {
+	callResult, err := toCallResult(&core_types.Call{Return: "00ff", GasUsed: 21})
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0x00, 0xff}, callResult.Return)
+	assert.Equal(t, int64(21), callResult.GasUsed)
+
+	_, err = toCallResult(&core_types.Call{Return: "not hex"})
+	assert.Error(t, err)
+}
+
+func TestToEvent(t *testing.T) {
+	address := word256.LeftPadWord256([]byte{1, 2, 3})
+	topic := word256.RightPadWord256([]byte("topic"))
+	ev, err := toEvent(txs.EventStringLogEvent(address.Postfix(20)),
+		txs.EventDataLog{
+			Address: address,
+			Topics:  []word256.Word256{topic},
+			Data:    []byte{4, 5},
+			Height:  7,
+		})
+	assert.NoError(t, err)
+	assert.Nil(t, ev.Call)
+	assert.Nil(t, ev.Tx)
+	if assert.NotNil(t, ev.Log) {
+		assert.Equal(t, address.Bytes(), ev.Log.Address)
+		assert.Equal(t, [][]byte{topic.Bytes()}, ev.Log.Topics)
+		assert.Equal(t, []byte{4, 5}, ev.Log.Data)
+		assert.Equal(t, int64(7), ev.Log.Height)
+	}
+
+	privAccount := acm.GenPrivAccount()
+	tx := txs.NewCallTxWithNonce(privAccount.PubKey, []byte{1}, []byte{2}, 1, 2, 3, 4)
+	ev, err = toEvent(txs.EventStringAccInput(privAccount.Address),
+		txs.EventDataTx{Tx: tx, Return: []byte{9}})
+	assert.NoError(t, err)
+	if assert.NotNil(t, ev.Tx) {
+		decodedTx, err := txs.DecodeTx(ev.Tx.Tx)
+		assert.NoError(t, err)
+		assert.Equal(t, tx, decodedTx)
+		assert.Equal(t, []byte{9}, ev.Tx.Return)
+	}
+}
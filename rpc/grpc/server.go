@@ -0,0 +1,81 @@
+// Copyright 2017 Monax Industries Limited
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpc
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/hyperledger/burrow/definitions"
+	"github.com/hyperledger/burrow/logging"
+	logging_types "github.com/hyperledger/burrow/logging/types"
+	"github.com/hyperledger/burrow/server"
+
+	"google.golang.org/grpc"
+)
+
+// GrpcServer serves the Accounts, Blockchain, NameReg, Transactor and Events
+// services defined in burrow.proto from a definitions.Pipe
+type GrpcServer struct {
+	server   *grpc.Server
+	listener net.Listener
+	logger   logging_types.InfoTraceLogger
+}
+
+// Create a new gRPC gateway listening on [servers.grpc] listen_address and
+// start serving in the background
+func NewGrpcServer(config *server.ServerConfig, pipe definitions.Pipe,
+	logger logging_types.InfoTraceLogger) (*GrpcServer, error) {
+
+	if pipe == nil {
+		return nil, fmt.Errorf("No pipe provided to gRPC gateway.")
+	}
+	listenAddress := config.Grpc.ListenAddress
+	if listenAddress == "" {
+		return nil, fmt.Errorf("No gRPC listening address provided in " +
+			"[servers.grpc.listen_address] in configuration file")
+	}
+	listener, err := net.Listen("tcp", listenAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	grpcServer := grpc.NewServer()
+	RegisterAccountsServer(grpcServer, &accountsServer{pipe: pipe})
+	RegisterBlockchainServer(grpcServer, &blockchainServer{pipe: pipe})
+	RegisterNameRegServer(grpcServer, &nameRegServer{pipe: pipe})
+	RegisterTransactorServer(grpcServer, &transactorServer{pipe: pipe})
+	RegisterEventsServer(grpcServer, &eventsServer{pipe: pipe})
+
+	logger = logging.WithScope(logger, "GrpcServer")
+	go func() {
+		err := grpcServer.Serve(listener)
+		if err != nil {
+			logging.InfoMsg(logger, "gRPC server stopped", "error", err)
+		}
+	}()
+	logging.InfoMsg(logger, "gRPC server started.", "address", listenAddress)
+
+	return &GrpcServer{
+		server:   grpcServer,
+		listener: listener,
+		logger:   logger,
+	}, nil
+}
+
+// Close the listener and all open connections, cancelling any streams
+func (grpcServer *GrpcServer) Shutdown() {
+	grpcServer.server.Stop()
+}
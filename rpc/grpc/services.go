@@ -0,0 +1,397 @@
+// Copyright 2017 Monax Industries Limited
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpc
+
+import (
+	"github.com/hyperledger/burrow/definitions"
+	"github.com/hyperledger/burrow/event"
+	"github.com/hyperledger/burrow/txs"
+
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+// Number of events or blocks we buffer for a stream before giving up on a
+// client that is not keeping up
+const streamBufferSize = 1000
+
+//------------------------------------------------------------------------------
+// Accounts
+
+type accountsServer struct {
+	pipe definitions.Pipe
+}
+
+var _ AccountsServer = (*accountsServer)(nil)
+
+func (as *accountsServer) GetAccount(ctx context.Context,
+	param *AddressParam) (*Account, error) {
+	acc, err := as.pipe.Accounts().Account(param.Address)
+	if err != nil {
+		return nil, grpc.Errorf(codes.Internal, "%v", err)
+	}
+	if acc == nil {
+		return nil, grpc.Errorf(codes.NotFound, "Account %X does not exist",
+			param.Address)
+	}
+	return toAccount(acc), nil
+}
+
+func (as *accountsServer) ListAccounts(ctx context.Context,
+	param *FiltersParam) (*AccountList, error) {
+	accountList, err := as.pipe.Accounts().Accounts(fromFilters(param))
+	if err != nil {
+		return nil, grpc.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	return toAccountList(accountList), nil
+}
+
+func (as *accountsServer) GetStorage(ctx context.Context,
+	param *AddressParam) (*Storage, error) {
+	storage, err := as.pipe.Accounts().Storage(param.Address)
+	if err != nil {
+		return nil, grpc.Errorf(codes.Internal, "%v", err)
+	}
+	return toStorage(storage), nil
+}
+
+func (as *accountsServer) GetStorageAt(ctx context.Context,
+	param *StorageAtParam) (*StorageItem, error) {
+	storageItem, err := as.pipe.Accounts().StorageAt(param.Address, param.Key)
+	if err != nil {
+		return nil, grpc.Errorf(codes.Internal, "%v", err)
+	}
+	return toStorageItem(storageItem), nil
+}
+
+//------------------------------------------------------------------------------
+// Blockchain
+
+type blockchainServer struct {
+	pipe definitions.Pipe
+}
+
+var _ BlockchainServer = (*blockchainServer)(nil)
+
+func (bs *blockchainServer) GetInfo(ctx context.Context,
+	param *Empty) (*BlockchainInfo, error) {
+	blockchain := bs.pipe.Blockchain()
+	return &BlockchainInfo{
+		ChainId:           blockchain.ChainId(),
+		GenesisHash:       bs.pipe.GenesisHash(),
+		LatestBlockHeight: int64(blockchain.Height()),
+	}, nil
+}
+
+func (bs *blockchainServer) GetBlock(ctx context.Context,
+	param *HeightParam) (*Block, error) {
+	block := bs.pipe.Blockchain().Block(int(param.Height))
+	if block == nil {
+		return nil, grpc.Errorf(codes.NotFound, "Block at height %v not found",
+			param.Height)
+	}
+	return toBlock(block), nil
+}
+
+func (bs *blockchainServer) ListBlocks(param *BlocksParam,
+	stream Blockchain_ListBlocksServer) error {
+	blockchain := bs.pipe.Blockchain()
+	minHeight, maxHeight := int(param.MinHeight), int(param.MaxHeight)
+	if minHeight < 1 {
+		minHeight = 1
+	}
+	if latestHeight := blockchain.Height(); maxHeight == 0 || maxHeight > latestHeight {
+		maxHeight = latestHeight
+	}
+	if minHeight > maxHeight {
+		return grpc.Errorf(codes.InvalidArgument,
+			"Minimum height %v is greater than maximum height %v", minHeight, maxHeight)
+	}
+	for height := minHeight; height <= maxHeight; height++ {
+		block := blockchain.Block(height)
+		if block == nil {
+			return grpc.Errorf(codes.NotFound, "Block at height %v not found", height)
+		}
+		err := stream.Send(toBlock(block))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (bs *blockchainServer) StreamBlocks(param *StreamBlocksParam,
+	stream Blockchain_StreamBlocksServer) error {
+	blockchain := bs.pipe.Blockchain()
+	subId, err := event.GenerateSubId()
+	if err != nil {
+		return grpc.Errorf(codes.Internal, "%v", err)
+	}
+	// We only use the NewBlock event as a signal to read from the block store so
+	// that we neither skip nor repeat blocks between backfilling and following
+	heights := make(chan int, streamBufferSize)
+	overflow := make(chan struct{}, 1)
+	emitter := eventEmitter(bs.pipe)
+	err = emitter.Subscribe(subId, txs.EventStringNewBlock(),
+		func(eventData txs.EventData) {
+			newBlock, ok := eventData.(txs.EventDataNewBlock)
+			if !ok || newBlock.Block == nil {
+				return
+			}
+			// NOTE: EventSwitch callbacks must be nonblocking
+			select {
+			case heights <- newBlock.Block.Height:
+			default:
+				select {
+				case overflow <- struct{}{}:
+				default:
+				}
+			}
+		})
+	if err != nil {
+		return grpc.Errorf(codes.Internal, "%v", err)
+	}
+	defer emitter.Unsubscribe(subId)
+
+	next := int(param.FromHeight)
+	if next == 0 {
+		next = blockchain.Height() + 1
+	}
+	sendUpTo := func(height int) error {
+		for ; next <= height; next++ {
+			block := blockchain.Block(next)
+			if block == nil {
+				// Not in the block store yet, we will pick it up on the next block
+				return nil
+			}
+			err := stream.Send(toBlock(block))
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	err = sendUpTo(blockchain.Height())
+	if err != nil {
+		return err
+	}
+	for {
+		select {
+		case height := <-heights:
+			err = sendUpTo(height)
+			if err != nil {
+				return err
+			}
+		case <-overflow:
+			return grpc.Errorf(codes.ResourceExhausted,
+				"Block stream client is not keeping up, resubscribe from height %v",
+				next)
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+		}
+	}
+}
+
+//------------------------------------------------------------------------------
+// NameReg
+
+type nameRegServer struct {
+	pipe definitions.Pipe
+}
+
+var _ NameRegServer = (*nameRegServer)(nil)
+
+func (ns *nameRegServer) GetEntry(ctx context.Context,
+	param *NameParam) (*NameRegEntry, error) {
+	entry, err := ns.pipe.NameReg().Entry(param.Name)
+	if err != nil {
+		return nil, grpc.Errorf(codes.NotFound, "%v", err)
+	}
+	return toNameRegEntry(entry), nil
+}
+
+func (ns *nameRegServer) ListEntries(ctx context.Context,
+	param *FiltersParam) (*NameRegEntryList, error) {
+	names, err := ns.pipe.NameReg().Entries(fromFilters(param))
+	if err != nil {
+		return nil, grpc.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	return toNameRegEntryList(names), nil
+}
+
+//------------------------------------------------------------------------------
+// Transactor
+
+type transactorServer struct {
+	pipe definitions.Pipe
+}
+
+var _ TransactorServer = (*transactorServer)(nil)
+
+func (ts *transactorServer) Call(ctx context.Context,
+	param *CallParam) (*CallResult, error) {
+	call, err := ts.pipe.Transactor().Call(param.From, param.Address, param.Data)
+	if err != nil {
+		return nil, grpc.Errorf(codes.Internal, "%v", err)
+	}
+	return toCallResult(call)
+}
+
+func (ts *transactorServer) CallCode(ctx context.Context,
+	param *CallCodeParam) (*CallResult, error) {
+	call, err := ts.pipe.Transactor().CallCode(param.From, param.Code, param.Data)
+	if err != nil {
+		return nil, grpc.Errorf(codes.Internal, "%v", err)
+	}
+	return toCallResult(call)
+}
+
+func (ts *transactorServer) BroadcastTx(ctx context.Context,
+	param *TxParam) (*Receipt, error) {
+	tx, err := txs.DecodeTx(param.Tx)
+	if err != nil {
+		return nil, grpc.Errorf(codes.InvalidArgument, "Could not decode tx: %v", err)
+	}
+	receipt, err := ts.pipe.Transactor().BroadcastTx(tx)
+	if err != nil {
+		return nil, grpc.Errorf(codes.Internal, "%v", err)
+	}
+	return toReceipt(receipt), nil
+}
+
+func (ts *transactorServer) Transact(ctx context.Context,
+	param *TransactParam) (*Receipt, error) {
+	receipt, err := ts.pipe.Transactor().Transact(param.PrivKey, param.Address,
+		param.Data, param.GasLimit, param.Fee)
+	if err != nil {
+		return nil, grpc.Errorf(codes.Internal, "%v", err)
+	}
+	return toReceipt(receipt), nil
+}
+
+func (ts *transactorServer) TransactAndHold(ctx context.Context,
+	param *TransactParam) (*EventDataCall, error) {
+	eventDataCall, err := ts.pipe.Transactor().TransactAndHold(param.PrivKey,
+		param.Address, param.Data, param.GasLimit, param.Fee)
+	if err != nil {
+		return nil, grpc.Errorf(codes.Internal, "%v", err)
+	}
+	return toEventDataCall(eventDataCall), nil
+}
+
+func (ts *transactorServer) Send(ctx context.Context,
+	param *SendParam) (*Receipt, error) {
+	receipt, err := ts.pipe.Transactor().Send(param.PrivKey, param.ToAddress,
+		param.Amount)
+	if err != nil {
+		return nil, grpc.Errorf(codes.Internal, "%v", err)
+	}
+	return toReceipt(receipt), nil
+}
+
+func (ts *transactorServer) SendAndHold(ctx context.Context,
+	param *SendParam) (*Receipt, error) {
+	receipt, err := ts.pipe.Transactor().SendAndHold(param.PrivKey,
+		param.ToAddress, param.Amount)
+	if err != nil {
+		return nil, grpc.Errorf(codes.Internal, "%v", err)
+	}
+	return toReceipt(receipt), nil
+}
+
+func (ts *transactorServer) TransactNameReg(ctx context.Context,
+	param *TransactNameRegParam) (*Receipt, error) {
+	receipt, err := ts.pipe.Transactor().TransactNameReg(param.PrivKey,
+		param.Name, param.Data, param.Amount, param.Fee)
+	if err != nil {
+		return nil, grpc.Errorf(codes.Internal, "%v", err)
+	}
+	return toReceipt(receipt), nil
+}
+
+//------------------------------------------------------------------------------
+// Events
+
+type eventsServer struct {
+	pipe definitions.Pipe
+}
+
+var _ EventsServer = (*eventsServer)(nil)
+
+func (es *eventsServer) Subscribe(param *EventIdParam,
+	stream Events_SubscribeServer) error {
+	subId, err := event.GenerateSubId()
+	if err != nil {
+		return grpc.Errorf(codes.Internal, "%v", err)
+	}
+	events := make(chan *Event, streamBufferSize)
+	overflow := make(chan struct{}, 1)
+	// so the client knows it has missed an event we could not convert
+	conversionErrs := make(chan error, 1)
+	emitter := eventEmitter(es.pipe)
+	err = emitter.Subscribe(subId, param.EventId,
+		func(eventData txs.EventData) {
+			ev, err := toEvent(param.EventId, eventData)
+			if err != nil {
+				select {
+				case conversionErrs <- err:
+				default:
+				}
+				return
+			}
+			// NOTE: EventSwitch callbacks must be nonblocking
+			select {
+			case events <- ev:
+			default:
+				select {
+				case overflow <- struct{}{}:
+				default:
+				}
+			}
+		})
+	if err != nil {
+		return grpc.Errorf(codes.Internal, "%v", err)
+	}
+	defer emitter.Unsubscribe(subId)
+
+	for {
+		select {
+		case ev := <-events:
+			err = stream.Send(ev)
+			if err != nil {
+				return err
+			}
+		case <-overflow:
+			return grpc.Errorf(codes.ResourceExhausted,
+				"Event stream client is not keeping up with %s", param.EventId)
+		case err := <-conversionErrs:
+			return grpc.Errorf(codes.Internal, "Could not convert %s event: %v",
+				param.EventId, err)
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+		}
+	}
+}
+
+// Consensus events such as NewBlock are emitted by the consensus engine rather
+// than the application, so listen to both where we can
+func eventEmitter(pipe definitions.Pipe) event.EventEmitter {
+	if consensusEngine := pipe.GetConsensusEngine(); consensusEngine != nil {
+		return event.Multiplex(pipe.Events(), consensusEngine.Events())
+	}
+	return pipe.Events()
+}
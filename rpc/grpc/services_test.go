@@ -0,0 +1,360 @@
+// Copyright 2017 Monax Industries Limited
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpc
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	blockchain_types "github.com/hyperledger/burrow/blockchain/types"
+	consensus_types "github.com/hyperledger/burrow/consensus/types"
+	"github.com/hyperledger/burrow/definitions"
+	"github.com/hyperledger/burrow/event"
+	"github.com/hyperledger/burrow/txs"
+
+	"github.com/stretchr/testify/assert"
+	tm_types "github.com/tendermint/tendermint/types"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+const streamTimeout = 5 * time.Second
+
+func TestStreamBlocks(t *testing.T) {
+	pipe := newPipeFake(2)
+	ctx, cancel := context.WithCancel(context.Background())
+	stream := &blockStreamFake{ctx: ctx, blocks: make(chan *Block)}
+	done := make(chan error)
+	go func() {
+		done <- (&blockchainServer{pipe: pipe}).StreamBlocks(
+			&StreamBlocksParam{FromHeight: 1}, stream)
+	}()
+
+	// Blocks already stored are sent first
+	assert.Equal(t, int64(1), receiveBlock(t, stream).Height)
+	assert.Equal(t, int64(2), receiveBlock(t, stream).Height)
+
+	// then those signalled by NewBlock, once they are in the block store
+	pipe.events.waitForSubscription(t, txs.EventStringNewBlock())
+	pipe.events.fire(txs.EventStringNewBlock(), newBlockEvent(3))
+	pipe.addBlock()
+	pipe.events.fire(txs.EventStringNewBlock(), newBlockEvent(3))
+	assert.Equal(t, int64(3), receiveBlock(t, stream).Height)
+
+	// A NewBlock for a height we have sent does not repeat it, and one that
+	// skips a height still sends the skipped block
+	pipe.events.fire(txs.EventStringNewBlock(), newBlockEvent(3))
+	pipe.addBlock()
+	pipe.addBlock()
+	pipe.events.fire(txs.EventStringNewBlock(), newBlockEvent(5))
+	assert.Equal(t, int64(4), receiveBlock(t, stream).Height)
+	assert.Equal(t, int64(5), receiveBlock(t, stream).Height)
+
+	cancel()
+	select {
+	case err := <-done:
+		assert.Equal(t, context.Canceled, err)
+	case <-time.After(streamTimeout):
+		t.Fatal("Timed out waiting for StreamBlocks to return after cancelling")
+	}
+	assert.Equal(t, 0, pipe.events.subscriptions())
+}
+
+func TestStreamBlocksFromLatest(t *testing.T) {
+	pipe := newPipeFake(2)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream := &blockStreamFake{ctx: ctx, blocks: make(chan *Block)}
+	go (&blockchainServer{pipe: pipe}).StreamBlocks(&StreamBlocksParam{}, stream)
+
+	// Without a height to stream from we only send blocks made after subscribing
+	pipe.events.waitForSubscription(t, txs.EventStringNewBlock())
+	pipe.addBlock()
+	pipe.events.fire(txs.EventStringNewBlock(), newBlockEvent(3))
+	assert.Equal(t, int64(3), receiveBlock(t, stream).Height)
+}
+
+func TestSubscribe(t *testing.T) {
+	pipe := newPipeFake(0)
+	ctx, cancel := context.WithCancel(context.Background())
+	stream := &eventStreamFake{ctx: ctx, events: make(chan *Event)}
+	eventId := txs.EventStringAccCall([]byte{1, 2, 3})
+	done := make(chan error)
+	go func() {
+		done <- (&eventsServer{pipe: pipe}).Subscribe(&EventIdParam{EventId: eventId}, stream)
+	}()
+
+	pipe.events.waitForSubscription(t, eventId)
+	pipe.events.fire(eventId, txs.EventDataCall{TxID: []byte{4}, Return: []byte{5}})
+	ev := receiveEvent(t, stream)
+	assert.Equal(t, eventId, ev.EventId)
+	if assert.NotNil(t, ev.Call) {
+		assert.Equal(t, []byte{4}, ev.Call.TxId)
+		assert.Equal(t, []byte{5}, ev.Call.Return)
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		assert.Equal(t, context.Canceled, err)
+	case <-time.After(streamTimeout):
+		t.Fatal("Timed out waiting for Subscribe to return after cancelling")
+	}
+	assert.Equal(t, 0, pipe.events.subscriptions())
+}
+
+func TestSubscribeOverflow(t *testing.T) {
+	pipe := newPipeFake(0)
+	// A client that never receives
+	stream := &eventStreamFake{ctx: context.Background(), events: make(chan *Event)}
+	eventId := txs.EventStringAccCall([]byte{1, 2, 3})
+	done := make(chan error)
+	go func() {
+		done <- (&eventsServer{pipe: pipe}).Subscribe(&EventIdParam{EventId: eventId}, stream)
+	}()
+
+	pipe.events.waitForSubscription(t, eventId)
+	for i := 0; i <= streamBufferSize+1; i++ {
+		pipe.events.fire(eventId, txs.EventDataCall{})
+	}
+	// Let the server give up on the client
+	go func() {
+		for range stream.events {
+		}
+	}()
+	select {
+	case err := <-done:
+		assert.Equal(t, codes.ResourceExhausted, grpc.Code(err))
+	case <-time.After(streamTimeout):
+		t.Fatal("Timed out waiting for Subscribe to give up on slow client")
+	}
+	assert.Equal(t, 0, pipe.events.subscriptions())
+}
+
+// A Tx go-wire cannot encode since it is not registered
+type unregisteredTx struct {
+	*txs.SendTx
+}
+
+func TestSubscribeConversionError(t *testing.T) {
+	pipe := newPipeFake(0)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream := &eventStreamFake{ctx: ctx, events: make(chan *Event)}
+	eventId := txs.EventStringAccInput([]byte{1, 2, 3})
+	done := make(chan error)
+	go func() {
+		done <- (&eventsServer{pipe: pipe}).Subscribe(&EventIdParam{EventId: eventId}, stream)
+	}()
+
+	// An event that cannot be sent ends the stream rather than being skipped
+	pipe.events.waitForSubscription(t, eventId)
+	pipe.events.fire(eventId, txs.EventDataTx{Tx: unregisteredTx{&txs.SendTx{}}})
+	select {
+	case err := <-done:
+		assert.Equal(t, codes.Internal, grpc.Code(err))
+	case <-time.After(streamTimeout):
+		t.Fatal("Timed out waiting for Subscribe to end the stream")
+	}
+	assert.Equal(t, 0, pipe.events.subscriptions())
+}
+
+func receiveBlock(t *testing.T, stream *blockStreamFake) *Block {
+	select {
+	case block := <-stream.blocks:
+		return block
+	case <-time.After(streamTimeout):
+		t.Fatal("Timed out waiting for block")
+		return nil
+	}
+}
+
+func receiveEvent(t *testing.T, stream *eventStreamFake) *Event {
+	select {
+	case ev := <-stream.events:
+		return ev
+	case <-time.After(streamTimeout):
+		t.Fatal("Timed out waiting for event")
+		return nil
+	}
+}
+
+func newBlockEvent(height int) txs.EventData {
+	return txs.EventDataNewBlock{
+		Block: &tm_types.Block{Header: &tm_types.Header{Height: height}},
+	}
+}
+
+//------------------------------------------------------------------------------
+// Fakes
+
+// A pipe with a growing blockchain and an event emitter the test fires
+type pipeFake struct {
+	definitions.Pipe
+	sync.Mutex
+	blocks []*tm_types.Block
+	events *eventEmitterFake
+}
+
+var _ blockchain_types.Blockchain = (*pipeFake)(nil)
+
+func newPipeFake(height int) *pipeFake {
+	pipe := &pipeFake{events: &eventEmitterFake{callbacks: make(map[string]*subscriptionFake)}}
+	for i := 0; i < height; i++ {
+		pipe.addBlock()
+	}
+	return pipe
+}
+
+func (pipe *pipeFake) addBlock() {
+	pipe.Lock()
+	defer pipe.Unlock()
+	pipe.blocks = append(pipe.blocks, &tm_types.Block{
+		Header: &tm_types.Header{ChainID: "test-chain", Height: len(pipe.blocks) + 1},
+		Data:   &tm_types.Data{},
+	})
+}
+
+func (pipe *pipeFake) Blockchain() blockchain_types.Blockchain {
+	return pipe
+}
+
+func (pipe *pipeFake) Events() event.EventEmitter {
+	return pipe.events
+}
+
+func (pipe *pipeFake) GetConsensusEngine() consensus_types.ConsensusEngine {
+	return nil
+}
+
+func (pipe *pipeFake) ChainId() string {
+	return "test-chain"
+}
+
+func (pipe *pipeFake) Height() int {
+	pipe.Lock()
+	defer pipe.Unlock()
+	return len(pipe.blocks)
+}
+
+func (pipe *pipeFake) BlockMeta(height int) *tm_types.BlockMeta {
+	return nil
+}
+
+func (pipe *pipeFake) Block(height int) *tm_types.Block {
+	pipe.Lock()
+	defer pipe.Unlock()
+	if height < 1 || height > len(pipe.blocks) {
+		return nil
+	}
+	return pipe.blocks[height-1]
+}
+
+type subscriptionFake struct {
+	event    string
+	callback func(txs.EventData)
+}
+
+type eventEmitterFake struct {
+	sync.Mutex
+	callbacks map[string]*subscriptionFake
+}
+
+func (emitter *eventEmitterFake) Subscribe(subId, event string,
+	callback func(txs.EventData)) error {
+	emitter.Lock()
+	defer emitter.Unlock()
+	emitter.callbacks[subId] = &subscriptionFake{event: event, callback: callback}
+	return nil
+}
+
+func (emitter *eventEmitterFake) Unsubscribe(subId string) error {
+	emitter.Lock()
+	defer emitter.Unlock()
+	delete(emitter.callbacks, subId)
+	return nil
+}
+
+func (emitter *eventEmitterFake) fire(event string, eventData txs.EventData) {
+	emitter.Lock()
+	defer emitter.Unlock()
+	for _, subscription := range emitter.callbacks {
+		if subscription.event == event {
+			subscription.callback(eventData)
+		}
+	}
+}
+
+func (emitter *eventEmitterFake) subscriptions() int {
+	emitter.Lock()
+	defer emitter.Unlock()
+	return len(emitter.callbacks)
+}
+
+func (emitter *eventEmitterFake) waitForSubscription(t *testing.T, event string) {
+	deadline := time.Now().Add(streamTimeout)
+	for time.Now().Before(deadline) {
+		emitter.Lock()
+		for _, subscription := range emitter.callbacks {
+			if subscription.event == event {
+				emitter.Unlock()
+				return
+			}
+		}
+		emitter.Unlock()
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("Timed out waiting for subscription to %s", event)
+}
+
+type blockStreamFake struct {
+	grpc.ServerStream
+	ctx    context.Context
+	blocks chan *Block
+}
+
+func (stream *blockStreamFake) Context() context.Context {
+	return stream.ctx
+}
+
+func (stream *blockStreamFake) Send(block *Block) error {
+	select {
+	case stream.blocks <- block:
+		return nil
+	case <-stream.ctx.Done():
+		return stream.ctx.Err()
+	}
+}
+
+type eventStreamFake struct {
+	grpc.ServerStream
+	ctx    context.Context
+	events chan *Event
+}
+
+func (stream *eventStreamFake) Context() context.Context {
+	return stream.ctx
+}
+
+func (stream *eventStreamFake) Send(ev *Event) error {
+	select {
+	case stream.events <- ev:
+		return nil
+	case <-stream.ctx.Done():
+		return stream.ctx.Err()
+	}
+}
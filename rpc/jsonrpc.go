@@ -15,6 +15,7 @@
 package rpc
 
 import (
+	"bytes"
 	"encoding/json"
 )
 
@@ -106,3 +107,31 @@ func (rpcResultResponse *RPCResultResponse) AssertIsRPCResponse() bool {
 func (rpcErrorResponse *RPCErrorResponse) AssertIsRPCResponse() bool {
 	return true
 }
+
+// IsBatchRequest reports whether the body of a JSON-RPC 2.0 call holds a batch,
+// that is a JSON array of request objects, rather than a single request object.
+// Refer to http://www.jsonrpc.org/specification#batch
+func IsBatchRequest(body []byte) bool {
+	trimmed := bytes.TrimLeft(body, " \t\r\n")
+	return len(trimmed) > 0 && trimmed[0] == '['
+}
+
+// IsRequestObject reports whether an element of a batch is a JSON object, as
+// each request must be. Other elements are invalid requests rather than
+// unparseable ones since the batch itself parsed.
+func IsRequestObject(rawRequest []byte) bool {
+	trimmed := bytes.TrimLeft(rawRequest, " \t\r\n")
+	return len(trimmed) > 0 && trimmed[0] == '{'
+}
+
+// IsNotification reports whether a request object is a notification, that is
+// one without an id member, which the server must not answer.
+// Refer to http://www.jsonrpc.org/specification#notification
+func IsNotification(rawRequest []byte) bool {
+	members := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(rawRequest, &members); err != nil {
+		return false
+	}
+	_, hasId := members["id"]
+	return !hasId
+}
@@ -46,3 +46,18 @@ func TestNewJsonRpcErrorResponse(t *testing.T) {
 	respGen := NewRPCErrorResponse(id, code, message)
 	assert.Equal(t, respGen, resp)
 }
+
+func TestIsBatchRequest(t *testing.T) {
+	assert.True(t, IsBatchRequest([]byte(`[{"jsonrpc":"2.0","method":"foo","id":"1"}]`)))
+	assert.True(t, IsBatchRequest([]byte(" \n\t[]")))
+	assert.False(t, IsBatchRequest([]byte(`{"jsonrpc":"2.0","method":"foo","id":"1"}`)))
+	assert.False(t, IsBatchRequest([]byte("  ")))
+	assert.False(t, IsBatchRequest(nil))
+}
+
+func TestIsNotification(t *testing.T) {
+	assert.True(t, IsNotification([]byte(`{"jsonrpc":"2.0","method":"foo"}`)))
+	assert.False(t, IsNotification([]byte(`{"jsonrpc":"2.0","method":"foo","id":"1"}`)))
+	assert.False(t, IsNotification([]byte(`{"jsonrpc":"2.0","method":"foo","id":null}`)))
+	assert.False(t, IsNotification([]byte(`1`)))
+}
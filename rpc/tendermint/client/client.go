@@ -140,6 +140,31 @@ func GetName(client RPCClient, name string) (*core_types.NameRegEntry, error) {
 	return res.(*rpc_types.ResultGetName).Entry, nil
 }
 
+func ListNames(client RPCClient) (*rpc_types.ResultListNames, error) {
+	res, err := call(client, "list_names")
+	if err != nil {
+		return nil, err
+	}
+	return res.(*rpc_types.ResultListNames), err
+}
+
+func GetProposal(client RPCClient, hash []byte) (*rpc_types.ResultGetProposal, error) {
+	res, err := call(client, "get_proposal",
+		"hash", hash)
+	if err != nil {
+		return nil, err
+	}
+	return res.(*rpc_types.ResultGetProposal), nil
+}
+
+func ListProposals(client RPCClient) (*rpc_types.ResultListProposals, error) {
+	res, err := call(client, "list_proposals")
+	if err != nil {
+		return nil, err
+	}
+	return res.(*rpc_types.ResultListProposals), err
+}
+
 func BlockchainInfo(client RPCClient, minHeight,
 	maxHeight int) (*rpc_types.ResultBlockchainInfo, error) {
 	res, err := call(client, "blockchain",
@@ -0,0 +1,166 @@
+// Copyright 2017 Monax Industries Limited
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/hyperledger/burrow/rpc"
+)
+
+// The go-rpc server only understands single JSON-RPC request objects, so we
+// wrap its handlers and split any batch (a JSON array of request objects)
+// POSTed to the root path into individual requests, collecting their
+// responses into an array in request order. Notifications are served but not
+// answered, so a batch of only notifications gets no body.
+type batchHandler struct {
+	handler      http.Handler
+	maxBatchSize int
+}
+
+func newBatchHandler(handler http.Handler, maxBatchSize int) http.Handler {
+	return &batchHandler{
+		handler:      handler,
+		maxBatchSize: maxBatchSize,
+	}
+}
+
+func (bh *batchHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" || r.URL.Path != "/" {
+		bh.handler.ServeHTTP(w, r)
+		return
+	}
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		writeJSON(w, rpc.NewRPCErrorResponse("", rpc.PARSE_ERROR,
+			"Failed to read request: "+err.Error()))
+		return
+	}
+	if !rpc.IsBatchRequest(body) {
+		// Restore the body we consumed and hand over to go-rpc
+		r.Body = ioutil.NopCloser(bytes.NewReader(body))
+		bh.handler.ServeHTTP(w, r)
+		return
+	}
+
+	rawRequests := []json.RawMessage{}
+	err = json.Unmarshal(body, &rawRequests)
+	if err != nil {
+		writeJSON(w, rpc.NewRPCErrorResponse("", rpc.PARSE_ERROR,
+			"Failed to parse batch request: "+err.Error()))
+		return
+	}
+	if len(rawRequests) == 0 {
+		writeJSON(w, rpc.NewRPCErrorResponse("", rpc.INVALID_REQUEST,
+			"Empty batch request"))
+		return
+	}
+	if bh.maxBatchSize > 0 && len(rawRequests) > bh.maxBatchSize {
+		writeJSON(w, rpc.NewRPCErrorResponse("", rpc.INVALID_REQUEST,
+			fmt.Sprintf("Batch of %v requests exceeds maximum batch size of %v",
+				len(rawRequests), bh.maxBatchSize)))
+		return
+	}
+
+	buf := new(bytes.Buffer)
+	buf.WriteByte('[')
+	for _, rawRequest := range rawRequests {
+		response := bh.serveSingle(r, rawRequest)
+		if response == nil {
+			continue
+		}
+		if buf.Len() > 1 {
+			buf.WriteByte(',')
+		}
+		buf.Write(response)
+	}
+	if buf.Len() == 1 {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	buf.WriteByte(']')
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+	w.Write(buf.Bytes())
+}
+
+// Run a single request object from a batch through the wrapped handler and
+// return the bytes of its response object, or nil for a notification.
+func (bh *batchHandler) serveSingle(r *http.Request, rawRequest []byte) []byte {
+	if !rpc.IsRequestObject(rawRequest) {
+		response, _ := json.Marshal(rpc.NewRPCErrorResponse("", rpc.INVALID_REQUEST,
+			"Batch element is not a request object: "+string(rawRequest)))
+		return response
+	}
+	single := new(http.Request)
+	*single = *r
+	single.Body = ioutil.NopCloser(bytes.NewReader(rawRequest))
+	single.ContentLength = int64(len(rawRequest))
+	brw := newBufferedResponseWriter()
+	bh.handler.ServeHTTP(brw, single)
+	if rpc.IsNotification(rawRequest) {
+		return nil
+	}
+	response := bytes.TrimSpace(brw.body.Bytes())
+	if len(response) == 0 {
+		req := &rpc.RPCRequest{}
+		json.Unmarshal(rawRequest, req)
+		response, _ = json.Marshal(rpc.NewRPCErrorResponse(req.Id,
+			rpc.INTERNAL_ERROR, "No response for request in batch"))
+	}
+	return response
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	bs, err := json.Marshal(v)
+	if err != nil {
+		http.Error(w, "Failed to marshal response: "+err.Error(), 500)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+	w.Write(bs)
+}
+
+// Collects the response to a single request from a batch in memory
+type bufferedResponseWriter struct {
+	header http.Header
+	body   *bytes.Buffer
+	status int
+}
+
+func newBufferedResponseWriter() *bufferedResponseWriter {
+	return &bufferedResponseWriter{
+		header: make(http.Header),
+		body:   new(bytes.Buffer),
+		status: 200,
+	}
+}
+
+func (brw *bufferedResponseWriter) Header() http.Header {
+	return brw.header
+}
+
+func (brw *bufferedResponseWriter) Write(bs []byte) (int, error) {
+	return brw.body.Write(bs)
+}
+
+func (brw *bufferedResponseWriter) WriteHeader(status int) {
+	brw.status = status
+}
@@ -0,0 +1,158 @@
+// Copyright 2017 Monax Industries Limited
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hyperledger/burrow/rpc"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Stands in for go-rpc: answers each request object with its method as the
+// result, except for the method "silent" which gets no response
+func echoHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		w.Write([]byte("not a post"))
+		return
+	}
+	body, _ := ioutil.ReadAll(r.Body)
+	request := &rpc.RPCRequest{}
+	if err := json.Unmarshal(body, request); err != nil {
+		writeJSON(w, rpc.NewRPCErrorResponse("", rpc.PARSE_ERROR, err.Error()))
+		return
+	}
+	if request.Method == "silent" {
+		return
+	}
+	writeJSON(w, rpc.NewRPCResponse(request.Id, request.Method))
+}
+
+func postBatch(handler http.Handler, method, path, body string) []byte {
+	r := httptest.NewRequest(method, path, strings.NewReader(body))
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	return w.Body.Bytes()
+}
+
+func request(id, method string) string {
+	return `{"jsonrpc":"2.0","method":"` + method + `","params":{},"id":"` + id + `"}`
+}
+
+func TestBatchHandlerPassThrough(t *testing.T) {
+	handler := newBatchHandler(http.HandlerFunc(echoHandler), 2)
+
+	assert.Equal(t, "not a post", string(postBatch(handler, "GET", "/", "")))
+
+	response := &rpc.RPCResultResponse{}
+	assert.NoError(t, json.Unmarshal(postBatch(handler, "POST", "/",
+		request("a", "status")), response))
+	assert.Equal(t, "a", response.Id)
+	assert.Equal(t, "status", response.Result)
+
+	// Only the root path takes batches
+	response = &rpc.RPCResultResponse{}
+	assert.NoError(t, json.Unmarshal(postBatch(handler, "POST", "/status",
+		request("b", "status")), response))
+	assert.Equal(t, "b", response.Id)
+}
+
+func TestBatchHandlerBatch(t *testing.T) {
+	handler := newBatchHandler(http.HandlerFunc(echoHandler), 4)
+
+	responses := []map[string]interface{}{}
+	assert.NoError(t, json.Unmarshal(postBatch(handler, "POST", "/",
+		"["+request("a", "status")+","+request("b", "net_info")+"]"), &responses))
+	if assert.Len(t, responses, 2) {
+		assert.Equal(t, "a", responses[0]["id"])
+		assert.Equal(t, "status", responses[0]["result"])
+		assert.Equal(t, "b", responses[1]["id"])
+		assert.Equal(t, "net_info", responses[1]["result"])
+	}
+
+	// Elements that are not request objects are invalid requests, and a request
+	// the wrapped handler does not answer is an internal error
+	responses = []map[string]interface{}{}
+	assert.NoError(t, json.Unmarshal(postBatch(handler, "POST", "/",
+		`[1, "x", `+request("c", "silent")+","+request("d", "status")+"]"),
+		&responses))
+	if assert.Len(t, responses, 4) {
+		assert.Equal(t, float64(rpc.INVALID_REQUEST), errorCode(responses[0]))
+		assert.Equal(t, float64(rpc.INVALID_REQUEST), errorCode(responses[1]))
+		assert.Equal(t, "c", responses[2]["id"])
+		assert.Equal(t, float64(rpc.INTERNAL_ERROR), errorCode(responses[2]))
+		assert.Equal(t, "d", responses[3]["id"])
+	}
+}
+
+func TestBatchHandlerNotifications(t *testing.T) {
+	handler := newBatchHandler(http.HandlerFunc(echoHandler), 4)
+	notification := `{"jsonrpc":"2.0","method":"status","params":{}}`
+
+	// Notifications are served but not answered
+	responses := []map[string]interface{}{}
+	assert.NoError(t, json.Unmarshal(postBatch(handler, "POST", "/",
+		"["+notification+","+request("a", "status")+","+notification+"]"),
+		&responses))
+	if assert.Len(t, responses, 1) {
+		assert.Equal(t, "a", responses[0]["id"])
+	}
+
+	// so a batch of only notifications gets no response
+	r := httptest.NewRequest("POST", "/", strings.NewReader("["+notification+"]"))
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Empty(t, w.Body.Bytes())
+}
+
+func TestBatchHandlerErrors(t *testing.T) {
+	handler := newBatchHandler(http.HandlerFunc(echoHandler), 2)
+
+	for body, code := range map[string]int{
+		"[":  rpc.PARSE_ERROR,
+		"[]": rpc.INVALID_REQUEST,
+		"[" + request("a", "status") + "," + request("b", "status") + "," +
+			request("c", "status") + "]": rpc.INVALID_REQUEST,
+	} {
+		response := &rpc.RPCErrorResponse{}
+		assert.NoError(t, json.Unmarshal(postBatch(handler, "POST", "/", body), response))
+		if assert.NotNil(t, response.Error, body) {
+			assert.Equal(t, code, response.Error.Code, body)
+		}
+	}
+
+	// Without a maximum any size of batch is served
+	handler = newBatchHandler(http.HandlerFunc(echoHandler), 0)
+	responses := []map[string]interface{}{}
+	assert.NoError(t, json.Unmarshal(postBatch(handler, "POST", "/",
+		"["+request("a", "status")+","+request("b", "status")+","+
+			request("c", "status")+"]"), &responses))
+	assert.Len(t, responses, 3)
+}
+
+func errorCode(response map[string]interface{}) interface{} {
+	rpcError, ok := response["error"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	return rpcError["code"]
+}
@@ -52,6 +52,8 @@ func (tmRoutes *TendermintRoutes) GetRoutes() map[string]*rpc.RPCFunc {
 		"list_accounts":           rpc.NewRPCFunc(tmRoutes.ListAccountsResult, ""),
 		"get_name":                rpc.NewRPCFunc(tmRoutes.GetNameResult, "name"),
 		"list_names":              rpc.NewRPCFunc(tmRoutes.ListNamesResult, ""),
+		"get_proposal":            rpc.NewRPCFunc(tmRoutes.GetProposalResult, "hash"),
+		"list_proposals":          rpc.NewRPCFunc(tmRoutes.ListProposalsResult, ""),
 		"broadcast_tx":            rpc.NewRPCFunc(tmRoutes.BroadcastTxResult, "tx"),
 		"blockchain":              rpc.NewRPCFunc(tmRoutes.BlockchainInfo, "minHeight,maxHeight"),
 		"get_block":               rpc.NewRPCFunc(tmRoutes.GetBlock, "height"),
@@ -196,6 +198,22 @@ func (tmRoutes *TendermintRoutes) ListNamesResult() (ctypes.BurrowResult, error)
 	}
 }
 
+func (tmRoutes *TendermintRoutes) GetProposalResult(hash []byte) (ctypes.BurrowResult, error) {
+	if r, err := tmRoutes.tendermintPipe.GetProposal(hash); err != nil {
+		return nil, err
+	} else {
+		return r, nil
+	}
+}
+
+func (tmRoutes *TendermintRoutes) ListProposalsResult() (ctypes.BurrowResult, error) {
+	if r, err := tmRoutes.tendermintPipe.ListProposals(); err != nil {
+		return nil, err
+	} else {
+		return r, nil
+	}
+}
+
 func (tmRoutes *TendermintRoutes) GenPrivAccountResult() (ctypes.BurrowResult, error) {
 	//if r, err := tmRoutes.tendermintPipe.GenPrivAccount(); err != nil {
 	//	return nil, err
@@ -18,6 +18,7 @@ import (
 	acm "github.com/hyperledger/burrow/account"
 	core_types "github.com/hyperledger/burrow/core/types"
 	genesis "github.com/hyperledger/burrow/genesis"
+	ptypes "github.com/hyperledger/burrow/permission/types"
 	"github.com/hyperledger/burrow/txs"
 	tendermint_types "github.com/tendermint/tendermint/types"
 
@@ -116,6 +117,18 @@ type ResultListNames struct {
 	Names       []*core_types.NameRegEntry `json:"names"`
 }
 
+type ResultGetProposal struct {
+	Proposal *ptypes.Proposal `json:"proposal"`
+	// One of ptypes.ProposalStatusPending, ProposalStatusExecuted or
+	// ProposalStatusExpired
+	Status string `json:"status"`
+}
+
+type ResultListProposals struct {
+	BlockHeight int                `json:"block_height"`
+	Proposals   []*ptypes.Proposal `json:"proposals"`
+}
+
 type ResultGenPrivAccount struct {
 	PrivAccount *acm.PrivAccount `json:"priv_account"`
 }
@@ -179,6 +192,8 @@ const (
 	ResultTypeUnsubscribe        = byte(0x15)
 	ResultTypePeerConsensusState = byte(0x16)
 	ResultTypeChainId            = byte(0x17)
+	ResultTypeGetProposal        = byte(0x18)
+	ResultTypeListProposals      = byte(0x19)
 )
 
 type BurrowResult interface {
@@ -210,6 +225,8 @@ func ConcreteTypes() []wire.ConcreteType {
 		{&ResultSubscribe{}, ResultTypeSubscribe},
 		{&ResultUnsubscribe{}, ResultTypeUnsubscribe},
 		{&ResultChainId{}, ResultTypeChainId},
+		{&ResultGetProposal{}, ResultTypeGetProposal},
+		{&ResultListProposals{}, ResultTypeListProposals},
 	}
 }
 
@@ -54,7 +54,8 @@ func NewTendermintWebsocketServer(config *server.ServerConfig,
 		wm := rpcserver.NewWebsocketManager(routes, evsw)
 		mux.HandleFunc(config.Tendermint.Endpoint, wm.WebsocketHandler)
 		rpcserver.RegisterRPCFuncs(mux, routes)
-		listener, err := rpcserver.StartHTTPServer(listenerAddress, mux)
+		listener, err := rpcserver.StartHTTPServer(listenerAddress,
+			newBatchHandler(mux, int(config.Tendermint.MaxBatchSize)))
 		if err != nil {
 			return nil, err
 		}
@@ -215,7 +215,7 @@ func TestNameReg(t *testing.T) {
 	wsc := newWSClient()
 	testWithAllClients(t, func(t *testing.T, clientName string, client burrow_client.RPCClient) {
 
-		txs.MinNameRegistrationPeriod = 1
+		nameRegParams := genesisDoc.Params.NameRegParams()
 
 		// register a new name, check if its there
 		// since entries ought to be unique and these run against different clients, we append the client
@@ -223,7 +223,7 @@ func TestNameReg(t *testing.T) {
 		const data = "if not now, when"
 		fee := int64(1000)
 		numDesiredBlocks := int64(2)
-		amt := fee + numDesiredBlocks*txs.NameByteCostMultiplier*txs.NameBlockCostMultiplier*txs.NameBaseCost(name, data)
+		amt := fee + numDesiredBlocks*nameRegParams.CostPerBlock(txs.NameBaseCost(name, data))
 
 		tx := makeDefaultNameTx(t, client, name, data, amt, fee)
 		// verify the name by both using the event and by checking get_name
@@ -247,13 +247,24 @@ func TestNameReg(t *testing.T) {
 		assert.Equal(t, data, entry.Data)
 		assert.Equal(t, users[0].Address, entry.Owner)
 
+		names, err := burrow_client.ListNames(client)
+		assert.NoError(t, err)
+		listed := false
+		for _, listedEntry := range names.Names {
+			if listedEntry.Name == name {
+				listed = true
+				assert.Equal(t, data, listedEntry.Data)
+			}
+		}
+		assert.True(t, listed, "Name should be returned by ListNames")
+
 		// update the data as the owner, make sure still there
 		numDesiredBlocks = int64(5)
 		const updatedData = "these are amongst the things I wish to bestow upon " +
 			"the youth of generations come: a safe supply of honey, and a better " +
 			"money. For what else shall they need"
-		amt = fee + numDesiredBlocks*txs.NameByteCostMultiplier*
-			txs.NameBlockCostMultiplier*txs.NameBaseCost(name, updatedData)
+		amt = fee + numDesiredBlocks*
+			nameRegParams.CostPerBlock(txs.NameBaseCost(name, updatedData))
 		tx = makeDefaultNameTx(t, client, name, updatedData, amt, fee)
 		broadcastTxAndWaitForBlock(t, client, wsc, tx)
 		mempoolCount = 0
@@ -266,7 +277,7 @@ func TestNameReg(t *testing.T) {
 			getNonce(t, client, users[1].Address)+1)
 		tx.Sign(chainID, users[1])
 
-		_, err := broadcastTxAndWaitForBlock(t, client, wsc, tx)
+		_, err = broadcastTxAndWaitForBlock(t, client, wsc, tx)
 		assert.Error(t, err, "Expected error when updating someone else's unexpired"+
 			" name registry entry")
 		if err != nil {
@@ -118,7 +118,10 @@ func initGlobalVariables(ffs *fixtures.FileFixtures) error {
 	rootWorkDir = ffs.AddDir("rootWorkDir")
 	rootDataDir := ffs.AddDir("rootDataDir")
 	genesisFile := ffs.AddFile("rootWorkDir/genesis.json", string(genesisBytes))
-	genesisDoc = genesis.GenesisDocFromJSON(genesisBytes)
+	genesisDoc, err = genesis.GenesisDocFromJSON(genesisBytes)
+	if err != nil {
+		return err
+	}
 
 	if ffs.Error != nil {
 		return ffs.Error
@@ -202,7 +205,17 @@ func genesisFileBytesFromUsers(chainName string, accounts []*acm.PrivAccount) ([
 		genesisAccounts[i] = genesisAccountFromPrivAccount(acc)
 	}
 
-	return genesis.GenerateGenesisFileBytes(chainName, genesisAccounts, genesisValidators)
+	genesisDoc, err := genesis.MakeGenesisDocFromAccounts(chainName,
+		genesisAccounts, genesisValidators)
+	if err != nil {
+		return nil, err
+	}
+	// so names can be registered for the couple of blocks a test runs over
+	minRegistrationPeriod := 1
+	genesisDoc.Params.NameReg = &txs.GenesisNameRegParams{
+		MinRegistrationPeriod: &minRegistrationPeriod,
+	}
+	return genesis.GetGenesisFileBytes(&genesisDoc)
 }
 
 func genesisValidatorFromPrivAccount(account *acm.PrivAccount) *genesis.GenesisValidator {
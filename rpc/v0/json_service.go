@@ -15,7 +15,10 @@
 package v0
 
 import (
+	"bytes"
 	"encoding/json"
+	"fmt"
+	"io/ioutil"
 	"net/http"
 
 	definitions "github.com/hyperledger/burrow/definitions"
@@ -69,13 +72,20 @@ type BurrowJsonService struct {
 	pipe            definitions.Pipe
 	eventSubs       *event.EventSubscriptions
 	defaultHandlers map[string]RequestHandlerFunc
+	// Maximum number of requests accepted in a single batch, 0 means no limit
+	maxBatchSize int
 }
 
 // Create a new JSON-RPC 2.0 service for burrow (tendermint).
 func NewBurrowJsonService(codec rpc.Codec, pipe definitions.Pipe,
-	eventSubs *event.EventSubscriptions) server.HttpService {
+	eventSubs *event.EventSubscriptions, maxBatchSize int) server.HttpService {
 
-	tmhttps := &BurrowJsonService{codec: codec, pipe: pipe, eventSubs: eventSubs}
+	tmhttps := &BurrowJsonService{
+		codec:        codec,
+		pipe:         pipe,
+		eventSubs:    eventSubs,
+		maxBatchSize: maxBatchSize,
+	}
 	mtds := NewBurrowMethods(codec, pipe)
 
 	dhMap := mtds.getMethods()
@@ -87,40 +97,91 @@ func NewBurrowJsonService(codec rpc.Codec, pipe definitions.Pipe,
 	return tmhttps
 }
 
-// Process a request.
+// Process a request. The body may hold a single request object or a batch
+// (array) of request objects, in which case the responses are written as an
+// array in the same order as the requests. Notifications in a batch are
+// processed but not answered, so a batch of only notifications gets no body.
 func (this *BurrowJsonService) Process(r *http.Request, w http.ResponseWriter) {
+	body, errR := ioutil.ReadAll(r.Body)
+	if errR != nil {
+		this.writeError("Failed to read request: "+errR.Error(), "",
+			rpc.PARSE_ERROR, w)
+		return
+	}
+
+	if !rpc.IsBatchRequest(body) {
+		this.write(this.processRequest(body, w), w)
+		return
+	}
+
+	rawRequests := []json.RawMessage{}
+	errU := json.Unmarshal(body, &rawRequests)
+	if errU != nil {
+		this.writeError("Failed to parse batch request: "+errU.Error(), "",
+			rpc.PARSE_ERROR, w)
+		return
+	}
+	if len(rawRequests) == 0 {
+		this.writeError("Empty batch request", "", rpc.INVALID_REQUEST, w)
+		return
+	}
+	if this.maxBatchSize > 0 && len(rawRequests) > this.maxBatchSize {
+		this.writeError(fmt.Sprintf("Batch of %v requests exceeds maximum batch size of %v",
+			len(rawRequests), this.maxBatchSize), "", rpc.INVALID_REQUEST, w)
+		return
+	}
+
+	responses := make([]rpc.RPCResponse, 0, len(rawRequests))
+	for _, rawRequest := range rawRequests {
+		if !rpc.IsRequestObject(rawRequest) {
+			responses = append(responses, rpc.NewRPCErrorResponse("", rpc.INVALID_REQUEST,
+				"Batch element is not a request object: "+string(rawRequest)))
+			continue
+		}
+		response := this.processRequest(rawRequest, w)
+		if !rpc.IsNotification(rawRequest) {
+			responses = append(responses, response)
+		}
+	}
+	if len(responses) == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	this.writeBatch(responses, w)
+}
+
+// Decode and dispatch a single request object, returning the response object
+// to be written back to the caller.
+func (this *BurrowJsonService) processRequest(rawRequest []byte,
+	requester interface{}) rpc.RPCResponse {
 
 	// Create new request object and unmarshal.
 	req := &rpc.RPCRequest{}
-	decoder := json.NewDecoder(r.Body)
-	errU := decoder.Decode(req)
+	errU := json.Unmarshal(rawRequest, req)
 
 	// Error when decoding.
 	if errU != nil {
-		this.writeError("Failed to parse request: "+errU.Error(), "",
-			rpc.PARSE_ERROR, w)
-		return
+		return rpc.NewRPCErrorResponse("", rpc.PARSE_ERROR,
+			"Failed to parse request: "+errU.Error())
 	}
 
 	// Wrong protocol version.
 	if req.JSONRPC != "2.0" {
-		this.writeError("Wrong protocol version: "+req.JSONRPC, req.Id,
-			rpc.INVALID_REQUEST, w)
-		return
+		return rpc.NewRPCErrorResponse(req.Id, rpc.INVALID_REQUEST,
+			"Wrong protocol version: "+req.JSONRPC)
 	}
 
 	mName := req.Method
 
 	if handler, ok := this.defaultHandlers[mName]; ok {
-		resp, errCode, err := handler(req, w)
+		resp, errCode, err := handler(req, requester)
 		if err != nil {
-			this.writeError(err.Error(), req.Id, errCode, w)
-		} else {
-			this.writeResponse(req.Id, resp, w)
+			return rpc.NewRPCErrorResponse(req.Id, errCode, err.Error())
 		}
-	} else {
-		this.writeError("Method not found: "+mName, req.Id, rpc.METHOD_NOT_FOUND, w)
+		return rpc.NewRPCResponse(req.Id, resp)
 	}
+	return rpc.NewRPCErrorResponse(req.Id, rpc.METHOD_NOT_FOUND,
+		"Method not found: "+mName)
 }
 
 // Helper for writing error responses.
@@ -135,17 +196,53 @@ func (this *BurrowJsonService) writeError(msg, id string, code int, w http.Respo
 	w.WriteHeader(200)
 }
 
-// Helper for writing responses.
-func (this *BurrowJsonService) writeResponse(id string, result interface{}, w http.ResponseWriter) {
-	response := rpc.NewRPCResponse(id, result)
+// Helper for writing a response object.
+func (this *BurrowJsonService) write(response rpc.RPCResponse, w http.ResponseWriter) {
 	err := this.codec.Encode(response, w)
 	if err != nil {
-		this.writeError("Internal error: "+err.Error(), id, rpc.INTERNAL_ERROR, w)
+		this.writeError("Internal error: "+err.Error(), responseId(response),
+			rpc.INTERNAL_ERROR, w)
 		return
 	}
 	w.WriteHeader(200)
 }
 
+// Helper for writing the responses to a batch request. Each response is
+// encoded on its own so that a failure to encode one result only turns that
+// entry into an error response.
+func (this *BurrowJsonService) writeBatch(responses []rpc.RPCResponse, w http.ResponseWriter) {
+	buf := new(bytes.Buffer)
+	buf.WriteByte('[')
+	for i, response := range responses {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		bs, err := this.codec.EncodeBytes(response)
+		if err != nil {
+			bs, err = this.codec.EncodeBytes(rpc.NewRPCErrorResponse(responseId(response),
+				rpc.INTERNAL_ERROR, "Internal error: "+err.Error()))
+			if err != nil {
+				http.Error(w, "Failed to marshal standard error response: "+err.Error(), 500)
+				return
+			}
+		}
+		buf.Write(bs)
+	}
+	buf.WriteByte(']')
+	w.Write(buf.Bytes())
+	w.WriteHeader(200)
+}
+
+func responseId(response rpc.RPCResponse) string {
+	switch r := response.(type) {
+	case *rpc.RPCResultResponse:
+		return r.Id
+	case *rpc.RPCErrorResponse:
+		return r.Id
+	}
+	return ""
+}
+
 // *************************************** Events ************************************
 
 // Subscribe to an event.
@@ -15,11 +15,16 @@
 package v0
 
 import (
+	"bytes"
+	"encoding/json"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/hyperledger/burrow/account"
+	"github.com/hyperledger/burrow/event"
 	"github.com/hyperledger/burrow/manager/burrow-mint/evm/opcodes"
 	"github.com/hyperledger/burrow/rpc"
+	"github.com/hyperledger/burrow/server"
 	"github.com/hyperledger/burrow/txs"
 
 	"github.com/stretchr/testify/assert"
@@ -44,6 +49,91 @@ func TestBroadcastTx(t *testing.T) {
 	assert.Equal(t, txs.TxHash(testData.GetChainId.Output.ChainId, tx), receipt.TxHash)
 }
 
+func TestBatchRequest(t *testing.T) {
+	testData := LoadTestData()
+	pipe := NewMockPipe(testData)
+	service := NewBurrowJsonService(NewTCodec(), pipe,
+		event.NewEventSubscriptions(pipe.Events()), 3)
+
+	batch := `[
+		{"jsonrpc":"2.0","method":"burrow.getChainId","params":{},"id":"a"},
+		{"jsonrpc":"2.0","method":"burrow.noSuchMethod","params":{},"id":"b"},
+		{"jsonrpc":"2.0","method":"burrow.getLatestBlockHeight","params":{},"id":"c"}
+	]`
+	responses := []map[string]interface{}{}
+	err := json.Unmarshal(postJsonRpc(service, batch), &responses)
+	assert.NoError(t, err)
+	if assert.Len(t, responses, 3) {
+		assert.Equal(t, "a", responses[0]["id"])
+		assert.Equal(t, testData.GetChainId.Output.ChainId,
+			responses[0]["result"].(map[string]interface{})["chain_id"])
+		assert.Equal(t, "b", responses[1]["id"])
+		assert.Equal(t, float64(rpc.METHOD_NOT_FOUND),
+			responses[1]["error"].(map[string]interface{})["code"])
+		assert.Equal(t, "c", responses[2]["id"])
+		assert.Nil(t, responses[2]["error"])
+	}
+
+	// Elements that are not request objects are invalid requests
+	responses = []map[string]interface{}{}
+	err = json.Unmarshal(postJsonRpc(service, `[1, "x",
+		{"jsonrpc":"2.0","method":"burrow.getChainId","params":{},"id":"e"}]`),
+		&responses)
+	assert.NoError(t, err)
+	if assert.Len(t, responses, 3) {
+		for _, response := range responses[:2] {
+			assert.Equal(t, float64(rpc.INVALID_REQUEST),
+				response["error"].(map[string]interface{})["code"])
+		}
+		assert.Equal(t, "e", responses[2]["id"])
+	}
+
+	// Notifications are not answered, so a batch of them gets no response
+	responses = []map[string]interface{}{}
+	err = json.Unmarshal(postJsonRpc(service, `[
+		{"jsonrpc":"2.0","method":"burrow.getChainId","params":{}},
+		{"jsonrpc":"2.0","method":"burrow.getChainId","params":{},"id":"f"}]`),
+		&responses)
+	assert.NoError(t, err)
+	if assert.Len(t, responses, 1) {
+		assert.Equal(t, "f", responses[0]["id"])
+	}
+	assert.Empty(t, postJsonRpc(service,
+		`[{"jsonrpc":"2.0","method":"burrow.getChainId","params":{}}]`))
+
+	// A single request object still gets a single response object
+	response := map[string]interface{}{}
+	err = json.Unmarshal(postJsonRpc(service,
+		`{"jsonrpc":"2.0","method":"burrow.getChainId","params":{},"id":"d"}`), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, "d", response["id"])
+}
+
+func TestBatchRequestLimits(t *testing.T) {
+	pipe := NewMockPipe(LoadTestData())
+	service := NewBurrowJsonService(NewTCodec(), pipe,
+		event.NewEventSubscriptions(pipe.Events()), 1)
+
+	for _, batch := range []string{
+		`[]`,
+		`[{"jsonrpc":"2.0","method":"burrow.getChainId","params":{},"id":"a"},
+		  {"jsonrpc":"2.0","method":"burrow.getChainId","params":{},"id":"b"}]`,
+	} {
+		response := map[string]interface{}{}
+		err := json.Unmarshal(postJsonRpc(service, batch), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, float64(rpc.INVALID_REQUEST),
+			response["error"].(map[string]interface{})["code"])
+	}
+}
+
+func postJsonRpc(service server.HttpService, body string) []byte {
+	request := httptest.NewRequest("POST", "/rpc", bytes.NewBufferString(body))
+	recorder := httptest.NewRecorder()
+	service.Process(request, recorder)
+	return recorder.Body.Bytes()
+}
+
 // Allows us to get the type byte included but then omit the outer struct and
 // embedded field
 type wrappedTx struct {
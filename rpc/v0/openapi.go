@@ -0,0 +1,567 @@
+// Copyright 2017 Monax Industries Limited
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v0
+
+import (
+	"fmt"
+	"path"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	acm "github.com/hyperledger/burrow/account"
+	consensus_types "github.com/hyperledger/burrow/consensus/types"
+	core_types "github.com/hyperledger/burrow/core/types"
+	event "github.com/hyperledger/burrow/event"
+	"github.com/hyperledger/burrow/rpc/v0/shared"
+	"github.com/hyperledger/burrow/txs"
+	"github.com/hyperledger/burrow/version"
+
+	tm_types "github.com/tendermint/tendermint/types"
+)
+
+const openAPIPath = "/openapi.json"
+
+// The OpenAPI 3 document for the REST server is generated from the routes
+// registered on the router together with the descriptions in restOperations.
+// Every registered route must have an entry here (and vice versa) or the
+// document cannot be generated.
+var restOperations = map[string]*restOperation{
+	// Accounts
+	"GET /accounts": {
+		id: "getAccounts", tag: "Accounts", summary: "List accounts matching a search query",
+		query: []string{"q"}, response: core_types.AccountList{},
+	},
+	"GET /accounts/:address": {
+		id: "getAccount", tag: "Accounts", summary: "Get an account",
+		response: acm.Account{},
+	},
+	"GET /accounts/:address/storage": {
+		id: "getStorage", tag: "Accounts", summary: "Get the storage of a contract account",
+		response: core_types.Storage{},
+	},
+	"GET /accounts/:address/storage/:key": {
+		id: "getStorageAt", tag: "Accounts", summary: "Get a single storage item of a contract account",
+		response: core_types.StorageItem{},
+	},
+	// Blockchain
+	"GET /blockchain": {
+		id: "getBlockchainInfo", tag: "Blockchain", summary: "Get chain id, genesis hash and latest block",
+		response: core_types.BlockchainInfo{},
+	},
+	"GET /blockchain/chain_id": {
+		id: "getChainId", tag: "Blockchain", summary: "Get the chain id",
+		response: core_types.ChainId{},
+	},
+	"GET /blockchain/genesis_hash": {
+		id: "getGenesisHash", tag: "Blockchain", summary: "Get the hash of the genesis state",
+		response: core_types.GenesisHash{},
+	},
+	"GET /blockchain/latest_block_height": {
+		id: "getLatestBlockHeight", tag: "Blockchain", summary: "Get the height of the latest block",
+		response: core_types.LatestBlockHeight{},
+	},
+	"GET /blockchain/latest_block": {
+		id: "getLatestBlock", tag: "Blockchain", summary: "Get the latest block",
+		response: tm_types.Block{},
+	},
+	"GET /blockchain/blocks": {
+		id: "getBlocks", tag: "Blockchain", summary: "List block metadata matching a search query on height",
+		query: []string{"q"}, response: core_types.Blocks{},
+	},
+	"GET /blockchain/block/:height": {
+		id: "getBlock", tag: "Blockchain", summary: "Get the block at a height",
+		response: tm_types.Block{},
+	},
+	// Consensus
+	"GET /consensus": {
+		id: "getConsensusState", tag: "Consensus", summary: "Get the consensus state",
+		response: consensus_types.ConsensusState{},
+	},
+	"GET /consensus/validators": {
+		id: "getValidators", tag: "Consensus", summary: "List the validators",
+		response: []consensus_types.Validator{},
+	},
+	// Events
+	"POST /event_subs": {
+		id: "eventSubscribe", tag: "Events", summary: "Subscribe to an event, events are then collected for polling",
+		request: EventIdParam{}, response: event.EventSub{},
+	},
+	"GET /event_subs/:id": {
+		id: "eventPoll", tag: "Events", summary: "Get the events collected since the subscription was last polled",
+		response: event.PollResponse{},
+	},
+	"DELETE /event_subs/:id": {
+		id: "eventUnsubscribe", tag: "Events", summary: "Remove an event subscription",
+		response: event.EventUnsub{},
+	},
+	// NameReg
+	"GET /namereg": {
+		id: "getNameRegEntries", tag: "NameReg", summary: "List name registry entries matching a search query",
+		query: []string{"q"}, response: core_types.ResultListNames{},
+	},
+	"GET /namereg/:key": {
+		id: "getNameRegEntry", tag: "NameReg", summary: "Get a name registry entry",
+		pathParams: map[string]string{"key": "Name of the entry"},
+		response:   core_types.NameRegEntry{},
+	},
+	// Network
+	"GET /network": {
+		id: "getNetworkInfo", tag: "Network", summary: "Get information about the node's network",
+		response: shared.NetworkInfo{},
+	},
+	"GET /network/client_version": {
+		id: "getClientVersion", tag: "Network", summary: "Get the client version",
+		response: core_types.ClientVersion{},
+	},
+	"GET /network/moniker": {
+		id: "getMoniker", tag: "Network", summary: "Get the node's moniker",
+		response: core_types.Moniker{},
+	},
+	"GET /network/listening": {
+		id: "isListening", tag: "Network", summary: "Get whether the node is listening for peers",
+		response: core_types.Listening{},
+	},
+	"GET /network/listeners": {
+		id: "getListeners", tag: "Network", summary: "List the node's peer listeners",
+		response: core_types.Listeners{},
+	},
+	"GET /network/peers": {
+		id: "getPeers", tag: "Network", summary: "List connected peers",
+		response: []consensus_types.Peer{},
+	},
+	"GET /network/peers/:address": {
+		id: "getPeer", tag: "Network", summary: "Get a connected peer",
+		pathParams: map[string]string{"address": "Remote address of the peer"},
+		response:   consensus_types.Peer{},
+	},
+	// Transactions
+	"POST /txpool": {
+		id: "broadcastTx", tag: "Transactions", summary: "Broadcast a signed CallTx",
+		request: txs.CallTx{}, response: txs.Receipt{},
+	},
+	"GET /txpool": {
+		id: "getUnconfirmedTxs", tag: "Transactions", summary: "List transactions in the mempool",
+		response: txs.UnconfirmedTxs{},
+	},
+	// Code execution
+	"POST /calls": {
+		id: "call", tag: "Code execution", summary: "Call a contract against the current state without a transaction",
+		request: CallParam{}, response: core_types.Call{},
+	},
+	"POST /codecalls": {
+		id: "callCode", tag: "Code execution", summary: "Run code against the current state without a transaction",
+		request: CallCodeParam{}, response: core_types.Call{},
+	},
+	// Unsafe
+	"GET /unsafe/pa_generator": {
+		id: "genPrivAccount", tag: "Unsafe", summary: "Generate a private account",
+		response: acm.PrivAccount{},
+	},
+	"POST /unsafe/txpool": {
+		id: "transact", tag: "Unsafe",
+		summary: "Create, sign and broadcast a CallTx with the given private key. " +
+			"With hold=true wait for the call and return its event data instead of a receipt",
+		query: []string{"hold"}, request: TransactParam{},
+		response: oneOf{txs.Receipt{}, txs.EventDataCall{}},
+	},
+	"POST /unsafe/namereg/txpool": {
+		id: "transactNameReg", tag: "Unsafe", summary: "Create, sign and broadcast a NameTx with the given private key",
+		request: TransactNameRegParam{}, response: txs.Receipt{},
+	},
+	"POST /unsafe/tx_signer": {
+		id: "signTx", tag: "Unsafe", summary: "Sign a CallTx with the given private accounts",
+		request: SignTxParam{}, response: txs.CallTx{},
+	},
+	// Specification
+	"GET " + openAPIPath: {
+		id: "getOpenAPI", tag: "Specification", summary: "Get this OpenAPI document",
+	},
+}
+
+// Descriptions of path parameters shared across routes
+var restPathParameters = map[string]*OpenAPIParameter{
+	"address": {
+		Description: "Hex encoded 20 byte address",
+		Schema:      &OpenAPISchema{Type: "string", Pattern: "^[0-9a-fA-F]{40}$"},
+	},
+	"key": {
+		Description: "Hex encoded storage key",
+		Schema:      &OpenAPISchema{Type: "string", Pattern: "^[0-9a-fA-F]*$"},
+	},
+	"height": {
+		Description: "Block height",
+		Schema:      &OpenAPISchema{Type: "integer", Minimum: new(int64)},
+	},
+	"id": {
+		Description: "Subscription id returned when subscribing",
+		Schema:      &OpenAPISchema{Type: "string"},
+	},
+}
+
+// Descriptions of query parameters shared across routes
+var restQueryParameters = map[string]*OpenAPIParameter{
+	"q": {
+		Description: "Space separated list of field:statement filters, for example " +
+			"'balance:>=100 code:' or 'height:10..*'",
+		Schema: &OpenAPISchema{Type: "string"},
+	},
+	"hold": {
+		Description: "Whether to wait for the transaction to be committed",
+		Schema:      &OpenAPISchema{Type: "string", Enum: []string{"true", "false"}},
+	},
+}
+
+type restOperation struct {
+	id      string
+	tag     string
+	summary string
+	// Names of query parameters read by the route's middleware
+	query []string
+	// Overrides for the descriptions of path parameters
+	pathParams map[string]string
+	// Prototypes of the request body and response, nil for none. A response
+	// that may be one of several types is given as a oneOf of their prototypes.
+	request  interface{}
+	response interface{}
+}
+
+// Prototypes of the alternative responses of a route
+type oneOf []interface{}
+
+// Subset of the OpenAPI 3.0 document object model needed to describe the
+// REST server. Refer to https://spec.openapis.org/oas/v3.0.0
+type (
+	OpenAPIDocument struct {
+		OpenAPI    string                     `json:"openapi"`
+		Info       *OpenAPIInfo               `json:"info"`
+		Paths      map[string]OpenAPIPathItem `json:"paths"`
+		Components *OpenAPIComponents         `json:"components"`
+	}
+
+	OpenAPIInfo struct {
+		Title   string `json:"title"`
+		Version string `json:"version"`
+	}
+
+	// Maps lower case http method to operation
+	OpenAPIPathItem map[string]*OpenAPIOperation
+
+	OpenAPIOperation struct {
+		OperationId string                      `json:"operationId"`
+		Summary     string                      `json:"summary,omitempty"`
+		Tags        []string                    `json:"tags,omitempty"`
+		Parameters  []*OpenAPIParameter         `json:"parameters,omitempty"`
+		RequestBody *OpenAPIRequestBody         `json:"requestBody,omitempty"`
+		Responses   map[string]*OpenAPIResponse `json:"responses"`
+	}
+
+	OpenAPIParameter struct {
+		Name        string         `json:"name"`
+		In          string         `json:"in"`
+		Description string         `json:"description,omitempty"`
+		Required    bool           `json:"required,omitempty"`
+		Schema      *OpenAPISchema `json:"schema,omitempty"`
+	}
+
+	OpenAPIRequestBody struct {
+		Required bool                         `json:"required,omitempty"`
+		Content  map[string]*OpenAPIMediaType `json:"content"`
+	}
+
+	OpenAPIResponse struct {
+		Description string                       `json:"description"`
+		Content     map[string]*OpenAPIMediaType `json:"content,omitempty"`
+	}
+
+	OpenAPIMediaType struct {
+		Schema *OpenAPISchema `json:"schema"`
+	}
+
+	OpenAPIComponents struct {
+		Schemas map[string]*OpenAPISchema `json:"schemas"`
+	}
+
+	OpenAPISchema struct {
+		Ref                  string                    `json:"$ref,omitempty"`
+		Type                 string                    `json:"type,omitempty"`
+		Format               string                    `json:"format,omitempty"`
+		Pattern              string                    `json:"pattern,omitempty"`
+		Description          string                    `json:"description,omitempty"`
+		Enum                 []string                  `json:"enum,omitempty"`
+		Minimum              *int64                    `json:"minimum,omitempty"`
+		Items                *OpenAPISchema            `json:"items,omitempty"`
+		Properties           map[string]*OpenAPISchema `json:"properties,omitempty"`
+		AdditionalProperties *OpenAPISchema            `json:"additionalProperties,omitempty"`
+		OneOf                []*OpenAPISchema          `json:"oneOf,omitempty"`
+	}
+)
+
+func (restServer *RestServer) handleOpenAPI(c *gin.Context) {
+	doc, err := NewOpenAPIDocument(restServer.routes)
+	if err != nil {
+		c.AbortWithError(500, err)
+		return
+	}
+	c.JSON(200, doc)
+}
+
+// Generate the OpenAPI document describing routes. Returns an error if a route
+// has no entry in restOperations or an entry does not match any route.
+func NewOpenAPIDocument(routes gin.RoutesInfo) (*OpenAPIDocument, error) {
+	schemas := newSchemaGenerator()
+	doc := &OpenAPIDocument{
+		OpenAPI: "3.0.0",
+		Info: &OpenAPIInfo{
+			Title:   "Burrow REST API",
+			Version: version.GetBurrowVersion().GetVersionString(),
+		},
+		Paths:      make(map[string]OpenAPIPathItem),
+		Components: &OpenAPIComponents{Schemas: schemas.components},
+	}
+	documented := make(map[string]bool)
+	for _, route := range routes {
+		key := route.Method + " " + route.Path
+		op, ok := restOperations[key]
+		if !ok {
+			return nil, fmt.Errorf("Route %s has no OpenAPI operation in "+
+				"restOperations", key)
+		}
+		documented[key] = true
+		openAPIPath, operation := op.toOpenAPI(route.Path, schemas)
+		pathItem, ok := doc.Paths[openAPIPath]
+		if !ok {
+			pathItem = make(OpenAPIPathItem)
+			doc.Paths[openAPIPath] = pathItem
+		}
+		pathItem[strings.ToLower(route.Method)] = operation
+	}
+	var stale []string
+	for key := range restOperations {
+		if !documented[key] {
+			stale = append(stale, key)
+		}
+	}
+	if len(stale) > 0 {
+		sort.Strings(stale)
+		return nil, fmt.Errorf("OpenAPI operations in restOperations do not "+
+			"match any route: %s", strings.Join(stale, ", "))
+	}
+	return doc, nil
+}
+
+// Returns the routes in after that are not in before
+func addedRoutes(before, after gin.RoutesInfo) gin.RoutesInfo {
+	existing := make(map[string]bool, len(before))
+	for _, route := range before {
+		existing[route.Method+" "+route.Path] = true
+	}
+	var added gin.RoutesInfo
+	for _, route := range after {
+		if !existing[route.Method+" "+route.Path] {
+			added = append(added, route)
+		}
+	}
+	return added
+}
+
+// Convert a gin path and its operation description to an OpenAPI path and
+// operation
+func (op *restOperation) toOpenAPI(ginPath string,
+	schemas *schemaGenerator) (string, *OpenAPIOperation) {
+	operation := &OpenAPIOperation{
+		OperationId: op.id,
+		Summary:     op.summary,
+		Tags:        []string{op.tag},
+		Responses: map[string]*OpenAPIResponse{
+			"200": {Description: "OK"},
+		},
+	}
+	segments := strings.Split(ginPath, "/")
+	for i, segment := range segments {
+		if !strings.HasPrefix(segment, ":") {
+			continue
+		}
+		name := segment[1:]
+		segments[i] = "{" + name + "}"
+		param := &OpenAPIParameter{
+			Name:     name,
+			In:       "path",
+			Required: true,
+			Schema:   &OpenAPISchema{Type: "string"},
+		}
+		if shared, ok := restPathParameters[name]; ok {
+			param.Description = shared.Description
+			param.Schema = shared.Schema
+		}
+		if description, ok := op.pathParams[name]; ok {
+			param.Description = description
+			param.Schema = &OpenAPISchema{Type: "string"}
+		}
+		operation.Parameters = append(operation.Parameters, param)
+	}
+	for _, name := range op.query {
+		param := &OpenAPIParameter{Name: name, In: "query"}
+		if shared, ok := restQueryParameters[name]; ok {
+			param.Description = shared.Description
+			param.Schema = shared.Schema
+		}
+		operation.Parameters = append(operation.Parameters, param)
+	}
+	if op.request != nil {
+		operation.RequestBody = &OpenAPIRequestBody{
+			Required: true,
+			Content: map[string]*OpenAPIMediaType{
+				"application/json": {Schema: schemas.schemaOf(reflect.TypeOf(op.request))},
+			},
+		}
+	}
+	if op.response != nil {
+		var schema *OpenAPISchema
+		if alternatives, ok := op.response.(oneOf); ok {
+			schema = &OpenAPISchema{}
+			for _, alternative := range alternatives {
+				schema.OneOf = append(schema.OneOf, schemas.schemaOf(reflect.TypeOf(alternative)))
+			}
+		} else {
+			schema = schemas.schemaOf(reflect.TypeOf(op.response))
+		}
+		operation.Responses["200"].Content = map[string]*OpenAPIMediaType{
+			"application/json": {Schema: schema},
+		}
+	}
+	return strings.Join(segments, "/"), operation
+}
+
+// Builds schemas for Go types by reflection, following the JSON encoding of
+// go-wire used by the REST server. Named struct types are collected as
+// components and referenced.
+type schemaGenerator struct {
+	components map[string]*OpenAPISchema
+	names      map[reflect.Type]string
+}
+
+func newSchemaGenerator() *schemaGenerator {
+	return &schemaGenerator{
+		components: make(map[string]*OpenAPISchema),
+		names:      make(map[reflect.Type]string),
+	}
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+func (sg *schemaGenerator) schemaOf(rt reflect.Type) *OpenAPISchema {
+	if rt == timeType {
+		return &OpenAPISchema{Type: "string", Format: "date-time"}
+	}
+	switch rt.Kind() {
+	case reflect.Ptr:
+		return sg.schemaOf(rt.Elem())
+	case reflect.Bool:
+		return &OpenAPISchema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &OpenAPISchema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &OpenAPISchema{Type: "number"}
+	case reflect.String:
+		return &OpenAPISchema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if rt.Elem().Kind() == reflect.Uint8 {
+			return &OpenAPISchema{Type: "string", Format: "hex",
+				Pattern: "^[0-9A-F]*$"}
+		}
+		return &OpenAPISchema{Type: "array", Items: sg.schemaOf(rt.Elem())}
+	case reflect.Map:
+		return &OpenAPISchema{Type: "object",
+			AdditionalProperties: sg.schemaOf(rt.Elem())}
+	case reflect.Interface:
+		return &OpenAPISchema{Description: "go-wire encoded interface: a " +
+			"[type byte, value] array, the type byte identifying the concrete type"}
+	case reflect.Struct:
+		return sg.structSchema(rt)
+	}
+	return &OpenAPISchema{}
+}
+
+func (sg *schemaGenerator) structSchema(rt reflect.Type) *OpenAPISchema {
+	if rt.Name() == "" {
+		return sg.objectSchema(rt)
+	}
+	name, ok := sg.names[rt]
+	if !ok {
+		name = sg.componentName(rt)
+		sg.names[rt] = name
+		// Reserve the name before recursing so self-referencing types terminate
+		sg.components[name] = &OpenAPISchema{}
+		*sg.components[name] = *sg.objectSchema(rt)
+	}
+	return &OpenAPISchema{Ref: "#/components/schemas/" + name}
+}
+
+func (sg *schemaGenerator) objectSchema(rt reflect.Type) *OpenAPISchema {
+	schema := &OpenAPISchema{
+		Type:       "object",
+		Properties: make(map[string]*OpenAPISchema),
+	}
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		if field.PkgPath != "" && !field.Anonymous {
+			// unexported
+			continue
+		}
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "-" {
+			continue
+		}
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				for property, propertySchema := range sg.objectSchema(embedded).Properties {
+					schema.Properties[property] = propertySchema
+				}
+				continue
+			}
+		}
+		if name == "" {
+			name = field.Name
+		}
+		schema.Properties[name] = sg.schemaOf(field.Type)
+	}
+	return schema
+}
+
+// Name components after their package and type, using the parent package for
+// the several packages called 'types' (e.g. core.AccountList)
+func (sg *schemaGenerator) componentName(rt reflect.Type) string {
+	pkg := path.Base(rt.PkgPath())
+	if pkg == "types" {
+		pkg = path.Base(path.Dir(rt.PkgPath()))
+	}
+	name := pkg + "." + rt.Name()
+	// Disambiguate in the unlikely event of a clash
+	for i := 2; ; i++ {
+		if _, taken := sg.components[name]; !taken {
+			return name
+		}
+		name = fmt.Sprintf("%s.%s%d", pkg, rt.Name(), i)
+	}
+}
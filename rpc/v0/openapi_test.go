@@ -0,0 +1,100 @@
+// Copyright 2017 Monax Industries Limited
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v0
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	event "github.com/hyperledger/burrow/event"
+	server "github.com/hyperledger/burrow/server"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func startTestRestServer() (*RestServer, *gin.Engine) {
+	gin.SetMode(gin.ReleaseMode)
+	pipe := NewMockPipe(LoadTestData())
+	restServer := NewRestServer(NewTCodec(), pipe,
+		event.NewEventSubscriptions(pipe.Events()))
+	router := gin.New()
+	// A route belonging to another server on the shared router
+	router.POST("/rpc", func(c *gin.Context) {})
+	restServer.Start(server.DefaultServerConfig(), router)
+	return restServer, router
+}
+
+// Fails when a route is added to the REST server without describing it in
+// restOperations (or a route is removed and its operation left behind)
+func TestOpenAPIDocumentsAllRoutes(t *testing.T) {
+	restServer, _ := startTestRestServer()
+	for _, route := range restServer.routes {
+		_, ok := restOperations[route.Method+" "+route.Path]
+		assert.True(t, ok, "Route %s %s has no OpenAPI operation", route.Method,
+			route.Path)
+	}
+	assert.Len(t, restServer.routes, len(restOperations))
+
+	doc, err := NewOpenAPIDocument(restServer.routes)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "3.0.0", doc.OpenAPI)
+		assert.NotContains(t, doc.Paths, "/rpc")
+		pathItem := doc.Paths["/accounts/{address}/storage/{key}"]
+		if assert.NotNil(t, pathItem["get"]) {
+			assert.Equal(t, "getStorageAt", pathItem["get"].OperationId)
+			assert.Len(t, pathItem["get"].Parameters, 2)
+		}
+		assert.Contains(t, doc.Components.Schemas, "core.AccountList")
+
+		// Holding a transaction responds with its call event rather than a receipt
+		transact := doc.Paths["/unsafe/txpool"]["post"]
+		if assert.NotNil(t, transact) {
+			schema := transact.Responses["200"].Content["application/json"].Schema
+			if assert.Len(t, schema.OneOf, 2) {
+				assert.Equal(t, "#/components/schemas/txs.Receipt", schema.OneOf[0].Ref)
+				assert.Equal(t, "#/components/schemas/txs.EventDataCall", schema.OneOf[1].Ref)
+			}
+			assert.Contains(t, doc.Components.Schemas, "txs.Receipt")
+			assert.Contains(t, doc.Components.Schemas, "txs.EventDataCall")
+		}
+	}
+}
+
+func TestOpenAPIDocumentRejectsUndocumentedRoute(t *testing.T) {
+	restServer, _ := startTestRestServer()
+	routes := append(restServer.routes,
+		gin.RouteInfo{Method: "GET", Path: "/undocumented"})
+	_, err := NewOpenAPIDocument(routes)
+	assert.Error(t, err)
+
+	_, err = NewOpenAPIDocument(restServer.routes[1:])
+	assert.Error(t, err)
+}
+
+func TestServeOpenAPI(t *testing.T) {
+	_, router := startTestRestServer()
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest("GET", openAPIPath, nil)
+	assert.NoError(t, err)
+	router.ServeHTTP(recorder, request)
+	assert.Equal(t, 200, recorder.Code)
+
+	doc := new(OpenAPIDocument)
+	assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), doc))
+	assert.Contains(t, doc.Paths, "/blockchain/block/{height}")
+}
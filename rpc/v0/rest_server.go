@@ -41,7 +41,9 @@ type RestServer struct {
 	pipe          definitions.Pipe
 	eventSubs     *event.EventSubscriptions
 	filterFactory *event.FilterFactory
-	running       bool
+	// Routes registered by this server, used to generate the OpenAPI document
+	routes  gin.RoutesInfo
+	running bool
 }
 
 // Create a new rest server.
@@ -57,6 +59,8 @@ func NewRestServer(codec rpc.Codec, pipe definitions.Pipe,
 
 // Starting the server means registering all the handlers with the router.
 func (restServer *RestServer) Start(config *server.ServerConfig, router *gin.Engine) {
+	// The router is shared with other servers
+	existingRoutes := router.Routes()
 	// Accounts
 	router.GET("/accounts", parseSearchQuery, restServer.handleAccounts)
 	router.GET("/accounts/:address", addressParam, restServer.handleAccount)
@@ -99,6 +103,9 @@ func (restServer *RestServer) Start(config *server.ServerConfig, router *gin.Eng
 	router.POST("/unsafe/txpool", parseTxModifier, restServer.handleTransact)
 	router.POST("/unsafe/namereg/txpool", restServer.handleTransactNameReg)
 	router.POST("/unsafe/tx_signer", restServer.handleSignTx)
+	// Specification
+	router.GET(openAPIPath, restServer.handleOpenAPI)
+	restServer.routes = addedRoutes(existingRoutes, router.Routes())
 	restServer.running = true
 }
 
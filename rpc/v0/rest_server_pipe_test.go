@@ -304,6 +304,10 @@ func (trans *transactor) SendAndHold(privKey, toAddress []byte, amount int64) (*
 	return nil, nil
 }
 
+func (trans *transactor) SendMulti(privKeys [][]byte, amounts []int64, outputs []*txs.TxOutput) (*txs.Receipt, error) {
+	return nil, nil
+}
+
 func (trans *transactor) TransactNameReg(privKey []byte, name, data string, amount, fee int64) (*txs.Receipt, error) {
 	return trans.testData.TransactNameReg.Output, nil
 }
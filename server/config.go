@@ -21,6 +21,10 @@ import (
 	viper "github.com/spf13/viper"
 )
 
+// Maximum number of requests in a JSON-RPC batch when the configuration
+// does not set one
+const DefaultMaxBatchSize = 100
+
 type (
 	ServerConfig struct {
 		ChainId    string
@@ -30,6 +34,7 @@ type (
 		HTTP       HTTP      `toml:"HTTP"`
 		WebSocket  WebSocket `toml:"web_socket"`
 		Tendermint Tendermint
+		Grpc       Grpc `toml:"grpc"`
 	}
 
 	Bind struct {
@@ -56,6 +61,8 @@ type (
 
 	HTTP struct {
 		JsonRpcEndpoint string `toml:"json_rpc_endpoint"`
+		// Maximum number of requests in a JSON-RPC batch, 0 means no limit
+		MaxBatchSize uint16 `toml:"max_batch_size"`
 	}
 
 	WebSocket struct {
@@ -68,6 +75,12 @@ type (
 	Tendermint struct {
 		RpcLocalAddress string
 		Endpoint        string
+		MaxBatchSize    uint16
+	}
+
+	Grpc struct {
+		Enable        bool   `toml:"enable"`
+		ListenAddress string `toml:"listen_address"`
 	}
 )
 
@@ -101,6 +114,16 @@ func ReadServerConfig(viper *viper.Viper) (*ServerConfig, error) {
 		return nil, fmt.Errorf("Failed to read maximum websocket sessions: %v",
 			maxWebsocketSessions)
 	}
+	// check domain range for http.max_batch_size
+	httpMaxBatchSize, err := readMaxBatchSize(viper, "http.max_batch_size")
+	if err != nil {
+		return nil, err
+	}
+	// check domain range for tendermint.max_batch_size
+	tendermintMaxBatchSize, err := readMaxBatchSize(viper, "tendermint.max_batch_size")
+	if err != nil {
+		return nil, err
+	}
 	// check domain range for websocket.read_buffer_size
 	readBufferSize := viper.GetInt("websocket.read_buffer_size")
 	var readBufferSizeUint64 uint64 = 0
@@ -142,6 +165,7 @@ func ReadServerConfig(viper *viper.Viper) (*ServerConfig, error) {
 		},
 		HTTP: HTTP{
 			JsonRpcEndpoint: viper.GetString("http.json_rpc_endpoint"),
+			MaxBatchSize:    httpMaxBatchSize,
 		},
 		WebSocket: WebSocket{
 			WebSocketEndpoint:    viper.GetString("websocket.endpoint"),
@@ -152,10 +176,30 @@ func ReadServerConfig(viper *viper.Viper) (*ServerConfig, error) {
 		Tendermint: Tendermint{
 			RpcLocalAddress: viper.GetString("tendermint.rpc_local_address"),
 			Endpoint:        viper.GetString("tendermint.endpoint"),
+			MaxBatchSize:    tendermintMaxBatchSize,
+		},
+		Grpc: Grpc{
+			Enable:        viper.GetBool("grpc.enable"),
+			ListenAddress: viper.GetString("grpc.listen_address"),
 		},
 	}, nil
 }
 
+// readMaxBatchSize reads the maximum batch size at key, which is
+// DefaultMaxBatchSize when not set, so that configurations from before batches
+// were limited do not read 0 and allow batches of any size
+func readMaxBatchSize(viper *viper.Viper, key string) (uint16, error) {
+	if !viper.IsSet(key) {
+		return DefaultMaxBatchSize, nil
+	}
+	maxBatchSize := viper.GetInt(key)
+	if maxBatchSize < 0 || maxBatchSize > math.MaxUint16 {
+		return 0, fmt.Errorf("Failed to read maximum batch size from %s: %v",
+			key, maxBatchSize)
+	}
+	return uint16(maxBatchSize), nil
+}
+
 // NOTE: [ben] only preserved for /test/server tests; but should not be used and
 // will be deprecated.
 func DefaultServerConfig() *ServerConfig {
@@ -171,7 +215,10 @@ func DefaultServerConfig() *ServerConfig {
 			KeyPath:  kp,
 		},
 		CORS: CORS{},
-		HTTP: HTTP{JsonRpcEndpoint: "/rpc"},
+		HTTP: HTTP{
+			JsonRpcEndpoint: "/rpc",
+			MaxBatchSize:    DefaultMaxBatchSize,
+		},
 		WebSocket: WebSocket{
 			WebSocketEndpoint:    "/socketrpc",
 			MaxWebSocketSessions: 50,
@@ -181,6 +228,11 @@ func DefaultServerConfig() *ServerConfig {
 		Tendermint: Tendermint{
 			RpcLocalAddress: "0.0.0.0:46657",
 			Endpoint:        "/websocket",
+			MaxBatchSize:    DefaultMaxBatchSize,
+		},
+		Grpc: Grpc{
+			Enable:        false,
+			ListenAddress: "0.0.0.0:10997",
 		},
 	}
 }
@@ -16,8 +16,10 @@ package server
 
 import (
 	//"fmt"
+	"math"
 	"testing"
 
+	viper "github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -53,3 +55,28 @@ func TestIdFull(t *testing.T) {
 	_, err := idPool.GetId()
 	assert.Error(t, err)
 }
+
+func TestReadMaxBatchSize(t *testing.T) {
+	config := viper.New()
+	// Configurations written before batches were limited get the default
+	maxBatchSize, err := readMaxBatchSize(config, "http.max_batch_size")
+	assert.NoError(t, err)
+	assert.Equal(t, uint16(DefaultMaxBatchSize), maxBatchSize)
+
+	// while 0 set explicitly still means no limit
+	config.Set("http.max_batch_size", 0)
+	maxBatchSize, err = readMaxBatchSize(config, "http.max_batch_size")
+	assert.NoError(t, err)
+	assert.Equal(t, uint16(0), maxBatchSize)
+
+	config.Set("http.max_batch_size", 7)
+	maxBatchSize, err = readMaxBatchSize(config, "http.max_batch_size")
+	assert.NoError(t, err)
+	assert.Equal(t, uint16(7), maxBatchSize)
+
+	for _, invalid := range []int{-1, math.MaxUint16 + 1} {
+		config.Set("http.max_batch_size", invalid)
+		_, err = readMaxBatchSize(config, "http.max_batch_size")
+		assert.Error(t, err)
+	}
+}
@@ -58,6 +58,7 @@ const (
 	EventDataTypeCall           = byte(0x04)
 	EventDataTypeLog            = byte(0x05)
 	EventDataTypeNewBlockHeader = byte(0x06)
+	EventDataTypeNameReg        = byte(0x07)
 
 	EventDataTypeRoundState = byte(0x11)
 	EventDataTypeVote       = byte(0x12)
@@ -75,6 +76,7 @@ var _ = wire.RegisterInterface(
 	wire.ConcreteType{EventDataTx{}, EventDataTypeTx},
 	wire.ConcreteType{EventDataCall{}, EventDataTypeCall},
 	wire.ConcreteType{EventDataLog{}, EventDataTypeLog},
+	wire.ConcreteType{EventDataNameReg{}, EventDataTypeNameReg},
 	wire.ConcreteType{EventDataRoundState{}, EventDataTypeRoundState},
 	wire.ConcreteType{EventDataVote{}, EventDataTypeVote},
 )
@@ -122,6 +124,17 @@ type EventDataLog struct {
 	Height  int64     `json:"height"`
 }
 
+// EventDataNameReg fires when a name registry entry is removed at the end of
+// a block because it has expired, when the unused credit of an entry
+// deleted before it expires is refunded to its owner, and when a contract
+// changes the data or owner of an entry through the name registry SNative
+type EventDataNameReg struct {
+	Name    string `json:"name"`
+	Owner   []byte `json:"owner"`
+	Expired bool   `json:"expired"`
+	Refund  int64  `json:"refund"`
+}
+
 // We fire the most recent round state that led to the event
 // (ie. NewRound will have the previous rounds state)
 type EventDataRoundState struct {
@@ -150,5 +163,6 @@ func (_ EventDataNewBlockHeader) AssertIsEventData() {}
 func (_ EventDataTx) AssertIsEventData()             {}
 func (_ EventDataCall) AssertIsEventData()           {}
 func (_ EventDataLog) AssertIsEventData()            {}
+func (_ EventDataNameReg) AssertIsEventData()        {}
 func (_ EventDataRoundState) AssertIsEventData()     {}
 func (_ EventDataVote) AssertIsEventData()           {}
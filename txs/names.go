@@ -15,24 +15,20 @@
 package txs
 
 import (
+	"encoding/hex"
+	"fmt"
 	"regexp"
+	"strings"
+	"unicode/utf8"
 
 	core_types "github.com/hyperledger/burrow/core/types"
 )
 
 var (
-	MinNameRegistrationPeriod int = 5
-
 	// NOTE: base costs and validity checks are here so clients
 	// can use them without importing state
 
-	// cost for storing a name for a block is
-	// CostPerBlock*CostPerByte*(len(data) + 32)
-	NameByteCostMultiplier  int64 = 1
-	NameBlockCostMultiplier int64 = 1
-
 	MaxNameLength = 64
-	MaxDataLength = 1 << 16
 
 	// Name should be file system lik
 	// Data should be anything permitted in JSON
@@ -40,13 +36,162 @@ var (
 	regexpJSON     = regexp.MustCompile(`^[a-zA-Z0-9_/ \-+"':,\n\t.{}()\[\]]*$`)
 )
 
+// How the data stored in a name registry entry is validated
+const (
+	// Only the restrictive subset of characters found in simple JSON
+	NameDataValidationJSON = "json"
+	// Any valid UTF-8, so real JSON documents can be stored
+	NameDataValidationUTF8 = "utf8"
+	// Any bytes at all, hex encoded in NameTx.Data and so stored, since
+	// transactions are signed and sent as JSON, which only holds UTF-8
+	NameDataValidationBinary = "binary"
+)
+
+// The economics and validity checks of the name registry, set per chain by
+// the genesis params (see GenesisNameRegParams)
+type NameRegParams struct {
+	// cost for storing a name for a block is
+	// BlockCostMultiplier*ByteCostMultiplier*(len(data) + 32)
+	ByteCostMultiplier  int64 `json:"byte_cost_multiplier"`
+	BlockCostMultiplier int64 `json:"block_cost_multiplier"`
+	// Minimum number of blocks a name can be registered for
+	MinRegistrationPeriod int `json:"min_registration_period"`
+	MaxDataLength         int `json:"max_data_length"`
+	// Maximum number of expired entries removed at the end of each block. A
+	// limit of 0 or less, the default, disables the removal of expired entries
+	// so that chains made before it keep the entries their blocks kept.
+	ExpiredRemovalLimit int `json:"expired_removal_limit"`
+	// One of json (the default), utf8, or binary
+	DataValidation string `json:"data_validation,omitempty"`
+}
+
+var DefaultNameRegParams = NameRegParams{
+	ByteCostMultiplier:    1,
+	BlockCostMultiplier:   1,
+	MinRegistrationPeriod: 5,
+	MaxDataLength:         1 << 16,
+	DataValidation:        NameDataValidationJSON,
+}
+
+// Checks that params describe a usable name registry
+func (params NameRegParams) Validate() error {
+	// the cost per block divides the credit of an entry to give its expiry
+	if params.ByteCostMultiplier <= 0 {
+		return fmt.Errorf("Name registry byte cost multiplier must be positive "+
+			"but is %v", params.ByteCostMultiplier)
+	}
+	if params.BlockCostMultiplier <= 0 {
+		return fmt.Errorf("Name registry block cost multiplier must be positive "+
+			"but is %v", params.BlockCostMultiplier)
+	}
+	if params.MinRegistrationPeriod < 0 {
+		return fmt.Errorf("Name registry minimum registration period must not "+
+			"be negative but is %v", params.MinRegistrationPeriod)
+	}
+	if params.MaxDataLength < 0 {
+		return fmt.Errorf("Name registry maximum data length must not be "+
+			"negative but is %v", params.MaxDataLength)
+	}
+	switch params.DataValidation {
+	case NameDataValidationJSON, NameDataValidationUTF8, NameDataValidationBinary:
+	default:
+		return fmt.Errorf("Unknown name registry data validation '%s', must be "+
+			"one of %s, %s, or %s", params.DataValidation, NameDataValidationJSON,
+			NameDataValidationUTF8, NameDataValidationBinary)
+	}
+	return nil
+}
+
+// The name registry params as given in the genesis params. Fields that are not
+// set (nil or, for DataValidation, empty) take their values from
+// DefaultNameRegParams, so a genesis need only give the params it changes and
+// may still set any of them to zero.
+type GenesisNameRegParams struct {
+	ByteCostMultiplier    *int64 `json:"byte_cost_multiplier,omitempty"`
+	BlockCostMultiplier   *int64 `json:"block_cost_multiplier,omitempty"`
+	MinRegistrationPeriod *int   `json:"min_registration_period,omitempty"`
+	MaxDataLength         *int   `json:"max_data_length,omitempty"`
+	ExpiredRemovalLimit   *int   `json:"expired_removal_limit,omitempty"`
+	DataValidation        string `json:"data_validation,omitempty"`
+}
+
+// Get the params with any unset fields taken from DefaultNameRegParams
+func (genesisParams *GenesisNameRegParams) NameRegParams() NameRegParams {
+	params := DefaultNameRegParams
+	if genesisParams == nil {
+		return params
+	}
+	if genesisParams.ByteCostMultiplier != nil {
+		params.ByteCostMultiplier = *genesisParams.ByteCostMultiplier
+	}
+	if genesisParams.BlockCostMultiplier != nil {
+		params.BlockCostMultiplier = *genesisParams.BlockCostMultiplier
+	}
+	if genesisParams.MinRegistrationPeriod != nil {
+		params.MinRegistrationPeriod = *genesisParams.MinRegistrationPeriod
+	}
+	if genesisParams.MaxDataLength != nil {
+		params.MaxDataLength = *genesisParams.MaxDataLength
+	}
+	if genesisParams.ExpiredRemovalLimit != nil {
+		params.ExpiredRemovalLimit = *genesisParams.ExpiredRemovalLimit
+	}
+	if genesisParams.DataValidation != "" {
+		params.DataValidation = genesisParams.DataValidation
+	}
+	return params
+}
+
+// Checks that the params (with defaults applied) describe a usable name
+// registry
+func (genesisParams *GenesisNameRegParams) Validate() error {
+	return genesisParams.NameRegParams().Validate()
+}
+
+// Names are file system like: the owner of a name controls the names nested
+// beneath it after a NameSeparator, so the owner of a may create, revoke and
+// transfer a/b and a/b/c
+const NameSeparator = "/"
+
+// Get the names a name is nested beneath, nearest first, so a/b/c gives a/b
+// then a. Empty segments are not names so /a gives none.
+func NameAncestors(name string) []string {
+	var ancestors []string
+	for i := strings.LastIndex(name, NameSeparator); i > 0; i = strings.LastIndex(name, NameSeparator) {
+		name = name[:i]
+		if strings.HasSuffix(name, NameSeparator) {
+			// a//b has no name a/ between a and a//b
+			continue
+		}
+		ancestors = append(ancestors, name)
+	}
+	return ancestors
+}
+
 // filter strings
 func validateNameRegEntryName(name string) bool {
 	return regexpAlphaNum.Match([]byte(name))
 }
 
-func validateNameRegEntryData(data string) bool {
-	return regexpJSON.Match([]byte(data))
+func validateNameRegEntryData(params NameRegParams, data string) error {
+	if len(data) > params.MaxDataLength {
+		return ErrTxInvalidString{fmt.Sprintf("Data is too long. Max %d bytes", params.MaxDataLength)}
+	}
+	switch params.DataValidation {
+	case NameDataValidationBinary:
+		if _, err := hex.DecodeString(data); err != nil {
+			return ErrTxInvalidString{"NameTx.Data must be hex encoded bytes"}
+		}
+	case NameDataValidationUTF8:
+		if !utf8.ValidString(data) {
+			return ErrTxInvalidString{"NameTx.Data is not valid UTF-8"}
+		}
+	default:
+		if !regexpJSON.Match([]byte(data)) {
+			return ErrTxInvalidString{fmt.Sprintf("Invalid characters found in NameTx.Data (%s). Only the kind of things found in a JSON file are allowed", data)}
+		}
+	}
+	return nil
 }
 
 // base cost is "effective" number of bytes
@@ -54,8 +199,8 @@ func NameBaseCost(name, data string) int64 {
 	return int64(len(data) + 32)
 }
 
-func NameCostPerBlock(baseCost int64) int64 {
-	return NameBlockCostMultiplier * NameByteCostMultiplier * baseCost
+func (params NameRegParams) CostPerBlock(baseCost int64) int64 {
+	return params.BlockCostMultiplier * params.ByteCostMultiplier * baseCost
 }
 
 // XXX: vestige of an older time
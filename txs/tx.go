@@ -67,6 +67,7 @@ Account Txs:
  - SendTx         Send coins to address
  - CallTx         Send a msg to a contract that runs in the vm
  - NameTx	  Store some value under a name in the global namereg
+ - NameTransferTx Hand a name in the global namereg to another account
 
 Validation Txs:
  - BondTx         New validator posts a bond
@@ -84,6 +85,8 @@ const (
 	TxTypeCall = byte(0x02)
 	TxTypeName = byte(0x03)
 
+	TxTypeNameTransfer = byte(0x04)
+
 	// Validation transactions
 	TxTypeBond    = byte(0x11)
 	TxTypeUnbond  = byte(0x12)
@@ -100,6 +103,7 @@ var _ = wire.RegisterInterface(
 	wire.ConcreteType{&SendTx{}, TxTypeSend},
 	wire.ConcreteType{&CallTx{}, TxTypeCall},
 	wire.ConcreteType{&NameTx{}, TxTypeName},
+	wire.ConcreteType{&NameTransferTx{}, TxTypeNameTransfer},
 	wire.ConcreteType{&BondTx{}, TxTypeBond},
 	wire.ConcreteType{&UnbondTx{}, TxTypeUnbond},
 	wire.ConcreteType{&RebondTx{}, TxTypeRebond},
@@ -138,6 +142,14 @@ type (
 		Fee   int64    `json:"fee"`
 	}
 
+	// Transfers a name to NewOwner. Can be sent by the owner of the name or the
+	// owner of any name it is nested beneath (see NameAncestors)
+	NameTransferTx struct {
+		Input    *TxInput `json:"input"`
+		Name     string   `json:"name"`
+		NewOwner []byte   `json:"new_owner"`
+	}
+
 	CallTx struct {
 		Input    *TxInput `json:"input"`
 		Address  []byte   `json:"address"`
@@ -257,30 +269,52 @@ func (tx *NameTx) WriteSignBytes(chainID string, w io.Writer, n *int, err *error
 	wire.WriteTo([]byte(`}]}`), w, n, err)
 }
 
-func (tx *NameTx) ValidateStrings() error {
-	if len(tx.Name) == 0 {
+func (tx *NameTx) ValidateStrings(params NameRegParams) error {
+	if err := validateName("NameTx", tx.Name); err != nil {
+		return err
+	}
+	return validateNameRegEntryData(params, tx.Data)
+}
+
+func (tx *NameTx) String() string {
+	return Fmt("NameTx{%v -> %s: %s}", tx.Input, tx.Name, tx.Data)
+}
+
+func validateName(txName, name string) error {
+	if len(name) == 0 {
 		return ErrTxInvalidString{"Name must not be empty"}
 	}
-	if len(tx.Name) > MaxNameLength {
+	if len(name) > MaxNameLength {
 		return ErrTxInvalidString{Fmt("Name is too long. Max %d bytes", MaxNameLength)}
 	}
-	if len(tx.Data) > MaxDataLength {
-		return ErrTxInvalidString{Fmt("Data is too long. Max %d bytes", MaxDataLength)}
+	if !validateNameRegEntryName(name) {
+		return ErrTxInvalidString{Fmt("Invalid characters found in %s.Name (%s). Only alphanumeric, underscores, dashes, forward slashes, and @ are allowed", txName, name)}
 	}
+	return nil
+}
 
-	if !validateNameRegEntryName(tx.Name) {
-		return ErrTxInvalidString{Fmt("Invalid characters found in NameTx.Name (%s). Only alphanumeric, underscores, dashes, forward slashes, and @ are allowed", tx.Name)}
-	}
+//-----------------------------------------------------------------------------
 
-	if !validateNameRegEntryData(tx.Data) {
-		return ErrTxInvalidString{Fmt("Invalid characters found in NameTx.Data (%s). Only the kind of things found in a JSON file are allowed", tx.Data)}
-	}
+func (tx *NameTransferTx) WriteSignBytes(chainID string, w io.Writer, n *int, err *error) {
+	wire.WriteTo([]byte(Fmt(`{"chain_id":%s`, jsonEscape(chainID))), w, n, err)
+	wire.WriteTo([]byte(Fmt(`,"tx":[%v,{"input":`, TxTypeNameTransfer)), w, n, err)
+	tx.Input.WriteSignBytes(w, n, err)
+	wire.WriteTo([]byte(Fmt(`,"name":%s,"new_owner":"%X"`, jsonEscape(tx.Name), tx.NewOwner)), w, n, err)
+	wire.WriteTo([]byte(`}]}`), w, n, err)
+}
 
+func (tx *NameTransferTx) ValidateStrings() error {
+	if err := validateName("NameTransferTx", tx.Name); err != nil {
+		return err
+	}
+	if len(tx.NewOwner) != 20 {
+		return ErrTxInvalidAddress
+	}
 	return nil
 }
 
-func (tx *NameTx) String() string {
-	return Fmt("NameTx{%v -> %s: %s}", tx.Input, tx.Name, tx.Data)
+func (tx *NameTransferTx) String() string {
+	return Fmt("NameTransferTx{%v -> %s: %X}", tx.Input, tx.Name, tx.NewOwner)
 }
 
 //-----------------------------------------------------------------------------
@@ -425,6 +459,25 @@ func DecodeTx(txBytes []byte) (Tx, error) {
 	return *tx, nil
 }
 
+// EncodeTxJSON encodes tx as go-wire JSON, which records the tx type so that
+// DecodeTxJSON can recover it
+func EncodeTxJSON(tx Tx) []byte {
+	return wire.JSONBytes(&tx)
+}
+
+func DecodeTxJSON(txJSON []byte) (Tx, error) {
+	var err error
+	tx := new(Tx)
+	wire.ReadJSONPtr(tx, txJSON, &err)
+	if err != nil {
+		return nil, err
+	}
+	if *tx == nil {
+		return nil, errors.New("No transaction in JSON")
+	}
+	return *tx, nil
+}
+
 func GenerateReceipt(chainId string, tx Tx) Receipt {
 	receipt := Receipt{
 		TxHash:          TxHash(chainId, tx),
@@ -103,6 +103,83 @@ func TestNameTxSignable(t *testing.T) {
 	}
 }
 
+func TestNameTransferTxSignable(t *testing.T) {
+	nameTransferTx := &NameTransferTx{
+		Input: &TxInput{
+			Address:  []byte("input1"),
+			Amount:   12345,
+			Sequence: 250,
+		},
+		Name:     "google.com/maps",
+		NewOwner: []byte("newowner"),
+	}
+	signBytes := acm.SignBytes(chainID, nameTransferTx)
+	signStr := string(signBytes)
+	expected := Fmt(`{"chain_id":"%s","tx":[4,{"input":{"address":"696E70757431","amount":12345,"sequence":250},"name":"google.com/maps","new_owner":"6E65776F776E6572"}]}`,
+		chainID)
+	if signStr != expected {
+		t.Errorf("Got unexpected sign string for NameTransferTx. Expected:\n%v\nGot:\n%v", expected, signStr)
+	}
+}
+
+func TestNameAncestors(t *testing.T) {
+	assert.Equal(t, []string{"a/b", "a"}, NameAncestors("a/b/c"))
+	assert.Equal(t, []string{"a"}, NameAncestors("a//b"))
+	assert.Empty(t, NameAncestors("a"))
+	assert.Empty(t, NameAncestors("/a"))
+}
+
+func TestNameTxValidateStrings(t *testing.T) {
+	document := &NameTx{Name: "doc", Data: `{"greeting": "héllo & <welcome>!"}`}
+	assert.Error(t, document.ValidateStrings(DefaultNameRegParams))
+	utf8Params := DefaultNameRegParams
+	utf8Params.DataValidation = NameDataValidationUTF8
+	assert.NoError(t, document.ValidateStrings(utf8Params))
+
+	binary := &NameTx{Name: "bin", Data: string([]byte{0x00, 0xff, 0xfe})}
+	assert.Error(t, binary.ValidateStrings(utf8Params))
+	binaryParams := DefaultNameRegParams
+	binaryParams.DataValidation = NameDataValidationBinary
+	// binary data must be hex encoded to survive signing and JSON
+	assert.Error(t, binary.ValidateStrings(binaryParams))
+	binary.Data = "00FFFE"
+	assert.NoError(t, binary.ValidateStrings(binaryParams))
+
+	binaryParams.MaxDataLength = 2
+	assert.Error(t, binary.ValidateStrings(binaryParams))
+}
+
+func TestGenesisNameRegParams(t *testing.T) {
+	var genesisParams *GenesisNameRegParams
+	assert.Equal(t, DefaultNameRegParams, genesisParams.NameRegParams())
+	// expired entries are only removed when a chain enables it
+	assert.Equal(t, 0, genesisParams.NameRegParams().ExpiredRemovalLimit)
+	limit := 50
+	genesisParams = &GenesisNameRegParams{ExpiredRemovalLimit: &limit}
+	assert.Equal(t, limit, genesisParams.NameRegParams().ExpiredRemovalLimit)
+
+	// zero is kept where it is set rather than taking the default
+	zero, zero64 := 0, int64(0)
+	genesisParams = &GenesisNameRegParams{
+		MinRegistrationPeriod: &zero,
+		MaxDataLength:         &zero,
+		DataValidation:        NameDataValidationUTF8,
+	}
+	params := genesisParams.NameRegParams()
+	assert.Equal(t, 0, params.MinRegistrationPeriod)
+	assert.Equal(t, 0, params.MaxDataLength)
+	assert.Equal(t, NameDataValidationUTF8, params.DataValidation)
+	assert.Equal(t, DefaultNameRegParams.ByteCostMultiplier, params.ByteCostMultiplier)
+	assert.NoError(t, genesisParams.Validate())
+
+	// but costs must be positive since they divide an entry's credit
+	genesisParams.ByteCostMultiplier = &zero64
+	assert.Error(t, genesisParams.Validate())
+	negative := -1
+	assert.Error(t, (&GenesisNameRegParams{MaxDataLength: &negative}).Validate())
+	assert.Error(t, (&GenesisNameRegParams{DataValidation: "xml"}).Validate())
+}
+
 func TestBondTxSignable(t *testing.T) {
 	privKeyBytes := make([]byte, 64)
 	privAccount := acm.GenPrivAccountFromPrivKeyBytes(privKeyBytes)
@@ -189,6 +266,21 @@ func TestPermissionsTxSignable(t *testing.T) {
 	if signStr != expected {
 		t.Errorf("Got unexpected sign string for PermsTx. Expected:\n%v\nGot:\n%v", expected, signStr)
 	}
+
+	// Grants that lapse have their own type byte so the encoding of those that
+	// do not is unchanged
+	permsTx.PermArgs = &ptypes.SetBaseUntilArgs{
+		Address:    []byte("address1"),
+		Permission: 1,
+		Value:      true,
+		Expires:    20,
+	}
+	signStr = string(acm.SignBytes(chainID, permsTx))
+	expected = Fmt(`{"chain_id":"%s","tx":[32,{"args":"[13,{"address":"6164647265737331","permission":1,"value":true,"expires":20}]","input":{"address":"696E70757431","amount":12345,"sequence":250}}]}`,
+		chainID)
+	if signStr != expected {
+		t.Errorf("Got unexpected sign string for PermsTx. Expected:\n%v\nGot:\n%v", expected, signStr)
+	}
 }
 
 func TestEncodeTxDecodeTx(t *testing.T) {
@@ -216,6 +308,24 @@ func TestEncodeTxDecodeTx(t *testing.T) {
 	assert.Equal(t, tx, txOut)
 }
 
+func TestEncodeTxJSONDecodeTxJSON(t *testing.T) {
+	privAccount := acm.GenPrivAccountFromSecret("encodeJSON")
+	tx := NewCallTxWithNonce(privAccount.PubKey, []byte{1, 2, 3}, []byte{4, 5},
+		10, 1000, 1, 7)
+	txOut, err := DecodeTxJSON(EncodeTxJSON(tx))
+	assert.NoError(t, err, "DecodeTxJSON error")
+	assert.Equal(t, tx, txOut)
+	assert.Equal(t, TxHash(chainID, tx), TxHash(chainID, txOut))
+
+	tx.Input.Signature = privAccount.Sign(chainID, tx)
+	txOut, err = DecodeTxJSON(EncodeTxJSON(tx))
+	assert.NoError(t, err, "DecodeTxJSON error")
+	assert.Equal(t, tx, txOut)
+
+	_, err = DecodeTxJSON([]byte("null"))
+	assert.Error(t, err)
+}
+
 /*
 func TestDupeoutTxSignable(t *testing.T) {
 	privAcc := acm.GenPrivAccount()
@@ -150,6 +150,44 @@ func (tx *NameTx) Sign(chainID string, privAccount *acm.PrivAccount) {
 	tx.Input.Signature = privAccount.Sign(chainID, tx)
 }
 
+//----------------------------------------------------------------------------
+// NameTransferTx interface for creating tx
+
+func NewNameTransferTx(st AccountGetter, from crypto.PubKey, name string, newOwner []byte,
+	amt int64) (*NameTransferTx, error) {
+	addr := from.Address()
+	acc := st.GetAccount(addr)
+	if acc == nil {
+		return nil, fmt.Errorf("Invalid address %X from pubkey %X", addr, from)
+	}
+
+	nonce := acc.Sequence + 1
+	return NewNameTransferTxWithNonce(from, name, newOwner, amt, nonce), nil
+}
+
+func NewNameTransferTxWithNonce(from crypto.PubKey, name string, newOwner []byte,
+	amt int64, nonce int) *NameTransferTx {
+	addr := from.Address()
+	input := &TxInput{
+		Address:   addr,
+		Amount:    amt,
+		Sequence:  nonce,
+		Signature: crypto.SignatureEd25519{},
+		PubKey:    from,
+	}
+
+	return &NameTransferTx{
+		Input:    input,
+		Name:     name,
+		NewOwner: newOwner,
+	}
+}
+
+func (tx *NameTransferTx) Sign(chainID string, privAccount *acm.PrivAccount) {
+	tx.Input.PubKey = privAccount.PubKey
+	tx.Input.Signature = privAccount.Sign(chainID, tx)
+}
+
 //----------------------------------------------------------------------------
 // BondTx interface for adding inputs/outputs and adding signatures
 
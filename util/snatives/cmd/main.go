@@ -16,6 +16,7 @@ package main
 
 import (
 	"fmt"
+	"sort"
 
 	"github.com/hyperledger/burrow/manager/burrow-mint/evm"
 	"github.com/hyperledger/burrow/util/snatives/templates"
@@ -24,10 +25,17 @@ import (
 // Dump SNative contracts
 func main() {
 	contracts := vm.SNativeContracts()
+	// Dump contracts in a stable order so the output can be checked in
+	names := make([]string, 0, len(contracts))
+	for name := range contracts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
 	// Index of next contract
 	i := 1
 	fmt.Print("pragma solidity >=0.0.0;\n\n")
-	for _, contract := range contracts {
+	for _, name := range names {
+		contract := contracts[name]
 		solidity, err := templates.NewSolidityContract(contract).Solidity()
 		if err != nil {
 			fmt.Printf("Error generating solidity for contract %s: %s\n",
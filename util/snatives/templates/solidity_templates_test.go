@@ -43,3 +43,13 @@ func TestSNativeContractTemplate(t *testing.T) {
 	assert.NoError(t, err)
 	fmt.Println(solidity)
 }
+
+func TestSNativeNameRegContractTemplate(t *testing.T) {
+	contract := vm.SNativeContracts()["NameReg"]
+	solidityContract := NewSolidityContract(contract)
+	solidity, err := solidityContract.Solidity()
+	assert.NoError(t, err)
+	assert.Contains(t, solidity, "function setData(string _name, string _data)")
+	assert.Contains(t, solidity, "returns (string data)")
+	fmt.Println(solidity)
+}